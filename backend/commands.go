@@ -0,0 +1,228 @@
+package main
+
+import (
+	"boilerplate/internal/config"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// maskedValue replaces secret values in printed configuration
+const maskedValue = "********"
+
+// configFlags select the configuration layers; they are shared by all commands
+type configFlags struct {
+	path      string
+	profile   string
+	envPrefix string
+	overrides []string
+}
+
+// options converts the flags into config load options
+func (f *configFlags) options() ([]config.Option, error) {
+	overrides := make(map[string]string, len(f.overrides))
+	for _, override := range f.overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set value %q, expected key=value", override)
+		}
+		overrides[key] = value
+	}
+
+	return []config.Option{
+		config.WithEnvPrefix(f.envPrefix),
+		config.WithProfile(f.profile),
+		config.WithOverrides(overrides),
+	}, nil
+}
+
+// load reads the layered configuration and validates it
+func (f *configFlags) load() (*config.Config, error) {
+	opts, err := f.options()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(f.path, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func newRootCommand() *cobra.Command {
+	flags := &configFlags{}
+
+	serve := newServeCommand(flags)
+	// Without a subcommand the binary starts the server, as it did before the CLI existed
+	root := &cobra.Command{
+		Use:           "boilerplate",
+		Short:         "Boilerplate API server and administration tool",
+		Args:          cobra.NoArgs,
+		RunE:          serve.RunE,
+		SilenceUsage:  true,
+		SilenceErrors: false,
+	}
+
+	// An explicit config path must exist; without it config/local.yaml is looked up
+	// in the usual locations and defaults plus environment variables are used if absent
+	root.PersistentFlags().StringVar(&flags.path, "config", os.Getenv("CONFIG_PATH"), "base config file (env CONFIG_PATH)")
+	root.PersistentFlags().StringVar(&flags.profile, "profile", os.Getenv("APP_PROFILE"), "profile file <profile>.yaml merged over the base file (env APP_PROFILE)")
+	root.PersistentFlags().StringVar(&flags.envPrefix, "env-prefix", os.Getenv("CONFIG_ENV_PREFIX"), "prefix of environment overrides (env CONFIG_ENV_PREFIX)")
+	root.PersistentFlags().StringArrayVar(&flags.overrides, "set", nil, "override a config key, e.g. --set service.port=9090 (repeatable)")
+
+	root.AddCommand(
+		serve,
+		newMigrateCommand(flags),
+		newSeedCommand(flags),
+		newExportCommand(flags),
+		newImportCommand(flags),
+		newConfigCommand(flags),
+		newHealthcheckCommand(flags),
+	)
+
+	return root
+}
+
+func newServeCommand(flags *configFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := flags.load()
+			if err != nil {
+				return err
+			}
+			return serve(cfg, flags)
+		},
+	}
+}
+
+func newConfigCommand(flags *configFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "Print the effective configuration with the source of each value",
+		Long: "Print the effective configuration after merging defaults, the base, profile and override files,\n" +
+			"environment variables and --set flags. Secret values are masked.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := flags.options()
+			if err != nil {
+				return err
+			}
+			settings, err := config.Describe(flags.path, opts...)
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+			for _, setting := range settings {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", setting.Key, formatValue(setting), setting.Source)
+			}
+			return w.Flush()
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check",
+		Short: "Validate the configuration",
+		Long:  "Load the configuration with all layers and report every problem. Exits with a non-zero status if it is invalid.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := flags.load(); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "configuration is valid")
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func newHealthcheckCommand(flags *configFlags) *cobra.Command {
+	var (
+		url     string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Probe the readiness endpoint of a running server",
+		Long: "Request /ready of the server configured by service.host and service.port, or --url,\n" +
+			"and exit with a non-zero status unless it reports ready. Intended for container HEALTHCHECK.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if url == "" {
+				cfg, err := flags.load()
+				if err != nil {
+					return err
+				}
+				url = readyURL(cfg.Service)
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return fmt.Errorf("readiness probe failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("server not ready: %s", resp.Status)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "ready")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "readiness URL to probe instead of the configured address")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "maximum time to wait for the response")
+
+	return cmd
+}
+
+// readyURL returns the readiness URL of the local server. Wildcard hosts are
+// probed on the loopback address.
+func readyURL(cfg config.ServiceConfig) string {
+	host := cfg.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(cfg.Port)) + "/ready"
+}
+
+func formatValue(setting config.Setting) string {
+	if setting.Value == nil {
+		return ""
+	}
+	value := fmt.Sprint(setting.Value)
+	if value != "" && config.IsSecret(setting.Key) {
+		return maskedValue
+	}
+	return value
+}
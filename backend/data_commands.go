@@ -0,0 +1,270 @@
+package main
+
+import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/dataset"
+	"boilerplate/internal/service"
+	"boilerplate/internal/storage"
+	"boilerplate/internal/storage/blob"
+	"boilerplate/internal/storage/mongodb"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// database is the connection used by the commands that work on stored data
+type database struct {
+	cfg    *config.Config
+	client *mongo.Client
+	blobs  blob.Store
+}
+
+// openDatabase loads the configuration, connects to MongoDB and opens the attachment store
+func openDatabase(flags *configFlags) (*database, error) {
+	cfg, err := flags.load()
+	if err != nil {
+		return nil, err
+	}
+	client, err := connectMongo(cfg.Database, nil)
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := blob.New(context.Background(), cfg.Attachments)
+	if err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	return &database{cfg: cfg, client: client, blobs: blobs}, nil
+}
+
+func (d *database) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	d.client.Disconnect(ctx)
+}
+
+func (d *database) service() *service.Service {
+	repo := storage.NewRepository(d.client, d.cfg.Database.Database)
+	repo.BlobStore = d.blobs
+	return service.NewService(&repo, d.cfg.Tasks, d.cfg.Attachments, d.cfg.Notifications)
+}
+
+func (d *database) migrator() *mongodb.Migrator {
+	return mongodb.NewMigrator(d.client, d.cfg.Database.Database, mongodb.Migrations)
+}
+
+func newMigrateCommand(flags *configFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage database schema migrations",
+	}
+
+	var target int
+	up := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDatabase(flags)
+			if err != nil {
+				return err
+			}
+			defer db.close()
+
+			applied, err := db.migrator().Up(cmd.Context(), target)
+			for _, migration := range applied {
+				fmt.Fprintf(cmd.OutOrStdout(), "applied %d: %s\n", migration.Version, migration.Description)
+			}
+			if err == nil && len(applied) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "database is up to date")
+			}
+			return err
+		},
+	}
+	up.Flags().IntVar(&target, "to", 0, "apply migrations up to this version only (default: all)")
+
+	var steps int
+	down := &cobra.Command{
+		Use:   "down",
+		Short: "Revert the most recently applied migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps < 1 {
+				return fmt.Errorf("--steps must be at least 1")
+			}
+			db, err := openDatabase(flags)
+			if err != nil {
+				return err
+			}
+			defer db.close()
+
+			reverted, err := db.migrator().Down(cmd.Context(), steps)
+			for _, migration := range reverted {
+				fmt.Fprintf(cmd.OutOrStdout(), "reverted %d: %s\n", migration.Version, migration.Description)
+			}
+			if err == nil && len(reverted) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no applied migrations")
+			}
+			return err
+		},
+	}
+	down.Flags().IntVar(&steps, "steps", 1, "number of migrations to revert")
+
+	status := &cobra.Command{
+		Use:   "status",
+		Short: "List migrations and whether they have been applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDatabase(flags)
+			if err != nil {
+				return err
+			}
+			defer db.close()
+
+			statuses, err := db.migrator().Status(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tDESCRIPTION\tAPPLIED")
+			for _, s := range statuses {
+				applied := "pending"
+				if s.Applied() {
+					applied = s.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Description, applied)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.AddCommand(up, down, status)
+	return cmd
+}
+
+func newSeedCommand(flags *configFlags) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Load sample projects and tasks",
+		Long:  "Load sample projects and tasks for development and demos. Refuses to run against a database that already contains projects unless --force is given.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDatabase(flags)
+			if err != nil {
+				return err
+			}
+			defer db.close()
+
+			svc := db.service()
+			if !force {
+				existing, err := svc.Project.FindAll(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if len(existing) > 0 {
+					return fmt.Errorf("database already contains %d projects, use --force to seed anyway", len(existing))
+				}
+			}
+
+			summary, err := dataset.Import(cmd.Context(), svc, dataset.Sample(time.Now()))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "created %d projects and %d tasks\n", summary.Projects, summary.Tasks)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&force, "force", false, "seed even if the database already contains projects")
+
+	return cmd
+}
+
+func newExportCommand(flags *configFlags) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all projects and tasks as JSON",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openDatabase(flags)
+			if err != nil {
+				return err
+			}
+			defer db.close()
+
+			data, err := dataset.Export(cmd.Context(), db.service())
+			if err != nil {
+				return err
+			}
+
+			if output == "" || output == "-" {
+				return dataset.Write(cmd.OutOrStdout(), data)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			if err := dataset.Write(file, data); err != nil {
+				file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "exported %d projects to %s\n", len(data.Projects), output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write to (default: stdout)")
+
+	return cmd
+}
+
+func newImportCommand(flags *configFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import projects and tasks from an export",
+		Long: "Import projects and tasks written by export, reading from stdin if the file is \"-\".\n" +
+			"Every project and task is created as a new record; existing data is left untouched.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var input io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				input = file
+			}
+
+			data, err := dataset.Read(input)
+			if err != nil {
+				return err
+			}
+
+			db, err := openDatabase(flags)
+			if err != nil {
+				return err
+			}
+			defer db.close()
+
+			summary, err := dataset.Import(cmd.Context(), db.service(), data)
+			if err != nil {
+				return fmt.Errorf("import stopped after %d projects and %d tasks: %w", summary.Projects, summary.Tasks, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "imported %d projects and %d tasks\n", summary.Projects, summary.Tasks)
+			return nil
+		},
+	}
+}
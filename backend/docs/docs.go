@@ -24,48 +24,30 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/projects": {
+        "/api/v1/admin/log-levels": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all projects with optional pagination",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the global log level and the levels of individual components",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
-                ],
-                "summary": "List projects",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number (1-based)",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "admin"
                 ],
+                "summary": "Get log levels",
                 "responses": {
                     "200": {
-                        "description": "Paginated response with data, total, page, and limit",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/logger.LevelsSnapshot"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Missing admin role",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -74,14 +56,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/api/v1/admin/log-levels/{component}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new project",
+                "description": "Change the global log level or the level of a component (e.g. auth, http, storage), optionally for a limited time",
                 "consumes": [
                     "application/json"
                 ],
@@ -89,29 +73,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "admin"
                 ],
-                "summary": "Create project",
+                "summary": "Set log level",
                 "parameters": [
                     {
-                        "description": "Project to create",
-                        "name": "project",
+                        "type": "string",
+                        "description": "Component name or 'global'",
+                        "name": "component",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New level",
+                        "name": "level",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.CreateProjectRequest"
+                            "$ref": "#/definitions/http.SetLogLevelRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.Project"
+                            "$ref": "#/definitions/logger.LevelsSnapshot"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or missing name",
+                        "description": "Invalid level or TTL",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -119,8 +110,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Missing admin role",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -129,31 +120,26 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/projects/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a single project by its ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Restore the configured level of a component or of the global level",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "admin"
                 ],
-                "summary": "Get project by ID",
+                "summary": "Reset log level",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
+                        "description": "Component name or 'global'",
+                        "name": "component",
                         "in": "path",
                         "required": true
                     }
@@ -162,20 +148,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.Project"
-                        }
-                    },
-                    "400": {
-                        "description": "Missing project ID",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/logger.LevelsSnapshot"
                         }
                     },
-                    "404": {
-                        "description": "Project not found",
+                    "403": {
+                        "description": "Missing admin role",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -184,14 +161,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/v1/me/notification-preferences": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing project",
+                "description": "Get when and how the authenticated user is reminded of the due dates of their tasks. Users who never changed them get the defaults.",
                 "consumes": [
                     "application/json"
                 ],
@@ -199,45 +178,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
-                ],
-                "summary": "Update project",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Project updates",
-                        "name": "project",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.UpdateProjectRequest"
-                        }
-                    }
+                    "notifications"
                 ],
+                "summary": "Get my reminder settings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.Project"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body or missing name",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/entities.NotificationPreferences"
                         }
                     },
-                    "404": {
-                        "description": "Project not found",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -256,13 +208,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a project by ID",
+                "description": "Replace when and how the authenticated user is reminded of the due dates of their tasks. Reminders always reach the inbox; email and webhooks are only available when the server configures them.",
                 "consumes": [
                     "application/json"
                 ],
@@ -270,24 +222,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "projects"
+                    "notifications"
                 ],
-                "summary": "Delete project",
+                "summary": "Update my reminder settings",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Reminder settings",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.NotificationPreferencesRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.NotificationPreferences"
+                        }
                     },
                     "400": {
-                        "description": "Missing project ID",
+                        "description": "Invalid request body, remind window, unavailable channel or webhook URL",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -307,14 +273,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/projects/{id}/tasks": {
+        "/api/v1/me/notifications": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all tasks for a specific project",
+                "description": "Get a page of the reminders in the inbox of the authenticated user, newest first. The response also carries the number of unread notifications.",
                 "consumes": [
                     "application/json"
                 ],
@@ -322,30 +288,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "notifications"
                 ],
-                "summary": "List tasks by project",
+                "summary": "List my notifications",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (1-based, default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Notifications per page (default 20, at most 100)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Paginated response with data, total, page, limit and unread",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entities.Task"
-                            }
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Missing project ID",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -363,14 +338,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/me/notifications/read-all": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new task for a specific project",
+                "description": "Mark every unread notification in the inbox of the authenticated user as read",
                 "consumes": [
                     "application/json"
                 ],
@@ -378,36 +355,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
-                ],
-                "summary": "Create task for project",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Project ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Task to create",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.CreateTaskRequest"
-                        }
-                    }
+                    "notifications"
                 ],
+                "summary": "Mark all notifications as read",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "Number of notifications marked as read",
                         "schema": {
-                            "$ref": "#/definitions/entities.Task"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body, missing title, or invalid status",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -427,14 +389,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/tasks/{id}": {
-            "get": {
+        "/api/v1/me/notifications/{id}/read": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a single task by its ID",
+                "description": "Mark a notification in the inbox of the authenticated user as read",
                 "consumes": [
                     "application/json"
                 ],
@@ -442,13 +404,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "notifications"
                 ],
-                "summary": "Get task by ID",
+                "summary": "Mark notification as read",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Notification ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -458,11 +420,11 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.Task"
+                            "$ref": "#/definitions/entities.Notification"
                         }
                     },
-                    "400": {
-                        "description": "Missing task ID",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -471,7 +433,16 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Notification not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -481,13 +452,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing task (partial updates supported)",
+                "description": "Mark a notification in the inbox of the authenticated user as unread again",
                 "consumes": [
                     "application/json"
                 ],
@@ -495,36 +466,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "notifications"
                 ],
-                "summary": "Update task",
+                "summary": "Mark notification as unread",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Task ID",
+                        "description": "Notification ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Task updates",
-                        "name": "task",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.UpdateTaskRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entities.Task"
+                            "$ref": "#/definitions/entities.Notification"
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body, empty title, or invalid status",
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -533,7 +495,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Task not found",
+                        "description": "Notification not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -551,14 +513,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/v1/me/tasks": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a task by ID",
+                "description": "Get the tasks of all projects assigned to the authenticated user, earliest due date first",
                 "consumes": [
                     "application/json"
                 ],
@@ -568,22 +532,19 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Delete task",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Task ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "summary": "List my tasks",
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Missing task ID",
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Task"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -602,70 +563,4298 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entities.Project": {
+        },
+        "/api/v1/me/timer": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the running timer of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Get my running timer",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TimeEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No timer is running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all projects with optional pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "List projects",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (1-based)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated response with data, total, page, and limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new project. An authenticated creator becomes its first member.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Create project",
+                "parameters": [
+                    {
+                        "description": "Project to create",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, missing name or invalid workflow",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single project by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get project by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing project ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Update project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Project updates",
+                        "name": "project",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.UpdateProjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Project"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or missing name",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a project by ID together with all of its tasks, labels and custom fields",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Delete project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Missing project ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/custom-fields": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the custom field definitions of a project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "List custom fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.CustomField"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a typed custom field for the tasks of a project. single_select fields require options.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Create custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom field to create",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateCustomFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, name, type or options",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/custom-fields/{fieldId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename a custom field or change the options of a single_select field. Options that tasks still use cannot be removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Update custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom field ID",
+                        "name": "fieldId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom field updates",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.UpdateCustomFieldRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, name or options, or a removed option still in use",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a custom field and remove its values from all tasks of the project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Delete custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Custom field ID",
+                        "name": "fieldId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/labels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the labels of a project sorted by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "labels"
+                ],
+                "summary": "List labels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Label"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a label for a project. Names are unique per project, ignoring case.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "labels"
+                ],
+                "summary": "Create label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Label to create",
+                        "name": "label",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.LabelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Label"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, name or colour",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/labels/{labelId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Rename a label or change its colour",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "labels"
+                ],
+                "summary": "Update label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label ID",
+                        "name": "labelId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Label updates",
+                        "name": "label",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.LabelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Label"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, name or colour",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Label not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a label and remove it from all tasks of the project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "labels"
+                ],
+                "summary": "Delete label",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label ID",
+                        "name": "labelId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Label not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the users taking part in a project sorted by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "members"
+                ],
+                "summary": "List project members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.ProjectMember"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/members/{userId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a user, identified by the Keycloak subject, to a project or refresh the stored name and email.\nUse \"me\" or your own subject to add yourself with the name and email of your access token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "members"
+                ],
+                "summary": "Add project member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keycloak subject of the user, or me",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name and email of another user",
+                        "name": "member",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.MemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.ProjectMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "me used without authentication",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a user from a project. They are unassigned from the project's tasks and stop watching them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "members"
+                ],
+                "summary": "Remove project member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Keycloak subject of the user, or me",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "me used without authentication",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Member not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/reports/time": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sum up the time spent on the tasks of a project per user, per task and per day. Entries count on the day they started in the time zone; running timers are left out. With format=csv one breakdown, chosen by groupBy, is returned as a CSV download.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Get time report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day, YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day, YYYY-MM-DD, at most 366 days after from",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the days (default UTC)",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Breakdown of the CSV export: task (default), user or day",
+                        "name": "groupBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid range, time zone, format or grouping",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the tasks of a specific project, optionally filtered. Filters combine with AND.\nCustom fields are filtered with one field.\u003cfieldId\u003e=\u003cvalue\u003e parameter per field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List tasks by project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks with this workflow status, e.g. IN_REVIEW",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated priorities, e.g. HIGH,URGENT or P0,P1",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated label IDs; tasks must have all of them",
+                        "name": "label",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only tasks assigned to this user (Keycloak subject), or me",
+                        "name": "assignee",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing project ID or invalid filter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "assignee=me used without authentication",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new task for a specific project. With parentId the task becomes a subtask of another task of the project.\nAssignees and watchers must be members of the project.\nWith a recurrence (an RFC 5545 RRULE and an IANA time zone) the task becomes the first occurrence of a series; the next occurrence is created when it is completed or when it is due to start within the lookahead of the scheduler.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create task for project",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task to create",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CreateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, missing title, invalid status or priority, unknown label, invalid custom field value or parent, nesting too deep, assignee or watcher not a member, invalid recurrence or negative estimate",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "me used without authentication",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/tasks/graph": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the dependency DAG of a project's tasks, including linked tasks of other projects.\norder lists every blocker before the tasks it blocks, earlier due dates first.\ncriticalPath is the chain of open tasks whose due dates span the longest time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Get dependency graph",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TaskGraph"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/tasks:batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply a list of create, update and delete operations to the tasks of a project using bulk writes.\nCreates run first, then updates, then deletes; a task may only be updated or deleted by one operation of a batch.\nWith atomic=true the operations run in a transaction and either all or none are committed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Batch task operations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Operations to apply",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.BatchTasksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/http.BatchTasksResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, no operations, or too many operations",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Atomic batch rolled back",
+                        "schema": {
+                            "$ref": "#/definitions/http.BatchTasksResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/projects/{id}/workflow": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the statuses, their categories and the allowed transitions of a project's tasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Get project workflow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Workflow"
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the workflow of a project. Statuses are listed in board order and new tasks start in the first one.\nWithout transitions tasks may move between any statuses. Statuses that tasks are still in cannot be removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "projects"
+                ],
+                "summary": "Update project workflow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Project ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New workflow",
+                        "name": "workflow",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entities.Workflow"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Workflow"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or workflow, or a removed status is still in use",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Project not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single task by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing task ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing task (partial updates supported).\nStatus changes must follow the transitions of the project's workflow. Subtasks move along with their parent.\nCompleting the latest occurrence of a recurring task creates the next one, which carries the recurrence from then on. A recurrence with an empty rrule ends the series.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task updates",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.UpdateTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, empty title, invalid status or priority, unknown label, invalid custom field value, a parent that is invalid, below the task itself or too deep, assignee or watcher not a member, invalid recurrence or negative estimate",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "me used without authentication",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Status transition not allowed by the workflow or task blocked by open tasks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a task by ID together with all of its subtasks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Missing task ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/attachments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the metadata of all files attached to a task, oldest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "List task attachments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Attachment"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attach a file to a task. The body is streamed to the attachment store; the content type is sniffed from the content. An optional checksum field with the hex encoded SHA-256 digest of the file must precede the file part and is verified.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Upload attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex encoded SHA-256 digest of the file",
+                        "name": "checksum",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Attachment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid multipart body, missing or empty file, or checksum mismatch",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "File too large or project quota exceeded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/attachments/{attachmentId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the metadata of an attachment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Get attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Attachment"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete an attachment and its content",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Delete attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/attachments/{attachmentId}/content": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream the content of an attachment. Range requests are supported; the ETag and Repr-Digest headers carry the SHA-256 digest of the content. Images, PDFs and plain text are served inline, everything else as a download.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "attachments"
+                ],
+                "summary": "Download attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Attachment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Range not satisfiable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/blocked-by": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that another task, possibly of another project, has to be done before this task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Add blocker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Blocked task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Blocking task",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.DependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TaskDependency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, unknown task or duplicate link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Link would create a cycle",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/blocked-by/{blockerId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the link from a blocking task to this task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Remove blocker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Blocked task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Blocking task ID",
+                        "name": "blockerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/blocks": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that this task has to be done before another task, possibly of another project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Add blocked task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Blocking task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Blocked task",
+                        "name": "dependency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.DependencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TaskDependency"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, unknown task or duplicate link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Link would create a cycle",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/blocks/{blockedId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the link from this task to a task it blocks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "Remove blocked task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Blocking task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Blocked task ID",
+                        "name": "blockedId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Dependency not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/children": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the direct subtasks of a task",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List subtasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.Task"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of the top-level comments of a task, oldest first, each with all of its replies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "List task comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (1-based, default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Threads per page (default 20, at most 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated response with data, total, page, and limit",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Comment on a task, or reply to a top-level comment with parentId. The body is Markdown; members mentioned as @userId or @email are recorded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Create comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, empty body or reply to a reply",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/comments/{commentId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a single comment with its edit history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Comment"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the body of a comment; the previous body is kept in its edit history. Only the author can edit a comment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Update comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New body",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or empty body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not the author of the comment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a comment together with its replies. Only the author can delete a comment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Delete comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Not the author of the comment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/dependencies": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the tasks blocking a task and the tasks it blocks, across projects",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dependencies"
+                ],
+                "summary": "List task dependencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TaskDependencies"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/subtree": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a task with its subtasks at every level nested below it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task subtree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TaskTree"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/time-entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the time entries of a task in order of their start, running timers included",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "List task time entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entities.TimeEntry"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record time the authenticated user spent on a task, with a duration of up to 24 hours that must not end in the future",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Create time entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Time entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.TimeEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, duration, start or note",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/time-entries/{entryId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the start, duration or note of a stopped time entry. Only the user who tracked the time can change it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Update time entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.UpdateTimeEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, duration, start or note",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not the user who tracked the time",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Time entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "The timer is still running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a time entry or discard a running timer. Only the user who tracked the time can delete it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Delete time entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time entry ID",
+                        "name": "entryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Not the user who tracked the time",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Time entry not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/timer/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a timer for the authenticated user on a task. A user has at most one running timer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Start timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "timer",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.TimerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or note too long",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "A timer is already running",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}/timer/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop the timer of the authenticated user on a task and record the time spent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time-tracking"
+                ],
+                "summary": "Stop timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entities.TimeEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Not authenticated",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "No timer is running on the task",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entities.Attachment": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "description": "ContentType is sniffed from the first bytes of the content",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "fileName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "description": "SHA256 is the hex encoded SHA-256 digest of the content",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "in bytes",
+                    "type": "integer"
+                },
+                "taskId": {
+                    "type": "string"
+                },
+                "uploadedBy": {
+                    "$ref": "#/definitions/entities.UserRef"
+                }
+            }
+        },
+        "entities.Comment": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "$ref": "#/definitions/entities.UserRef"
+                },
+                "body": {
+                    "description": "Body is the Markdown source of the comment",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "edits": {
+                    "description": "Edits holds the previous bodies of the comment, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.CommentEdit"
+                    }
+                },
+                "html": {
+                    "description": "HTML is the rendered and sanitized body, computed when the comment is read",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "description": "Mentions are the project members mentioned with @userId or @email in the body",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.UserRef"
+                    }
+                },
+                "parentId": {
+                    "description": "ParentID references the top-level comment a reply belongs to; empty for top-level comments",
+                    "type": "string"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "taskId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.CommentEdit": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "editedAt": {
+                    "description": "EditedAt is when the body was replaced",
+                    "type": "string"
+                }
+            }
+        },
+        "entities.CustomField": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "description": "Options lists the allowed values of single_select fields",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/entities.CustomFieldType"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.CustomFieldType": {
+            "type": "string",
+            "enum": [
+                "text",
+                "number",
+                "date",
+                "single_select",
+                "user"
+            ],
+            "x-enum-varnames": [
+                "CustomFieldTypeText",
+                "CustomFieldTypeNumber",
+                "CustomFieldTypeDate",
+                "CustomFieldTypeSingleSelect",
+                "CustomFieldTypeUser"
+            ]
+        },
+        "entities.CustomFieldValues": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "entities.Label": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "hex colour, e.g. #d73a4a",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.Notification": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entities.NotificationKind"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "readAt": {
+                    "description": "ReadAt is when the recipient marked the notification as read; nil while unread",
+                    "type": "string"
+                },
+                "taskId": {
+                    "type": "string"
+                },
+                "taskTitle": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "UserID is the Keycloak subject of the recipient",
+                    "type": "string"
+                }
+            }
+        },
+        "entities.NotificationKind": {
+            "type": "string",
+            "enum": [
+                "due_soon",
+                "overdue"
+            ],
+            "x-enum-varnames": [
+                "NotificationDueSoon",
+                "NotificationOverdue"
+            ]
+        },
+        "entities.NotificationPreferences": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Email also sends reminders to the email address of the user",
+                    "type": "boolean",
+                    "example": false
+                },
+                "overdue": {
+                    "description": "Overdue enables reminders for open tasks past their due date",
+                    "type": "boolean",
+                    "example": true
+                },
+                "remindBefore": {
+                    "description": "RemindBefore is how many hours before the due date a due soon reminder\nis sent; 0 disables them",
+                    "type": "integer",
+                    "example": 24
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "webhookUrl": {
+                    "description": "WebhookURL also posts reminders to this URL when set",
+                    "type": "string",
+                    "example": "https://hooks.example.com/reminders"
+                }
+            }
+        },
+        "entities.Project": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "workflow": {
+                    "$ref": "#/definitions/entities.Workflow"
+                }
+            }
+        },
+        "entities.ProjectMember": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "UserID is the Keycloak subject of the user",
+                    "type": "string"
+                }
+            }
+        },
+        "entities.StatusCategory": {
+            "type": "string",
+            "enum": [
+                "todo",
+                "active",
+                "done"
+            ],
+            "x-enum-varnames": [
+                "StatusCategoryTodo",
+                "StatusCategoryActive",
+                "StatusCategoryDone"
+            ]
+        },
+        "entities.Task": {
+            "type": "object",
+            "properties": {
+                "assignees": {
+                    "description": "Assignees and Watchers are members of the task's project",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.UserRef"
+                    }
+                },
+                "commentCount": {
+                    "description": "CommentCount counts the comments and replies on the task and is computed when the task is read",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "customFields": {
+                    "description": "CustomFields maps custom field IDs of the task's project to their values",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.CustomFieldValues"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "description": "Estimate is the expected effort in seconds; 0 means no estimate",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "labelIds": {
+                    "description": "LabelIDs reference labels of the task's project",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "occurrence": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "ParentID references the parent task in the same project; empty for top-level tasks",
+                    "type": "string"
+                },
+                "priority": {
+                    "$ref": "#/definitions/entities.TaskPriority"
+                },
+                "progress": {
+                    "description": "Progress is computed when the task is read and only set for tasks with subtasks",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.TaskProgress"
+                        }
+                    ]
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence repeats the task. It is carried by the latest occurrence of a\nseries; removing it, or deleting that occurrence, ends the series.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.TaskRecurrence"
+                        }
+                    ]
+                },
+                "seriesId": {
+                    "description": "SeriesID and Occurrence are set by the service: SeriesID is shared by\nall occurrences of a recurring task, Occurrence is the start of the\noccurrence the task stands for",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entities.TaskStatus"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "watchers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.UserRef"
+                    }
+                }
+            }
+        },
+        "entities.TaskBatchOpType": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "TaskBatchOpCreate",
+                "TaskBatchOpUpdate",
+                "TaskBatchOpDelete"
+            ]
+        },
+        "entities.TaskBatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "op": {
+                    "$ref": "#/definitions/entities.TaskBatchOpType"
+                },
+                "status": {
+                    "$ref": "#/definitions/entities.TaskBatchStatus"
+                },
+                "task": {
+                    "$ref": "#/definitions/entities.Task"
+                }
+            }
+        },
+        "entities.TaskBatchStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "failed",
+                "rolled_back"
+            ],
+            "x-enum-varnames": [
+                "TaskBatchStatusOK",
+                "TaskBatchStatusFailed",
+                "TaskBatchStatusRolledBack"
+            ]
+        },
+        "entities.TaskDependencies": {
+            "type": "object",
+            "properties": {
+                "blockedBy": {
+                    "description": "BlockedBy are the links to tasks that have to be done first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TaskDependency"
+                    }
+                },
+                "blocks": {
+                    "description": "Blocks are the links to tasks waiting for this task",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TaskDependency"
+                    }
+                }
+            }
+        },
+        "entities.TaskDependency": {
+            "type": "object",
+            "properties": {
+                "blockedId": {
+                    "type": "string"
+                },
+                "blockedProjectId": {
+                    "type": "string"
+                },
+                "blockerId": {
+                    "type": "string"
+                },
+                "blockerProjectId": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.TaskGraph": {
+            "type": "object",
+            "properties": {
+                "criticalPath": {
+                    "description": "CriticalPath is the chain of dependent tasks spanning the longest time\nbetween due dates, from the first blocker to the last blocked task",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TaskDependency"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TaskGraphNode"
+                    }
+                },
+                "order": {
+                    "description": "Order lists the node IDs so that every blocker precedes the tasks it blocks",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entities.TaskGraphNode": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "description": "Done is set for tasks in a status of the done category",
+                    "type": "boolean"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "external": {
+                    "description": "External marks tasks of other projects that are linked to the project's tasks",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entities.TaskStatus"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.TaskPriority": {
+            "type": "integer",
+            "enum": [
+                0,
+                1,
+                2,
+                3,
+                4
+            ],
+            "x-enum-varnames": [
+                "TaskPriorityNone",
+                "TaskPriorityLow",
+                "TaskPriorityMedium",
+                "TaskPriorityHigh",
+                "TaskPriorityUrgent"
+            ]
+        },
+        "entities.TaskProgress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "description": "Done counts the subtasks in a status of the done category",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.TaskRecurrence": {
+            "type": "object",
+            "properties": {
+                "rrule": {
+                    "description": "RRule is an RFC 5545 recurrence rule without DTSTART, repeating at most daily",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;BYDAY=MO"
+                },
+                "start": {
+                    "description": "Start is the DTSTART of the rule; its local time of day is inherited by\nthe occurrences unless the rule sets BYHOUR or BYMINUTE",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA time zone the rule is evaluated in, so that e.g.\na weekly task keeps its local time across daylight saving changes",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "entities.TaskStatus": {
+            "type": "string",
+            "enum": [
+                "TODO",
+                "IN_PROGRESS",
+                "DONE"
+            ],
+            "x-enum-varnames": [
+                "TaskStatusTodo",
+                "TaskStatusInProgress",
+                "TaskStatusDone"
+            ]
+        },
+        "entities.TaskTree": {
+            "type": "object",
+            "properties": {
+                "assignees": {
+                    "description": "Assignees and Watchers are members of the task's project",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.UserRef"
+                    }
+                },
+                "commentCount": {
+                    "description": "CommentCount counts the comments and replies on the task and is computed when the task is read",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "customFields": {
+                    "description": "CustomFields maps custom field IDs of the task's project to their values",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.CustomFieldValues"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimate": {
+                    "description": "Estimate is the expected effort in seconds; 0 means no estimate",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "labelIds": {
+                    "description": "LabelIDs reference labels of the task's project",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "occurrence": {
+                    "type": "string"
+                },
+                "parentId": {
+                    "description": "ParentID references the parent task in the same project; empty for top-level tasks",
+                    "type": "string"
+                },
+                "priority": {
+                    "$ref": "#/definitions/entities.TaskPriority"
+                },
+                "progress": {
+                    "description": "Progress is computed when the task is read and only set for tasks with subtasks",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.TaskProgress"
+                        }
+                    ]
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "recurrence": {
+                    "description": "Recurrence repeats the task. It is carried by the latest occurrence of a\nseries; removing it, or deleting that occurrence, ends the series.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.TaskRecurrence"
+                        }
+                    ]
+                },
+                "seriesId": {
+                    "description": "SeriesID and Occurrence are set by the service: SeriesID is shared by\nall occurrences of a recurring task, Occurrence is the start of the\noccurrence the task stands for",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/entities.TaskStatus"
+                },
+                "subtasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TaskTree"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "watchers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.UserRef"
+                    }
+                }
+            }
+        },
+        "entities.TimeByDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2030-03-25"
+                },
+                "duration": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entities.TimeByTask": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "estimate": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "taskId": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "entities.TimeByUser": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/entities.UserRef"
+                }
+            }
+        },
+        "entities.TimeEntry": {
             "type": "object",
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
-                "description": {
+                "duration": {
+                    "description": "in seconds, 0 while the timer runs",
+                    "type": "integer"
+                },
+                "end": {
+                    "description": "End is unset while the timer runs",
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "note": {
+                    "type": "string"
+                },
+                "projectId": {
+                    "type": "string"
+                },
+                "start": {
+                    "description": "Start is when the work began",
+                    "type": "string"
+                },
+                "taskId": {
                     "type": "string"
                 },
                 "updatedAt": {
                     "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/entities.UserRef"
                 }
             }
         },
-        "entities.Task": {
+        "entities.TimeReport": {
             "type": "object",
             "properties": {
-                "createdAt": {
-                    "type": "string"
+                "byDay": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TimeByDay"
+                    }
                 },
-                "description": {
+                "byTask": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TimeByTask"
+                    }
+                },
+                "byUser": {
+                    "description": "ByUser, ByTask and ByDay break the total down, the largest first for\nusers and tasks and in calendar order for days",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TimeByUser"
+                    }
+                },
+                "duration": {
+                    "description": "in seconds",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "from": {
+                    "description": "From and To are the first and the last day of the report, YYYY-MM-DD",
+                    "type": "string",
+                    "example": "2030-03-01"
+                },
+                "projectId": {
                     "type": "string"
                 },
-                "dueDate": {
+                "timezone": {
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2030-03-31"
+                }
+            }
+        },
+        "entities.UserRef": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "projectId": {
+                "name": {
                     "type": "string"
+                }
+            }
+        },
+        "entities.Workflow": {
+            "type": "object",
+            "properties": {
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.WorkflowStatus"
+                    }
                 },
-                "status": {
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.WorkflowTransition"
+                    }
+                }
+            }
+        },
+        "entities.WorkflowStatus": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/entities.StatusCategory"
+                },
+                "key": {
                     "$ref": "#/definitions/entities.TaskStatus"
                 },
-                "title": {
+                "name": {
                     "type": "string"
+                }
+            }
+        },
+        "entities.WorkflowTransition": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "$ref": "#/definitions/entities.TaskStatus"
                 },
-                "updatedAt": {
-                    "type": "string"
+                "to": {
+                    "$ref": "#/definitions/entities.TaskStatus"
                 }
             }
         },
-        "entities.TaskStatus": {
-            "type": "integer",
-            "enum": [
-                0,
-                1,
-                2
-            ],
-            "x-enum-varnames": [
-                "TaskStatusTodo",
-                "TaskStatusInProgress",
-                "TaskStatusDone"
-            ]
+        "http.BatchTaskOperation": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Detailed task description"
+                },
+                "due_date": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "op": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "example": "update"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "NONE",
+                        "LOW",
+                        "MEDIUM",
+                        "HIGH",
+                        "URGENT",
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3"
+                    ],
+                    "example": "HIGH"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "DONE"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Implement feature X"
+                }
+            }
+        },
+        "http.BatchTasksRequest": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.BatchTaskOperation"
+                    }
+                }
+            }
+        },
+        "http.BatchTasksResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entities.TaskBatchResult"
+                    }
+                }
+            }
+        },
+        "http.CommentRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Looks good, @jane@example.com please review"
+                },
+                "parentId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "http.CreateCustomFieldRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Team"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "web",
+                        "mobile"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "number",
+                        "date",
+                        "single_select",
+                        "user"
+                    ],
+                    "example": "single_select"
+                }
+            }
         },
         "http.CreateProjectRequest": {
             "type": "object",
@@ -677,12 +4866,29 @@ const docTemplate = `{
                 "name": {
                     "type": "string",
                     "example": "My Project"
+                },
+                "workflow": {
+                    "$ref": "#/definitions/entities.Workflow"
                 }
             }
         },
         "http.CreateTaskRequest": {
             "type": "object",
             "properties": {
+                "assigneeIds": {
+                    "description": "AssigneeIDs and WatcherIDs are Keycloak subjects of project members, or me",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "me"
+                    ]
+                },
+                "customFields": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
                 "description": {
                     "type": "string",
                     "example": "Detailed task description"
@@ -691,18 +4897,184 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-12-31T23:59:59Z"
                 },
-                "status": {
+                "estimate": {
+                    "description": "Estimate is the expected effort in seconds",
+                    "type": "integer",
+                    "example": 7200
+                },
+                "labelIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parentId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "priority": {
                     "type": "string",
                     "enum": [
-                        "TODO",
-                        "IN_PROGRESS",
-                        "DONE"
+                        "NONE",
+                        "LOW",
+                        "MEDIUM",
+                        "HIGH",
+                        "URGENT",
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3"
                     ],
+                    "example": "HIGH"
+                },
+                "recurrence": {
+                    "description": "Recurrence makes the task the first occurrence of a recurring series",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entities.TaskRecurrence"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string",
                     "example": "TODO"
                 },
                 "title": {
                     "type": "string",
                     "example": "Implement feature X"
+                },
+                "watcherIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.DependencyRequest": {
+            "type": "object",
+            "properties": {
+                "taskId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                }
+            }
+        },
+        "http.LabelRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#d73a4a"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "bug"
+                }
+            }
+        },
+        "http.MemberRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "jane@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Jane Doe"
+                }
+            }
+        },
+        "http.NotificationPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "Email sends reminders to the email address the user was assigned with; requires a configured mail server",
+                    "type": "boolean",
+                    "example": false
+                },
+                "overdue": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "remindBefore": {
+                    "description": "RemindBefore is how many hours before the due date to remind, at most 168; 0 disables due soon reminders",
+                    "type": "integer",
+                    "example": 24
+                },
+                "webhookUrl": {
+                    "description": "WebhookURL receives reminders as signed JSON posts; empty disables the webhook",
+                    "type": "string",
+                    "example": "https://hooks.example.com/reminders"
+                }
+            }
+        },
+        "http.SetLogLevelRequest": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "debug"
+                },
+                "ttl": {
+                    "description": "TTL after which the level reverts to the configured one, as a Go duration",
+                    "type": "string",
+                    "example": "15m"
+                }
+            }
+        },
+        "http.TimeEntryRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "in seconds, at most 24 hours",
+                    "type": "integer",
+                    "example": 5400
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Workshop"
+                },
+                "start": {
+                    "description": "Start defaults to the duration before now",
+                    "type": "string",
+                    "example": "2030-03-25T09:00:00Z"
+                }
+            }
+        },
+        "http.TimerRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "example": "Call with the client"
+                }
+            }
+        },
+        "http.UpdateCustomFieldRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Team"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "web",
+                        "mobile",
+                        "backend"
+                    ]
                 }
             }
         },
@@ -722,6 +5094,16 @@ const docTemplate = `{
         "http.UpdateTaskRequest": {
             "type": "object",
             "properties": {
+                "assigneeIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "customFields": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
                 "description": {
                     "type": "string",
                     "example": "Updated description"
@@ -730,18 +5112,84 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-12-31T23:59:59Z"
                 },
-                "status": {
+                "estimate": {
+                    "description": "Estimate replaces the expected effort in seconds; 0 removes it",
+                    "type": "integer",
+                    "example": 7200
+                },
+                "labelIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parentId": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "priority": {
                     "type": "string",
                     "enum": [
-                        "TODO",
-                        "IN_PROGRESS",
-                        "DONE"
+                        "NONE",
+                        "LOW",
+                        "MEDIUM",
+                        "HIGH",
+                        "URGENT",
+                        "P0",
+                        "P1",
+                        "P2",
+                        "P3"
                     ],
+                    "example": "URGENT"
+                },
+                "recurrence": {
+                    "$ref": "#/definitions/entities.TaskRecurrence"
+                },
+                "status": {
+                    "type": "string",
                     "example": "IN_PROGRESS"
                 },
                 "title": {
                     "type": "string",
                     "example": "Updated task title"
+                },
+                "watcherIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.UpdateTimeEntryRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "in seconds, at most 24 hours",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Workshop and minutes"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2030-03-25T09:00:00Z"
+                }
+            }
+        },
+        "logger.LevelsSnapshot": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "global": {
+                    "type": "string"
                 }
             }
         }
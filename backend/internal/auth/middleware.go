@@ -2,6 +2,8 @@ package auth
 
 import (
 	"boilerplate/internal/config"
+	"boilerplate/internal/logger"
+	"boilerplate/internal/metrics"
 	"context"
 	"crypto/rsa"
 	"encoding/base64"
@@ -25,6 +27,28 @@ const (
 	UserContextKey contextKey = "user"
 )
 
+// Reasons reported when a request fails authentication
+const (
+	FailureMissingHeader    = "missing_header"
+	FailureInvalidHeader    = "invalid_header"
+	FailureExpired          = "expired"
+	FailureMalformed        = "malformed"
+	FailureInvalidSignature = "invalid_signature"
+	FailureInvalidIssuer    = "invalid_issuer"
+	FailureUnknownKey       = "unknown_key"
+	FailureInvalidToken     = "invalid_token"
+)
+
+var (
+	errInvalidIssuer = errors.New("invalid issuer")
+	errTokenExpired  = errors.New("token expired")
+	errUnknownKey    = errors.New("signing key not found")
+)
+
+// jwksMaxAge is how old the cached signing keys may get before the readiness
+// check refreshes them to verify the identity provider is still reachable
+const jwksMaxAge = 5 * time.Minute
+
 // UserClaims represents the claims extracted from JWT
 type UserClaims struct {
 	Subject  string   `json:"sub"`
@@ -34,17 +58,29 @@ type UserClaims struct {
 	ClientID string   `json:"azp"`
 }
 
+// HasRole reports whether the user has the given realm role
+func (c *UserClaims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 // Middleware provides JWT authentication middleware
 type Middleware struct {
 	config    config.AuthConfig
 	logger    *slog.Logger
+	metrics   *metrics.Metrics
 	jwksCache *jwksCache
 }
 
-func NewMiddleware(cfg config.AuthConfig, logger *slog.Logger) *Middleware {
-	m := &Middleware{
-		config: cfg,
-		logger: logger,
+func NewMiddleware(cfg config.AuthConfig, logger *slog.Logger, m *metrics.Metrics) *Middleware {
+	mw := &Middleware{
+		config:  cfg,
+		logger:  logger,
+		metrics: m,
 		jwksCache: &jwksCache{
 			keys: make(map[string]*rsa.PublicKey),
 		},
@@ -52,10 +88,10 @@ func NewMiddleware(cfg config.AuthConfig, logger *slog.Logger) *Middleware {
 
 	// Pre-load JWKS if configured
 	if cfg.JWKSURL != "" {
-		go m.refreshJWKS()
+		go mw.refreshJWKS(context.Background())
 	}
 
-	return m
+	return mw
 }
 
 // Authenticate is the HTTP middleware that validates JWT tokens
@@ -70,14 +106,16 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 		// Extract token from Authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			m.logger.Debug("missing authorization header")
+			m.logger.DebugContext(r.Context(), "missing authorization header")
+			m.metrics.AuthFailed(FailureMissingHeader)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			m.logger.Debug("invalid authorization header format")
+			m.logger.DebugContext(r.Context(), "invalid authorization header format")
+			m.metrics.AuthFailed(FailureInvalidHeader)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
@@ -87,17 +125,44 @@ func (m *Middleware) Authenticate(next http.Handler) http.Handler {
 		// Parse and validate token
 		claims, err := m.validateToken(tokenString)
 		if err != nil {
-			m.logger.Debug("token validation failed", "error", err)
+			m.logger.DebugContext(r.Context(), "token validation failed", "error", err)
+			m.metrics.AuthFailed(failureReason(err))
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
+		// Tag all further log records of this request with the user
+		logger.AddAttrs(r.Context(), slog.String("user_sub", claims.Subject))
+
 		// Add claims to request context
 		ctx := context.WithValue(r.Context(), UserContextKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// RequireRole rejects requests whose token does not carry the given realm role.
+// It must run after Authenticate. When authentication is disabled every request
+// is allowed, like the rest of the API.
+func (m *Middleware) RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.config.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, ok := GetUserClaims(r.Context())
+			if !ok || !claims.HasRole(role) {
+				m.logger.WarnContext(r.Context(), "missing required role", "role", role)
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // validateToken validates a JWT token and returns the claims
 func (m *Middleware) validateToken(tokenString string) (*UserClaims, error) {
 	// Parse token
@@ -114,12 +179,12 @@ func (m *Middleware) validateToken(tokenString string) (*UserClaims, error) {
 
 		publicKey, err := m.jwksCache.getKey(kid)
 		if err != nil {
-			if err := m.refreshJWKS(); err != nil {
-				return nil, fmt.Errorf("failed to refresh JWKS: %w", err)
+			if err := m.refreshJWKS(context.Background()); err != nil {
+				return nil, fmt.Errorf("%w: failed to refresh JWKS: %w", errUnknownKey, err)
 			}
 			publicKey, err = m.jwksCache.getKey(kid)
 			if err != nil {
-				return nil, fmt.Errorf("key not found in JWKS: %w", err)
+				return nil, fmt.Errorf("%w: key not found in JWKS: %w", errUnknownKey, err)
 			}
 		}
 
@@ -144,7 +209,7 @@ func (m *Middleware) validateToken(tokenString string) (*UserClaims, error) {
 	if m.config.Issuer != "" {
 		iss, ok := claims["iss"].(string)
 		if !ok || iss != m.config.Issuer {
-			return nil, errors.New("invalid issuer")
+			return nil, errInvalidIssuer
 		}
 	}
 
@@ -154,7 +219,7 @@ func (m *Middleware) validateToken(tokenString string) (*UserClaims, error) {
 		return nil, errors.New("missing exp claim")
 	}
 	if time.Now().Unix() > int64(exp) {
-		return nil, errors.New("token expired")
+		return nil, errTokenExpired
 	}
 
 	// Extract user claims
@@ -190,13 +255,64 @@ func (m *Middleware) validateToken(tokenString string) (*UserClaims, error) {
 	return userClaims, nil
 }
 
-// refreshJWKS fetches the latest JWKS from the provider
-func (m *Middleware) refreshJWKS() error {
+// failureReason maps a token validation error to a bounded set of metric labels
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, errTokenExpired), errors.Is(err, jwt.ErrTokenExpired):
+		return FailureExpired
+	case errors.Is(err, errInvalidIssuer):
+		return FailureInvalidIssuer
+	case errors.Is(err, errUnknownKey):
+		return FailureUnknownKey
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return FailureMalformed
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return FailureInvalidSignature
+	default:
+		return FailureInvalidToken
+	}
+}
+
+// CheckJWKS reports whether signing keys are available to validate tokens.
+// Keys older than jwksMaxAge are refreshed first, so an unreachable identity
+// provider is detected. It is meant to be registered as a readiness check.
+func (m *Middleware) CheckJWKS(ctx context.Context) error {
+	if time.Since(m.jwksCache.refreshedAt()) > jwksMaxAge {
+		if err := m.refreshJWKS(ctx); err != nil {
+			return err
+		}
+	}
+
+	if m.jwksCache.size() == 0 {
+		return errors.New("no signing keys loaded")
+	}
+
+	return nil
+}
+
+// refreshJWKS fetches the latest JWKS from the provider and records the outcome
+func (m *Middleware) refreshJWKS(ctx context.Context) error {
+	err := m.fetchJWKS(ctx)
+	if err != nil {
+		m.metrics.JWKSRefreshed("error")
+		return err
+	}
+	m.metrics.JWKSRefreshed("success")
+	return nil
+}
+
+// fetchJWKS downloads the JWKS and caches its RSA signing keys
+func (m *Middleware) fetchJWKS(ctx context.Context) error {
 	if m.config.JWKSURL == "" {
 		return errors.New("JWKS URL not configured")
 	}
 
-	resp, err := http.Get(m.config.JWKSURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.config.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
@@ -237,6 +353,7 @@ func (m *Middleware) refreshJWKS() error {
 
 		m.jwksCache.keys[key.Kid] = publicKey
 	}
+	m.jwksCache.lastRefresh = time.Now()
 
 	m.logger.Info("refreshed JWKS", "key_count", len(m.jwksCache.keys))
 	return nil
@@ -244,8 +361,21 @@ func (m *Middleware) refreshJWKS() error {
 
 // jwksCache holds cached JWKS public keys
 type jwksCache struct {
-	mu   sync.RWMutex
-	keys map[string]*rsa.PublicKey
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	lastRefresh time.Time
+}
+
+func (c *jwksCache) refreshedAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastRefresh
+}
+
+func (c *jwksCache) size() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.keys)
 }
 
 func (c *jwksCache) getKey(kid string) (*rsa.PublicKey, error) {
@@ -2,16 +2,24 @@ package config
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Config struct {
-	Service   ServiceConfig    `yaml:"service" mapstructure:"service"`
-	Database  DatabaseConfig   `yaml:"database" mapstructure:"database"`
-	Auth      AuthConfig       `yaml:"auth" mapstructure:"auth"`
-	Logging   LoggingConfig    `yaml:"logging" mapstructure:"logging"`
-	CORS      CORSConfig       `yaml:"cors" mapstructure:"cors"`
-	Docs      DocsConfig       `yaml:"docs" mapstructure:"docs"`
-	RateLimit RateLimitConfig  `yaml:"rate_limit" mapstructure:"rate_limit"`
+	Service     ServiceConfig     `yaml:"service" mapstructure:"service"`
+	Database    DatabaseConfig    `yaml:"database" mapstructure:"database"`
+	Auth        AuthConfig        `yaml:"auth" mapstructure:"auth"`
+	Logging     LoggingConfig     `yaml:"logging" mapstructure:"logging"`
+	CORS        CORSConfig        `yaml:"cors" mapstructure:"cors"`
+	Docs        DocsConfig        `yaml:"docs" mapstructure:"docs"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" mapstructure:"rate_limit"`
+	Metrics     MetricsConfig     `yaml:"metrics" mapstructure:"metrics"`
+	Tracing     TracingConfig     `yaml:"tracing" mapstructure:"tracing"`
+	Tasks       TasksConfig       `yaml:"tasks" mapstructure:"tasks"`
+	Attachments AttachmentsConfig `yaml:"attachments" mapstructure:"attachments"`
+	Scheduler   SchedulerConfig   `yaml:"scheduler" mapstructure:"scheduler"`
+	// Notifications holds the due date reminders and the channels delivering them
+	Notifications NotificationsConfig `yaml:"notifications" mapstructure:"notifications"`
 }
 
 type ServiceConfig struct {
@@ -19,6 +27,11 @@ type ServiceConfig struct {
 	Port         int    `yaml:"port" mapstructure:"port"`
 	ReadTimeout  int    `yaml:"read_timeout" mapstructure:"read_timeout"`   // in seconds
 	WriteTimeout int    `yaml:"write_timeout" mapstructure:"write_timeout"` // in seconds
+	// ShutdownDelay is how long /ready reports failure before the server stops
+	// accepting connections, giving load balancers time to stop routing traffic
+	ShutdownDelay int `yaml:"shutdown_delay" mapstructure:"shutdown_delay"` // in seconds
+	// WatchConfig reloads the reloadable settings when a config file changes
+	WatchConfig bool `yaml:"watch_config" mapstructure:"watch_config"`
 }
 
 type DatabaseConfig struct {
@@ -27,6 +40,8 @@ type DatabaseConfig struct {
 	Username string `yaml:"username,omitempty" mapstructure:"username"` // optional
 	Password string `yaml:"password,omitempty" mapstructure:"password"` // optional
 	Timeout  int    `yaml:"timeout" mapstructure:"timeout"`             // in seconds
+	// MigrateOnStartup applies pending schema migrations before the server starts
+	MigrateOnStartup bool `yaml:"migrate_on_startup" mapstructure:"migrate_on_startup"`
 }
 
 type AuthConfig struct {
@@ -35,17 +50,34 @@ type AuthConfig struct {
 	ClientID     string `yaml:"client_id" mapstructure:"client_id"`
 	ClientSecret string `yaml:"client_secret" mapstructure:"client_secret"`
 	JWKSURL      string `yaml:"jwks_url" mapstructure:"jwks_url"`
+	AdminRole    string `yaml:"admin_role" mapstructure:"admin_role"` // realm role required for /api/v1/admin endpoints
 }
 
 type LoggingConfig struct {
-	Level      string      `yaml:"level" mapstructure:"level"`   // debug, info, warn, error
-	Format     string      `yaml:"format" mapstructure:"format"` // console, json
-	LokiConfig *LokiConfig `yaml:"loki,omitempty" mapstructure:"loki"`
+	Level      string            `yaml:"level" mapstructure:"level"`                     // debug, info, warn, error
+	Format     string            `yaml:"format" mapstructure:"format"`                   // console, json
+	Components map[string]string `yaml:"components,omitempty" mapstructure:"components"` // per-component levels, e.g. auth: debug
+	LokiConfig *LokiConfig       `yaml:"loki,omitempty" mapstructure:"loki"`
+	Redaction  RedactionConfig   `yaml:"redaction" mapstructure:"redaction"`
+}
+
+// RedactionConfig controls how sensitive data is removed from log records
+type RedactionConfig struct {
+	Enabled    bool     `yaml:"enabled" mapstructure:"enabled"`
+	Keys       []string `yaml:"keys" mapstructure:"keys"`               // case-insensitive substrings of attribute keys whose values are redacted
+	MaskEmails bool     `yaml:"mask_emails" mapstructure:"mask_emails"` // mask the local part of email addresses
 }
 
 type LokiConfig struct {
-	URL         string `yaml:"url" mapstructure:"url"`
-	BearerToken string `yaml:"bearer_token,omitempty" mapstructure:"bearer_token"`
+	URL           string            `yaml:"url" mapstructure:"url"`
+	BearerToken   string            `yaml:"bearer_token,omitempty" mapstructure:"bearer_token"`
+	Labels        map[string]string `yaml:"labels,omitempty" mapstructure:"labels"`                 // static stream labels, e.g. service, env, host
+	LabelAttrs    []string          `yaml:"label_attrs,omitempty" mapstructure:"label_attrs"`       // attributes promoted to stream labels, e.g. logger, route
+	BatchSize     int               `yaml:"batch_size,omitempty" mapstructure:"batch_size"`         // max records per push
+	BatchInterval int               `yaml:"batch_interval,omitempty" mapstructure:"batch_interval"` // in seconds
+	QueueSize     int               `yaml:"queue_size,omitempty" mapstructure:"queue_size"`         // records buffered before dropping
+	MaxRetries    int               `yaml:"max_retries,omitempty" mapstructure:"max_retries"`
+	Timeout       int               `yaml:"timeout,omitempty" mapstructure:"timeout"` // in seconds
 }
 
 type CORSConfig struct {
@@ -61,16 +93,120 @@ type DocsConfig struct {
 	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
 }
 
+type MetricsConfig struct {
+	Enabled     bool   `yaml:"enabled" mapstructure:"enabled"`
+	Path        string `yaml:"path" mapstructure:"path"`
+	BearerToken string `yaml:"bearer_token,omitempty" mapstructure:"bearer_token"` // optional, required from scrapers if set
+}
+
+type TracingConfig struct {
+	Enabled     bool              `yaml:"enabled" mapstructure:"enabled"`
+	ServiceName string            `yaml:"service_name" mapstructure:"service_name"`
+	Exporter    string            `yaml:"exporter" mapstructure:"exporter"` // otlp, stdout
+	Protocol    string            `yaml:"protocol" mapstructure:"protocol"` // http, grpc (otlp only)
+	Endpoint    string            `yaml:"endpoint" mapstructure:"endpoint"` // host:port of the OTLP collector
+	Insecure    bool              `yaml:"insecure" mapstructure:"insecure"` // disable TLS towards the collector
+	Headers     map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	SampleRatio float64           `yaml:"sample_ratio" mapstructure:"sample_ratio"` // 0.0 - 1.0
+}
+
 type RateLimitConfig struct {
 	Enabled           bool `yaml:"enabled" mapstructure:"enabled"`
 	RequestsPerSecond int  `yaml:"requests_per_second" mapstructure:"requests_per_second"`
 	Burst             int  `yaml:"burst" mapstructure:"burst"`
 }
 
-func Load(configPath string) (*Config, error) {
-	return LoadWithViper(configPath)
+type TasksConfig struct {
+	MaxDepth   int              `yaml:"max_depth" mapstructure:"max_depth"` // levels of subtasks below a top-level task
+	Recurrence RecurrenceConfig `yaml:"recurrence" mapstructure:"recurrence"`
+}
+
+// RecurrenceConfig controls how far ahead the scheduler creates the
+// occurrences of recurring tasks
+type RecurrenceConfig struct {
+	Interval  int `yaml:"interval" mapstructure:"interval"`   // in seconds between scheduler runs
+	Lookahead int `yaml:"lookahead" mapstructure:"lookahead"` // in hours; occurrences starting within are created ahead of time
+}
+
+// SchedulerConfig controls the background jobs. Replicas sharing a database
+// take turns through leases, so every replica may enable the scheduler.
+type SchedulerConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+}
+
+// NotificationsConfig controls the due date reminders. Reminders always reach
+// the in-app inbox; email and webhooks are only offered when configured here.
+type NotificationsConfig struct {
+	Interval    int           `yaml:"interval" mapstructure:"interval"`         // in seconds between reminder runs
+	MaxAttempts int           `yaml:"max_attempts" mapstructure:"max_attempts"` // deliveries per channel before giving up
+	SMTP        SMTPConfig    `yaml:"smtp" mapstructure:"smtp"`
+	Webhook     WebhookConfig `yaml:"webhook" mapstructure:"webhook"`
+}
+
+// SMTPConfig configures the mail server sending reminder emails. Email is
+// disabled without a host. STARTTLS is used whenever the server offers it.
+type SMTPConfig struct {
+	Host     string `yaml:"host" mapstructure:"host"`
+	Port     int    `yaml:"port" mapstructure:"port"`
+	Username string `yaml:"username,omitempty" mapstructure:"username"` // optional
+	Password string `yaml:"password,omitempty" mapstructure:"password"` // optional
+	From     string `yaml:"from" mapstructure:"from"`                   // sender address
+}
+
+// WebhookConfig configures the webhooks users register for their reminders
+type WebhookConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	Timeout int  `yaml:"timeout" mapstructure:"timeout"` // in seconds per request
+	// Secret signs the payloads with HMAC-SHA256 in the X-Signature-256 header when set
+	Secret string `yaml:"secret,omitempty" mapstructure:"secret"`
+	// AllowPrivateNetworks lets webhooks reach loopback and private addresses,
+	// which are refused by default so that users cannot call internal services
+	AllowPrivateNetworks bool `yaml:"allow_private_networks" mapstructure:"allow_private_networks"`
+}
+
+// AttachmentsConfig selects where the files attached to tasks are stored and
+// how large they may get
+type AttachmentsConfig struct {
+	Store        string           `yaml:"store" mapstructure:"store"`                 // local, s3
+	MaxFileSize  int64            `yaml:"max_file_size" mapstructure:"max_file_size"` // in bytes
+	ProjectQuota int64            `yaml:"project_quota" mapstructure:"project_quota"` // in bytes per project, 0 for unlimited
+	Local        LocalStoreConfig `yaml:"local" mapstructure:"local"`
+	S3           S3StoreConfig    `yaml:"s3" mapstructure:"s3"`
+}
+
+type LocalStoreConfig struct {
+	Path string `yaml:"path" mapstructure:"path"` // directory holding the files
+}
+
+// S3StoreConfig configures an S3-compatible object store such as AWS S3 or MinIO
+type S3StoreConfig struct {
+	Endpoint     string `yaml:"endpoint" mapstructure:"endpoint"` // host:port, without scheme
+	Bucket       string `yaml:"bucket" mapstructure:"bucket"`
+	Region       string `yaml:"region,omitempty" mapstructure:"region"`
+	AccessKey    string `yaml:"access_key" mapstructure:"access_key"`
+	SecretKey    string `yaml:"secret_key,omitempty" mapstructure:"secret_key"`
+	UseSSL       bool   `yaml:"use_ssl" mapstructure:"use_ssl"`
+	CreateBucket bool   `yaml:"create_bucket" mapstructure:"create_bucket"` // create the bucket on startup if it is missing
+}
+
+func Load(configPath string, opts ...Option) (*Config, error) {
+	return LoadWithViper(configPath, opts...)
 }
 
 func (c *ServiceConfig) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// secretKeyPatterns identify keys whose values must not be displayed
+var secretKeyPatterns = []string{"password", "secret", "token"}
+
+// IsSecret reports whether the value of a config key is sensitive
+func IsSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range secretKeyPatterns {
+		if strings.Contains(key, pattern) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// fileEnvSuffix marks environment variables that hold the path of a file
+// containing the value, e.g. DATABASE_PASSWORD_FILE=/run/secrets/db_password
+const fileEnvSuffix = "_FILE"
+
+// envAliases are additional, shorter environment variable names accepted for
+// some keys. The canonical name is always the key path in upper case with
+// dots replaced by underscores, e.g. logging.level -> LOGGING_LEVEL.
+var envAliases = map[string][]string{
+	"database.database":         {"DATABASE_NAME"},
+	"logging.level":             {"LOG_LEVEL"},
+	"logging.format":            {"LOG_FORMAT"},
+	"logging.loki.url":          {"LOKI_URL"},
+	"logging.loki.bearer_token": {"LOKI_BEARER_TOKEN"},
+}
+
+// configKeys returns the key paths of all leaf fields of Config, derived from
+// the mapstructure tags. Maps and slices are leaves.
+func configKeys() []string {
+	return appendKeys(nil, "", reflect.TypeOf(Config{}))
+}
+
+func appendKeys(keys []string, prefix string, t reflect.Type) []string {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			keys = appendKeys(keys, key, fieldType)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// envNames returns the environment variable names of a key, canonical name first
+func envNames(prefix, key string) []string {
+	names := []string{prefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))}
+	for _, alias := range envAliases[key] {
+		names = append(names, prefix+alias)
+	}
+	return names
+}
+
+// bindEnv binds every config key to its environment variables. Viper's
+// AutomaticEnv only applies to keys it already knows about, so keys without a
+// default or a value in the config file would otherwise never be overridden.
+// Values of NAME_FILE variables are read from the referenced file. It returns
+// the name of the variable that set each overridden key.
+func bindEnv(v *viper.Viper, prefix string) (map[string]string, error) {
+	sources := make(map[string]string)
+	for _, key := range configKeys() {
+		names := envNames(prefix, key)
+		if err := v.BindEnv(append([]string{key}, names...)...); err != nil {
+			return nil, fmt.Errorf("failed to bind environment variables for %s: %w", key, err)
+		}
+
+		if name, ok := firstSetEnv(names); ok {
+			sources[key] = name
+			continue
+		}
+		for _, name := range names {
+			path, ok := os.LookupEnv(name + fileEnvSuffix)
+			if !ok {
+				continue
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s%s: %w", name, fileEnvSuffix, err)
+			}
+			v.Set(key, strings.TrimRight(string(content), "\r\n"))
+			sources[key] = name + fileEnvSuffix
+			break
+		}
+	}
+	return sources, nil
+}
+
+// firstSetEnv returns the first of names that is set in the environment
+func firstSetEnv(names []string) (string, bool) {
+	for _, name := range names {
+		if _, ok := os.LookupEnv(name); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// stringToCollectionHook decodes string values, as they come from environment
+// variables, into lists ("a, b, c") and maps ("key=value,key2=value2")
+func stringToCollectionHook() mapstructure.DecodeHookFunc {
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from.Kind() != reflect.String {
+			return data, nil
+		}
+		raw := data.(string)
+
+		switch {
+		case to.Kind() == reflect.Slice && to.Elem().Kind() == reflect.String:
+			return splitList(raw), nil
+		case to.Kind() == reflect.Map && to.Key().Kind() == reflect.String && to.Elem().Kind() == reflect.String:
+			result := make(map[string]string)
+			for _, pair := range splitList(raw) {
+				k, v, ok := strings.Cut(pair, "=")
+				if !ok {
+					return nil, fmt.Errorf("invalid map entry %q, expected key=value", pair)
+				}
+				result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			}
+			return result, nil
+		}
+		return data, nil
+	}
+}
+
+// splitList splits a comma-separated list, trimming spaces and dropping empty items
+func splitList(raw string) []string {
+	items := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
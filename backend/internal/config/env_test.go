@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testConfigYAML = `
+service:
+  port: 8080
+auth:
+  enabled: false
+cors:
+  allowed_origins:
+    - "http://localhost:4200"
+`
+
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfigYAML), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	t.Setenv("SERVICE_PORT", "9090")
+	t.Setenv("AUTH_ENABLED", "true")
+	t.Setenv("AUTH_ISSUER", "http://keycloak/realms/boilerplate")
+	t.Setenv("DATABASE_PASSWORD", "s3cret")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com,")
+	t.Setenv("LOGGING_COMPONENTS", "auth=debug, storage=warn")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("DATABASE_NAME", "tasks")
+
+	cfg, err := Load(writeTestConfig(t))
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Service.Port != 9090 {
+		t.Errorf("expected port 9090, got %d", cfg.Service.Port)
+	}
+	if !cfg.Auth.Enabled || cfg.Auth.Issuer != "http://keycloak/realms/boilerplate" {
+		t.Errorf("expected auth overrides, got %+v", cfg.Auth)
+	}
+	if cfg.Database.Password != "s3cret" || cfg.Database.Database != "tasks" {
+		t.Errorf("expected database overrides, got %+v", cfg.Database)
+	}
+	if expected := []string{"https://a.example.com", "https://b.example.com"}; !reflect.DeepEqual(cfg.CORS.AllowedOrigins, expected) {
+		t.Errorf("expected origins %v, got %v", expected, cfg.CORS.AllowedOrigins)
+	}
+	if expected := map[string]string{"auth": "debug", "storage": "warn"}; !reflect.DeepEqual(cfg.Logging.Components, expected) {
+		t.Errorf("expected components %v, got %v", expected, cfg.Logging.Components)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("expected log level from alias, got %q", cfg.Logging.Level)
+	}
+	if cfg.Logging.LokiConfig != nil {
+		t.Errorf("expected no Loki config without Loki variables, got %+v", cfg.Logging.LokiConfig)
+	}
+}
+
+func TestLoad_EnvPrefix(t *testing.T) {
+	t.Setenv("SERVICE_PORT", "9090")
+	t.Setenv("BOILERPLATE_SERVICE_PORT", "7070")
+	t.Setenv("BOILERPLATE_LOKI_URL", "http://loki:3100")
+
+	cfg, err := Load(writeTestConfig(t), WithEnvPrefix("boilerplate"))
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Service.Port != 7070 {
+		t.Errorf("expected prefixed variable to win, got port %d", cfg.Service.Port)
+	}
+	if cfg.Logging.LokiConfig == nil || cfg.Logging.LokiConfig.URL != "http://loki:3100" {
+		t.Errorf("expected Loki URL from prefixed alias, got %+v", cfg.Logging.LokiConfig)
+	}
+}
+
+func TestLoad_FileEnvSecrets(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secretPath, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret: %v", err)
+	}
+	t.Setenv("DATABASE_PASSWORD_FILE", secretPath)
+	t.Setenv("AUTH_CLIENT_SECRET", "from-env")
+	t.Setenv("AUTH_CLIENT_SECRET_FILE", secretPath)
+
+	cfg, err := Load(writeTestConfig(t))
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Database.Password != "from-file" {
+		t.Errorf("expected password from file without trailing newline, got %q", cfg.Database.Password)
+	}
+	if cfg.Auth.ClientSecret != "from-env" {
+		t.Errorf("expected plain variable to take precedence over _FILE, got %q", cfg.Auth.ClientSecret)
+	}
+}
+
+func TestLoad_FileEnvMissingFile(t *testing.T) {
+	t.Setenv("DATABASE_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	if _, err := Load(writeTestConfig(t)); err == nil {
+		t.Error("expected error for unreadable secret file")
+	}
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// reloadableKeys are the keys, or key prefixes, whose changes take effect
+// without a restart. Everything else is read once at startup.
+var reloadableKeys = []string{
+	"cors",
+	"rate_limit",
+	"logging.level",
+	"logging.components",
+	"docs.enabled",
+}
+
+// Reloadable reports whether a change of key takes effect without a restart
+func Reloadable(key string) bool {
+	for _, prefix := range reloadableKeys {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Live holds the configuration currently in effect. Components that support
+// hot reload read their settings through Get on every use instead of keeping
+// a copy, so a reload is a single atomic swap.
+type Live struct {
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex
+	subscribers []func(*Config)
+}
+
+// NewLive creates a live handle holding cfg
+func NewLive(cfg *Config) *Live {
+	l := &Live{}
+	l.current.Store(cfg)
+	return l
+}
+
+// Get returns the configuration in effect. It must not be modified.
+func (l *Live) Get() *Config {
+	return l.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration after every
+// reload that changed a reloadable setting
+func (l *Live) Subscribe(fn func(*Config)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscribers = append(l.subscribers, fn)
+}
+
+// Reload applies the reloadable settings of loaded, which must already be
+// validated. It returns the changed keys that were applied and those that
+// were ignored because they need a restart. The remaining settings of the
+// current configuration are kept, so Get always reflects what is in effect.
+func (l *Live) Reload(loaded *Config) (applied, ignored []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.current.Load()
+	for _, key := range ChangedKeys(current, loaded) {
+		if Reloadable(key) {
+			applied = append(applied, key)
+		} else {
+			ignored = append(ignored, key)
+		}
+	}
+	if len(applied) == 0 {
+		return applied, ignored
+	}
+
+	next := *current
+	next.CORS = loaded.CORS
+	next.RateLimit = loaded.RateLimit
+	next.Logging.Level = loaded.Logging.Level
+	next.Logging.Components = loaded.Logging.Components
+	next.Docs.Enabled = loaded.Docs.Enabled
+	l.current.Store(&next)
+
+	for _, fn := range l.subscribers {
+		fn(&next)
+	}
+	return applied, ignored
+}
+
+// ChangedKeys returns the key paths whose values differ between a and b, in
+// the order of the Config fields
+func ChangedKeys(a, b *Config) []string {
+	valuesA := configValues(a)
+	valuesB := configValues(b)
+
+	var changed []string
+	for _, key := range configKeys() {
+		if !reflect.DeepEqual(valuesA[key], valuesB[key]) {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// configValues maps the key path of every leaf field of cfg to its value.
+// Fields of nil pointers are reported as zero values.
+func configValues(cfg *Config) map[string]interface{} {
+	values := make(map[string]interface{})
+	appendValues(values, "", reflect.ValueOf(cfg).Elem())
+	return values
+}
+
+func appendValues(values map[string]interface{}, prefix string, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+
+		fieldValue := v.Field(i)
+		if fieldValue.Kind() == reflect.Pointer {
+			if fieldValue.IsNil() {
+				fieldValue = reflect.New(fieldValue.Type().Elem())
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Struct {
+			appendValues(values, key, fieldValue)
+			continue
+		}
+		values[key] = normalize(fieldValue)
+	}
+}
+
+// normalize makes empty and nil collections compare equal
+func normalize(v reflect.Value) interface{} {
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+		return nil
+	}
+	return v.Interface()
+}
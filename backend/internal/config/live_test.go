@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLive_ReloadAppliesOnlyReloadableSettings(t *testing.T) {
+	current := &Config{
+		Service:  ServiceConfig{Port: 8080},
+		Database: DatabaseConfig{URI: "mongodb://old:27017"},
+		Logging:  LoggingConfig{Level: "info", Format: "json"},
+		CORS:     CORSConfig{AllowedOrigins: []string{"https://old.example.com"}},
+	}
+	live := NewLive(current)
+
+	var notified *Config
+	live.Subscribe(func(cfg *Config) { notified = cfg })
+
+	loaded := &Config{
+		Service:  ServiceConfig{Port: 9090},
+		Database: DatabaseConfig{URI: "mongodb://new:27017"},
+		Logging:  LoggingConfig{Level: "debug", Format: "json"},
+		CORS:     CORSConfig{AllowedOrigins: []string{"https://new.example.com"}},
+	}
+	applied, ignored := live.Reload(loaded)
+
+	if want := []string{"logging.level", "cors.allowed_origins"}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("expected applied keys %v, got %v", want, applied)
+	}
+	if want := []string{"service.port", "database.uri"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("expected ignored keys %v, got %v", want, ignored)
+	}
+
+	got := live.Get()
+	if got.Service.Port != 8080 || got.Database.URI != "mongodb://old:27017" {
+		t.Errorf("expected non-reloadable settings to be kept, got port %d and uri %s", got.Service.Port, got.Database.URI)
+	}
+	if got.Logging.Level != "debug" || got.CORS.AllowedOrigins[0] != "https://new.example.com" {
+		t.Errorf("expected reloadable settings to be applied, got %+v", got)
+	}
+	if notified != got {
+		t.Error("expected subscribers to be notified with the new configuration")
+	}
+	if current.Logging.Level != "info" {
+		t.Error("expected the previous configuration to stay unchanged")
+	}
+}
+
+func TestLive_ReloadWithoutReloadableChanges(t *testing.T) {
+	current := &Config{Service: ServiceConfig{Port: 8080}}
+	live := NewLive(current)
+	live.Subscribe(func(*Config) { t.Error("expected no notification") })
+
+	applied, ignored := live.Reload(&Config{Service: ServiceConfig{Port: 9090}})
+
+	if len(applied) != 0 || len(ignored) != 1 {
+		t.Errorf("expected only service.port to be ignored, got applied %v ignored %v", applied, ignored)
+	}
+	if live.Get() != current {
+		t.Error("expected the configuration not to be swapped")
+	}
+}
+
+func TestChangedKeys_TreatsEmptyAndNilAlike(t *testing.T) {
+	a := &Config{Logging: LoggingConfig{Components: map[string]string{}}}
+	b := &Config{Logging: LoggingConfig{LokiConfig: &LokiConfig{}}}
+
+	if changed := ChangedKeys(a, b); len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+}
+
+func TestWatch_CallsOnChange(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "local.yaml", "service:\n  port: 8080\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan struct{}, 1)
+	if err := Watch(ctx, dir, func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("failed to watch: %v", err)
+	}
+
+	writeFile(t, dir, "notes.txt", "ignored")
+	writeFile(t, dir, "local.yaml", "service:\n  port: 9090\n")
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected onChange after the config file was written")
+	}
+}
@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// FieldError describes a problem with a single configuration value
+type FieldError struct {
+	Key     string // config key path, e.g. service.port
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// ValidationError aggregates all problems found in a configuration
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		lines[i] = "  - " + fe.Error()
+	}
+	return fmt.Sprintf("invalid configuration (%d problems):\n%s", len(e.Errors), strings.Join(lines, "\n"))
+}
+
+// validator collects field errors while the rules are checked
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) addf(key, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) nonNegative(key string, value int) {
+	if value < 0 {
+		v.addf(key, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validator) url(key, value string, schemes ...string) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || !slices.Contains(schemes, u.Scheme) {
+		v.addf(key, "must be a valid %s URL, got %q", strings.Join(schemes, " or "), value)
+	}
+}
+
+var (
+	logLevels    = []string{"debug", "info", "warn", "warning", "error"}
+	logFormats   = []string{"console", "json"}
+	tracingTypes = []string{"otlp", "stdout"}
+	otlpProtocol = []string{"http", "grpc"}
+	blobStores   = []string{"local", "s3"}
+)
+
+// Validate checks the configuration, including rules that span several
+// fields, and reports every problem at once as a *ValidationError
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	c.validateService(v)
+	c.validateDatabase(v)
+	c.validateAuth(v)
+	c.validateLogging(v)
+	c.validateCORS(v)
+	c.validateRateLimit(v)
+	c.validateMetrics(v)
+	c.validateTracing(v)
+	c.validateTasks(v)
+	c.validateAttachments(v)
+	c.validateNotifications(v)
+
+	if len(v.errors) > 0 {
+		return &ValidationError{Errors: v.errors}
+	}
+	return nil
+}
+
+func (c *Config) validateService(v *validator) {
+	if c.Service.Port < 1 || c.Service.Port > 65535 {
+		v.addf("service.port", "must be between 1 and 65535, got %d", c.Service.Port)
+	}
+	v.nonNegative("service.read_timeout", c.Service.ReadTimeout)
+	v.nonNegative("service.write_timeout", c.Service.WriteTimeout)
+	v.nonNegative("service.shutdown_delay", c.Service.ShutdownDelay)
+}
+
+func (c *Config) validateDatabase(v *validator) {
+	if c.Database.URI == "" {
+		v.addf("database.uri", "is required")
+	} else if !strings.HasPrefix(c.Database.URI, "mongodb://") && !strings.HasPrefix(c.Database.URI, "mongodb+srv://") {
+		v.addf("database.uri", "must start with mongodb:// or mongodb+srv://")
+	}
+	if c.Database.Database == "" {
+		v.addf("database.database", "is required")
+	}
+	if c.Database.Timeout <= 0 {
+		v.addf("database.timeout", "must be positive, got %d", c.Database.Timeout)
+	}
+	if (c.Database.Username == "") != (c.Database.Password == "") {
+		v.addf("database.username", "username and password must be set together")
+	}
+}
+
+func (c *Config) validateAuth(v *validator) {
+	if !c.Auth.Enabled {
+		return
+	}
+	if c.Auth.Issuer == "" {
+		v.addf("auth.issuer", "is required when auth is enabled")
+	}
+	if c.Auth.JWKSURL == "" {
+		v.addf("auth.jwks_url", "is required when auth is enabled")
+	} else {
+		v.url("auth.jwks_url", c.Auth.JWKSURL, "http", "https")
+	}
+	if c.Auth.AdminRole == "" {
+		v.addf("auth.admin_role", "is required when auth is enabled")
+	}
+}
+
+func (c *Config) validateLogging(v *validator) {
+	if !slices.Contains(logLevels, strings.ToLower(c.Logging.Level)) {
+		v.addf("logging.level", "must be one of debug, info, warn, error, got %q", c.Logging.Level)
+	}
+	if !slices.Contains(logFormats, c.Logging.Format) {
+		v.addf("logging.format", "must be one of console, json, got %q", c.Logging.Format)
+	}
+	for component, level := range c.Logging.Components {
+		if !slices.Contains(logLevels, strings.ToLower(level)) {
+			v.addf("logging.components."+component, "must be one of debug, info, warn, error, got %q", level)
+		}
+	}
+
+	loki := c.Logging.LokiConfig
+	if loki == nil || loki.URL == "" {
+		return
+	}
+	v.url("logging.loki.url", loki.URL, "http", "https")
+	v.nonNegative("logging.loki.batch_size", loki.BatchSize)
+	v.nonNegative("logging.loki.batch_interval", loki.BatchInterval)
+	v.nonNegative("logging.loki.queue_size", loki.QueueSize)
+	v.nonNegative("logging.loki.max_retries", loki.MaxRetries)
+	v.nonNegative("logging.loki.timeout", loki.Timeout)
+}
+
+func (c *Config) validateCORS(v *validator) {
+	if c.CORS.AllowCredentials && slices.Contains(c.CORS.AllowedOrigins, "*") {
+		v.addf("cors.allowed_origins", "must list explicit origins when cors.allow_credentials is enabled, browsers reject \"*\" with credentials")
+	}
+	v.nonNegative("cors.max_age", c.CORS.MaxAge)
+}
+
+func (c *Config) validateRateLimit(v *validator) {
+	v.nonNegative("rate_limit.requests_per_second", c.RateLimit.RequestsPerSecond)
+	v.nonNegative("rate_limit.burst", c.RateLimit.Burst)
+	if !c.RateLimit.Enabled {
+		return
+	}
+	if c.RateLimit.RequestsPerSecond == 0 {
+		v.addf("rate_limit.requests_per_second", "must be positive when rate limiting is enabled")
+	}
+	if c.RateLimit.Burst == 0 {
+		v.addf("rate_limit.burst", "must be positive when rate limiting is enabled")
+	}
+}
+
+func (c *Config) validateMetrics(v *validator) {
+	if !c.Metrics.Enabled {
+		return
+	}
+	path := c.Metrics.Path
+	switch {
+	case !strings.HasPrefix(path, "/"):
+		v.addf("metrics.path", "must start with /, got %q", path)
+	case path == "/health" || path == "/ready" || strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/docs") || strings.HasPrefix(path, "/swagger/"):
+		v.addf("metrics.path", "conflicts with a built-in route, got %q", path)
+	}
+}
+
+func (c *Config) validateTracing(v *validator) {
+	if !c.Tracing.Enabled {
+		return
+	}
+	if !slices.Contains(tracingTypes, c.Tracing.Exporter) {
+		v.addf("tracing.exporter", "must be one of otlp, stdout, got %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.Exporter == "otlp" {
+		if !slices.Contains(otlpProtocol, c.Tracing.Protocol) {
+			v.addf("tracing.protocol", "must be one of http, grpc, got %q", c.Tracing.Protocol)
+		}
+		if c.Tracing.Endpoint == "" {
+			v.addf("tracing.endpoint", "is required for the otlp exporter")
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+}
+
+func (c *Config) validateTasks(v *validator) {
+	if c.Tasks.MaxDepth < 1 {
+		v.addf("tasks.max_depth", "must be at least 1, got %d", c.Tasks.MaxDepth)
+	}
+	if c.Tasks.Recurrence.Interval < 1 {
+		v.addf("tasks.recurrence.interval", "must be at least 1, got %d", c.Tasks.Recurrence.Interval)
+	}
+	v.nonNegative("tasks.recurrence.lookahead", c.Tasks.Recurrence.Lookahead)
+}
+
+func (c *Config) validateAttachments(v *validator) {
+	a := c.Attachments
+	if a.MaxFileSize <= 0 {
+		v.addf("attachments.max_file_size", "must be positive, got %d", a.MaxFileSize)
+	}
+	if a.ProjectQuota < 0 {
+		v.addf("attachments.project_quota", "must not be negative, got %d", a.ProjectQuota)
+	}
+
+	switch a.Store {
+	case "local":
+		if a.Local.Path == "" {
+			v.addf("attachments.local.path", "is required for the local store")
+		}
+	case "s3":
+		if a.S3.Endpoint == "" {
+			v.addf("attachments.s3.endpoint", "is required for the s3 store")
+		} else if strings.Contains(a.S3.Endpoint, "://") {
+			v.addf("attachments.s3.endpoint", "must be host:port without a scheme, got %q", a.S3.Endpoint)
+		}
+		if a.S3.Bucket == "" {
+			v.addf("attachments.s3.bucket", "is required for the s3 store")
+		}
+		if (a.S3.AccessKey == "") != (a.S3.SecretKey == "") {
+			v.addf("attachments.s3.access_key", "access key and secret key must be set together")
+		}
+	default:
+		v.addf("attachments.store", "must be one of %s, got %q", strings.Join(blobStores, ", "), a.Store)
+	}
+}
+
+func (c *Config) validateNotifications(v *validator) {
+	n := c.Notifications
+	if n.Interval < 1 {
+		v.addf("notifications.interval", "must be at least 1, got %d", n.Interval)
+	}
+	if n.MaxAttempts < 1 {
+		v.addf("notifications.max_attempts", "must be at least 1, got %d", n.MaxAttempts)
+	}
+	if n.SMTP.Host != "" {
+		if n.SMTP.Port < 1 || n.SMTP.Port > 65535 {
+			v.addf("notifications.smtp.port", "must be between 1 and 65535, got %d", n.SMTP.Port)
+		}
+		if _, err := mail.ParseAddress(n.SMTP.From); err != nil {
+			v.addf("notifications.smtp.from", "must be an email address, got %q", n.SMTP.From)
+		}
+	}
+	if n.Webhook.Enabled && n.Webhook.Timeout < 1 {
+		v.addf("notifications.webhook.timeout", "must be at least 1, got %d", n.Webhook.Timeout)
+	}
+}
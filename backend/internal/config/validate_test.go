@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Service:   ServiceConfig{Host: "localhost", Port: 8080, ReadTimeout: 10, WriteTimeout: 10},
+		Database:  DatabaseConfig{URI: "mongodb://localhost:27017", Database: "boilerplate", Timeout: 10},
+		Auth:      AuthConfig{AdminRole: "admin"},
+		Logging:   LoggingConfig{Level: "info", Format: "console"},
+		CORS:      CORSConfig{AllowedOrigins: []string{"*"}},
+		RateLimit: RateLimitConfig{Enabled: true, RequestsPerSecond: 10, Burst: 20},
+		Metrics:   MetricsConfig{Path: "/metrics"},
+		Tracing:   TracingConfig{Exporter: "otlp", Protocol: "http", Endpoint: "localhost:4318", SampleRatio: 1},
+		Tasks:     TasksConfig{MaxDepth: 5, Recurrence: RecurrenceConfig{Interval: 300, Lookahead: 168}},
+		Attachments: AttachmentsConfig{Store: "local", MaxFileSize: 25 << 20, ProjectQuota: 1 << 30,
+			Local: LocalStoreConfig{Path: "data/attachments"}},
+		Notifications: NotificationsConfig{Interval: 300, MaxAttempts: 5,
+			Webhook: WebhookConfig{Enabled: true, Timeout: 10}},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(c *Config)
+		expectedKeys []string
+	}{
+		{
+			name:   "valid config",
+			modify: func(c *Config) {},
+		},
+		{
+			name:         "port 0",
+			modify:       func(c *Config) { c.Service.Port = 0 },
+			expectedKeys: []string{"service.port"},
+		},
+		{
+			name:         "auth enabled without issuer and JWKS URL",
+			modify:       func(c *Config) { c.Auth.Enabled = true },
+			expectedKeys: []string{"auth.issuer", "auth.jwks_url"},
+		},
+		{
+			name: "auth enabled with malformed JWKS URL",
+			modify: func(c *Config) {
+				c.Auth = AuthConfig{Enabled: true, Issuer: "http://localhost:8081/realms/boilerplate", JWKSURL: "localhost/certs", AdminRole: "admin"}
+			},
+			expectedKeys: []string{"auth.jwks_url"},
+		},
+		{
+			name:         "negative rate limit",
+			modify:       func(c *Config) { c.RateLimit.RequestsPerSecond = -1 },
+			expectedKeys: []string{"rate_limit.requests_per_second"},
+		},
+		{
+			name:         "rate limiting enabled with zero burst",
+			modify:       func(c *Config) { c.RateLimit.Burst = 0 },
+			expectedKeys: []string{"rate_limit.burst"},
+		},
+		{
+			name:         "subtasks disabled",
+			modify:       func(c *Config) { c.Tasks.MaxDepth = 0 },
+			expectedKeys: []string{"tasks.max_depth"},
+		},
+		{
+			name:         "recurring tasks never materialized",
+			modify:       func(c *Config) { c.Tasks.Recurrence = RecurrenceConfig{Interval: 0, Lookahead: -1} },
+			expectedKeys: []string{"tasks.recurrence.interval", "tasks.recurrence.lookahead"},
+		},
+		{
+			name: "unknown logging format and levels",
+			modify: func(c *Config) {
+				c.Logging = LoggingConfig{Level: "verbose", Format: "xml", Components: map[string]string{"auth": "trace"}}
+			},
+			expectedKeys: []string{"logging.level", "logging.format", "logging.components.auth"},
+		},
+		{
+			name:         "CORS wildcard with credentials",
+			modify:       func(c *Config) { c.CORS.AllowCredentials = true },
+			expectedKeys: []string{"cors.allowed_origins"},
+		},
+		{
+			name: "explicit CORS origins with credentials",
+			modify: func(c *Config) {
+				c.CORS = CORSConfig{AllowedOrigins: []string{"http://localhost:4200"}, AllowCredentials: true}
+			},
+		},
+		{
+			name:         "database without URI and with partial credentials",
+			modify:       func(c *Config) { c.Database.URI = ""; c.Database.Username = "admin" },
+			expectedKeys: []string{"database.uri", "database.username"},
+		},
+		{
+			name:         "metrics path conflicting with the API",
+			modify:       func(c *Config) { c.Metrics = MetricsConfig{Enabled: true, Path: "/api/metrics"} },
+			expectedKeys: []string{"metrics.path"},
+		},
+		{
+			name: "tracing with invalid exporter settings",
+			modify: func(c *Config) {
+				c.Tracing = TracingConfig{Enabled: true, Exporter: "otlp", Protocol: "udp", SampleRatio: 2}
+			},
+			expectedKeys: []string{"tracing.protocol", "tracing.endpoint", "tracing.sample_ratio"},
+		},
+		{
+			name: "s3 attachment store without bucket",
+			modify: func(c *Config) {
+				c.Attachments.Store = "s3"
+				c.Attachments.MaxFileSize = 0
+				c.Attachments.S3 = S3StoreConfig{Endpoint: "http://localhost:9000", AccessKey: "minio"}
+			},
+			expectedKeys: []string{"attachments.max_file_size", "attachments.s3.endpoint", "attachments.s3.bucket", "attachments.s3.access_key"},
+		},
+		{
+			name:         "unknown attachment store",
+			modify:       func(c *Config) { c.Attachments.Store = "ftp"; c.Attachments.ProjectQuota = -1 },
+			expectedKeys: []string{"attachments.project_quota", "attachments.store"},
+		},
+		{
+			name: "invalid notification channels",
+			modify: func(c *Config) {
+				c.Notifications.MaxAttempts = 0
+				c.Notifications.SMTP = SMTPConfig{Host: "mail.example.com", Port: 0, From: "reminders"}
+				c.Notifications.Webhook.Timeout = 0
+			},
+			expectedKeys: []string{"notifications.max_attempts", "notifications.smtp.port", "notifications.smtp.from", "notifications.webhook.timeout"},
+		},
+		{
+			name:         "invalid Loki URL",
+			modify:       func(c *Config) { c.Logging.LokiConfig = &LokiConfig{URL: "loki:3100", QueueSize: -1} },
+			expectedKeys: []string{"logging.loki.url", "logging.loki.queue_size"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.expectedKeys) == 0 {
+				if err != nil {
+					t.Fatalf("expected valid config, got %v", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected *ValidationError, got %v", err)
+			}
+
+			keys := map[string]bool{}
+			for _, fe := range validationErr.Errors {
+				keys[fe.Key] = true
+			}
+			for _, key := range tt.expectedKeys {
+				if !keys[key] {
+					t.Errorf("expected error for %s, got %v", key, err)
+				}
+			}
+			if len(validationErr.Errors) != len(tt.expectedKeys) {
+				t.Errorf("expected %d errors, got %d: %v", len(tt.expectedKeys), len(validationErr.Errors), err)
+			}
+		})
+	}
+}
+
+func TestValidationError_ListsAllProblems(t *testing.T) {
+	cfg := validConfig()
+	cfg.Service.Port = 0
+	cfg.Logging.Format = "xml"
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, expected := range []string{"2 problems", "service.port: must be between 1 and 65535", "logging.format: must be one of console, json"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in %q", expected, err.Error())
+		}
+	}
+}
+
+func TestLoad_LocalConfigIsValid(t *testing.T) {
+	cfg, err := Load("../../../config/local.yaml")
+	if err != nil {
+		t.Fatalf("failed to load local config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected local config to be valid, got %v", err)
+	}
+}
@@ -2,46 +2,250 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/viper"
 )
 
-func LoadWithViper(configPath string) (*Config, error) {
+// Option customizes how the configuration is loaded
+type Option func(*loadOptions)
+
+type loadOptions struct {
+	envPrefix string
+	profile   string
+	overrides map[string]string
+}
+
+// WithEnvPrefix makes environment overrides use the given prefix, e.g. with
+// "BOILERPLATE" the port is read from BOILERPLATE_SERVICE_PORT
+func WithEnvPrefix(prefix string) Option {
+	return func(o *loadOptions) {
+		prefix = strings.ToUpper(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, "_") {
+			prefix += "_"
+		}
+		o.envPrefix = prefix
+	}
+}
+
+// WithProfile merges <profile>.yaml from the directory of the base file over it
+func WithProfile(profile string) Option {
+	return func(o *loadOptions) {
+		o.profile = profile
+	}
+}
+
+// WithOverrides sets values by key path, e.g. from command-line flags.
+// They take precedence over every other source.
+func WithOverrides(overrides map[string]string) Option {
+	return func(o *loadOptions) {
+		o.overrides = overrides
+	}
+}
+
+// OverrideFile is merged over the base and profile files when it exists next to
+// the base file. It holds machine-specific settings and is not committed.
+const OverrideFile = "override.yaml"
+
+// Sources of configuration values, from lowest to highest precedence
+const (
+	SourceDefault  = "default"
+	SourceBase     = "base"
+	SourceProfile  = "profile"
+	SourceOverride = "override"
+	SourceEnv      = "env"
+	SourceFlag     = "flag"
+	SourceUnset    = "unset"
+)
+
+// Setting is the effective value of a configuration key and where it came from
+type Setting struct {
+	Key    string
+	Value  interface{}
+	Source string // one of the Source constants, with the file or variable name where applicable
+}
+
+// defaultSearchPaths are checked for local.yaml when no config path is given
+var defaultSearchPaths = []string{
+	"./config",     // From project root
+	"../config",    // From backend/
+	"../../config", // From backend/internal/
+	".",            // Current directory
+}
+
+// fileLayer is a configuration file merged over the layers below it
+type fileLayer struct {
+	source string
+	path   string
+	values *viper.Viper
+}
+
+// LoadWithViper loads the configuration in layers, each overriding the previous:
+// defaults, the base file, the profile file, the override file, environment
+// variables and explicit overrides.
+func LoadWithViper(configPath string, opts ...Option) (*Config, error) {
+	cfg, _, err := load(configPath, opts...)
+	return cfg, err
+}
+
+// Describe loads the configuration like Load and returns the effective value
+// and source of every key, sorted by key
+func Describe(configPath string, opts ...Option) ([]Setting, error) {
+	_, settings, err := load(configPath, opts...)
+	return settings, err
+}
+
+func load(configPath string, opts ...Option) (*Config, []Setting, error) {
+	options := &loadOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	v := viper.New()
 	setDefaults(v)
-	v.SetConfigType("yaml")
-
-	if configPath != "" {
-		v.SetConfigFile(configPath)
-	} else {
-		v.SetConfigName("local")
-		v.AddConfigPath("./config")     // From project root
-		v.AddConfigPath("../config")    // From backend/
-		v.AddConfigPath("../../config") // From backend/internal/
-		v.AddConfigPath(".")            // Current directory
-	}
-
-	// Enable environment variable support
-	v.SetEnvPrefix("")
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	v.AutomaticEnv()
-
-	// Read config file (ignore if not found, we'll use defaults + env vars)
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+
+	layers, err := readLayers(configPath, options.profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, layer := range layers {
+		if err := v.MergeConfigMap(layer.values.AllSettings()); err != nil {
+			return nil, nil, fmt.Errorf("failed to merge %s: %w", layer.path, err)
 		}
-		// Config file not found; use defaults and env vars
 	}
 
-	// Unmarshal into Config struct
+	// Environment variables override the config files for every key
+	envSources, err := bindEnv(v, options.envPrefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := configKeys()
+	for key, value := range options.overrides {
+		if !knownKey(keys, key) {
+			return nil, nil, fmt.Errorf("unknown configuration key %q", key)
+		}
+		v.Set(key, value)
+	}
+
+	// Unmarshal into Config struct; lists and maps may come from environment variables as strings
 	cfg := &Config{}
-	if err := v.Unmarshal(cfg); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		stringToCollectionHook(),
+	))
+	if err := v.Unmarshal(cfg, decodeHook); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	defaults := viper.New()
+	setDefaults(defaults)
+
+	settings := make([]Setting, 0, len(keys))
+	for _, key := range keys {
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  v.Get(key),
+			Source: settingSource(key, options.overrides, envSources, layers, defaults),
+		})
 	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
 
-	return cfg, nil
+	return cfg, settings, nil
+}
+
+// readLayers reads the base, profile and override files. An explicitly given
+// base file and a selected profile must exist; otherwise missing files are skipped.
+func readLayers(configPath, profile string) ([]fileLayer, error) {
+	dir := "./config"
+	var layers []fileLayer
+
+	basePath := configPath
+	if basePath == "" {
+		basePath = findBaseFile()
+	}
+	if basePath != "" {
+		values, err := readFile(basePath)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, fileLayer{source: SourceBase, path: basePath, values: values})
+		dir = filepath.Dir(basePath)
+	}
+
+	if profile != "" {
+		profilePath := filepath.Join(dir, profile+".yaml")
+		values, err := readFile(profilePath)
+		if err != nil {
+			return nil, fmt.Errorf("profile %q: %w", profile, err)
+		}
+		layers = append(layers, fileLayer{source: SourceProfile, path: profilePath, values: values})
+	}
+
+	overridePath := filepath.Join(dir, OverrideFile)
+	if _, err := os.Stat(overridePath); err == nil {
+		values, err := readFile(overridePath)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, fileLayer{source: SourceOverride, path: overridePath, values: values})
+	}
+
+	return layers, nil
+}
+
+func findBaseFile() string {
+	for _, dir := range defaultSearchPaths {
+		path := filepath.Join(dir, "local.yaml")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+func readFile(path string) (*viper.Viper, error) {
+	values := viper.New()
+	values.SetConfigFile(path)
+	values.SetConfigType("yaml")
+	if err := values.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return values, nil
+}
+
+// knownKey reports whether key is a config key or an entry of a map-valued key
+func knownKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if key == k || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func settingSource(key string, overrides, envSources map[string]string, layers []fileLayer, defaults *viper.Viper) string {
+	for override := range overrides {
+		if override == key || strings.HasPrefix(override, key+".") {
+			return SourceFlag
+		}
+	}
+	if name, ok := envSources[key]; ok {
+		return SourceEnv + " (" + name + ")"
+	}
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].values.IsSet(key) {
+			return layers[i].source + " (" + layers[i].path + ")"
+		}
+	}
+	if defaults.IsSet(key) {
+		return SourceDefault
+	}
+	return SourceUnset
 }
 
 func setDefaults(v *viper.Viper) {
@@ -50,21 +254,63 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("service.port", 8080)
 	v.SetDefault("service.read_timeout", 10)
 	v.SetDefault("service.write_timeout", 10)
+	v.SetDefault("service.shutdown_delay", 0)
+	v.SetDefault("service.watch_config", true)
 
 	// Database defaults
 	v.SetDefault("database.uri", "mongodb://localhost:27017")
 	v.SetDefault("database.database", "boilerplate")
 	v.SetDefault("database.timeout", 10)
+	v.SetDefault("database.migrate_on_startup", false)
 
 	// Auth defaults
 	v.SetDefault("auth.enabled", false)
+	v.SetDefault("auth.admin_role", "admin")
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "console")
+	v.SetDefault("logging.redaction.enabled", true)
+	v.SetDefault("logging.redaction.keys", []string{"password", "token", "authorization", "secret"})
+	v.SetDefault("logging.redaction.mask_emails", true)
 
 	// Rate limit defaults
 	v.SetDefault("rate_limit.enabled", true)
 	v.SetDefault("rate_limit.requests_per_second", 10)
 	v.SetDefault("rate_limit.burst", 20)
+
+	// Metrics defaults
+	v.SetDefault("metrics.enabled", false)
+	v.SetDefault("metrics.path", "/metrics")
+
+	// Tracing defaults
+	v.SetDefault("tracing.enabled", false)
+	v.SetDefault("tracing.service_name", "boilerplate")
+	v.SetDefault("tracing.exporter", "otlp")
+	v.SetDefault("tracing.protocol", "http")
+	v.SetDefault("tracing.endpoint", "localhost:4318")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	// Task defaults
+	v.SetDefault("tasks.max_depth", 5)
+	v.SetDefault("tasks.recurrence.interval", 300)
+	v.SetDefault("tasks.recurrence.lookahead", 168)
+
+	// Attachment defaults
+	v.SetDefault("attachments.store", "local")
+	v.SetDefault("attachments.max_file_size", 25<<20)
+	v.SetDefault("attachments.project_quota", 1<<30)
+	v.SetDefault("attachments.local.path", "data/attachments")
+	v.SetDefault("attachments.s3.use_ssl", true)
+
+	// Scheduler defaults
+	v.SetDefault("scheduler.enabled", true)
+
+	// Notification defaults
+	v.SetDefault("notifications.interval", 300)
+	v.SetDefault("notifications.max_attempts", 5)
+	v.SetDefault("notifications.smtp.port", 587)
+	v.SetDefault("notifications.webhook.enabled", true)
+	v.SetDefault("notifications.webhook.timeout", 10)
+	v.SetDefault("notifications.webhook.allow_private_networks", false)
 }
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad_Layers(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, "base.yaml", `
+service:
+  port: 8080
+  read_timeout: 5
+logging:
+  level: info
+  format: console
+`)
+	writeFile(t, dir, "prod.yaml", `
+service:
+  port: 80
+logging:
+  format: json
+`)
+	writeFile(t, dir, OverrideFile, `
+logging:
+  level: debug
+`)
+	t.Setenv("LOGGING_FORMAT", "console")
+
+	cfg, err := Load(base,
+		WithProfile("prod"),
+		WithOverrides(map[string]string{"service.port": "9090"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Service.ReadTimeout != 5 {
+		t.Errorf("expected read timeout from base file, got %d", cfg.Service.ReadTimeout)
+	}
+	if cfg.Logging.Level != "debug" {
+		t.Errorf("expected level from override file, got %q", cfg.Logging.Level)
+	}
+	if cfg.Logging.Format != "console" {
+		t.Errorf("expected environment to override the profile, got %q", cfg.Logging.Format)
+	}
+	if cfg.Service.Port != 9090 {
+		t.Errorf("expected explicit override to win, got %d", cfg.Service.Port)
+	}
+	if cfg.Service.WriteTimeout != 10 {
+		t.Errorf("expected default write timeout, got %d", cfg.Service.WriteTimeout)
+	}
+}
+
+func TestLoad_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, "base.yaml", "service:\n  port: 8080\n")
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected error for missing explicit config file")
+	}
+	if _, err := Load(base, WithProfile("staging")); err == nil {
+		t.Error("expected error for missing profile file")
+	}
+	if _, err := Load(base, WithOverrides(map[string]string{"service.prot": "1"})); err == nil {
+		t.Error("expected error for unknown override key")
+	}
+}
+
+func TestDescribe_ReportsSources(t *testing.T) {
+	dir := t.TempDir()
+	base := writeFile(t, dir, "base.yaml", "service:\n  port: 8080\ndatabase:\n  password: secret\n")
+	profile := writeFile(t, dir, "dev.yaml", "service:\n  host: 0.0.0.0\n")
+	t.Setenv("AUTH_ISSUER", "http://keycloak")
+
+	settings, err := Describe(base,
+		WithProfile("dev"),
+		WithOverrides(map[string]string{"logging.loki.labels.env": "dev"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to describe config: %v", err)
+	}
+
+	sources := map[string]string{}
+	for _, setting := range settings {
+		sources[setting.Key] = setting.Source
+	}
+
+	expected := map[string]string{
+		"service.port":         SourceBase + " (" + base + ")",
+		"service.host":         SourceProfile + " (" + profile + ")",
+		"auth.issuer":          SourceEnv + " (AUTH_ISSUER)",
+		"logging.loki.labels":  SourceFlag,
+		"service.read_timeout": SourceDefault,
+		"auth.client_id":       SourceUnset,
+	}
+	for key, source := range expected {
+		if sources[key] != source {
+			t.Errorf("expected source %q for %s, got %q", source, key, sources[key])
+		}
+	}
+}
+
+func TestIsSecret(t *testing.T) {
+	for _, key := range []string{"database.password", "auth.client_secret", "metrics.bearer_token", "logging.loki.bearer_token"} {
+		if !IsSecret(key) {
+			t.Errorf("expected %s to be secret", key)
+		}
+	}
+	for _, key := range []string{"database.username", "auth.client_id", "service.port"} {
+		if IsSecret(key) {
+			t.Errorf("expected %s not to be secret", key)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce collapses the burst of events editors and Kubernetes ConfigMap
+// updates produce for a single change
+const watchDebounce = 500 * time.Millisecond
+
+// Dir returns the directory holding the base, profile and override files for
+// the given config path, i.e. the directory to watch for changes
+func Dir(configPath string) string {
+	if configPath == "" {
+		configPath = findBaseFile()
+	}
+	if configPath == "" {
+		return "./config"
+	}
+	return filepath.Dir(configPath)
+}
+
+// Watch calls onChange whenever a YAML file in dir is written, created,
+// replaced or removed, until ctx is cancelled. The directory is watched
+// rather than single files so that atomic replacements by editors and
+// ConfigMap symlink swaps are noticed as well.
+func Watch(ctx context.Context, dir string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	if err := watcher.Add(dir); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if relevant(event) {
+					debounce = time.After(watchDebounce)
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			case <-debounce:
+				debounce = nil
+				onChange()
+			}
+		}
+	}()
+	return nil
+}
+
+// relevant reports whether an event may have changed a config file. Kubernetes
+// mounts ConfigMaps through a ..data symlink that is swapped on update.
+func relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Base(event.Name)
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") || name == "..data"
+}
@@ -0,0 +1,349 @@
+// Package dataset exports and imports projects with their tasks as a portable
+// JSON document, and provides the sample data used to seed a database.
+package dataset
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FormatVersion is the version of the document layout written by Export.
+// Version 2 added time entries, dependency links and comments; Import still
+// reads version 1 documents.
+const FormatVersion = 2
+
+// Dataset is a snapshot of projects and their tasks
+type Dataset struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Projects   []Project `json:"projects"`
+	// Dependencies are the "blocks" links between the tasks, which may
+	// belong to different projects
+	Dependencies []entities.TaskDependency `json:"dependencies,omitempty"`
+}
+
+// Project is a project together with its members, labels, custom fields, tasks
+// and the time entries and comments of its tasks
+type Project struct {
+	entities.Project
+	Members      []entities.ProjectMember `json:"members,omitempty"`
+	Labels       []entities.Label         `json:"labels,omitempty"`
+	CustomFields []entities.CustomField   `json:"customFields,omitempty"`
+	Tasks        []entities.Task          `json:"tasks"`
+	TimeEntries  []entities.TimeEntry     `json:"timeEntries,omitempty"`
+	// Comments are the top-level comments of the tasks with their replies and edit history
+	Comments []entities.CommentThread `json:"comments,omitempty"`
+}
+
+// commentPageSize is the number of comment threads Export reads at once
+const commentPageSize = 100
+
+// Summary counts the records written by Import
+type Summary struct {
+	Projects int
+	Tasks    int
+}
+
+// Export reads all projects and their tasks. Running timers are left out, only
+// stopped time entries are exported.
+func Export(ctx context.Context, svc *service.Service) (Dataset, error) {
+	projects, err := svc.Project.FindAll(ctx)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("failed to read projects: %w", err)
+	}
+
+	data := Dataset{
+		Version:    FormatVersion,
+		ExportedAt: time.Now().UTC(),
+		Projects:   make([]Project, 0, len(projects)),
+	}
+	for _, project := range projects {
+		members, err := svc.Member.FindByProjectID(ctx, project.ID)
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to read members of project %s: %w", project.ID, err)
+		}
+		labels, err := svc.Label.FindByProjectID(ctx, project.ID)
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to read labels of project %s: %w", project.ID, err)
+		}
+		fields, err := svc.CustomField.FindByProjectID(ctx, project.ID)
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to read custom fields of project %s: %w", project.ID, err)
+		}
+		tasks, err := svc.Task.FindByProjectID(ctx, project.ID, entities.TaskFilter{})
+		if err != nil {
+			return Dataset{}, fmt.Errorf("failed to read tasks of project %s: %w", project.ID, err)
+		}
+		if tasks == nil {
+			tasks = []entities.Task{}
+		}
+		var entries []entities.TimeEntry
+		var comments []entities.CommentThread
+		for _, task := range tasks {
+			taskEntries, err := svc.TimeEntry.FindByTaskID(ctx, task.ID)
+			if err != nil {
+				return Dataset{}, fmt.Errorf("failed to read time entries of task %s: %w", task.ID, err)
+			}
+			for _, entry := range taskEntries {
+				if !entry.Running() {
+					entries = append(entries, entry)
+				}
+			}
+			// Every link is exported once, with the tasks it blocks
+			dependencies, err := svc.Dependency.FindByTaskID(ctx, task.ID)
+			if err != nil {
+				return Dataset{}, fmt.Errorf("failed to read dependencies of task %s: %w", task.ID, err)
+			}
+			data.Dependencies = append(data.Dependencies, dependencies.Blocks...)
+			threads, err := taskComments(ctx, svc, task.ID)
+			if err != nil {
+				return Dataset{}, fmt.Errorf("failed to read comments of task %s: %w", task.ID, err)
+			}
+			comments = append(comments, threads...)
+		}
+		data.Projects = append(data.Projects, Project{Project: project, Members: members, Labels: labels, CustomFields: fields, Tasks: tasks, TimeEntries: entries, Comments: comments})
+	}
+	return data, nil
+}
+
+// taskComments reads all comment threads of a task
+func taskComments(ctx context.Context, svc *service.Service, taskID string) ([]entities.CommentThread, error) {
+	var comments []entities.CommentThread
+	for {
+		page, total, err := svc.Comment.FindByTaskID(ctx, taskID, commentPageSize, len(comments))
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, page...)
+		if len(page) == 0 || int64(len(comments)) >= total {
+			return comments, nil
+		}
+	}
+}
+
+// Import creates the projects, members, labels, custom fields, tasks, time
+// entries, comments and dependency links of data as new records. IDs and
+// timestamps in data are not preserved; tasks are attached to the newly created
+// project they are listed under and their parent, label and custom field
+// references are rewritten to the new IDs, as are the tasks of time entries,
+// comments and dependency links and the threads of replies. The edit history
+// of a comment is replayed as edits by its author. Links are added once all
+// tasks exist; entries, comments and links of tasks missing from data are
+// skipped.
+func Import(ctx context.Context, svc *service.Service, data Dataset) (Summary, error) {
+	var summary Summary
+	if data.Version < 1 || data.Version > FormatVersion {
+		return summary, fmt.Errorf("unsupported dataset version %d, expected 1 to %d", data.Version, FormatVersion)
+	}
+
+	taskIDs := make(map[string]string)
+	for _, p := range data.Projects {
+		project := p.Project
+		project.ID = ""
+		if err := svc.Project.Insert(ctx, &project); err != nil {
+			return summary, fmt.Errorf("failed to create project %q: %w", p.Name, err)
+		}
+		summary.Projects++
+
+		// Members go first so that the tasks' assignees and watchers are accepted
+		for _, m := range p.Members {
+			member := m
+			member.ProjectID = project.ID
+			if err := svc.Member.Add(ctx, &member); err != nil {
+				return summary, fmt.Errorf("failed to add member %q to project %q: %w", m.UserID, p.Name, err)
+			}
+		}
+
+		labelIDs := make(map[string]string, len(p.Labels))
+		for _, l := range p.Labels {
+			label := l
+			label.ID = ""
+			label.ProjectID = project.ID
+			if err := svc.Label.Insert(ctx, &label); err != nil {
+				return summary, fmt.Errorf("failed to create label %q of project %q: %w", l.Name, p.Name, err)
+			}
+			labelIDs[l.ID] = label.ID
+		}
+
+		fieldIDs := make(map[string]string, len(p.CustomFields))
+		for _, f := range p.CustomFields {
+			field := f
+			field.ID = ""
+			field.ProjectID = project.ID
+			if err := svc.CustomField.Insert(ctx, &field); err != nil {
+				return summary, fmt.Errorf("failed to create custom field %q of project %q: %w", f.Name, p.Name, err)
+			}
+			fieldIDs[f.ID] = field.ID
+		}
+
+		tasks, err := parentsFirst(p.Tasks)
+		if err != nil {
+			return summary, fmt.Errorf("failed to import tasks of project %q: %w", p.Name, err)
+		}
+		for _, t := range tasks {
+			task := t
+			task.ID = ""
+			task.ProjectID = project.ID
+			task.ParentID = taskIDs[t.ParentID]
+			task.Progress = nil
+			task.LabelIDs = remapLabels(t.LabelIDs, labelIDs)
+			task.CustomFields = remapFields(t.CustomFields, fieldIDs)
+			if err := svc.Task.Insert(ctx, &task); err != nil {
+				return summary, fmt.Errorf("failed to create task %q of project %q: %w", t.Title, p.Name, err)
+			}
+			if t.ID != "" {
+				taskIDs[t.ID] = task.ID
+			}
+			summary.Tasks++
+		}
+
+		for _, e := range p.TimeEntries {
+			taskID, ok := taskIDs[e.TaskID]
+			if !ok {
+				continue
+			}
+			entry := e
+			entry.ID = ""
+			entry.TaskID = taskID
+			entry.ProjectID = ""
+			if err := svc.TimeEntry.Create(ctx, &entry); err != nil {
+				return summary, fmt.Errorf("failed to create time entry of %s on task %s of project %q: %w", e.User.ID, e.TaskID, p.Name, err)
+			}
+		}
+
+		for _, thread := range p.Comments {
+			taskID, ok := taskIDs[thread.TaskID]
+			if !ok {
+				continue
+			}
+			parentID, err := importComment(ctx, svc, thread.Comment, taskID, "")
+			if err != nil {
+				return summary, fmt.Errorf("failed to create comment %s on task %s of project %q: %w", thread.ID, thread.TaskID, p.Name, err)
+			}
+			for _, reply := range thread.Replies {
+				if _, err := importComment(ctx, svc, reply, taskID, parentID); err != nil {
+					return summary, fmt.Errorf("failed to create reply %s on task %s of project %q: %w", reply.ID, thread.TaskID, p.Name, err)
+				}
+			}
+		}
+	}
+
+	for _, d := range data.Dependencies {
+		blockerID, blockerOK := taskIDs[d.BlockerID]
+		blockedID, blockedOK := taskIDs[d.BlockedID]
+		if !blockerOK || !blockedOK {
+			continue
+		}
+		if _, err := svc.Dependency.Add(ctx, blockerID, blockedID); err != nil {
+			return summary, fmt.Errorf("failed to link task %s to blocked task %s: %w", d.BlockerID, d.BlockedID, err)
+		}
+	}
+	return summary, nil
+}
+
+// importComment creates a comment with the first version of its body and
+// replays its edits, and returns the ID of the new comment
+func importComment(ctx context.Context, svc *service.Service, c entities.Comment, taskID, parentID string) (string, error) {
+	bodies := make([]string, 0, len(c.Edits)+1)
+	for _, edit := range c.Edits {
+		bodies = append(bodies, edit.Body)
+	}
+	bodies = append(bodies, c.Body)
+
+	comment := entities.Comment{TaskID: taskID, ParentID: parentID, Author: c.Author, Body: bodies[0]}
+	if err := svc.Comment.Create(ctx, &comment); err != nil {
+		return "", err
+	}
+	for _, body := range bodies[1:] {
+		edit := entities.Comment{ID: comment.ID, Body: body}
+		if err := svc.Comment.Update(ctx, &edit, c.Author); err != nil {
+			return "", err
+		}
+	}
+	return comment.ID, nil
+}
+
+// parentsFirst orders tasks so that every parent precedes its subtasks. Tasks
+// whose parent is not part of the list become top-level tasks.
+func parentsFirst(tasks []entities.Task) ([]entities.Task, error) {
+	listed := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if task.ID != "" {
+			listed[task.ID] = true
+		}
+	}
+
+	ordered := make([]entities.Task, 0, len(tasks))
+	placed := make(map[string]bool, len(tasks))
+	pending := tasks
+	for len(pending) > 0 {
+		var rest []entities.Task
+		for _, task := range pending {
+			if task.ParentID != "" && listed[task.ParentID] && !placed[task.ParentID] {
+				rest = append(rest, task)
+				continue
+			}
+			if !listed[task.ParentID] {
+				task.ParentID = ""
+			}
+			ordered = append(ordered, task)
+			placed[task.ID] = true
+		}
+		if len(rest) == len(pending) {
+			return nil, fmt.Errorf("task %q is part of a parent cycle", rest[0].Title)
+		}
+		pending = rest
+	}
+	return ordered, nil
+}
+
+// remapLabels replaces exported label IDs by the IDs of the imported labels
+func remapLabels(ids []string, mapping map[string]string) []string {
+	if len(ids) == 0 {
+		return ids
+	}
+	remapped := make([]string, len(ids))
+	for i, id := range ids {
+		if newID, ok := mapping[id]; ok {
+			id = newID
+		}
+		remapped[i] = id
+	}
+	return remapped
+}
+
+// remapFields replaces exported custom field IDs by the IDs of the imported fields
+func remapFields(values entities.CustomFieldValues, mapping map[string]string) entities.CustomFieldValues {
+	if len(values) == 0 {
+		return values
+	}
+	remapped := make(entities.CustomFieldValues, len(values))
+	for id, value := range values {
+		if newID, ok := mapping[id]; ok {
+			id = newID
+		}
+		remapped[id] = value
+	}
+	return remapped
+}
+
+// Write encodes data as indented JSON
+func Write(w io.Writer, data Dataset) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// Read decodes a dataset written by Write
+func Read(r io.Reader) (Dataset, error) {
+	var data Dataset
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return Dataset{}, fmt.Errorf("failed to decode dataset: %w", err)
+	}
+	return data, nil
+}
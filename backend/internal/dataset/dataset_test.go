@@ -0,0 +1,583 @@
+package dataset
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore implements the services used by Export and Import in memory
+type memoryStore struct {
+	nextID      int
+	projects    []entities.Project
+	tasks       []entities.Task
+	labels      []entities.Label
+	fields      []entities.CustomField
+	members     []entities.ProjectMember
+	timeEntries []entities.TimeEntry
+	links       []entities.TaskDependency
+	comments    []entities.Comment
+}
+
+func (m *memoryStore) id() string {
+	m.nextID++
+	return fmt.Sprintf("id-%d", m.nextID)
+}
+
+func (m *memoryStore) task(id string) (entities.Task, bool) {
+	for _, task := range m.tasks {
+		if task.ID == id {
+			return task, true
+		}
+	}
+	return entities.Task{}, false
+}
+
+type memoryProjects struct{ *memoryStore }
+
+func (m memoryProjects) Insert(ctx context.Context, project *entities.Project) error {
+	project.ID = m.id()
+	m.projects = append(m.projects, *project)
+	return nil
+}
+func (m memoryProjects) Update(ctx context.Context, project *entities.Project) error {
+	return errors.New("not implemented")
+}
+func (m memoryProjects) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+func (m memoryProjects) FindByID(ctx context.Context, id string) (entities.Project, error) {
+	return entities.Project{}, errors.New("not implemented")
+}
+func (m memoryProjects) FindAll(ctx context.Context) ([]entities.Project, error) {
+	return m.projects, nil
+}
+func (m memoryProjects) FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+type memoryTasks struct{ *memoryStore }
+
+func (m memoryTasks) Insert(ctx context.Context, task *entities.Task) error {
+	task.ID = m.id()
+	m.tasks = append(m.tasks, *task)
+	return nil
+}
+func (m memoryTasks) Update(ctx context.Context, task *entities.Task) error {
+	return errors.New("not implemented")
+}
+func (m memoryTasks) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+func (m memoryTasks) FindByID(ctx context.Context, id string) (entities.Task, error) {
+	return entities.Task{}, errors.New("not implemented")
+}
+func (m memoryTasks) FindAll(ctx context.Context) ([]entities.Task, error) {
+	return m.tasks, nil
+}
+func (m memoryTasks) FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error) {
+	return nil, errors.New("not implemented")
+}
+func (m memoryTasks) FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
+	var tasks []entities.Task
+	for _, task := range m.tasks {
+		if task.ProjectID == projectID {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+func (m memoryTasks) Children(ctx context.Context, id string) ([]entities.Task, error) {
+	return nil, errors.New("not implemented")
+}
+func (m memoryTasks) Subtree(ctx context.Context, id string) (entities.TaskTree, error) {
+	return entities.TaskTree{}, errors.New("not implemented")
+}
+func (m memoryTasks) ExecuteBatch(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m memoryTasks) MaterializeRecurrences(ctx context.Context, horizon time.Time) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+type memoryLabels struct{ *memoryStore }
+
+func (m memoryLabels) Insert(ctx context.Context, label *entities.Label) error {
+	label.ID = m.id()
+	m.labels = append(m.labels, *label)
+	return nil
+}
+func (m memoryLabels) Update(ctx context.Context, label *entities.Label) error {
+	return errors.New("not implemented")
+}
+func (m memoryLabels) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+func (m memoryLabels) FindByID(ctx context.Context, id string) (entities.Label, error) {
+	return entities.Label{}, errors.New("not implemented")
+}
+func (m memoryLabels) FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error) {
+	var labels []entities.Label
+	for _, label := range m.labels {
+		if label.ProjectID == projectID {
+			labels = append(labels, label)
+		}
+	}
+	return labels, nil
+}
+
+type memoryFields struct{ *memoryStore }
+
+func (m memoryFields) Insert(ctx context.Context, field *entities.CustomField) error {
+	field.ID = m.id()
+	m.fields = append(m.fields, *field)
+	return nil
+}
+func (m memoryFields) Update(ctx context.Context, field *entities.CustomField) error {
+	return errors.New("not implemented")
+}
+func (m memoryFields) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+func (m memoryFields) FindByID(ctx context.Context, id string) (entities.CustomField, error) {
+	return entities.CustomField{}, errors.New("not implemented")
+}
+func (m memoryFields) FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error) {
+	var fields []entities.CustomField
+	for _, field := range m.fields {
+		if field.ProjectID == projectID {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+type memoryMembers struct{ *memoryStore }
+
+func (m memoryMembers) Add(ctx context.Context, member *entities.ProjectMember) error {
+	m.members = append(m.members, *member)
+	return nil
+}
+func (m memoryMembers) Remove(ctx context.Context, projectID, userID string) error {
+	return errors.New("not implemented")
+}
+func (m memoryMembers) FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error) {
+	var members []entities.ProjectMember
+	for _, member := range m.members {
+		if member.ProjectID == projectID {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+type memoryTimeEntries struct{ *memoryStore }
+
+func (m memoryTimeEntries) StartTimer(ctx context.Context, taskID string, user entities.UserRef, note string) (entities.TimeEntry, error) {
+	return entities.TimeEntry{}, errors.New("not implemented")
+}
+func (m memoryTimeEntries) StopTimer(ctx context.Context, taskID, userID string) (entities.TimeEntry, error) {
+	return entities.TimeEntry{}, errors.New("not implemented")
+}
+func (m memoryTimeEntries) RunningTimer(ctx context.Context, userID string) (entities.TimeEntry, error) {
+	return entities.TimeEntry{}, errors.New("not implemented")
+}
+func (m memoryTimeEntries) Create(ctx context.Context, entry *entities.TimeEntry) error {
+	task, ok := m.task(entry.TaskID)
+	if !ok {
+		return errors.New("task not found")
+	}
+	entry.ID = m.id()
+	entry.ProjectID = task.ProjectID
+	m.timeEntries = append(m.timeEntries, *entry)
+	return nil
+}
+func (m memoryTimeEntries) Update(ctx context.Context, entry *entities.TimeEntry, editor entities.UserRef) error {
+	return errors.New("not implemented")
+}
+func (m memoryTimeEntries) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	return errors.New("not implemented")
+}
+func (m memoryTimeEntries) FindByID(ctx context.Context, id string) (entities.TimeEntry, error) {
+	return entities.TimeEntry{}, errors.New("not implemented")
+}
+func (m memoryTimeEntries) FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error) {
+	var entries []entities.TimeEntry
+	for _, entry := range m.timeEntries {
+		if entry.TaskID == taskID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+func (m memoryTimeEntries) Report(ctx context.Context, projectID, from, to, timezone string) (entities.TimeReport, error) {
+	return entities.TimeReport{}, errors.New("not implemented")
+}
+
+type memoryDependencies struct{ *memoryStore }
+
+func (m memoryDependencies) Add(ctx context.Context, blockerID, blockedID string) (entities.TaskDependency, error) {
+	blocker, ok := m.task(blockerID)
+	if !ok {
+		return entities.TaskDependency{}, errors.New("blocker not found")
+	}
+	blocked, ok := m.task(blockedID)
+	if !ok {
+		return entities.TaskDependency{}, errors.New("blocked task not found")
+	}
+	link := entities.TaskDependency{BlockerID: blocker.ID, BlockerProjectID: blocker.ProjectID, BlockedID: blocked.ID, BlockedProjectID: blocked.ProjectID}
+	m.links = append(m.links, link)
+	return link, nil
+}
+func (m memoryDependencies) Remove(ctx context.Context, blockerID, blockedID string) error {
+	return errors.New("not implemented")
+}
+func (m memoryDependencies) FindByTaskID(ctx context.Context, taskID string) (entities.TaskDependencies, error) {
+	var dependencies entities.TaskDependencies
+	for _, link := range m.links {
+		if link.BlockedID == taskID {
+			dependencies.BlockedBy = append(dependencies.BlockedBy, link)
+		}
+		if link.BlockerID == taskID {
+			dependencies.Blocks = append(dependencies.Blocks, link)
+		}
+	}
+	return dependencies, nil
+}
+func (m memoryDependencies) Graph(ctx context.Context, projectID string) (entities.TaskGraph, error) {
+	return entities.TaskGraph{}, errors.New("not implemented")
+}
+
+type memoryComments struct{ *memoryStore }
+
+func (m memoryComments) Create(ctx context.Context, comment *entities.Comment) error {
+	task, ok := m.task(comment.TaskID)
+	if !ok {
+		return errors.New("task not found")
+	}
+	comment.ID = m.id()
+	comment.ProjectID = task.ProjectID
+	comment.Edits = []entities.CommentEdit{}
+	m.comments = append(m.comments, *comment)
+	return nil
+}
+func (m memoryComments) Update(ctx context.Context, comment *entities.Comment, editor entities.UserRef) error {
+	for i, existing := range m.comments {
+		if existing.ID != comment.ID {
+			continue
+		}
+		if existing.Author.ID != editor.ID {
+			return errors.New("not the author")
+		}
+		existing.Edits = append(existing.Edits, entities.CommentEdit{Body: existing.Body})
+		existing.Body = comment.Body
+		m.comments[i] = existing
+		*comment = existing
+		return nil
+	}
+	return errors.New("comment not found")
+}
+func (m memoryComments) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	return errors.New("not implemented")
+}
+func (m memoryComments) FindByID(ctx context.Context, id string) (entities.Comment, error) {
+	return entities.Comment{}, errors.New("not implemented")
+}
+func (m memoryComments) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.CommentThread, int64, error) {
+	var threads []entities.CommentThread
+	for _, comment := range m.comments {
+		if comment.TaskID != taskID || comment.ParentID != "" {
+			continue
+		}
+		thread := entities.CommentThread{Comment: comment, Replies: []entities.Comment{}}
+		for _, reply := range m.comments {
+			if reply.ParentID == comment.ID {
+				thread.Replies = append(thread.Replies, reply)
+			}
+		}
+		threads = append(threads, thread)
+	}
+	total := int64(len(threads))
+	if offset > len(threads) {
+		offset = len(threads)
+	}
+	return threads[offset:min(offset+limit, len(threads))], total, nil
+}
+
+func newMemoryService() (*service.Service, *memoryStore) {
+	store := &memoryStore{}
+	return &service.Service{
+		Project:     memoryProjects{store},
+		Task:        memoryTasks{store},
+		Label:       memoryLabels{store},
+		CustomField: memoryFields{store},
+		Member:      memoryMembers{store},
+		TimeEntry:   memoryTimeEntries{store},
+		Dependency:  memoryDependencies{store},
+		Comment:     memoryComments{store},
+	}, store
+}
+
+func TestImport_Sample(t *testing.T) {
+	svc, store := newMemoryService()
+	sample := Sample(time.Now())
+
+	summary, err := Import(context.Background(), svc, sample)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(sample.Projects), summary.Projects)
+	assert.Equal(t, len(store.tasks), summary.Tasks)
+	for _, task := range store.tasks {
+		assert.NotEmpty(t, task.ProjectID, "task %q should belong to a project", task.Title)
+	}
+}
+
+func TestImport_RemapsLabelsAndCustomFields(t *testing.T) {
+	svc, store := newMemoryService()
+	data := Dataset{
+		Version: FormatVersion,
+		Projects: []Project{{
+			Project:      entities.Project{Name: "Project"},
+			Members:      []entities.ProjectMember{{ProjectID: "old-project", UserID: "jane", Name: "Jane"}},
+			Labels:       []entities.Label{{ID: "old-label", Name: "bug", Color: "#d73a4a"}},
+			CustomFields: []entities.CustomField{{ID: "old-field", Name: "Points", Type: entities.CustomFieldTypeNumber}},
+			Tasks: []entities.Task{{
+				Title:        "Fix crash",
+				LabelIDs:     []string{"old-label"},
+				CustomFields: entities.CustomFieldValues{"old-field": 3.0},
+			}},
+		}},
+	}
+
+	_, err := Import(context.Background(), svc, data)
+	require.NoError(t, err)
+
+	require.Len(t, store.labels, 1)
+	require.Len(t, store.fields, 1)
+	require.Len(t, store.tasks, 1)
+	require.Len(t, store.members, 1)
+	assert.Equal(t, store.projects[0].ID, store.members[0].ProjectID)
+	assert.Equal(t, store.projects[0].ID, store.labels[0].ProjectID)
+	assert.Equal(t, []string{store.labels[0].ID}, store.tasks[0].LabelIDs)
+	assert.Equal(t, entities.CustomFieldValues{store.fields[0].ID: 3.0}, store.tasks[0].CustomFields)
+}
+
+func TestImport_SubtasksFollowTheirParents(t *testing.T) {
+	svc, store := newMemoryService()
+	data := Dataset{
+		Version: FormatVersion,
+		Projects: []Project{{
+			Project: entities.Project{Name: "Project"},
+			Tasks: []entities.Task{
+				{ID: "old-step", ParentID: "old-story", Title: "Step"},
+				{ID: "old-story", ParentID: "old-epic", Title: "Story"},
+				{ID: "old-epic", Title: "Epic"},
+				{ID: "old-orphan", ParentID: "elsewhere", Title: "Orphan"},
+			},
+		}},
+	}
+
+	_, err := Import(context.Background(), svc, data)
+	require.NoError(t, err)
+
+	require.Len(t, store.tasks, 4)
+	byTitle := make(map[string]entities.Task, len(store.tasks))
+	for _, task := range store.tasks {
+		byTitle[task.Title] = task
+	}
+	assert.Equal(t, "Epic", store.tasks[0].Title, "parents are created first")
+	assert.Empty(t, byTitle["Epic"].ParentID)
+	assert.Equal(t, byTitle["Epic"].ID, byTitle["Story"].ParentID)
+	assert.Equal(t, byTitle["Story"].ID, byTitle["Step"].ParentID)
+	assert.Empty(t, byTitle["Orphan"].ParentID, "unknown parents are dropped")
+
+	data.Projects[0].Tasks = []entities.Task{
+		{ID: "a", ParentID: "b", Title: "A"},
+		{ID: "b", ParentID: "a", Title: "B"},
+	}
+	_, err = Import(context.Background(), svc, data)
+	assert.ErrorContains(t, err, "parent cycle")
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	source, _ := newMemoryService()
+	_, err := Import(context.Background(), source, Sample(time.Now()))
+	require.NoError(t, err)
+
+	exported, err := Export(context.Background(), source)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, exported))
+	decoded, err := Read(&buf)
+	require.NoError(t, err)
+
+	target, store := newMemoryService()
+	summary, err := Import(context.Background(), target, decoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, len(exported.Projects), summary.Projects)
+	byProject := make(map[string]int)
+	for _, task := range store.tasks {
+		byProject[task.ProjectID]++
+	}
+	for i, project := range store.projects {
+		assert.Equal(t, exported.Projects[i].Name, project.Name)
+		assert.Equal(t, len(exported.Projects[i].Tasks), byProject[project.ID], "tasks of %q", project.Name)
+	}
+}
+
+func TestImport_RemapsTimeEntries(t *testing.T) {
+	svc, store := newMemoryService()
+	start := time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	data := Dataset{
+		Version: FormatVersion,
+		Projects: []Project{{
+			Project: entities.Project{Name: "Project"},
+			Tasks:   []entities.Task{{ID: "old-task", Title: "Write docs"}},
+			TimeEntries: []entities.TimeEntry{
+				{ID: "old-entry", TaskID: "old-task", ProjectID: "old-project", User: entities.UserRef{ID: "jane"}, Start: start, End: &end, Duration: 3600, Note: "intro"},
+				{ID: "other-entry", TaskID: "elsewhere", User: entities.UserRef{ID: "jane"}, Start: start, End: &end, Duration: 3600},
+			},
+		}},
+	}
+
+	_, err := Import(context.Background(), svc, data)
+	require.NoError(t, err)
+
+	require.Len(t, store.timeEntries, 1, "entries of unknown tasks are skipped")
+	entry := store.timeEntries[0]
+	assert.Equal(t, store.tasks[0].ID, entry.TaskID)
+	assert.Equal(t, store.projects[0].ID, entry.ProjectID)
+	assert.Equal(t, "jane", entry.User.ID)
+	assert.Equal(t, start, entry.Start)
+	assert.Equal(t, int64(3600), entry.Duration)
+	assert.Equal(t, "intro", entry.Note)
+}
+
+func TestExport_SkipsRunningTimers(t *testing.T) {
+	svc, store := newMemoryService()
+	project := entities.Project{Name: "Project"}
+	require.NoError(t, svc.Project.Insert(context.Background(), &project))
+	task := entities.Task{ProjectID: project.ID, Title: "Write docs"}
+	require.NoError(t, svc.Task.Insert(context.Background(), &task))
+	start := time.Date(2030, 3, 4, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	store.timeEntries = []entities.TimeEntry{
+		{ID: "stopped", TaskID: task.ID, User: entities.UserRef{ID: "jane"}, Start: start, End: &end, Duration: 3600},
+		{ID: "running", TaskID: task.ID, User: entities.UserRef{ID: "john"}, Start: end},
+	}
+
+	exported, err := Export(context.Background(), svc)
+	require.NoError(t, err)
+
+	require.Len(t, exported.Projects, 1)
+	require.Len(t, exported.Projects[0].TimeEntries, 1)
+	assert.Equal(t, "stopped", exported.Projects[0].TimeEntries[0].ID)
+}
+
+func TestExportImport_DependenciesAcrossProjects(t *testing.T) {
+	source, _ := newMemoryService()
+	data := Dataset{
+		Version: FormatVersion,
+		Projects: []Project{
+			{Project: entities.Project{Name: "Backend"}, Tasks: []entities.Task{{ID: "api", Title: "API"}, {ID: "schema", Title: "Schema"}}},
+			{Project: entities.Project{Name: "Frontend"}, Tasks: []entities.Task{{ID: "ui", Title: "UI"}}},
+		},
+		Dependencies: []entities.TaskDependency{
+			{BlockerID: "schema", BlockedID: "api"},
+			{BlockerID: "api", BlockedID: "ui"},
+			{BlockerID: "elsewhere", BlockedID: "ui"},
+		},
+	}
+	_, err := Import(context.Background(), source, data)
+	require.NoError(t, err)
+
+	exported, err := Export(context.Background(), source)
+	require.NoError(t, err)
+	require.Len(t, exported.Dependencies, 2, "links of unknown tasks are skipped and the others exported once")
+
+	target, store := newMemoryService()
+	_, err = Import(context.Background(), target, exported)
+	require.NoError(t, err)
+
+	byTitle := make(map[string]entities.Task, len(store.tasks))
+	for _, task := range store.tasks {
+		byTitle[task.Title] = task
+	}
+	assert.ElementsMatch(t, []entities.TaskDependency{
+		{BlockerID: byTitle["Schema"].ID, BlockerProjectID: byTitle["Schema"].ProjectID, BlockedID: byTitle["API"].ID, BlockedProjectID: byTitle["API"].ProjectID},
+		{BlockerID: byTitle["API"].ID, BlockerProjectID: byTitle["API"].ProjectID, BlockedID: byTitle["UI"].ID, BlockedProjectID: byTitle["UI"].ProjectID},
+	}, store.links)
+	assert.NotEqual(t, byTitle["API"].ProjectID, byTitle["UI"].ProjectID)
+}
+
+func TestExportImport_CommentsWithRepliesAndEdits(t *testing.T) {
+	source, store := newMemoryService()
+	ctx := context.Background()
+	jane := entities.UserRef{ID: "jane", Name: "Jane"}
+	john := entities.UserRef{ID: "john", Name: "John"}
+	project := entities.Project{Name: "Project"}
+	require.NoError(t, source.Project.Insert(ctx, &project))
+	task := entities.Task{ProjectID: project.ID, Title: "Write docs"}
+	require.NoError(t, source.Task.Insert(ctx, &task))
+	question := entities.Comment{TaskID: task.ID, Author: jane, Body: "Draft?"}
+	require.NoError(t, source.Comment.Create(ctx, &question))
+	require.NoError(t, source.Comment.Update(ctx, &entities.Comment{ID: question.ID, Body: "Draft ready?"}, jane))
+	require.NoError(t, source.Comment.Update(ctx, &entities.Comment{ID: question.ID, Body: "Is the draft ready?"}, jane))
+	require.NoError(t, source.Comment.Create(ctx, &entities.Comment{TaskID: task.ID, ParentID: question.ID, Author: john, Body: "Tomorrow"}))
+	for i := 0; i < commentPageSize; i++ {
+		require.NoError(t, source.Comment.Create(ctx, &entities.Comment{TaskID: task.ID, Author: john, Body: fmt.Sprintf("Note %d", i)}))
+	}
+	require.Len(t, store.comments, commentPageSize+2)
+
+	exported, err := Export(ctx, source)
+	require.NoError(t, err)
+	require.Len(t, exported.Projects[0].Comments, commentPageSize+1, "all pages of threads are exported")
+
+	target, imported := newMemoryService()
+	_, err = Import(ctx, target, exported)
+	require.NoError(t, err)
+
+	require.Len(t, imported.comments, commentPageSize+2)
+	importedQuestion, reply := imported.comments[0], imported.comments[1]
+	assert.Equal(t, imported.tasks[0].ID, importedQuestion.TaskID)
+	assert.Equal(t, "Is the draft ready?", importedQuestion.Body)
+	assert.Equal(t, jane, importedQuestion.Author)
+	assert.Equal(t, []entities.CommentEdit{{Body: "Draft?"}, {Body: "Draft ready?"}}, importedQuestion.Edits)
+	assert.Equal(t, importedQuestion.ID, reply.ParentID)
+	assert.Equal(t, "Tomorrow", reply.Body)
+	assert.Equal(t, john, reply.Author)
+}
+
+func TestImport_ReadsVersion1(t *testing.T) {
+	svc, store := newMemoryService()
+
+	summary, err := Import(context.Background(), svc, Dataset{Version: 1, Projects: []Project{{
+		Project: entities.Project{Name: "Project"},
+		Tasks:   []entities.Task{{Title: "Task"}},
+	}}})
+
+	require.NoError(t, err)
+	assert.Equal(t, Summary{Projects: 1, Tasks: 1}, summary)
+	assert.Empty(t, store.timeEntries)
+}
+
+func TestImport_RejectsUnknownVersion(t *testing.T) {
+	svc, store := newMemoryService()
+
+	_, err := Import(context.Background(), svc, Dataset{Version: 99, Projects: []Project{{}}})
+
+	assert.ErrorContains(t, err, "unsupported dataset version")
+	assert.Empty(t, store.projects)
+}
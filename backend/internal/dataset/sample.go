@@ -0,0 +1,63 @@
+package dataset
+
+import (
+	"boilerplate/internal/entities"
+	"time"
+)
+
+// Sample returns the demo projects and tasks used by the seed command. Due
+// dates are relative to now so the data always contains upcoming and overdue
+// tasks. Label and task IDs are placeholders that Import replaces.
+func Sample(now time.Time) Dataset {
+	day := func(offset int) *time.Time {
+		due := now.UTC().Truncate(24*time.Hour).AddDate(0, 0, offset)
+		return &due
+	}
+
+	return Dataset{
+		Version: FormatVersion,
+		Projects: []Project{
+			{
+				Project: entities.Project{
+					Name:        "Website Relaunch",
+					Description: "Redesign and relaunch of the company website",
+				},
+				Labels: []entities.Label{
+					{ID: "design", Name: "design", Color: "#a2eeef"},
+					{ID: "content", Name: "content", Color: "#0e8a16"},
+				},
+				Tasks: []entities.Task{
+					{Title: "Collect requirements", Status: entities.TaskStatusDone, DueDate: day(-14), Description: "Interview stakeholders and write down the goals"},
+					{Title: "Create wireframes", Status: entities.TaskStatusDone, DueDate: day(-7), LabelIDs: []string{"design"}},
+					{ID: "landing", Title: "Implement landing page", Status: entities.TaskStatusInProgress, Priority: entities.TaskPriorityHigh, DueDate: day(3), Description: "Responsive layout based on the approved design", LabelIDs: []string{"design"}},
+					{ParentID: "landing", Title: "Hero section", Status: entities.TaskStatusDone},
+					{ParentID: "landing", Title: "Contact form", Status: entities.TaskStatusTodo},
+					{Title: "Migrate blog posts", Status: entities.TaskStatusTodo, Priority: entities.TaskPriorityMedium, DueDate: day(-1), LabelIDs: []string{"content"}},
+					{Title: "Go live", Status: entities.TaskStatusTodo, Priority: entities.TaskPriorityUrgent, DueDate: day(21)},
+				},
+			},
+			{
+				Project: entities.Project{
+					Name:        "Mobile App",
+					Description: "First version of the companion app",
+				},
+				Tasks: []entities.Task{
+					{Title: "Set up CI pipeline", Status: entities.TaskStatusDone},
+					{Title: "Login with Keycloak", Status: entities.TaskStatusInProgress, DueDate: day(5)},
+					{Title: "Offline mode", Status: entities.TaskStatusTodo, Description: "Cache projects and tasks for use without a connection"},
+					{Title: "Publish beta", Status: entities.TaskStatusTodo, DueDate: day(30)},
+				},
+			},
+			{
+				Project: entities.Project{
+					Name:        "Office Move",
+					Description: "Move to the new office building",
+				},
+				Tasks: []entities.Task{
+					{Title: "Order furniture", Status: entities.TaskStatusTodo, DueDate: day(10)},
+					{Title: "Book movers", Status: entities.TaskStatusTodo, DueDate: day(7)},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// Attachment is a file attached to a task. The metadata is stored in the
+// database, the content in a blob store.
+type Attachment struct {
+	ID        string `json:"id"`
+	TaskID    string `json:"taskId"`
+	ProjectID string `json:"projectId"`
+	FileName  string `json:"fileName"`
+	// ContentType is sniffed from the first bytes of the content
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"` // in bytes
+	// SHA256 is the hex encoded SHA-256 digest of the content
+	SHA256 string `json:"sha256"`
+	// StorageKey locates the content in the blob store
+	StorageKey string    `json:"-"`
+	UploadedBy UserRef   `json:"uploadedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// Comment is a Markdown message on a task. Comments form threads one level
+// deep: a reply references a top-level comment of the same task.
+type Comment struct {
+	ID        string `json:"id"`
+	TaskID    string `json:"taskId"`
+	ProjectID string `json:"projectId"`
+	// ParentID references the top-level comment a reply belongs to; empty for top-level comments
+	ParentID string  `json:"parentId,omitempty"`
+	Author   UserRef `json:"author"`
+	// Body is the Markdown source of the comment
+	Body string `json:"body"`
+	// HTML is the rendered and sanitized body, computed when the comment is read
+	HTML string `json:"html"`
+	// Mentions are the project members mentioned with @userId or @email in the body
+	Mentions []UserRef `json:"mentions"`
+	// Edits holds the previous bodies of the comment, oldest first
+	Edits     []CommentEdit `json:"edits"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// CommentEdit is a previous version of a comment body
+type CommentEdit struct {
+	Body string `json:"body"`
+	// EditedAt is when the body was replaced
+	EditedAt time.Time `json:"editedAt"`
+}
+
+// CommentThread is a top-level comment with its replies, oldest first
+type CommentThread struct {
+	Comment
+	Replies []Comment `json:"replies"`
+}
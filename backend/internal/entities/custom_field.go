@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// CustomFieldType determines which values a custom field accepts
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText         CustomFieldType = "text"
+	CustomFieldTypeNumber       CustomFieldType = "number"
+	CustomFieldTypeDate         CustomFieldType = "date"
+	CustomFieldTypeSingleSelect CustomFieldType = "single_select"
+	CustomFieldTypeUser         CustomFieldType = "user"
+)
+
+// CustomField defines an additional, typed task attribute of a project
+type CustomField struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"projectId"`
+	Name      string          `json:"name"`
+	Type      CustomFieldType `json:"type"`
+	// Options lists the allowed values of single_select fields
+	Options   []string  `json:"options,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CustomFieldValues maps custom field IDs to values. Text, single_select and
+// user values are strings (users are identified by their subject), numbers are
+// float64 and dates are time.Time.
+type CustomFieldValues map[string]interface{}
@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// TaskDependency records that the blocker task has to be done before the
+// blocked task. The tasks may belong to different projects.
+type TaskDependency struct {
+	BlockerID        string    `json:"blockerId"`
+	BlockerProjectID string    `json:"blockerProjectId"`
+	BlockedID        string    `json:"blockedId"`
+	BlockedProjectID string    `json:"blockedProjectId"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+// TaskDependencies lists the links of a single task in both directions
+type TaskDependencies struct {
+	// BlockedBy are the links to tasks that have to be done first
+	BlockedBy []TaskDependency `json:"blockedBy"`
+	// Blocks are the links to tasks waiting for this task
+	Blocks []TaskDependency `json:"blocks"`
+}
+
+// TaskGraphNode is a task in the dependency graph of a project
+type TaskGraphNode struct {
+	ID        string     `json:"id"`
+	ProjectID string     `json:"projectId"`
+	Title     string     `json:"title"`
+	Status    TaskStatus `json:"status"`
+	DueDate   *time.Time `json:"dueDate,omitempty"`
+	// Done is set for tasks in a status of the done category
+	Done bool `json:"done"`
+	// External marks tasks of other projects that are linked to the project's tasks
+	External bool `json:"external,omitempty"`
+}
+
+// TaskGraph is the dependency DAG of a project's tasks
+type TaskGraph struct {
+	Nodes []TaskGraphNode  `json:"nodes"`
+	Edges []TaskDependency `json:"edges"`
+	// Order lists the node IDs so that every blocker precedes the tasks it blocks
+	Order []string `json:"order"`
+	// CriticalPath is the chain of dependent tasks spanning the longest time
+	// between due dates, from the first blocker to the last blocked task
+	CriticalPath []string `json:"criticalPath"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// Label tags tasks of a project, e.g. "bug" or "frontend"
+type Label struct {
+	ID        string    `json:"id"`
+	ProjectID string    `json:"projectId"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"` // hex colour, e.g. #d73a4a
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// UserRef identifies a Keycloak user by the token subject. Name and Email are
+// a snapshot taken when the reference was stored and are not kept in sync.
+type UserRef struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// ProjectMember is a user who takes part in a project. Only members can be
+// assigned to or watch the project's tasks.
+type ProjectMember struct {
+	ProjectID string `json:"projectId"`
+	// UserID is the Keycloak subject of the user
+	UserID    string    `json:"userId"`
+	Name      string    `json:"name,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Ref returns the member as a user reference for tasks
+func (m ProjectMember) Ref() UserRef {
+	return UserRef{ID: m.UserID, Name: m.Name, Email: m.Email}
+}
@@ -0,0 +1,90 @@
+package entities
+
+import "time"
+
+// NotificationKind tells what a notification reminds of
+type NotificationKind string
+
+const (
+	// NotificationDueSoon reminds an assignee of a task that is due within their remind window
+	NotificationDueSoon NotificationKind = "due_soon"
+	// NotificationOverdue reminds an assignee of an open task past its due date
+	NotificationOverdue NotificationKind = "overdue"
+)
+
+// NotificationChannel is a way of delivering notifications besides the in-app inbox
+type NotificationChannel string
+
+const (
+	NotificationChannelEmail   NotificationChannel = "email"
+	NotificationChannelWebhook NotificationChannel = "webhook"
+)
+
+// DeliveryStatus is the state of the delivery of a notification through one channel
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending"
+	DeliverySent    DeliveryStatus = "sent"
+	// DeliveryFailed is final: the channel failed on every attempt
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Notification is a reminder in the inbox of a user. It keeps a snapshot of
+// the task, so it outlives changes to the task and its deletion.
+type Notification struct {
+	ID string `json:"id"`
+	// UserID is the Keycloak subject of the recipient
+	UserID    string           `json:"userId"`
+	Kind      NotificationKind `json:"kind"`
+	TaskID    string           `json:"taskId"`
+	ProjectID string           `json:"projectId"`
+	TaskTitle string           `json:"taskTitle"`
+	DueDate   time.Time        `json:"dueDate"`
+	// ReadAt is when the recipient marked the notification as read; nil while unread
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	// Key identifies the reminder; a user gets at most one notification per key
+	Key string `json:"-"`
+	// Deliveries track the channels the notification is sent through
+	Deliveries []NotificationDelivery `json:"-"`
+}
+
+// NotificationKey identifies a reminder of a task. It includes the due date,
+// so that moving the due date brings up a new reminder.
+func NotificationKey(kind NotificationKind, taskID string, dueDate time.Time) string {
+	return string(kind) + ":" + taskID + ":" + dueDate.UTC().Format(time.RFC3339)
+}
+
+// NotificationDelivery is the delivery of a notification through one channel
+type NotificationDelivery struct {
+	Channel NotificationChannel `json:"channel"`
+	// Target is the email address or webhook URL the notification goes to
+	Target    string         `json:"target"`
+	Status    DeliveryStatus `json:"status"`
+	Attempts  int            `json:"attempts"`
+	LastError string         `json:"lastError,omitempty"`
+	SentAt    *time.Time     `json:"sentAt,omitempty"`
+}
+
+// NotificationPreferences are the reminder settings of a user. Users without
+// stored preferences get DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	UserID string `json:"-"`
+	// RemindBefore is how many hours before the due date a due soon reminder
+	// is sent; 0 disables them
+	RemindBefore int `json:"remindBefore" example:"24"`
+	// Overdue enables reminders for open tasks past their due date
+	Overdue bool `json:"overdue" example:"true"`
+	// Email also sends reminders to the email address of the user
+	Email bool `json:"email" example:"false"`
+	// WebhookURL also posts reminders to this URL when set
+	WebhookURL string    `json:"webhookUrl,omitempty" example:"https://hooks.example.com/reminders"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// DefaultNotificationPreferences returns the settings of a user who has not
+// changed them: in-app reminders a day ahead and when overdue
+func DefaultNotificationPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{UserID: userID, RemindBefore: 24, Overdue: true}
+}
@@ -2,74 +2,170 @@ package entities
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
-type TaskStatus int
+// TaskStatus is the key of a status in the workflow of the task's project,
+// e.g. "IN_REVIEW". Keys consist of upper case letters, digits and underscores.
+type TaskStatus string
 
+// Statuses of the default workflow. Before projects had workflows, tasks were
+// stored with these statuses encoded as 0, 1 and 2.
 const (
-	TaskStatusTodo TaskStatus = iota
-	TaskStatusInProgress
-	TaskStatusDone
+	TaskStatusTodo       TaskStatus = "TODO"
+	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
+	TaskStatusDone       TaskStatus = "DONE"
 )
 
-func (s TaskStatus) String() string {
-	switch s {
-	case TaskStatusTodo:
-		return "TODO"
-	case TaskStatusInProgress:
-		return "IN_PROGRESS"
-	case TaskStatusDone:
-		return "DONE"
+var taskStatusPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+// ParseTaskStatus normalizes a status key to upper case and checks its format.
+// Whether a task may use the status is decided by the workflow of its project.
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	key := strings.ToUpper(strings.TrimSpace(s))
+	if !taskStatusPattern.MatchString(key) {
+		return "", fmt.Errorf("invalid task status: %s", s)
+	}
+	return TaskStatus(key), nil
+}
+
+// TaskPriority ranks tasks for triage. The zero value means no priority was set.
+type TaskPriority int
+
+const (
+	TaskPriorityNone TaskPriority = iota
+	TaskPriorityLow
+	TaskPriorityMedium
+	TaskPriorityHigh
+	TaskPriorityUrgent
+)
+
+func (p TaskPriority) String() string {
+	switch p {
+	case TaskPriorityNone:
+		return "NONE"
+	case TaskPriorityLow:
+		return "LOW"
+	case TaskPriorityMedium:
+		return "MEDIUM"
+	case TaskPriorityHigh:
+		return "HIGH"
+	case TaskPriorityUrgent:
+		return "URGENT"
 	default:
 		return "UNKNOWN"
 	}
 }
 
-func (s TaskStatus) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(`"%s"`, s.String())), nil
+func (p TaskPriority) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%s"`, p.String())), nil
 }
 
-func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+func (p *TaskPriority) UnmarshalJSON(data []byte) error {
 	str := string(data)
 	// Remove quotes
 	if len(str) >= 2 && str[0] == '"' && str[len(str)-1] == '"' {
 		str = str[1 : len(str)-1]
 	}
 
-	switch str {
-	case "TODO":
-		*s = TaskStatusTodo
-	case "IN_PROGRESS":
-		*s = TaskStatusInProgress
-	case "DONE":
-		*s = TaskStatusDone
-	default:
-		return fmt.Errorf("invalid task status: %s", str)
+	priority, err := ParseTaskPriority(str)
+	if err != nil {
+		return err
 	}
+	*p = priority
 	return nil
 }
 
-func ParseTaskStatus(s string) (TaskStatus, error) {
+// ParseTaskPriority accepts the priority names as well as P0 (urgent) to P3 (low)
+func ParseTaskPriority(s string) (TaskPriority, error) {
 	switch s {
-	case "TODO":
-		return TaskStatusTodo, nil
-	case "IN_PROGRESS":
-		return TaskStatusInProgress, nil
-	case "DONE":
-		return TaskStatusDone, nil
+	case "NONE":
+		return TaskPriorityNone, nil
+	case "LOW", "P3":
+		return TaskPriorityLow, nil
+	case "MEDIUM", "P2":
+		return TaskPriorityMedium, nil
+	case "HIGH", "P1":
+		return TaskPriorityHigh, nil
+	case "URGENT", "P0":
+		return TaskPriorityUrgent, nil
 	default:
-		return TaskStatusTodo, fmt.Errorf("invalid task status: %s", s)
+		return TaskPriorityNone, fmt.Errorf("invalid task priority: %s", s)
 	}
 }
 
 type Task struct {
-	ID          string     `json:"id"`
-	ProjectID   string     `json:"projectId"`
-	Title       string     `json:"title"`
-	Status      TaskStatus `json:"status"`
-	DueDate     *time.Time `json:"dueDate,omitempty"`
-	Description string     `json:"description"`
-	CreatedAt   time.Time  `json:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
+	ID        string `json:"id"`
+	ProjectID string `json:"projectId"`
+	// ParentID references the parent task in the same project; empty for top-level tasks
+	ParentID    string       `json:"parentId,omitempty"`
+	Title       string       `json:"title"`
+	Status      TaskStatus   `json:"status"`
+	Priority    TaskPriority `json:"priority"`
+	DueDate     *time.Time   `json:"dueDate,omitempty"`
+	Description string       `json:"description"`
+	// LabelIDs reference labels of the task's project
+	LabelIDs []string `json:"labelIds"`
+	// CustomFields maps custom field IDs of the task's project to their values
+	CustomFields CustomFieldValues `json:"customFields,omitempty"`
+	// Assignees and Watchers are members of the task's project
+	Assignees []UserRef `json:"assignees"`
+	Watchers  []UserRef `json:"watchers"`
+	// Progress is computed when the task is read and only set for tasks with subtasks
+	Progress *TaskProgress `json:"progress,omitempty"`
+	// CommentCount counts the comments and replies on the task and is computed when the task is read
+	CommentCount int `json:"commentCount"`
+	// Estimate is the expected effort in seconds; 0 means no estimate
+	Estimate int64 `json:"estimate,omitempty"`
+	// Recurrence repeats the task. It is carried by the latest occurrence of a
+	// series; removing it, or deleting that occurrence, ends the series.
+	Recurrence *TaskRecurrence `json:"recurrence,omitempty"`
+	// SeriesID and Occurrence are set by the service: SeriesID is shared by
+	// all occurrences of a recurring task, Occurrence is the start of the
+	// occurrence the task stands for
+	SeriesID   string     `json:"seriesId,omitempty"`
+	Occurrence *time.Time `json:"occurrence,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TaskRecurrence repeats a task on an RFC 5545 schedule
+type TaskRecurrence struct {
+	// RRule is an RFC 5545 recurrence rule without DTSTART, repeating at most daily
+	RRule string `json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO"`
+	// Timezone is the IANA time zone the rule is evaluated in, so that e.g.
+	// a weekly task keeps its local time across daylight saving changes
+	Timezone string `json:"timezone" example:"Europe/Berlin"`
+	// Start is the DTSTART of the rule; its local time of day is inherited by
+	// the occurrences unless the rule sets BYHOUR or BYMINUTE
+	Start time.Time `json:"start"`
+}
+
+// TaskProgress rolls up the subtasks at every level below a task
+type TaskProgress struct {
+	// Done counts the subtasks in a status of the done category
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// TaskTree is a task with its subtasks, nested to any depth
+type TaskTree struct {
+	Task
+	Subtasks []TaskTree `json:"subtasks"`
+}
+
+// TaskFilter narrows a task list. Empty fields match every task.
+type TaskFilter struct {
+	// IDs restricts the list to these tasks; malformed IDs match nothing
+	IDs        []string
+	Status     *TaskStatus
+	Priorities []TaskPriority
+	// LabelIDs matches tasks that have all of the labels
+	LabelIDs []string
+	// CustomFields matches tasks whose fields have exactly these values
+	CustomFields CustomFieldValues
+	// AssigneeID matches tasks assigned to this user
+	AssigneeID string
 }
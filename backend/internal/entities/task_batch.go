@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+type TaskBatchOpType string
+
+const (
+	TaskBatchOpCreate TaskBatchOpType = "create"
+	TaskBatchOpUpdate TaskBatchOpType = "update"
+	TaskBatchOpDelete TaskBatchOpType = "delete"
+)
+
+// TaskBatchStatus describes the outcome of a single operation in a batch
+type TaskBatchStatus string
+
+const (
+	TaskBatchStatusOK         TaskBatchStatus = "ok"
+	TaskBatchStatusFailed     TaskBatchStatus = "failed"
+	TaskBatchStatusRolledBack TaskBatchStatus = "rolled_back"
+)
+
+// TaskPatch holds the fields to change on an existing task. Nil fields are left untouched.
+type TaskPatch struct {
+	ID          string
+	Title       *string
+	Status      *TaskStatus
+	Priority    *TaskPriority
+	DueDate     *time.Time
+	Description *string
+}
+
+// TaskBatchOperation is a single create, update or delete inside a batch request.
+// Task is used for creates, Patch for updates and ID for deletes.
+type TaskBatchOperation struct {
+	Op    TaskBatchOpType
+	ID    string
+	Task  *Task
+	Patch *TaskPatch
+}
+
+// TaskBatchResult reports the outcome of the operation at Index in the request
+type TaskBatchResult struct {
+	Index  int             `json:"index"`
+	Op     TaskBatchOpType `json:"op"`
+	ID     string          `json:"id,omitempty"`
+	Status TaskBatchStatus `json:"status"`
+	Error  string          `json:"error,omitempty"`
+	Task   *Task           `json:"task,omitempty"`
+}
@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+// TimeEntry records time a user spent on a task. It is either tracked with a
+// timer, which runs until it is stopped, or added manually with a duration.
+type TimeEntry struct {
+	ID        string  `json:"id"`
+	TaskID    string  `json:"taskId"`
+	ProjectID string  `json:"projectId"`
+	User      UserRef `json:"user"`
+	// Start is when the work began
+	Start time.Time `json:"start"`
+	// End is unset while the timer runs
+	End       *time.Time `json:"end,omitempty"`
+	Duration  int64      `json:"duration"` // in seconds, 0 while the timer runs
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// Running reports whether the entry is a timer that has not been stopped yet
+func (e TimeEntry) Running() bool {
+	return e.End == nil
+}
+
+// TimeReport sums up the stopped time entries of a project that started
+// between two days. Days are calendar days in the time zone of the report.
+type TimeReport struct {
+	ProjectID string `json:"projectId"`
+	// From and To are the first and the last day of the report, YYYY-MM-DD
+	From     string `json:"from" example:"2030-03-01"`
+	To       string `json:"to" example:"2030-03-31"`
+	Timezone string `json:"timezone" example:"Europe/Berlin"`
+	Duration int64  `json:"duration"` // in seconds
+	Entries  int    `json:"entries"`
+	// ByUser, ByTask and ByDay break the total down, the largest first for
+	// users and tasks and in calendar order for days
+	ByUser []TimeByUser `json:"byUser"`
+	ByTask []TimeByTask `json:"byTask"`
+	ByDay  []TimeByDay  `json:"byDay"`
+}
+
+// TimeByUser is the time a user spent on the tasks of a report
+type TimeByUser struct {
+	User     UserRef `json:"user"`
+	Duration int64   `json:"duration"` // in seconds
+	Entries  int     `json:"entries"`
+}
+
+// TimeByTask is the time spent on a task, next to its estimate
+type TimeByTask struct {
+	TaskID   string `json:"taskId"`
+	Title    string `json:"title"`
+	Estimate int64  `json:"estimate,omitempty"` // in seconds
+	Duration int64  `json:"duration"`           // in seconds
+	Entries  int    `json:"entries"`
+}
+
+// TimeByDay is the time spent on a day; days without entries are left out
+type TimeByDay struct {
+	Date     string `json:"date" example:"2030-03-25"`
+	Duration int64  `json:"duration"` // in seconds
+	Entries  int    `json:"entries"`
+}
@@ -0,0 +1,83 @@
+package entities
+
+// StatusCategory groups workflow statuses by how far a task has progressed
+type StatusCategory string
+
+const (
+	StatusCategoryTodo   StatusCategory = "todo"
+	StatusCategoryActive StatusCategory = "active"
+	StatusCategoryDone   StatusCategory = "done"
+)
+
+// WorkflowStatus is a status tasks of a project can be in
+type WorkflowStatus struct {
+	Key      TaskStatus     `json:"key"`
+	Name     string         `json:"name"`
+	Category StatusCategory `json:"category"`
+}
+
+// WorkflowTransition allows tasks to move from one status to another
+type WorkflowTransition struct {
+	From TaskStatus `json:"from"`
+	To   TaskStatus `json:"to"`
+}
+
+// Workflow defines the statuses of a project's tasks in board order and the
+// moves between them. New tasks start in the first status. A workflow without
+// transitions allows moving between any two statuses.
+type Workflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions,omitempty"`
+}
+
+// DefaultWorkflow is used by projects that did not define their own
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses: []WorkflowStatus{
+			{Key: TaskStatusTodo, Name: "To Do", Category: StatusCategoryTodo},
+			{Key: TaskStatusInProgress, Name: "In Progress", Category: StatusCategoryActive},
+			{Key: TaskStatusDone, Name: "Done", Category: StatusCategoryDone},
+		},
+	}
+}
+
+// Status looks up a status by key
+func (w Workflow) Status(key TaskStatus) (WorkflowStatus, bool) {
+	for _, status := range w.Statuses {
+		if status.Key == key {
+			return status, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// Initial returns the status new tasks start in
+func (w Workflow) Initial() TaskStatus {
+	if len(w.Statuses) == 0 {
+		return ""
+	}
+	return w.Statuses[0].Key
+}
+
+// Allows reports whether a task may move from one status to another. Staying
+// in the same status is always allowed.
+func (w Workflow) Allows(from, to TaskStatus) bool {
+	if from == to || len(w.Transitions) == 0 {
+		return true
+	}
+	for _, transition := range w.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the status keys in board order
+func (w Workflow) Keys() []TaskStatus {
+	keys := make([]TaskStatus, len(w.Statuses))
+	for i, status := range w.Statuses {
+		keys[i] = status.Key
+	}
+	return keys
+}
@@ -0,0 +1,139 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp           = "UP"
+	StatusDown         = "DOWN"
+	StatusShuttingDown = "SHUTTING_DOWN"
+)
+
+// defaultTimeout applies to checks registered without a timeout
+const defaultTimeout = 2 * time.Second
+
+// CheckFunc reports the health of a dependency. It must respect ctx cancellation.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	timeout  time.Duration
+	fn       CheckFunc
+}
+
+// CheckResult is the outcome of a single check
+type CheckResult struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Duration string `json:"duration"`
+	// Error is only logged; /ready is public and must not reveal dependency details
+	Error string `json:"-"`
+}
+
+// Report is the aggregated readiness of the application. Status is DOWN when
+// any critical check fails; failing non-critical checks are reported but do
+// not affect the overall status.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Healthy reports whether the application can serve traffic
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// Registry holds the readiness checks that components register at startup
+type Registry struct {
+	mu           sync.RWMutex
+	checks       []check
+	shuttingDown atomic.Bool
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check. Critical checks make the application unready when they fail.
+func (r *Registry) Register(name string, critical bool, timeout time.Duration, fn CheckFunc) {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check{name: name, critical: critical, timeout: timeout, fn: fn})
+}
+
+// SetShuttingDown makes every following readiness report fail so that load
+// balancers stop routing new requests before the server starts draining.
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// ShuttingDown reports whether SetShuttingDown was called
+func (r *Registry) ShuttingDown() bool {
+	return r.shuttingDown.Load()
+}
+
+// Run executes all checks concurrently, each bounded by its own timeout
+func (r *Registry) Run(ctx context.Context) Report {
+	report := Report{Status: StatusUp, Checks: map[string]CheckResult{}}
+	if r.ShuttingDown() {
+		report.Status = StatusShuttingDown
+		return report
+	}
+
+	r.mu.RLock()
+	checks := append([]check(nil), r.checks...)
+	r.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if c.critical && results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, c check) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result = CheckResult{Status: StatusUp, Critical: c.critical}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			result.Status = StatusDown
+			result.Error = fmt.Sprintf("check panicked: %v", rec)
+		}
+		result.Duration = time.Since(start).String()
+	}()
+
+	if err := c.fn(ctx); err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
@@ -0,0 +1,113 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		register       func(r *Registry)
+		expectedStatus string
+	}{
+		{
+			name:           "no checks",
+			register:       func(r *Registry) {},
+			expectedStatus: StatusUp,
+		},
+		{
+			name: "all checks pass",
+			register: func(r *Registry) {
+				r.Register("mongodb", true, 0, func(ctx context.Context) error { return nil })
+				r.Register("loki", false, 0, func(ctx context.Context) error { return nil })
+			},
+			expectedStatus: StatusUp,
+		},
+		{
+			name: "critical check fails",
+			register: func(r *Registry) {
+				r.Register("mongodb", true, 0, func(ctx context.Context) error { return errors.New("connection refused") })
+				r.Register("loki", false, 0, func(ctx context.Context) error { return nil })
+			},
+			expectedStatus: StatusDown,
+		},
+		{
+			name: "non-critical check fails",
+			register: func(r *Registry) {
+				r.Register("mongodb", true, 0, func(ctx context.Context) error { return nil })
+				r.Register("loki", false, 0, func(ctx context.Context) error { return errors.New("unreachable") })
+			},
+			expectedStatus: StatusUp,
+		},
+		{
+			name: "critical check times out",
+			register: func(r *Registry) {
+				r.Register("mongodb", true, 10*time.Millisecond, func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+			},
+			expectedStatus: StatusDown,
+		},
+		{
+			name: "panicking check is reported as down",
+			register: func(r *Registry) {
+				r.Register("jwks", true, 0, func(ctx context.Context) error { panic("boom") })
+			},
+			expectedStatus: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := NewRegistry()
+			tt.register(registry)
+
+			report := registry.Run(context.Background())
+
+			if report.Status != tt.expectedStatus {
+				t.Errorf("expected status %s, got %s (checks: %+v)", tt.expectedStatus, report.Status, report.Checks)
+			}
+		})
+	}
+}
+
+func TestRegistry_RunReportsEachCheck(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("mongodb", true, 0, func(ctx context.Context) error { return errors.New("connection refused") })
+	registry.Register("loki", false, 0, func(ctx context.Context) error { return nil })
+
+	report := registry.Run(context.Background())
+
+	mongo, ok := report.Checks["mongodb"]
+	if !ok || mongo.Status != StatusDown || mongo.Error != "connection refused" || !mongo.Critical {
+		t.Errorf("unexpected mongodb result: %+v", mongo)
+	}
+
+	loki, ok := report.Checks["loki"]
+	if !ok || loki.Status != StatusUp || loki.Critical {
+		t.Errorf("unexpected loki result: %+v", loki)
+	}
+}
+
+func TestRegistry_ShuttingDown(t *testing.T) {
+	registry := NewRegistry()
+	called := false
+	registry.Register("mongodb", true, 0, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	registry.SetShuttingDown()
+	report := registry.Run(context.Background())
+
+	if report.Status != StatusShuttingDown || report.Healthy() {
+		t.Errorf("expected shutting down report, got %+v", report)
+	}
+	if called {
+		t.Error("expected checks to be skipped during shutdown")
+	}
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+type contextKey struct{}
+
+// attrSet holds attributes shared by every record logged during one request.
+// It is mutable so that inner middleware (e.g. authentication) can add
+// attributes that outer middleware sees when it logs after the handler returns.
+type attrSet struct {
+	mu    sync.RWMutex
+	attrs []slog.Attr
+}
+
+func (s *attrSet) add(attrs ...slog.Attr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+func (s *attrSet) snapshot() []slog.Attr {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]slog.Attr(nil), s.attrs...)
+}
+
+// ContextWithAttrs returns a context whose log records carry the given attributes
+// in addition to any attributes already carried by ctx.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	set := &attrSet{}
+	if parent, ok := ctx.Value(contextKey{}).(*attrSet); ok {
+		set.attrs = parent.snapshot()
+	}
+	set.add(attrs...)
+	return context.WithValue(ctx, contextKey{}, set)
+}
+
+// AddAttrs adds attributes to the set carried by ctx. It does nothing if ctx
+// was not created with ContextWithAttrs.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	if set, ok := ctx.Value(contextKey{}).(*attrSet); ok {
+		set.add(attrs...)
+	}
+}
+
+// contextHandler adds the attributes carried by the context to every record
+type contextHandler struct {
+	next slog.Handler
+}
+
+// NewContextHandler wraps next so that records logged with a context created by
+// ContextWithAttrs carry the attributes of that context
+func NewContextHandler(next slog.Handler) slog.Handler {
+	return &contextHandler{next: next}
+}
+
+func (h *contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if set, ok := ctx.Value(contextKey{}).(*attrSet); ok {
+		r.AddAttrs(set.snapshot()...)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{next: h.next.WithGroup(name)}
+}
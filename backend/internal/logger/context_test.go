@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestContextHandler_AddsContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := ContextWithAttrs(context.Background(), slog.String("request_id", "abc123"))
+	// Attributes added later are visible through the same context
+	AddAttrs(ctx, slog.String("user_sub", "user-1"))
+
+	logger.InfoContext(ctx, "handled request")
+
+	output := buf.String()
+	for _, expected := range []string{`"request_id":"abc123"`, `"user_sub":"user-1"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %s in output, got %s", expected, output)
+		}
+	}
+}
+
+func TestContextWithAttrs_DoesNotLeakIntoParent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	parent := ContextWithAttrs(context.Background(), slog.String("request_id", "abc123"))
+	child := ContextWithAttrs(parent, slog.String("component", "batch"))
+
+	logger.InfoContext(child, "child")
+	if !strings.Contains(buf.String(), `"request_id":"abc123"`) || !strings.Contains(buf.String(), `"component":"batch"`) {
+		t.Errorf("expected child record to carry inherited and own attributes, got %s", buf.String())
+	}
+
+	buf.Reset()
+	logger.InfoContext(parent, "parent")
+	if strings.Contains(buf.String(), "component") {
+		t.Errorf("expected parent record not to carry child attributes, got %s", buf.String())
+	}
+}
+
+func TestAddAttrs_WithoutSetIsNoop(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx := context.Background()
+	AddAttrs(ctx, slog.String("user_sub", "user-1"))
+	logger.InfoContext(ctx, "plain")
+
+	if strings.Contains(buf.String(), "user_sub") {
+		t.Errorf("expected no context attributes, got %s", buf.String())
+	}
+}
@@ -0,0 +1,259 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ComponentKey is the attribute that binds a logger to a component, e.g.
+// logger.With(ComponentKey, "auth"). Components can have their own level.
+const ComponentKey = "component"
+
+// Levels holds the global log level and optional per-component levels. They
+// can be changed at runtime; temporary changes revert to the configured
+// levels once their TTL expires.
+type Levels struct {
+	global slog.LevelVar
+
+	mu         sync.RWMutex
+	components map[string]slog.Level
+
+	// configured levels that Reset and expired TTLs restore
+	baseGlobal     slog.Level
+	baseComponents map[string]slog.Level
+
+	timers map[string]*revertTimer
+}
+
+// revertTimer resets a level when its TTL expires
+type revertTimer struct {
+	timer *time.Timer
+}
+
+// NewLevels creates the levels from the global level name and a map of
+// component names to level names. Unknown names fall back to info.
+func NewLevels(global string, components map[string]string) *Levels {
+	globalLevel, _ := ParseLevel(global)
+
+	l := &Levels{
+		baseGlobal:     globalLevel,
+		components:     make(map[string]slog.Level),
+		baseComponents: make(map[string]slog.Level),
+		timers:         make(map[string]*revertTimer),
+	}
+	l.global.Set(globalLevel)
+
+	for component, name := range components {
+		level, _ := ParseLevel(name)
+		l.components[component] = level
+		l.baseComponents[component] = level
+	}
+
+	return l
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// Level returns the effective level of a component. Components without their
+// own level, and the empty component, use the global level.
+func (l *Levels) Level(component string) slog.Level {
+	if component != "" {
+		l.mu.RLock()
+		level, ok := l.components[component]
+		l.mu.RUnlock()
+		if ok {
+			return level
+		}
+	}
+	return l.global.Level()
+}
+
+// Set changes the level of a component, or the global level when component
+// is empty. With a positive ttl the change reverts to the configured level
+// after ttl has passed.
+func (l *Levels) Set(component string, level slog.Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopTimer(component)
+	if component == "" {
+		l.global.Set(level)
+	} else {
+		l.components[component] = level
+	}
+
+	if ttl > 0 {
+		revert := &revertTimer{}
+		revert.timer = time.AfterFunc(ttl, func() { l.expire(component, revert) })
+		l.timers[component] = revert
+	}
+}
+
+// expire resets a component when its TTL passes, unless the level was changed
+// again in the meantime. The check and the reset share one lock so that a Set
+// between them is not undone.
+func (l *Levels) expire(component string, revert *revertTimer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.timers[component] == revert {
+		l.reset(component)
+	}
+}
+
+// Reset restores the configured level of a component, or the global level
+// when component is empty
+func (l *Levels) Reset(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reset(component)
+}
+
+// reset is Reset for callers holding the lock
+func (l *Levels) reset(component string) {
+	l.stopTimer(component)
+	if component == "" {
+		l.global.Set(l.baseGlobal)
+		return
+	}
+	if level, ok := l.baseComponents[component]; ok {
+		l.components[component] = level
+	} else {
+		delete(l.components, component)
+	}
+}
+
+// Configure replaces the configured levels, e.g. after a config reload. Levels
+// changed at runtime with a pending TTL are kept until it expires and then
+// revert to the new configured level; all other levels take the new values.
+func (l *Levels) Configure(global string, components map[string]string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.baseGlobal, _ = ParseLevel(global)
+	l.baseComponents = make(map[string]slog.Level, len(components))
+	for component, name := range components {
+		l.baseComponents[component], _ = ParseLevel(name)
+	}
+
+	if _, ok := l.timers[""]; !ok {
+		l.global.Set(l.baseGlobal)
+	}
+	for component := range l.components {
+		if _, ok := l.timers[component]; !ok {
+			delete(l.components, component)
+		}
+	}
+	for component, level := range l.baseComponents {
+		if _, ok := l.timers[component]; !ok {
+			l.components[component] = level
+		}
+	}
+}
+
+// ToggleDebug switches the global level between debug and the configured
+// level and returns the new level
+func (l *Levels) ToggleDebug() slog.Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopTimer("")
+	switch {
+	case l.global.Level() != slog.LevelDebug:
+		l.global.Set(slog.LevelDebug)
+	case l.baseGlobal != slog.LevelDebug:
+		l.global.Set(l.baseGlobal)
+	default:
+		l.global.Set(slog.LevelInfo)
+	}
+	return l.global.Level()
+}
+
+// LevelsSnapshot describes the current levels
+type LevelsSnapshot struct {
+	Global     string            `json:"global"`
+	Components map[string]string `json:"components"`
+}
+
+// Snapshot returns the current levels
+func (l *Levels) Snapshot() LevelsSnapshot {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	snapshot := LevelsSnapshot{
+		Global:     strings.ToLower(l.global.Level().String()),
+		Components: make(map[string]string, len(l.components)),
+	}
+	names := make([]string, 0, len(l.components))
+	for component := range l.components {
+		names = append(names, component)
+	}
+	sort.Strings(names)
+	for _, component := range names {
+		snapshot.Components[component] = strings.ToLower(l.components[component].String())
+	}
+	return snapshot
+}
+
+// stopTimer cancels a pending revert; l.mu must be held
+func (l *Levels) stopTimer(component string) {
+	if revert, ok := l.timers[component]; ok {
+		revert.timer.Stop()
+		delete(l.timers, component)
+	}
+}
+
+// levelHandler filters records by the level of the component the logger is
+// bound to. It must be the outermost handler, since slog.Logger consults only
+// the Enabled method of the handler it was created with.
+type levelHandler struct {
+	next      slog.Handler
+	levels    *Levels
+	component string
+}
+
+func newLevelHandler(next slog.Handler, levels *Levels) *levelHandler {
+	return &levelHandler{next: next, levels: levels}
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.component)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	component := h.component
+	for _, a := range attrs {
+		if a.Key == ComponentKey {
+			component = a.Value.String()
+		}
+	}
+	return &levelHandler{next: h.next.WithAttrs(attrs), levels: h.levels, component: component}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{next: h.next.WithGroup(name), levels: h.levels, component: h.component}
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevels_ComponentOverrides(t *testing.T) {
+	levels := NewLevels("info", map[string]string{"auth": "debug"})
+
+	if levels.Level("") != slog.LevelInfo {
+		t.Errorf("expected global level info, got %s", levels.Level(""))
+	}
+	if levels.Level("auth") != slog.LevelDebug {
+		t.Errorf("expected auth level debug, got %s", levels.Level("auth"))
+	}
+	if levels.Level("http") != slog.LevelInfo {
+		t.Errorf("expected components without a level to follow the global level, got %s", levels.Level("http"))
+	}
+
+	levels.Set("http", slog.LevelError, 0)
+	levels.Set("auth", slog.LevelWarn, 0)
+	levels.Reset("http")
+	levels.Reset("auth")
+
+	if levels.Level("http") != slog.LevelInfo || levels.Level("auth") != slog.LevelDebug {
+		t.Errorf("expected reset to restore configured levels, got %+v", levels.Snapshot())
+	}
+}
+
+func TestLevels_SetWithTTLReverts(t *testing.T) {
+	levels := NewLevels("info", nil)
+
+	levels.Set("", slog.LevelDebug, 20*time.Millisecond)
+	if levels.Level("") != slog.LevelDebug {
+		t.Fatalf("expected debug level, got %s", levels.Level(""))
+	}
+
+	waitFor(t, func() bool { return levels.Level("") == slog.LevelInfo })
+}
+
+func TestLevels_SetCancelsPendingRevert(t *testing.T) {
+	levels := NewLevels("info", nil)
+
+	levels.Set("storage", slog.LevelDebug, 20*time.Millisecond)
+	levels.Set("storage", slog.LevelWarn, 0)
+	time.Sleep(50 * time.Millisecond)
+
+	if levels.Level("storage") != slog.LevelWarn {
+		t.Errorf("expected permanent change to survive the earlier TTL, got %s", levels.Level("storage"))
+	}
+}
+
+func TestLevels_StaleRevertKeepsLevel(t *testing.T) {
+	levels := NewLevels("info", nil)
+
+	levels.Set("storage", slog.LevelDebug, time.Hour)
+	stale := levels.timers["storage"]
+	levels.Set("storage", slog.LevelWarn, 0)
+
+	// A timer that fired while Set replaced it must not undo the new level
+	levels.expire("storage", stale)
+	if levels.Level("storage") != slog.LevelWarn {
+		t.Errorf("expected the later level to survive a stale revert, got %s", levels.Level("storage"))
+	}
+}
+
+func TestLevels_Configure(t *testing.T) {
+	levels := NewLevels("info", map[string]string{"auth": "debug", "http": "debug"})
+	levels.Set("storage", slog.LevelDebug, time.Hour)
+
+	levels.Configure("warn", map[string]string{"auth": "error"})
+
+	if levels.Level("") != slog.LevelWarn {
+		t.Errorf("expected new global level warn, got %s", levels.Level(""))
+	}
+	if levels.Level("auth") != slog.LevelError {
+		t.Errorf("expected new auth level error, got %s", levels.Level("auth"))
+	}
+	if levels.Level("http") != slog.LevelWarn {
+		t.Errorf("expected http to follow the global level after its level was removed, got %s", levels.Level("http"))
+	}
+	if levels.Level("storage") != slog.LevelDebug {
+		t.Errorf("expected the pending TTL override to survive, got %s", levels.Level("storage"))
+	}
+
+	levels.Reset("storage")
+	if levels.Level("storage") != slog.LevelWarn {
+		t.Errorf("expected reset to use the new global level, got %s", levels.Level("storage"))
+	}
+}
+
+func TestLevels_ToggleDebug(t *testing.T) {
+	levels := NewLevels("warn", nil)
+
+	if level := levels.ToggleDebug(); level != slog.LevelDebug {
+		t.Errorf("expected debug after first toggle, got %s", level)
+	}
+	if level := levels.ToggleDebug(); level != slog.LevelWarn {
+		t.Errorf("expected configured level after second toggle, got %s", level)
+	}
+}
+
+func TestLevelHandler_FiltersByComponent(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels("info", map[string]string{"auth": "debug"})
+	logger := slog.New(newLevelHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), levels))
+
+	logger.Debug("global debug")
+	logger.With(ComponentKey, "auth").Debug("auth debug")
+	logger.With(ComponentKey, "http").Debug("http debug")
+
+	output := buf.String()
+	if !strings.Contains(output, "auth debug") {
+		t.Errorf("expected auth debug record, got %s", output)
+	}
+	if strings.Contains(output, "global debug") || strings.Contains(output, "http debug") {
+		t.Errorf("expected debug records of other components to be filtered, got %s", output)
+	}
+
+	// Changes apply to loggers that already exist
+	buf.Reset()
+	httpLogger := logger.With(ComponentKey, "http")
+	levels.Set("http", slog.LevelDebug, 0)
+	httpLogger.DebugContext(context.Background(), "http debug")
+	if !strings.Contains(buf.String(), "http debug") {
+		t.Errorf("expected runtime change to enable http debug, got %s", buf.String())
+	}
+}
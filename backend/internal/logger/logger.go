@@ -2,160 +2,80 @@ package logger
 
 import (
 	"boilerplate/internal/config"
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
-	"strings"
-	"time"
 )
 
-func New(cfg config.LoggingConfig) *slog.Logger {
-	level := parseLevel(cfg.Level)
+// CloseFunc flushes buffered log records and releases the log shippers
+type CloseFunc func(ctx context.Context) error
 
+// New creates the application logger. The returned Levels allow changing the
+// global and per-component levels at runtime. The returned CloseFunc must be
+// called on shutdown so that records still queued for Loki are delivered.
+func New(cfg config.LoggingConfig) (*slog.Logger, *Levels, CloseFunc) {
+	levels := NewLevels(cfg.Level, cfg.Components)
+
+	// The output handlers accept every level; filtering happens in the level
+	// handler so that components can log below the global level
 	var handler slog.Handler
 
 	if cfg.Format == "json" {
 		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: level,
+			Level: slog.LevelDebug,
 		})
 	} else {
 		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: level,
+			Level: slog.LevelDebug,
 		})
 	}
 
+	closeFunc := CloseFunc(func(context.Context) error { return nil })
+
 	// If Loki is configured, wrap with Loki handler
 	if cfg.LokiConfig != nil && cfg.LokiConfig.URL != "" {
-		handler = NewLokiHandler(handler, cfg.LokiConfig)
-	}
-
-	return slog.New(handler)
-}
-
-func parseLevel(level string) slog.Level {
-	switch strings.ToLower(level) {
-	case "debug":
-		return slog.LevelDebug
-	case "info":
-		return slog.LevelInfo
-	case "warn", "warning":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
-	}
-}
-
-type LokiHandler struct {
-	base   slog.Handler
-	config *config.LokiConfig
-	client *http.Client
-}
-
-func NewLokiHandler(base slog.Handler, cfg *config.LokiConfig) *LokiHandler {
-	return &LokiHandler{
-		base:   base,
-		config: cfg,
-		client: &http.Client{
-			Timeout: 5 * time.Second,
-		},
+		lokiHandler := NewLokiHandler(handler, cfg.LokiConfig)
+		handler = lokiHandler
+		closeFunc = lokiHandler.Close
 	}
-}
 
-func (h *LokiHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	return h.base.Enabled(ctx, level)
-}
-
-func (h *LokiHandler) Handle(ctx context.Context, r slog.Record) error {
-	// First, let the base handler handle the record (for local logging)
-	if err := h.base.Handle(ctx, r); err != nil {
-		return err
+	// Redact sensitive data before it reaches any output. It sits below the
+	// trace and context handlers so that request-scoped attributes are covered too.
+	if cfg.Redaction.Enabled {
+		handler = newRedactHandler(handler, cfg.Redaction)
 	}
 
-	// Then, send to Loki asynchronously (don't block on errors)
-	go h.sendToLoki(r)
-
-	return nil
-}
+	// Attach trace and span IDs and request-scoped attributes (request ID, user, route)
+	// to records logged with a context
+	handler = newTraceHandler(handler)
+	handler = NewContextHandler(handler)
+	handler = newLevelHandler(handler, levels)
 
-func (h *LokiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &LokiHandler{
-		base:   h.base.WithAttrs(attrs),
-		config: h.config,
-		client: h.client,
-	}
+	return slog.New(handler), levels, closeFunc
 }
 
-func (h *LokiHandler) WithGroup(name string) slog.Handler {
-	return &LokiHandler{
-		base:   h.base.WithGroup(name),
-		config: h.config,
-		client: h.client,
-	}
-}
-
-func (h *LokiHandler) sendToLoki(r slog.Record) {
-	labels := map[string]string{
-		"level": r.Level.String(),
-		"app":   "boilerplate",
-	}
-
-	attrs := make(map[string]interface{})
-	r.Attrs(func(a slog.Attr) bool {
-		attrs[a.Key] = a.Value.Any()
-		return true
-	})
-
-	logLine := map[string]interface{}{
-		"message": r.Message,
-		"attrs":   attrs,
-	}
-
-	logLineJSON, err := json.Marshal(logLine)
+// CheckLoki reports whether the Loki instance configured in cfg is ready to accept logs
+func CheckLoki(ctx context.Context, cfg *config.LokiConfig) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.URL+"/ready", nil)
 	if err != nil {
-		return
-	}
-
-	payload := map[string]interface{}{
-		"streams": []map[string]interface{}{
-			{
-				"stream": labels,
-				"values": [][]string{
-					{
-						fmt.Sprintf("%d", r.Time.UnixNano()),
-						string(logLineJSON),
-					},
-				},
-			},
-		},
+		return fmt.Errorf("failed to create Loki request: %w", err)
 	}
-
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		return
+	if cfg.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.BearerToken)
 	}
 
-	req, err := http.NewRequest("POST", h.config.URL+"/loki/api/v1/push", bytes.NewBuffer(payloadJSON))
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if h.config.BearerToken != "" {
-		req.Header.Set("Authorization", "Bearer "+h.config.BearerToken)
-	}
-
-	resp, err := h.client.Do(req)
-	if err != nil {
-		return
+		return fmt.Errorf("failed to reach Loki: %w", err)
 	}
 	defer resp.Body.Close()
-
 	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("loki returned status %d", resp.StatusCode)
+	}
+	return nil
 }
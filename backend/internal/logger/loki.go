@@ -0,0 +1,547 @@
+package logger
+
+import (
+	"boilerplate/internal/config"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults for Loki settings left empty in the configuration
+const (
+	defaultLokiBatchSize     = 500
+	defaultLokiBatchInterval = 1 // seconds
+	defaultLokiQueueSize     = 10000
+	defaultLokiMaxRetries    = 5
+	defaultLokiTimeout       = 5 // seconds
+
+	lokiMinBackoff = 100 * time.Millisecond
+	lokiMaxBackoff = 5 * time.Second
+)
+
+// lokiEntry is a single log line waiting to be pushed
+type lokiEntry struct {
+	labels map[string]string
+	ts     time.Time
+	line   string
+}
+
+// LokiHandler ships log records to Loki in addition to the base handler.
+// Records are queued in a bounded buffer and pushed in gzip-compressed batches
+// by a single background worker; records that do not fit into the queue or
+// cannot be delivered after all retries are dropped and counted.
+type LokiHandler struct {
+	base   slog.Handler
+	client *lokiClient
+	// goas holds the groups and attributes bound through WithGroup and WithAttrs, in order
+	goas []groupOrAttrs
+}
+
+// groupOrAttrs is either a group opened with WithGroup or attributes added with WithAttrs
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+func NewLokiHandler(base slog.Handler, cfg *config.LokiConfig) *LokiHandler {
+	return &LokiHandler{
+		base:   base,
+		client: newLokiClient(cfg),
+	}
+}
+
+func (h *LokiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+func (h *LokiHandler) Handle(ctx context.Context, r slog.Record) error {
+	// First, let the base handler handle the record (for local logging)
+	if err := h.base.Handle(ctx, r); err != nil {
+		return err
+	}
+
+	// Then queue it for Loki without blocking the caller
+	fields := h.fields(r)
+	line, err := json.Marshal(fields)
+	if err != nil {
+		h.client.dropped.Add(1)
+		return nil
+	}
+	h.client.enqueue(lokiEntry{
+		labels: h.client.streamLabels(r.Level, fields),
+		ts:     r.Time,
+		line:   string(line),
+	})
+
+	return nil
+}
+
+func (h *LokiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(h.base.WithAttrs(attrs), groupOrAttrs{attrs: attrs})
+}
+
+func (h *LokiHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(h.base.WithGroup(name), groupOrAttrs{group: name})
+}
+
+func (h *LokiHandler) with(base slog.Handler, goa groupOrAttrs) *LokiHandler {
+	goas := make([]groupOrAttrs, len(h.goas), len(h.goas)+1)
+	copy(goas, h.goas)
+	return &LokiHandler{
+		base:   base,
+		client: h.client,
+		goas:   append(goas, goa),
+	}
+}
+
+// Flush pushes all queued records and waits until they are delivered or dropped
+func (h *LokiHandler) Flush(ctx context.Context) error {
+	return h.client.flush(ctx)
+}
+
+// Close flushes the queue and stops the background worker. Records logged
+// afterwards are only written to the base handler. If ctx expires first,
+// pending pushes are aborted and the remaining records are dropped.
+func (h *LokiHandler) Close(ctx context.Context) error {
+	return h.client.close(ctx)
+}
+
+// Dropped returns the number of records that never reached Loki
+func (h *LokiHandler) Dropped() uint64 {
+	return h.client.dropped.Load()
+}
+
+// fields builds the log line of a record in the same shape slog.JSONHandler
+// writes it: built-in time, level and msg keys followed by the pre-bound and
+// record attributes, nested under the groups that were open when they were added.
+func (h *LokiHandler) fields(r slog.Record) map[string]interface{} {
+	root := map[string]interface{}{
+		slog.LevelKey:   r.Level.String(),
+		slog.MessageKey: r.Message,
+	}
+	if !r.Time.IsZero() {
+		root[slog.TimeKey] = r.Time
+	}
+
+	current := root
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			group := map[string]interface{}{}
+			current[goa.group] = group
+			current = group
+			continue
+		}
+		addAttrs(current, goa.attrs)
+	}
+
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(current, a)
+		return true
+	})
+
+	pruneEmptyGroups(root)
+	return root
+}
+
+func addAttrs(dst map[string]interface{}, attrs []slog.Attr) {
+	for _, a := range attrs {
+		addAttr(dst, a)
+	}
+}
+
+// addAttr adds a to dst following the slog handler rules: LogValuers are
+// resolved, empty attributes are ignored and groups without a key are inlined
+func addAttr(dst map[string]interface{}, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key == "" {
+			addAttrs(dst, attrs)
+			return
+		}
+		group := map[string]interface{}{}
+		addAttrs(group, attrs)
+		dst[a.Key] = group
+	case slog.KindDuration:
+		// slog.JSONHandler writes durations as nanoseconds
+		dst[a.Key] = int64(a.Value.Duration())
+	case slog.KindAny:
+		v := a.Value.Any()
+		if err, ok := v.(error); ok {
+			if _, isMarshaler := v.(json.Marshaler); !isMarshaler {
+				v = err.Error()
+			}
+		}
+		dst[a.Key] = v
+	default:
+		dst[a.Key] = a.Value.Any()
+	}
+}
+
+// pruneEmptyGroups removes groups that ended up without attributes, which
+// slog.JSONHandler omits as well. It reports whether m is empty afterwards.
+func pruneEmptyGroups(m map[string]interface{}) bool {
+	for k, v := range m {
+		if group, ok := v.(map[string]interface{}); ok && pruneEmptyGroups(group) {
+			delete(m, k)
+		}
+	}
+	return len(m) == 0
+}
+
+// lookupField returns the value at a dot-separated path such as "http.route"
+func lookupField(fields map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	current := fields
+	for i, key := range keys {
+		v, ok := current[key]
+		if !ok {
+			return nil, false
+		}
+		if i == len(keys)-1 {
+			return v, true
+		}
+		if current, ok = v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// labelName turns an attribute path into a valid Loki label name
+func labelName(path string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, path)
+}
+
+// lokiClient owns the queue and the worker that batches and pushes records.
+// It is shared by all handlers derived through WithAttrs and WithGroup.
+type lokiClient struct {
+	pushURL       string
+	bearerToken   string
+	labels        map[string]string
+	labelAttrs    []string
+	batchSize     int
+	batchInterval time.Duration
+	maxRetries    int
+	httpClient    *http.Client
+
+	queue    chan lokiEntry
+	flushReq chan chan struct{}
+	stop     chan struct{}
+	stopped  chan struct{}
+
+	// ctx is cancelled when Close gives up waiting, aborting in-flight pushes
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+	dropped   atomic.Uint64
+}
+
+func newLokiClient(cfg *config.LokiConfig) *lokiClient {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := &lokiClient{
+		pushURL:       strings.TrimSuffix(cfg.URL, "/") + "/loki/api/v1/push",
+		bearerToken:   cfg.BearerToken,
+		labels:        lokiLabels(cfg.Labels),
+		labelAttrs:    cfg.LabelAttrs,
+		batchSize:     valueOrDefault(cfg.BatchSize, defaultLokiBatchSize),
+		batchInterval: time.Duration(valueOrDefault(cfg.BatchInterval, defaultLokiBatchInterval)) * time.Second,
+		maxRetries:    valueOrDefault(cfg.MaxRetries, defaultLokiMaxRetries),
+		httpClient: &http.Client{
+			Timeout: time.Duration(valueOrDefault(cfg.Timeout, defaultLokiTimeout)) * time.Second,
+		},
+		queue:    make(chan lokiEntry, valueOrDefault(cfg.QueueSize, defaultLokiQueueSize)),
+		flushReq: make(chan chan struct{}),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+
+	go c.run()
+
+	return c
+}
+
+func valueOrDefault(value, fallback int) int {
+	if value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// lokiLabels returns the static stream labels. The service and host labels
+// are filled in when not configured so streams of several replicas stay apart.
+func lokiLabels(configured map[string]string) map[string]string {
+	labels := map[string]string{"service": "boilerplate"}
+	if host, err := os.Hostname(); err == nil {
+		labels["host"] = host
+	}
+	for k, v := range configured {
+		labels[k] = v
+	}
+	return labels
+}
+
+// streamLabels returns the labels of the stream a record belongs to: the static
+// labels, the level and the values of the attributes promoted to labels
+func (c *lokiClient) streamLabels(level slog.Level, fields map[string]interface{}) map[string]string {
+	labels := make(map[string]string, len(c.labels)+len(c.labelAttrs)+1)
+	for k, v := range c.labels {
+		labels[k] = v
+	}
+	labels["level"] = strings.ToLower(level.String())
+
+	for _, path := range c.labelAttrs {
+		v, ok := lookupField(fields, path)
+		if !ok {
+			continue
+		}
+		if _, isGroup := v.(map[string]interface{}); isGroup {
+			continue
+		}
+		labels[labelName(path)] = fmt.Sprint(v)
+	}
+	return labels
+}
+
+// enqueue adds an entry without blocking; it is dropped when the queue is full
+func (c *lokiClient) enqueue(e lokiEntry) {
+	if c.closed.Load() {
+		c.dropped.Add(1)
+		return
+	}
+
+	select {
+	case c.queue <- e:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+func (c *lokiClient) run() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.batchInterval)
+	defer ticker.Stop()
+
+	batch := make([]lokiEntry, 0, c.batchSize)
+	for {
+		select {
+		case e := <-c.queue:
+			batch = append(batch, e)
+			if len(batch) >= c.batchSize {
+				c.push(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			c.push(batch)
+			batch = batch[:0]
+		case ack := <-c.flushReq:
+			c.push(c.drain(batch))
+			batch = batch[:0]
+			close(ack)
+		case <-c.stop:
+			c.push(c.drain(batch))
+			return
+		}
+	}
+}
+
+// drain appends all currently queued entries to batch
+func (c *lokiClient) drain(batch []lokiEntry) []lokiEntry {
+	for {
+		select {
+		case e := <-c.queue:
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+// push sends entries in chunks of at most batchSize records
+func (c *lokiClient) push(entries []lokiEntry) {
+	for len(entries) > 0 {
+		n := min(len(entries), c.batchSize)
+		if err := c.pushWithRetry(entries[:n]); err != nil {
+			c.dropped.Add(uint64(n))
+			// The logger itself cannot be used here without feeding the failure back into Loki
+			fmt.Fprintf(os.Stderr, "loki: dropped %d log records: %v\n", n, err)
+		}
+		entries = entries[n:]
+	}
+}
+
+func (c *lokiClient) pushWithRetry(entries []lokiEntry) error {
+	body, err := encodeLokiPush(entries)
+	if err != nil {
+		return err
+	}
+
+	backoff := lokiMinBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := c.send(body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= c.maxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-c.ctx.Done():
+			return err
+		}
+		backoff = min(backoff*2, lokiMaxBackoff)
+	}
+}
+
+// send performs a single push and reports whether a failure is worth retrying
+func (c *lokiClient) send(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, c.pushURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return c.ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+
+	// Rate limiting and server errors are transient, other client errors are not
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("loki returned status %d", resp.StatusCode)
+}
+
+// encodeLokiPush groups entries by label set into streams and gzips the push payload
+func encodeLokiPush(entries []lokiEntry) ([]byte, error) {
+	type stream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+
+	var streams []*stream
+	byKey := make(map[string]*stream)
+	for _, e := range entries {
+		key := labelKey(e.labels)
+		s, ok := byKey[key]
+		if !ok {
+			s = &stream{Stream: e.labels}
+			byKey[key] = s
+			streams = append(streams, s)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.ts.UnixNano(), 10), e.line})
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(map[string]interface{}{"streams": streams}); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// labelKey returns a canonical representation of a label set
+func labelKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(labels[k])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func (c *lokiClient) flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case c.flushReq <- ack:
+	case <-c.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *lokiClient) close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		c.closed.Store(true)
+		close(c.stop)
+	})
+
+	select {
+	case <-c.stopped:
+		c.cancel()
+		return nil
+	case <-ctx.Done():
+		c.cancel()
+		<-c.stopped
+		return ctx.Err()
+	}
+}
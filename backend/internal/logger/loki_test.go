@@ -0,0 +1,338 @@
+package logger
+
+import (
+	"boilerplate/internal/config"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lokiPush struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	} `json:"streams"`
+}
+
+// lokiStub is an httptest Loki that records decoded pushes. Status codes in
+// failures are returned for the first requests before it starts accepting.
+type lokiStub struct {
+	mu       sync.Mutex
+	pushes   []lokiPush
+	requests int
+	failures []int
+	server   *httptest.Server
+	t        *testing.T
+}
+
+func newLokiStub(t *testing.T, failures ...int) *lokiStub {
+	stub := &lokiStub{failures: failures, t: t}
+	stub.server = httptest.NewServer(http.HandlerFunc(stub.handle))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *lokiStub) handle(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/loki/api/v1/push" || r.Header.Get("Content-Encoding") != "gzip" {
+		s.t.Errorf("unexpected request %s with encoding %q", r.URL.Path, r.Header.Get("Content-Encoding"))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+	if len(s.failures) > 0 {
+		status := s.failures[0]
+		s.failures = s.failures[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	gz, err := gzip.NewReader(r.Body)
+	if err != nil {
+		s.t.Errorf("failed to open gzip body: %v", err)
+		return
+	}
+	var push lokiPush
+	if err := json.NewDecoder(gz).Decode(&push); err != nil {
+		s.t.Errorf("failed to decode push: %v", err)
+		return
+	}
+	s.pushes = append(s.pushes, push)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *lokiStub) values() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, push := range s.pushes {
+		for _, stream := range push.Streams {
+			n += len(stream.Values)
+		}
+	}
+	return n
+}
+
+func (s *lokiStub) pushCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pushes)
+}
+
+func newTestLokiLogger(cfg *config.LokiConfig) (*slog.Logger, *LokiHandler) {
+	handler := NewLokiHandler(slog.NewJSONHandler(io.Discard, nil), cfg)
+	return slog.New(handler), handler
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLokiHandler_BatchesBySize(t *testing.T) {
+	stub := newLokiStub(t)
+	logger, handler := newTestLokiLogger(&config.LokiConfig{URL: stub.server.URL, BatchSize: 3, BatchInterval: 60})
+	defer handler.Close(context.Background())
+
+	for i := 0; i < 3; i++ {
+		logger.Info("record", "i", i)
+	}
+
+	waitFor(t, func() bool { return stub.values() == 3 })
+	if stub.pushCount() != 1 {
+		t.Errorf("expected a single push, got %d", stub.pushCount())
+	}
+}
+
+func TestLokiHandler_BatchesByInterval(t *testing.T) {
+	stub := newLokiStub(t)
+	logger, handler := newTestLokiLogger(&config.LokiConfig{URL: stub.server.URL, BatchSize: 100, BatchInterval: 1})
+	defer handler.Close(context.Background())
+
+	logger.Info("first")
+	logger.Info("second")
+
+	waitFor(t, func() bool { return stub.values() == 2 })
+}
+
+func TestLokiHandler_RetriesTransientFailures(t *testing.T) {
+	stub := newLokiStub(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+	logger, handler := newTestLokiLogger(&config.LokiConfig{URL: stub.server.URL, MaxRetries: 3})
+
+	logger.Info("record")
+	if err := handler.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+
+	if stub.values() != 1 {
+		t.Errorf("expected record to be delivered after retries, got %d values", stub.values())
+	}
+	if handler.Dropped() != 0 {
+		t.Errorf("expected no dropped records, got %d", handler.Dropped())
+	}
+	handler.Close(context.Background())
+}
+
+func TestLokiHandler_DropsAfterPermanentFailure(t *testing.T) {
+	stub := newLokiStub(t, http.StatusBadRequest)
+	logger, handler := newTestLokiLogger(&config.LokiConfig{URL: stub.server.URL, MaxRetries: 3})
+
+	logger.Info("first")
+	logger.Info("second")
+	handler.Flush(context.Background())
+
+	if handler.Dropped() != 2 {
+		t.Errorf("expected 2 dropped records, got %d", handler.Dropped())
+	}
+	if stub.requests != 1 {
+		t.Errorf("expected client errors not to be retried, got %d requests", stub.requests)
+	}
+	handler.Close(context.Background())
+}
+
+func TestLokiHandler_DropsWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	logger, handler := newTestLokiLogger(&config.LokiConfig{URL: server.URL, BatchSize: 1, QueueSize: 1})
+
+	// The worker blocks on the first push, so at most one more record fits into the queue
+	for i := 0; i < 5; i++ {
+		logger.Info("record", "i", i)
+	}
+	close(release)
+	handler.Close(context.Background())
+
+	if handler.Dropped() < 3 {
+		t.Errorf("expected at least 3 dropped records, got %d", handler.Dropped())
+	}
+}
+
+func TestLokiHandler_CloseDeliversPendingRecords(t *testing.T) {
+	stub := newLokiStub(t)
+	logger, handler := newTestLokiLogger(&config.LokiConfig{
+		URL:           stub.server.URL,
+		BatchInterval: 60,
+		Labels:        map[string]string{"env": "test", "host": "node-1"},
+	})
+
+	logger.Info("info record")
+	logger.Error("error record")
+
+	if err := handler.Close(context.Background()); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	if stub.values() != 2 {
+		t.Fatalf("expected 2 records after close, got %d", stub.values())
+	}
+
+	levels := map[string]bool{}
+	for _, stream := range stub.pushes[0].Streams {
+		labels := stream.Stream
+		if labels["service"] != "boilerplate" || labels["env"] != "test" || labels["host"] != "node-1" {
+			t.Errorf("unexpected stream labels: %v", labels)
+		}
+		levels[labels["level"]] = true
+	}
+	if !levels["info"] || !levels["error"] {
+		t.Errorf("expected separate info and error streams, got %v", levels)
+	}
+
+	// Records logged after close are dropped instead of blocking
+	logger.Info("late record")
+	if handler.Dropped() != 1 {
+		t.Errorf("expected late record to be dropped, got %d", handler.Dropped())
+	}
+}
+
+// userValue resolves to a group through slog.LogValuer
+type userValue struct{ id, name string }
+
+func (u userValue) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("id", u.id), slog.String("name", u.name))
+}
+
+func TestLokiHandler_MatchesJSONHandlerOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(logger *slog.Logger)
+	}{
+		{
+			name: "record attributes",
+			log: func(logger *slog.Logger) {
+				logger.Info("plain", "count", 3, "ratio", 0.5, "ok", true, "took", 1500*time.Millisecond)
+			},
+		},
+		{
+			name: "pre-bound attributes",
+			log: func(logger *slog.Logger) {
+				logger.With("logger", "auth", "version", 2).Warn("bound", "user", "alice")
+			},
+		},
+		{
+			name: "nested groups",
+			log: func(logger *slog.Logger) {
+				logger.With("service", "api").WithGroup("http").With("method", "GET").WithGroup("response").
+					Info("nested", "status", 200)
+			},
+		},
+		{
+			name: "empty groups are omitted",
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("empty").Info("no attrs")
+			},
+		},
+		{
+			name: "inline and explicit group attributes",
+			log: func(logger *slog.Logger) {
+				logger.Info("groups",
+					slog.Group("", slog.String("inlined", "yes")),
+					slog.Group("request", slog.String("id", "abc"), slog.Group("empty")),
+				)
+			},
+		},
+		{
+			name: "log valuers and errors",
+			log: func(logger *slog.Logger) {
+				logger.Error("failed", "user", userValue{id: "1", name: "alice"}, "error", io.ErrUnexpectedEOF)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newLokiStub(t)
+			var buf bytes.Buffer
+			handler := NewLokiHandler(slog.NewJSONHandler(&buf, nil), &config.LokiConfig{URL: stub.server.URL})
+
+			tt.log(slog.New(handler))
+			if err := handler.Close(context.Background()); err != nil {
+				t.Fatalf("unexpected close error: %v", err)
+			}
+
+			var expected, actual map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &expected); err != nil {
+				t.Fatalf("failed to decode JSON handler output: %v", err)
+			}
+			if stub.values() != 1 {
+				t.Fatalf("expected a single Loki record, got %d", stub.values())
+			}
+			line := stub.pushes[0].Streams[0].Values[0][1]
+			if err := json.Unmarshal([]byte(line), &actual); err != nil {
+				t.Fatalf("failed to decode Loki line: %v", err)
+			}
+
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("Loki line differs from JSON handler output\nexpected: %s\nactual:   %s", buf.String(), line)
+			}
+		})
+	}
+}
+
+func TestLokiHandler_PromotesAttributesToLabels(t *testing.T) {
+	stub := newLokiStub(t)
+	_, handler := newTestLokiLogger(&config.LokiConfig{
+		URL:        stub.server.URL,
+		LabelAttrs: []string{"logger", "http.route", "missing"},
+	})
+	logger := slog.New(NewContextHandler(handler))
+
+	ctx := ContextWithAttrs(context.Background(), slog.String("request_id", "abc"))
+	logger.With("logger", "auth").WithGroup("http").InfoContext(ctx, "request", "route", "GET /api/v1/projects")
+	handler.Close(context.Background())
+
+	if stub.values() != 1 {
+		t.Fatalf("expected a single Loki record, got %d", stub.values())
+	}
+	labels := stub.pushes[0].Streams[0].Stream
+	if labels["logger"] != "auth" || labels["http_route"] != "GET /api/v1/projects" || labels["level"] != "info" {
+		t.Errorf("unexpected stream labels: %v", labels)
+	}
+	if _, ok := labels["missing"]; ok {
+		t.Errorf("expected absent attributes not to become labels: %v", labels)
+	}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"boilerplate/internal/config"
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newRedactingLogger(buf *bytes.Buffer, cfg config.RedactionConfig) *slog.Logger {
+	return slog.New(newRedactHandler(slog.NewJSONHandler(buf, nil), cfg))
+}
+
+func TestRedactHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.RedactionConfig
+		log         func(logger *slog.Logger)
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name: "sensitive keys",
+			log: func(logger *slog.Logger) {
+				logger.Info("login", "password", "hunter2", "access_token", "abc", "Authorization", "Bearer xyz", "user", "alice")
+			},
+			expected:    []string{`"password":"[REDACTED]"`, `"access_token":"[REDACTED]"`, `"Authorization":"[REDACTED]"`, `"user":"alice"`},
+			notExpected: []string{"hunter2", "abc", "xyz"},
+		},
+		{
+			name: "configured keys replace the defaults",
+			cfg:  config.RedactionConfig{Keys: []string{"ssn"}},
+			log: func(logger *slog.Logger) {
+				logger.Info("profile", "user_ssn", "123-45-6789", "password", "visible")
+			},
+			expected:    []string{`"user_ssn":"[REDACTED]"`, `"password":"visible"`},
+			notExpected: []string{"123-45-6789"},
+		},
+		{
+			name: "URI userinfo",
+			log: func(logger *slog.Logger) {
+				logger.Info("connecting to MongoDB", "uri", "mongodb://admin:s3cret@db:27017/boilerplate")
+			},
+			expected:    []string{`"uri":"mongodb://db:27017/boilerplate"`},
+			notExpected: []string{"admin", "s3cret"},
+		},
+		{
+			name: "errors and messages",
+			log: func(logger *slog.Logger) {
+				logger.Error("dial mongodb://admin:s3cret@db failed", "error", errors.New("request with Bearer eyJhbGciOi.abc rejected"))
+			},
+			expected:    []string{`"msg":"dial mongodb://db failed"`, `"error":"request with Bearer [REDACTED] rejected"`},
+			notExpected: []string{"s3cret", "eyJhbGciOi"},
+		},
+		{
+			name: "email masking",
+			cfg:  config.RedactionConfig{MaskEmails: true},
+			log: func(logger *slog.Logger) {
+				logger.Info("invited", "email", "alice@example.com")
+			},
+			expected:    []string{`"email":"a***@example.com"`},
+			notExpected: []string{"alice@"},
+		},
+		{
+			name: "emails kept when masking is disabled",
+			log: func(logger *slog.Logger) {
+				logger.Info("invited", "email", "alice@example.com")
+			},
+			expected: []string{`"email":"alice@example.com"`},
+		},
+		{
+			name: "pre-bound attributes and groups",
+			log: func(logger *slog.Logger) {
+				logger.With("client_secret", "abc").WithGroup("db").Info("config",
+					slog.Group("credentials", slog.String("username", "admin"), slog.String("password", "hunter2")))
+			},
+			expected:    []string{`"client_secret":"[REDACTED]"`, `"username":"admin"`, `"password":"[REDACTED]"`},
+			notExpected: []string{"abc", "hunter2"},
+		},
+		{
+			name: "log valuers are resolved before redaction",
+			log: func(logger *slog.Logger) {
+				logger.Info("user", "account", credentialsValue{user: "admin", password: "hunter2"})
+			},
+			expected:    []string{`"password":"[REDACTED]"`},
+			notExpected: []string{"hunter2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(newRedactingLogger(&buf, tt.cfg))
+
+			output := buf.String()
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("expected %s in output, got %s", expected, output)
+				}
+			}
+			for _, notExpected := range tt.notExpected {
+				if strings.Contains(output, notExpected) {
+					t.Errorf("expected %s to be redacted, got %s", notExpected, output)
+				}
+			}
+		})
+	}
+}
+
+type credentialsValue struct{ user, password string }
+
+func (c credentialsValue) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("user", c.user), slog.String("password", c.password))
+}
+
+func TestRedactHandler_CoversContextAttrsAndLoki(t *testing.T) {
+	stub := newLokiStub(t)
+	var buf bytes.Buffer
+
+	handler := NewLokiHandler(slog.NewJSONHandler(&buf, nil), &config.LokiConfig{URL: stub.server.URL})
+	logger := slog.New(NewContextHandler(newRedactHandler(handler, config.RedactionConfig{})))
+
+	ctx := ContextWithAttrs(context.Background(), slog.String("refresh_token", "abc123"))
+	logger.InfoContext(ctx, "request")
+	handler.Close(context.Background())
+
+	if strings.Contains(buf.String(), "abc123") {
+		t.Errorf("expected context attribute to be redacted in stdout output, got %s", buf.String())
+	}
+	if stub.values() != 1 || strings.Contains(stub.pushes[0].Streams[0].Values[0][1], "abc123") {
+		t.Errorf("expected context attribute to be redacted in Loki output, got %+v", stub.pushes)
+	}
+}
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceHandler_AddsTraceAndSpanIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newTraceHandler(slog.NewJSONHandler(&buf, nil)))
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	logger.InfoContext(ctx, "traced message")
+
+	output := buf.String()
+	if !strings.Contains(output, `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`) {
+		t.Errorf("expected trace_id in output, got %s", output)
+	}
+	if !strings.Contains(output, `"span_id":"00f067aa0ba902b7"`) {
+		t.Errorf("expected span_id in output, got %s", output)
+	}
+}
+
+func TestTraceHandler_WithoutSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newTraceHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.InfoContext(context.Background(), "untraced message")
+
+	if strings.Contains(buf.String(), "trace_id") {
+		t.Errorf("expected no trace_id without an active span, got %s", buf.String())
+	}
+}
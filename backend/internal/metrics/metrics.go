@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "boilerplate"
+
+// Metrics holds the Prometheus collectors of the application.
+// All recording methods are safe to call on a nil *Metrics, which makes
+// metrics optional for every component that receives one.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	rateLimitRejections prometheus.Counter
+	authFailures        *prometheus.CounterVec
+	jwksRefreshes       *prometheus.CounterVec
+	mongoCommands       *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests by route pattern, method and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route pattern, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		rateLimitRejections: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "rate_limit_rejections_total",
+			Help:      "Total number of requests rejected by the rate limiter.",
+		}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "failures_total",
+			Help:      "Total number of rejected authentication attempts by reason.",
+		}, []string{"reason"}),
+		jwksRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "auth",
+			Name:      "jwks_refreshes_total",
+			Help:      "Total number of JWKS refresh attempts by outcome.",
+		}, []string{"outcome"}),
+		mongoCommands: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "mongodb",
+			Name:      "command_duration_seconds",
+			Help:      "MongoDB command latency by command name and outcome.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.rateLimitRejections,
+		m.authFailures,
+		m.jwksRefreshes,
+		m.mongoCommands,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler that exposes the metrics in Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a finished HTTP request. Route must be the matched
+// route pattern rather than the raw path to keep label cardinality bounded.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	statusLabel := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, statusLabel).Inc()
+	m.httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// RateLimitRejected records a request rejected by the rate limiter
+func (m *Metrics) RateLimitRejected() {
+	if m == nil {
+		return
+	}
+	m.rateLimitRejections.Inc()
+}
+
+// AuthFailed records a rejected authentication attempt
+func (m *Metrics) AuthFailed(reason string) {
+	if m == nil {
+		return
+	}
+	m.authFailures.WithLabelValues(reason).Inc()
+}
+
+// JWKSRefreshed records the outcome ("success" or "error") of a JWKS refresh
+func (m *Metrics) JWKSRefreshed(outcome string) {
+	if m == nil {
+		return
+	}
+	m.jwksRefreshes.WithLabelValues(outcome).Inc()
+}
+
+// CommandMonitor returns a MongoDB driver monitor that records command latency
+func (m *Metrics) CommandMonitor() *event.CommandMonitor {
+	if m == nil {
+		return nil
+	}
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			m.mongoCommands.WithLabelValues(evt.CommandName, "success").Observe(evt.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			m.mongoCommands.WithLabelValues(evt.CommandName, "error").Observe(evt.Duration.Seconds())
+		},
+	}
+}
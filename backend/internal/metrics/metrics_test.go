@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetrics_Exposition(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTPRequest(http.MethodGet, "/api/v1/tasks/{id}", http.StatusOK, 25*time.Millisecond)
+	m.RateLimitRejected()
+	m.AuthFailed("expired")
+	m.JWKSRefreshed("success")
+
+	monitor := m.CommandMonitor()
+	monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: 2 * time.Millisecond},
+	})
+
+	body := scrape(t, m)
+
+	expected := []string{
+		`boilerplate_http_requests_total{method="GET",route="/api/v1/tasks/{id}",status="200"} 1`,
+		`boilerplate_http_request_duration_seconds_count{method="GET",route="/api/v1/tasks/{id}",status="200"} 1`,
+		`boilerplate_http_rate_limit_rejections_total 1`,
+		`boilerplate_auth_failures_total{reason="expired"} 1`,
+		`boilerplate_auth_jwks_refreshes_total{outcome="success"} 1`,
+		`boilerplate_mongodb_command_duration_seconds_count{command="find",outcome="success"} 1`,
+		`go_goroutines`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected metrics output to contain %q", line)
+		}
+	}
+}
+
+func TestMetrics_NilIsNoop(t *testing.T) {
+	var m *Metrics
+
+	// None of these may panic when metrics are disabled
+	m.ObserveHTTPRequest(http.MethodGet, "/", http.StatusOK, time.Millisecond)
+	m.RateLimitRejected()
+	m.AuthFailed("expired")
+	m.JWKSRefreshed("error")
+
+	if m.CommandMonitor() != nil {
+		t.Error("expected nil command monitor for disabled metrics")
+	}
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/entities"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// EmailChannel sends notifications as plain text emails through an SMTP server
+type EmailChannel struct {
+	host string
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewEmailChannel creates a channel sending through the configured server. It
+// upgrades to TLS with STARTTLS when the server offers it and authenticates
+// when a username is set; net/smtp refuses to send credentials in plain text
+// to anything but localhost.
+func NewEmailChannel(cfg config.SMTPConfig) *EmailChannel {
+	channel := &EmailChannel{
+		host: cfg.Host,
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from: cfg.From,
+	}
+	if cfg.Username != "" {
+		channel.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return channel
+}
+
+// Send emails a notification to the address target
+func (c *EmailChannel) Send(ctx context.Context, target string, notification entities.Notification) error {
+	sender, err := mail.ParseAddress(c.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	recipient, err := mail.ParseAddress(target)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+	message, err := emailMessage(sender, recipient, notification)
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// net/smtp knows no contexts; closing the connection aborts the conversation
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.host}); err != nil {
+			return err
+		}
+	}
+	if c.auth != nil {
+		if err := client.Auth(c.auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(sender.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// emailMessage renders a notification as an RFC 5322 message. Header values
+// are encoded, so that task titles cannot inject headers.
+func emailMessage(sender, recipient *mail.Address, notification entities.Notification) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender)
+	fmt.Fprintf(&buf, "To: %s\r\n", recipient)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject(notification)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("Auto-Submitted: auto-generated\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(text(notification))); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,52 @@
+// Package notify delivers notifications outside of the application, by email
+// over SMTP or as signed webhook calls.
+package notify
+
+import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/entities"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Channel sends notifications to targets of one kind
+type Channel interface {
+	// Send delivers a notification to target, an email address or a URL
+	// depending on the channel
+	Send(ctx context.Context, target string, notification entities.Notification) error
+}
+
+// New creates the channels enabled in the configuration. Email needs an SMTP
+// host; webhooks can be switched off.
+func New(cfg config.NotificationsConfig) map[entities.NotificationChannel]Channel {
+	channels := make(map[entities.NotificationChannel]Channel)
+	if cfg.SMTP.Host != "" {
+		channels[entities.NotificationChannelEmail] = NewEmailChannel(cfg.SMTP)
+	}
+	if cfg.Webhook.Enabled {
+		channels[entities.NotificationChannelWebhook] = NewWebhookChannel(time.Duration(cfg.Webhook.Timeout)*time.Second, cfg.Webhook.Secret, cfg.Webhook.AllowPrivateNetworks)
+	}
+	return channels
+}
+
+// subject is the one-line summary of a notification used in emails
+func subject(notification entities.Notification) string {
+	switch notification.Kind {
+	case entities.NotificationOverdue:
+		return "Overdue: " + notification.TaskTitle
+	default:
+		return "Due soon: " + notification.TaskTitle
+	}
+}
+
+// text describes a notification in plain text
+func text(notification entities.Notification) string {
+	due := notification.DueDate.UTC().Format("Mon, 02 Jan 2006 15:04 MST")
+	switch notification.Kind {
+	case entities.NotificationOverdue:
+		return fmt.Sprintf("The task %q was due on %s and is still open.\r\n\r\nTask ID: %s\r\n", notification.TaskTitle, due, notification.TaskID)
+	default:
+		return fmt.Sprintf("The task %q is due on %s.\r\n\r\nTask ID: %s\r\n", notification.TaskTitle, due, notification.TaskID)
+	}
+}
@@ -0,0 +1,295 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"boilerplate/internal/config"
+	"boilerplate/internal/entities"
+	"boilerplate/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStub is a minimal SMTP server that accepts every message
+type smtpStub struct {
+	listener net.Listener
+
+	mu         sync.Mutex
+	messages   []string
+	recipients []string
+	auth       []string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	stub := &smtpStub{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go stub.serve(conn)
+		}
+	}()
+	return stub
+}
+
+func (s *smtpStub) config() config.SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	return config.SMTPConfig{Host: host, Port: portNumber, From: "Reminders <reminders@example.com>"}
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 localhost ESMTP stub")
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO":
+			text.PrintfLine("250-localhost")
+			text.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.mu.Lock()
+			s.auth = append(s.auth, line)
+			s.mu.Unlock()
+			text.PrintfLine("235 2.7.0 Authentication successful")
+		case "RCPT":
+			s.mu.Lock()
+			s.recipients = append(s.recipients, line)
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "DATA":
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *smtpStub) received() ([]string, []string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.messages, s.recipients, s.auth
+}
+
+func testNotification() entities.Notification {
+	return entities.Notification{
+		ID:        "notification-1",
+		UserID:    "jane",
+		Kind:      entities.NotificationDueSoon,
+		TaskID:    "task-1",
+		ProjectID: "project-1",
+		TaskTitle: "Renew certificate\r\nBcc: everyone@example.com",
+		DueDate:   time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEmailChannel_Send(t *testing.T) {
+	stub := newSMTPStub(t)
+	cfg := stub.config()
+	cfg.Username = "mailer"
+	cfg.Password = "secret"
+	channel := notify.NewEmailChannel(cfg)
+
+	err := channel.Send(context.Background(), "Jane <jane@example.com>", testNotification())
+	require.NoError(t, err)
+
+	messages, recipients, auth := stub.received()
+	require.Len(t, messages, 1)
+	assert.Equal(t, []string{"RCPT TO:<jane@example.com>"}, recipients)
+	require.Len(t, auth, 1)
+	credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth[0], "AUTH PLAIN "))
+	require.NoError(t, err)
+	assert.Equal(t, "\x00mailer\x00secret", string(credentials))
+
+	message, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	require.NoError(t, err)
+	assert.Empty(t, message.Header.Get("Bcc"), "the task title must not inject headers")
+	assert.Equal(t, "\"Reminders\" <reminders@example.com>", message.Header.Get("From"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Due soon: Renew certificate\r\nBcc: everyone@example.com", subject)
+
+	body, err := io.ReadAll(quotedprintable.NewReader(message.Body))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "is due on Mon, 02 Mar 2026 09:00 UTC")
+}
+
+func TestEmailChannel_SendInvalidRecipient(t *testing.T) {
+	stub := newSMTPStub(t)
+	channel := notify.NewEmailChannel(stub.config())
+
+	err := channel.Send(context.Background(), "not an address", testNotification())
+	assert.Error(t, err)
+	messages, _, _ := stub.received()
+	assert.Empty(t, messages)
+}
+
+func TestEmailChannel_SendUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().(*net.TCPAddr)
+	listener.Close()
+
+	channel := notify.NewEmailChannel(config.SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "reminders@example.com"})
+	assert.Error(t, channel.Send(context.Background(), "jane@example.com", testNotification()))
+}
+
+func TestEmailChannel_SendCancelled(t *testing.T) {
+	// A server that accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(io.Discard, bufio.NewReader(conn))
+		}
+	}()
+	addr := listener.Addr().(*net.TCPAddr)
+	channel := notify.NewEmailChannel(config.SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "reminders@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- channel.Send(ctx, "jane@example.com", testNotification()) }()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Send to give up once its context is done")
+	}
+}
+
+func TestWebhookChannel_Send(t *testing.T) {
+	var body []byte
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(time.Second, "webhook-secret", true)
+	require.NoError(t, channel.Send(context.Background(), server.URL, testNotification()))
+
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "notification-1", header.Get("X-Notification-Id"))
+	assert.Equal(t, notify.Sign([]byte("webhook-secret"), body), header.Get(notify.SignatureHeader))
+
+	var payload entities.Notification
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, entities.NotificationDueSoon, payload.Kind)
+	assert.Equal(t, "task-1", payload.TaskID)
+}
+
+func TestWebhookChannel_SendUnsigned(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(notify.SignatureHeader)
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(time.Second, "", true)
+	require.NoError(t, channel.Send(context.Background(), server.URL, testNotification()))
+	assert.Empty(t, signature)
+}
+
+func TestWebhookChannel_SendErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(50*time.Millisecond, "", true)
+	err := channel.Send(context.Background(), server.URL, testNotification())
+	assert.ErrorContains(t, err, "status 500")
+	assert.Error(t, channel.Send(context.Background(), server.URL+"/slow", testNotification()), "expected the call to time out")
+}
+
+func TestWebhookChannel_SendRefusesInternalAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(time.Second, "", false)
+	for _, target := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://[::1]:27017", "http://10.0.0.1", "http://0.0.0.0"} {
+		err := channel.Send(context.Background(), target, testNotification())
+		assert.ErrorIs(t, err, notify.ErrForbiddenAddress, target)
+	}
+	assert.False(t, called)
+}
+
+func TestWebhookChannel_SendDoesNotFollowRedirects(t *testing.T) {
+	redirected := false
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer internal.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internal.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	channel := notify.NewWebhookChannel(time.Second, "", true)
+	err := channel.Send(context.Background(), server.URL, testNotification())
+	assert.ErrorContains(t, err, "status 307")
+	assert.False(t, redirected)
+}
+
+func TestNew(t *testing.T) {
+	channels := notify.New(config.NotificationsConfig{Webhook: config.WebhookConfig{Enabled: true, Timeout: 10}})
+	assert.Contains(t, channels, entities.NotificationChannelWebhook)
+	assert.NotContains(t, channels, entities.NotificationChannelEmail, "email needs an SMTP host")
+
+	channels = notify.New(config.NotificationsConfig{SMTP: config.SMTPConfig{Host: "localhost", Port: 25}})
+	assert.Contains(t, channels, entities.NotificationChannelEmail)
+	assert.NotContains(t, channels, entities.NotificationChannelWebhook)
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"boilerplate/internal/entities"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the webhook body as "sha256=<hex>"
+const SignatureHeader = "X-Signature-256"
+
+// ErrForbiddenAddress is returned when a webhook URL resolves to an address
+// of the internal network
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// internalPrefixes are non-public ranges that netip has no predicate for
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT, often used inside clusters
+}
+
+// WebhookChannel posts notifications as JSON to URLs chosen by the users
+type WebhookChannel struct {
+	client *http.Client
+	secret []byte
+}
+
+// NewWebhookChannel creates a channel whose calls time out after timeout. With
+// a secret, every call is signed so that receivers can verify its origin.
+// Since the users choose the URLs, calls to loopback, private, link-local and
+// unspecified addresses are refused unless allowPrivate is set, and redirects
+// are never followed.
+func NewWebhookChannel(timeout time.Duration, secret string, allowPrivate bool) *WebhookChannel {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		// Checking the address that is dialed, rather than the host of the URL,
+		// also covers names resolving to internal addresses
+		dialer.Control = refuseInternal
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: timeout,
+	}
+
+	return &WebhookChannel{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// A redirect is answered like any other non-2xx response
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret: []byte(secret),
+	}
+}
+
+// refuseInternal is a dialer control that fails connections to addresses that
+// are not publicly routable
+func refuseInternal(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+		}
+	}
+	return nil
+}
+
+// Send posts a notification to the URL target. Any response but 2xx is an
+// error. Deliveries are retried, so receivers should deduplicate on the
+// X-Notification-Id header.
+func (c *WebhookChannel) Send(ctx context.Context, target string, notification entities.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Notification-Id", notification.ID)
+	if len(c.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(c.secret, body))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain a bounded part of the body so that the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature of a webhook body in the format of SignatureHeader
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
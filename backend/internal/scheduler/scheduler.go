@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"boilerplate/internal/storage"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// JobFunc does one run of a job. It must respect ctx cancellation; ctx is
+// cancelled when the scheduler stops or the run outlasts the job's interval.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+}
+
+// Scheduler runs background jobs periodically. With several replicas sharing
+// a database each job runs on one replica per interval: a run first takes a
+// lease named after the job for the interval and keeps it afterwards, so that
+// the other replicas skip the interval. Jobs must still be idempotent since
+// a lease can expire while a slow run is finishing.
+type Scheduler struct {
+	leases storage.LeaseRepository
+	logger *slog.Logger
+	jobs   []job
+	wg     sync.WaitGroup
+}
+
+func New(leases storage.LeaseRepository, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		leases: leases,
+		logger: logger,
+	}
+}
+
+// Register adds a job that runs every interval. Jobs must be registered before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, fn JobFunc) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Start runs every registered job right away and then on its interval until
+// ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}()
+	}
+}
+
+// Wait blocks until the jobs started by Start have returned after their context was cancelled
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, j)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a job if this replica gets its lease and reports whether it ran.
+// A failed run gives the lease back so that any replica can retry on its next tick.
+func (s *Scheduler) runOnce(ctx context.Context, j job) bool {
+	logger := s.logger.With("job", j.name)
+
+	acquired, err := s.leases.Acquire(ctx, j.name, j.interval)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WarnContext(ctx, "failed to acquire job lease", "error", err)
+		}
+		return false
+	}
+	if !acquired {
+		logger.DebugContext(ctx, "job skipped, leased by another replica")
+		return false
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, j.interval)
+	defer cancel()
+
+	start := time.Now()
+	if err := j.fn(runCtx); err != nil {
+		logger.ErrorContext(ctx, "job failed", "duration", time.Since(start), "error", err)
+		// The scheduler may be stopping, so the lease is released without ctx
+		if err := s.leases.Release(context.WithoutCancel(ctx), j.name); err != nil {
+			logger.WarnContext(ctx, "failed to release job lease", "error", err)
+		}
+		return true
+	}
+	logger.DebugContext(ctx, "job finished", "duration", time.Since(start))
+	return true
+}
@@ -0,0 +1,170 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// leaseTable is the shared state of the in-memory leases of several replicas
+type leaseTable struct {
+	mu      sync.Mutex
+	holders map[string]string
+	expires map[string]time.Time
+}
+
+func newLeaseTable() *leaseTable {
+	return &leaseTable{holders: map[string]string{}, expires: map[string]time.Time{}}
+}
+
+// memoryLeases is the view of one replica on a leaseTable
+type memoryLeases struct {
+	table  *leaseTable
+	holder string
+}
+
+func (l memoryLeases) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l.table.mu.Lock()
+	defer l.table.mu.Unlock()
+	now := time.Now()
+	if holder, ok := l.table.holders[name]; ok && holder != l.holder && now.Before(l.table.expires[name]) {
+		return false, nil
+	}
+	l.table.holders[name] = l.holder
+	l.table.expires[name] = now.Add(ttl)
+	return true, nil
+}
+
+func (l memoryLeases) Release(ctx context.Context, name string) error {
+	l.table.mu.Lock()
+	defer l.table.mu.Unlock()
+	if l.table.holders[name] == l.holder {
+		delete(l.table.holders, name)
+		delete(l.table.expires, name)
+	}
+	return nil
+}
+
+type failingLeases struct{}
+
+func (failingLeases) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return false, errors.New("database unavailable")
+}
+
+func (failingLeases) Release(ctx context.Context, name string) error {
+	return nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	table := newLeaseTable()
+	first := New(memoryLeases{table: table, holder: "replica-1"}, testLogger())
+	second := New(memoryLeases{table: table, holder: "replica-2"}, testLogger())
+
+	var runs atomic.Int32
+	j := job{name: "count", interval: time.Hour, fn: func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}}
+
+	if !first.runOnce(context.Background(), j) {
+		t.Fatal("expected the first replica to run the job")
+	}
+	if second.runOnce(context.Background(), j) {
+		t.Error("expected the second replica to skip the job while the first holds the lease")
+	}
+	if !first.runOnce(context.Background(), j) {
+		t.Error("expected the holder of the lease to run the job again")
+	}
+	if got := runs.Load(); got != 2 {
+		t.Errorf("expected 2 runs, got %d", got)
+	}
+}
+
+func TestScheduler_RunOnceFailureReleasesLease(t *testing.T) {
+	table := newLeaseTable()
+	first := New(memoryLeases{table: table, holder: "replica-1"}, testLogger())
+	second := New(memoryLeases{table: table, holder: "replica-2"}, testLogger())
+
+	failing := job{name: "sync", interval: time.Hour, fn: func(ctx context.Context) error {
+		return errors.New("boom")
+	}}
+	if !first.runOnce(context.Background(), failing) {
+		t.Fatal("expected the first replica to run the job")
+	}
+
+	succeeding := failing
+	succeeding.fn = func(ctx context.Context) error { return nil }
+	if !second.runOnce(context.Background(), succeeding) {
+		t.Error("expected another replica to retry after a failed run")
+	}
+}
+
+func TestScheduler_RunOnceLeaseError(t *testing.T) {
+	s := New(failingLeases{}, testLogger())
+	ran := false
+	j := job{name: "count", interval: time.Hour, fn: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}}
+
+	if s.runOnce(context.Background(), j) || ran {
+		t.Error("expected the job not to run without a lease")
+	}
+}
+
+func TestScheduler_RunOnceDeadline(t *testing.T) {
+	s := New(memoryLeases{table: newLeaseTable(), holder: "replica-1"}, testLogger())
+	var deadline time.Time
+	j := job{name: "slow", interval: time.Minute, fn: func(ctx context.Context) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	}}
+
+	s.runOnce(context.Background(), j)
+
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > time.Minute {
+		t.Errorf("expected the run to be bounded by the interval, got %v left", remaining)
+	}
+}
+
+func TestScheduler_StartAndStop(t *testing.T) {
+	s := New(memoryLeases{table: newLeaseTable(), holder: "replica-1"}, testLogger())
+	started := make(chan struct{}, 1)
+	s.Register("tick", time.Hour, func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Start(ctx)
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the job to run right after start")
+	}
+
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		s.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the scheduler to stop once its context is cancelled")
+	}
+}
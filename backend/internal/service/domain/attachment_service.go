@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxFileNameLength limits the length of attachment file names
+	maxFileNameLength = 255
+	// sniffLength is the number of bytes content types are detected from
+	sniffLength = 512
+)
+
+var (
+	// ErrFileTooLarge is returned when an upload exceeds the maximum file size
+	ErrFileTooLarge = errors.New("file exceeds the maximum size")
+	// ErrQuotaExceeded is returned when an upload would exceed the attachment quota of the project
+	ErrQuotaExceeded = errors.New("project attachment quota exceeded")
+)
+
+type attachmentService struct {
+	attachmentRepo storage.AttachmentRepository
+	taskRepo       storage.TaskRepository
+	blobs          storage.BlobStore
+	// maxFileSize limits the size of a single upload in bytes
+	maxFileSize int64
+	// projectQuota limits the total size of the attachments of a project in bytes; 0 is unlimited
+	projectQuota int64
+}
+
+func NewAttachmentService(attachmentRepo storage.AttachmentRepository, taskRepo storage.TaskRepository, blobs storage.BlobStore, maxFileSize, projectQuota int64) *attachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		blobs:          blobs,
+		maxFileSize:    maxFileSize,
+		projectQuota:   projectQuota,
+	}
+}
+
+// Upload streams content into the blob store and records the attachment. The
+// content type is sniffed and the SHA-256 digest computed on the way; if
+// checksum is set, it must match the digest. Uploads larger than the maximum
+// file size fail with ErrFileTooLarge, uploads that do not fit into the
+// remaining project quota with ErrQuotaExceeded. Concurrent uploads are
+// checked against the same remaining quota, so they may overshoot it slightly.
+func (s *attachmentService) Upload(ctx context.Context, attachment *entities.Attachment, content io.Reader, checksum string) error {
+	task, err := s.taskRepo.FindByID(ctx, attachment.TaskID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+	attachment.ProjectID = task.ProjectID
+
+	attachment.FileName = cleanFileName(attachment.FileName)
+	if attachment.FileName == "" {
+		return invalidf("fileName", "is required")
+	}
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	if checksum != "" {
+		if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+			return invalidf("checksum", "must be a hex encoded SHA-256 digest")
+		}
+	}
+
+	limit, err := s.uploadLimit(ctx, attachment.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	// Sniff the content type without consuming the content
+	buffered := bufio.NewReaderSize(io.LimitReader(content, limit+1), sniffLength)
+	head, err := buffered.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if len(head) == 0 {
+		return invalidf("file", "is empty")
+	}
+	attachment.ContentType = http.DetectContentType(head)
+
+	attachment.StorageKey = attachment.ProjectID + "/" + attachment.TaskID + "/" + rand.Text()
+	counter := &countingReader{r: buffered, hash: sha256.New()}
+	if err := s.blobs.Put(ctx, attachment.StorageKey, counter, -1, attachment.ContentType); err != nil {
+		s.blobs.Delete(ctx, attachment.StorageKey)
+		return err
+	}
+
+	attachment.Size = counter.n
+	attachment.SHA256 = hex.EncodeToString(counter.hash.Sum(nil))
+	if err := s.checkUpload(attachment, limit, checksum); err != nil {
+		s.blobs.Delete(ctx, attachment.StorageKey)
+		return err
+	}
+
+	if err := s.attachmentRepo.Insert(ctx, attachment); err != nil {
+		s.blobs.Delete(ctx, attachment.StorageKey)
+		return err
+	}
+	return nil
+}
+
+// uploadLimit returns the number of bytes an upload to the project may have
+func (s *attachmentService) uploadLimit(ctx context.Context, projectID string) (int64, error) {
+	if s.projectQuota <= 0 {
+		return s.maxFileSize, nil
+	}
+	used, err := s.attachmentRepo.SizeByProjectID(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	remaining := s.projectQuota - used
+	if remaining <= 0 {
+		return 0, ErrQuotaExceeded
+	}
+	return min(s.maxFileSize, remaining), nil
+}
+
+// checkUpload verifies a stored upload against the size limit and the checksum
+func (s *attachmentService) checkUpload(attachment *entities.Attachment, limit int64, checksum string) error {
+	switch {
+	case attachment.Size > s.maxFileSize:
+		return fmt.Errorf("%w of %d bytes", ErrFileTooLarge, s.maxFileSize)
+	case attachment.Size > limit:
+		return ErrQuotaExceeded
+	case checksum != "" && checksum != attachment.SHA256:
+		return invalidf("checksum", "does not match the SHA-256 digest %s of the uploaded file", attachment.SHA256)
+	}
+	return nil
+}
+
+// Open returns an attachment with its content, which the caller must close
+func (s *attachmentService) Open(ctx context.Context, id string) (entities.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := s.attachmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return entities.Attachment{}, nil, err
+	}
+	content, err := s.blobs.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return entities.Attachment{}, nil, fmt.Errorf("failed to open content of attachment %s: %w", id, err)
+	}
+	return attachment, content, nil
+}
+
+// Delete removes an attachment and its content
+func (s *attachmentService) Delete(ctx context.Context, id string) error {
+	attachment, err := s.attachmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.blobs.Delete(ctx, attachment.StorageKey)
+}
+
+func (s *attachmentService) FindByID(ctx context.Context, id string) (entities.Attachment, error) {
+	return s.attachmentRepo.FindByID(ctx, id)
+}
+
+// FindByTaskID returns the attachments of a task, oldest first
+func (s *attachmentService) FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error) {
+	if _, err := s.taskRepo.FindByID(ctx, taskID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+	return s.attachmentRepo.FindByTaskID(ctx, taskID)
+}
+
+// deleteAttachmentBlobs removes the content of deleted attachments. It keeps
+// going after a failure; blobs left behind only take up space.
+func deleteAttachmentBlobs(ctx context.Context, blobs storage.BlobStore, attachments []entities.Attachment) error {
+	var errs []error
+	for _, attachment := range attachments {
+		if err := blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete content of attachment %s: %w", attachment.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cleanFileName reduces a client supplied file name to its last path element
+// without control characters
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "." || name == "/" || name == ".." {
+		return ""
+	}
+	for utf8.RuneCountInString(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+// countingReader counts and hashes the bytes read through it
+type countingReader struct {
+	r    io.Reader
+	n    int64
+	hash hash.Hash
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	c.hash.Write(p[:n])
+	return n, err
+}
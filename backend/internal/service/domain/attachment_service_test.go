@@ -0,0 +1,320 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"boilerplate/internal/storage"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Insert(ctx context.Context, attachment *entities.Attachment) error {
+	args := m.Called(attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) FindByID(ctx context.Context, id string) (entities.Attachment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return entities.Attachment{}, args.Error(1)
+	}
+	return args.Get(0).(entities.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) FindByTaskIDs(ctx context.Context, taskIDs []string) ([]entities.Attachment, error) {
+	args := m.Called(taskIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.Attachment, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) SizeByProjectID(ctx context.Context, projectID string) (int64, error) {
+	args := m.Called(projectID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noAttachments returns an attachment repository for tasks without attachments
+func noAttachments() *MockAttachmentRepository {
+	attachmentRepo := new(MockAttachmentRepository)
+	attachmentRepo.On("FindByTaskIDs", mock.Anything).Return([]entities.Attachment{}, nil).Maybe()
+	attachmentRepo.On("FindByProjectID", mock.Anything).Return([]entities.Attachment{}, nil).Maybe()
+	attachmentRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	attachmentRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return attachmentRepo
+}
+
+// memoryBlobs is a BlobStore keeping blobs in memory
+type memoryBlobs map[string][]byte
+
+func (b memoryBlobs) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	b[key] = data
+	return nil
+}
+
+func (b memoryBlobs) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	data, ok := b[key]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+func (b memoryBlobs) Delete(ctx context.Context, key string) error {
+	delete(b, key)
+	return nil
+}
+
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+func (nopCloser) Close() error { return nil }
+
+// pngHeader starts every PNG file and is sniffed as image/png
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	png := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 100)...)
+
+	tests := []struct {
+		name          string
+		taskID        string
+		fileName      string
+		content       []byte
+		checksum      string
+		used          int64
+		expectedError error
+		expectedText  string
+	}{
+		{name: "image with checksum", taskID: "task-1", fileName: "screen.png", content: png, checksum: strings.ToUpper(digest(png))},
+		{name: "path is stripped from the name", taskID: "task-1", fileName: `C:\Users\jane\notes.txt`, content: []byte("hello")},
+		{name: "unknown task", taskID: "task-9", fileName: "a.txt", content: []byte("hello"), expectedError: domain.ErrTaskNotFound},
+		{name: "missing file name", taskID: "task-1", fileName: "../", content: []byte("hello"), expectedText: "fileName: is required"},
+		{name: "malformed checksum", taskID: "task-1", fileName: "a.txt", content: []byte("hello"), checksum: "abc", expectedText: "checksum: must be a hex encoded SHA-256 digest"},
+		{name: "checksum mismatch", taskID: "task-1", fileName: "a.txt", content: []byte("hello"), checksum: digest([]byte("hallo")), expectedText: "checksum: does not match"},
+		{name: "empty file", taskID: "task-1", fileName: "a.txt", content: []byte{}, expectedText: "file: is empty"},
+		{name: "larger than the file size limit", taskID: "task-1", fileName: "big.bin", content: make([]byte, 1001), expectedError: domain.ErrFileTooLarge},
+		{name: "larger than the remaining quota", taskID: "task-1", fileName: "a.bin", content: make([]byte, 600), used: 1500, expectedError: domain.ErrQuotaExceeded},
+		{name: "quota used up", taskID: "task-1", fileName: "a.txt", content: []byte("hello"), used: 2000, expectedError: domain.ErrQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachmentRepo := new(MockAttachmentRepository)
+			attachmentRepo.On("SizeByProjectID", "project-1").Return(tt.used, nil).Maybe()
+			attachmentRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+			blobs := memoryBlobs{}
+			service := domain.NewAttachmentService(attachmentRepo, commentedTask(), blobs, 1000, 2000)
+
+			attachment := &entities.Attachment{TaskID: tt.taskID, FileName: tt.fileName, UploadedBy: entities.UserRef{ID: "jane"}}
+			err := service.Upload(context.Background(), attachment, bytes.NewReader(tt.content), tt.checksum)
+
+			if tt.expectedError == nil && tt.expectedText == "" {
+				require.NoError(t, err)
+				attachmentRepo.AssertCalled(t, "Insert", attachment)
+				assert.Equal(t, "project-1", attachment.ProjectID)
+				assert.Equal(t, int64(len(tt.content)), attachment.Size)
+				assert.Equal(t, digest(tt.content), attachment.SHA256)
+				assert.Equal(t, tt.content, blobs[attachment.StorageKey])
+				return
+			}
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				var validationErr *domain.ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Contains(t, err.Error(), tt.expectedText)
+			}
+			attachmentRepo.AssertNotCalled(t, "Insert", mock.Anything)
+			assert.Empty(t, blobs, "rejected uploads should not leave content behind")
+		})
+	}
+}
+
+func TestAttachmentService_UploadDetails(t *testing.T) {
+	attachmentRepo := new(MockAttachmentRepository)
+	attachmentRepo.On("Insert", mock.Anything).Return(nil)
+	service := domain.NewAttachmentService(attachmentRepo, commentedTask(), memoryBlobs{}, 1000, 0)
+
+	t.Run("content type is sniffed", func(t *testing.T) {
+		image := &entities.Attachment{TaskID: "task-1", FileName: "photo.txt"}
+		require.NoError(t, service.Upload(context.Background(), image, bytes.NewReader(append(pngHeader, 0, 0)), ""))
+		assert.Equal(t, "image/png", image.ContentType)
+
+		text := &entities.Attachment{TaskID: "task-1", FileName: "notes.png"}
+		require.NoError(t, service.Upload(context.Background(), text, strings.NewReader("plain notes"), ""))
+		assert.Equal(t, "text/plain; charset=utf-8", text.ContentType)
+	})
+
+	t.Run("file name is cleaned", func(t *testing.T) {
+		attachment := &entities.Attachment{TaskID: "task-1", FileName: " ../../etc/pass\x00wd\n"}
+		require.NoError(t, service.Upload(context.Background(), attachment, strings.NewReader("x"), ""))
+		assert.Equal(t, "passwd", attachment.FileName)
+	})
+
+	t.Run("without a quota the size of the project is not needed", func(t *testing.T) {
+		attachmentRepo.AssertNotCalled(t, "SizeByProjectID", mock.Anything)
+	})
+
+	t.Run("content is removed if the metadata cannot be stored", func(t *testing.T) {
+		failingRepo := new(MockAttachmentRepository)
+		failingRepo.On("Insert", mock.Anything).Return(errors.New("connection lost"))
+		blobs := memoryBlobs{}
+		service := domain.NewAttachmentService(failingRepo, commentedTask(), blobs, 1000, 0)
+
+		err := service.Upload(context.Background(), &entities.Attachment{TaskID: "task-1", FileName: "a.txt"}, strings.NewReader("x"), "")
+		assert.EqualError(t, err, "connection lost")
+		assert.Empty(t, blobs)
+	})
+}
+
+func TestAttachmentService_OpenAndDelete(t *testing.T) {
+	blobs := memoryBlobs{"project-1/task-1/key": []byte("hello")}
+	attachment := entities.Attachment{ID: "a1", TaskID: "task-1", StorageKey: "project-1/task-1/key"}
+	attachmentRepo := new(MockAttachmentRepository)
+	attachmentRepo.On("FindByID", "a1").Return(attachment, nil)
+	attachmentRepo.On("FindByTaskID", "task-1").Return([]entities.Attachment{attachment}, nil)
+	attachmentRepo.On("Delete", "a1").Return(nil)
+	service := domain.NewAttachmentService(attachmentRepo, commentedTask(), blobs, 1000, 0)
+
+	found, content, err := service.Open(context.Background(), "a1")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	content.Close()
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, attachment, found)
+
+	attachments, err := service.FindByTaskID(context.Background(), "task-1")
+	require.NoError(t, err)
+	assert.Len(t, attachments, 1)
+	_, err = service.FindByTaskID(context.Background(), "task-9")
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+
+	require.NoError(t, service.Delete(context.Background(), "a1"))
+	assert.Empty(t, blobs)
+
+	_, _, err = service.Open(context.Background(), "a1")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestAttachmentCascade(t *testing.T) {
+	stored := func() (memoryBlobs, []entities.Attachment) {
+		blobs := memoryBlobs{"k1": []byte("1"), "k2": []byte("2"), "other": []byte("3")}
+		return blobs, []entities.Attachment{{ID: "a1", StorageKey: "k1"}, {ID: "a2", StorageKey: "k2"}}
+	}
+
+	t.Run("deleting a task removes the attachments of its subtasks", func(t *testing.T) {
+		blobs, attachments := stored()
+		taskRepo := hierarchyRepo()
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"step"}).Return([]error{nil}, nil).Once()
+		taskRepo.On("Delete", "story").Return(nil).Once()
+		attachmentRepo := new(MockAttachmentRepository)
+		attachmentRepo.On("FindByTaskIDs", []string{"step", "story"}).Return(attachments, nil).Once()
+		attachmentRepo.On("DeleteByTaskIDs", []string{"step", "story"}).Return(nil).Once()
+		repo := testRepository(taskRepo, nil)
+		repo.AttachmentRepository = attachmentRepo
+		repo.BlobStore = blobs
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		require.NoError(t, service.Delete(context.Background(), "story"))
+		attachmentRepo.AssertExpectations(t)
+		assert.Equal(t, memoryBlobs{"other": []byte("3")}, blobs)
+	})
+
+	t.Run("batch deletes remove the content after the writes", func(t *testing.T) {
+		blobs, attachments := stored()
+		taskRepo := hierarchyRepo()
+		taskRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{}, nil)
+		taskRepo.On("UpdateMany", mock.Anything, "project-1", mock.Anything).Return([]error{}, nil)
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"story", "step"}).Return([]error{nil, nil}, nil).Once()
+		attachmentRepo := new(MockAttachmentRepository)
+		attachmentRepo.On("FindByTaskIDs", []string{"story", "step"}).Return(attachments, nil).Once()
+		attachmentRepo.On("DeleteByTaskIDs", []string{"story", "step"}).Return(nil).Once()
+		repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.AttachmentRepository = attachmentRepo
+		repo.BlobStore = blobs
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		ops := []entities.TaskBatchOperation{{Op: entities.TaskBatchOpDelete, ID: "story"}}
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[0].Status)
+		attachmentRepo.AssertExpectations(t)
+		assert.Equal(t, memoryBlobs{"other": []byte("3")}, blobs)
+	})
+
+	t.Run("deleting a project removes all attachments", func(t *testing.T) {
+		blobs, attachments := stored()
+		projectRepo := new(MockProjectRepository)
+		projectRepo.On("Delete", "project-1").Return(nil)
+		taskRepo := new(MockTaskRepository)
+		taskRepo.On("DeleteByProjectID", "project-1").Return(nil)
+		attachmentRepo := new(MockAttachmentRepository)
+		attachmentRepo.On("FindByProjectID", "project-1").Return(attachments, nil).Once()
+		attachmentRepo.On("DeleteByProjectID", "project-1").Return(nil).Once()
+		repo := testRepository(taskRepo, projectRepo)
+		repo.AttachmentRepository = attachmentRepo
+		repo.BlobStore = blobs
+		service := domain.NewProjectService(repo)
+
+		require.NoError(t, service.Delete(context.Background(), "project-1"))
+		attachmentRepo.AssertExpectations(t)
+		assert.Equal(t, memoryBlobs{"other": []byte("3")}, blobs)
+	})
+}
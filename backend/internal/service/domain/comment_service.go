@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxCommentLength limits the length of comment bodies
+const maxCommentLength = 10000
+
+var (
+	// ErrTaskNotFound is returned when comments are read or written for a task that does not exist
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrNotCommentAuthor is returned when a comment is edited or deleted by someone else than its author
+	ErrNotCommentAuthor = errors.New("only the author can change a comment")
+)
+
+// mentionPattern matches @userId and @email mentions that do not follow a word
+// character, so that the domain of a plain email address is no mention
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@(\w[\w.+-]*(?:@\w[\w-]*(?:\.[\w-]+)+)?)`)
+
+type commentService struct {
+	commentRepo storage.CommentRepository
+	taskRepo    storage.TaskRepository
+	memberRepo  storage.MemberRepository
+}
+
+func NewCommentService(commentRepo storage.CommentRepository, taskRepo storage.TaskRepository, memberRepo storage.MemberRepository) *commentService {
+	return &commentService{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+		memberRepo:  memberRepo,
+	}
+}
+
+// Create adds a comment to a task. Replies must reference a top-level comment
+// of the same task. Mentions of project members are taken from the body.
+func (s *commentService) Create(ctx context.Context, comment *entities.Comment) error {
+	task, err := s.taskRepo.FindByID(ctx, comment.TaskID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+	comment.ProjectID = task.ProjectID
+
+	if comment.ParentID != "" {
+		parent, err := s.commentRepo.FindByID(ctx, comment.ParentID)
+		if err != nil || parent.TaskID != comment.TaskID {
+			return invalidf("parentId", "comment %s not found on the task", comment.ParentID)
+		}
+		if parent.ParentID != "" {
+			return invalidf("parentId", "replies cannot be replied to")
+		}
+	}
+
+	if err := s.prepareBody(ctx, comment); err != nil {
+		return err
+	}
+	comment.Edits = []entities.CommentEdit{}
+	if err := s.commentRepo.Insert(ctx, comment); err != nil {
+		return err
+	}
+	comment.HTML = renderMarkdown(comment.Body)
+	return nil
+}
+
+// Update replaces the body of a comment and records the previous body in its
+// edit history. Only the author may edit a comment.
+func (s *commentService) Update(ctx context.Context, comment *entities.Comment, editor entities.UserRef) error {
+	existing, err := s.commentRepo.FindByID(ctx, comment.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Author.ID != editor.ID {
+		return ErrNotCommentAuthor
+	}
+
+	body := comment.Body
+	*comment = existing
+	comment.Body = body
+	if err := s.prepareBody(ctx, comment); err != nil {
+		return err
+	}
+	if comment.Body == existing.Body {
+		comment.HTML = renderMarkdown(comment.Body)
+		return nil
+	}
+
+	comment.Edits = append(comment.Edits, entities.CommentEdit{Body: existing.Body, EditedAt: time.Now()})
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
+		return err
+	}
+	comment.HTML = renderMarkdown(comment.Body)
+	return nil
+}
+
+// prepareBody validates the body of a comment and resolves its mentions
+// against the members of the task's project
+func (s *commentService) prepareBody(ctx context.Context, comment *entities.Comment) error {
+	comment.Body = strings.TrimSpace(comment.Body)
+	if comment.Body == "" {
+		return invalidf("body", "is required")
+	}
+	if utf8.RuneCountInString(comment.Body) > maxCommentLength {
+		return invalidf("body", "must be at most %d characters", maxCommentLength)
+	}
+
+	members, err := s.memberRepo.FindByProjectID(ctx, comment.ProjectID)
+	if err != nil {
+		return err
+	}
+	comment.Mentions = parseMentions(comment.Body, members)
+	return nil
+}
+
+// Delete removes a comment together with its replies. Only the author may
+// delete a comment.
+func (s *commentService) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	existing, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.Author.ID != actor.ID {
+		return ErrNotCommentAuthor
+	}
+	return s.commentRepo.Delete(ctx, id)
+}
+
+// FindByID returns a comment with its rendered body and edit history
+func (s *commentService) FindByID(ctx context.Context, id string) (entities.Comment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return entities.Comment{}, err
+	}
+	comment.HTML = renderMarkdown(comment.Body)
+	return comment, nil
+}
+
+// FindByTaskID returns a page of the top-level comments of a task with all of
+// their replies, and the total number of top-level comments
+func (s *commentService) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.CommentThread, int64, error) {
+	if _, err := s.taskRepo.FindByID(ctx, taskID); err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+
+	comments, total, err := s.commentRepo.FindByTaskID(ctx, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	replies, err := s.commentRepo.FindReplies(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byParent := make(map[string][]entities.Comment)
+	for _, reply := range replies {
+		reply.HTML = renderMarkdown(reply.Body)
+		byParent[reply.ParentID] = append(byParent[reply.ParentID], reply)
+	}
+	threads := make([]entities.CommentThread, len(comments))
+	for i, comment := range comments {
+		comment.HTML = renderMarkdown(comment.Body)
+		threads[i] = entities.CommentThread{Comment: comment, Replies: byParent[comment.ID]}
+		if threads[i].Replies == nil {
+			threads[i].Replies = []entities.Comment{}
+		}
+	}
+	return threads, total, nil
+}
+
+// parseMentions returns the project members mentioned in a Markdown body by
+// user ID or email, in order of their first mention. Unknown names are no
+// mentions.
+func parseMentions(body string, members []entities.ProjectMember) []entities.UserRef {
+	mentions := []entities.UserRef{}
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		name := strings.TrimRight(match[1], ".-")
+		for _, member := range members {
+			if member.UserID != name && (member.Email == "" || !strings.EqualFold(member.Email, name)) {
+				continue
+			}
+			if !seen[member.UserID] {
+				seen[member.UserID] = true
+				mentions = append(mentions, member.Ref())
+			}
+			break
+		}
+	}
+	return mentions
+}
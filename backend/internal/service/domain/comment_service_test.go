@@ -0,0 +1,286 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) Insert(ctx context.Context, comment *entities.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Update(ctx context.Context, comment *entities.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) FindByID(ctx context.Context, id string) (entities.Comment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return entities.Comment{}, args.Error(1)
+	}
+	return args.Get(0).(entities.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.Comment, int64, error) {
+	args := m.Called(taskID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCommentRepository) FindReplies(ctx context.Context, parentIDs []string) ([]entities.Comment, error) {
+	args := m.Called(parentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) CountByTaskIDs(ctx context.Context, taskIDs []string) (map[string]int, error) {
+	args := m.Called(taskIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockCommentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noComments returns a comment repository for tasks without comments
+func noComments() *MockCommentRepository {
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("CountByTaskIDs", mock.Anything).Return(map[string]int{}, nil).Maybe()
+	commentRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	commentRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return commentRepo
+}
+
+// commentedTask returns a task repository holding task-1 of project-1
+func commentedTask() *MockTaskRepository {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByID", "task-1").Return(entities.Task{ID: "task-1", ProjectID: "project-1"}, nil).Maybe()
+	taskRepo.On("FindByID", mock.Anything).Return(nil, errors.New("task not found")).Maybe()
+	return taskRepo
+}
+
+func TestCommentService_Create(t *testing.T) {
+	comments := map[string]entities.Comment{
+		"top":   {ID: "top", TaskID: "task-1"},
+		"reply": {ID: "reply", TaskID: "task-1", ParentID: "top"},
+		"other": {ID: "other", TaskID: "task-2"},
+	}
+
+	tests := []struct {
+		name          string
+		comment       entities.Comment
+		expectedError string
+		expectedIs    error
+	}{
+		{name: "top-level comment", comment: entities.Comment{TaskID: "task-1", Body: "Looks good"}},
+		{name: "reply", comment: entities.Comment{TaskID: "task-1", ParentID: "top", Body: "Thanks"}},
+		{name: "empty body", comment: entities.Comment{TaskID: "task-1", Body: "  \n"}, expectedError: "body: is required"},
+		{name: "too long", comment: entities.Comment{TaskID: "task-1", Body: strings.Repeat("a", 10001)}, expectedError: "body: must be at most 10000 characters"},
+		{name: "reply to a reply", comment: entities.Comment{TaskID: "task-1", ParentID: "reply", Body: "Nested"}, expectedError: "parentId: replies cannot be replied to"},
+		{name: "parent on another task", comment: entities.Comment{TaskID: "task-1", ParentID: "other", Body: "Wrong thread"}, expectedError: "parentId: comment other not found on the task"},
+		{name: "unknown task", comment: entities.Comment{TaskID: "task-9", Body: "Hello"}, expectedIs: domain.ErrTaskNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepo := new(MockCommentRepository)
+			for id, comment := range comments {
+				commentRepo.On("FindByID", id).Return(comment, nil).Maybe()
+			}
+			commentRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+			service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+			comment := tt.comment
+			err := service.Create(context.Background(), &comment)
+
+			switch {
+			case tt.expectedIs != nil:
+				assert.ErrorIs(t, err, tt.expectedIs)
+				commentRepo.AssertNotCalled(t, "Insert", mock.Anything)
+			case tt.expectedError != "":
+				assert.EqualError(t, err, tt.expectedError)
+				commentRepo.AssertNotCalled(t, "Insert", mock.Anything)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "project-1", comment.ProjectID)
+				assert.NotNil(t, comment.Edits)
+				commentRepo.AssertCalled(t, "Insert", &comment)
+			}
+		})
+	}
+}
+
+func TestCommentService_MentionsAndMarkdown(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("Insert", mock.Anything).Return(nil)
+	service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+	comment := &entities.Comment{
+		TaskID: "task-1",
+		Body:   "**@joe** and @JANE@example.com, see @nobody and mail jane@example.com.\n\n<script>alert(1)</script>[x](javascript:alert(1)) @joe.",
+	}
+	require.NoError(t, service.Create(context.Background(), comment))
+
+	assert.Equal(t, []entities.UserRef{
+		{ID: "joe", Name: "Joe"},
+		{ID: "jane", Name: "Jane", Email: "jane@example.com"},
+	}, comment.Mentions)
+	assert.Contains(t, comment.HTML, "<strong>@joe</strong>")
+	assert.NotContains(t, comment.HTML, "<script")
+	assert.NotContains(t, comment.HTML, "javascript:")
+}
+
+func TestCommentService_Update(t *testing.T) {
+	existing := entities.Comment{
+		ID:        "comment-1",
+		TaskID:    "task-1",
+		ProjectID: "project-1",
+		Author:    entities.UserRef{ID: "jane", Name: "Jane"},
+		Body:      "First draft",
+		Edits:     []entities.CommentEdit{},
+	}
+
+	t.Run("author edits and keeps the history", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		commentRepo.On("FindByID", "comment-1").Return(existing, nil)
+		commentRepo.On("Update", mock.Anything).Return(nil)
+		service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+		comment := &entities.Comment{ID: "comment-1", Body: "Second draft for @joe"}
+		require.NoError(t, service.Update(context.Background(), comment, entities.UserRef{ID: "jane"}))
+
+		assert.Equal(t, "Second draft for @joe", comment.Body)
+		assert.Equal(t, "task-1", comment.TaskID)
+		assert.Equal(t, existing.Author, comment.Author)
+		require.Len(t, comment.Edits, 1)
+		assert.Equal(t, "First draft", comment.Edits[0].Body)
+		assert.False(t, comment.Edits[0].EditedAt.IsZero())
+		assert.Equal(t, []entities.UserRef{{ID: "joe", Name: "Joe"}}, comment.Mentions)
+		assert.Equal(t, "<p>Second draft for @joe</p>\n", comment.HTML)
+		commentRepo.AssertExpectations(t)
+	})
+
+	t.Run("unchanged body is not recorded", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		commentRepo.On("FindByID", "comment-1").Return(existing, nil)
+		service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+		comment := &entities.Comment{ID: "comment-1", Body: "First draft "}
+		require.NoError(t, service.Update(context.Background(), comment, entities.UserRef{ID: "jane"}))
+		assert.Empty(t, comment.Edits)
+		commentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("others cannot edit", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		commentRepo.On("FindByID", "comment-1").Return(existing, nil)
+		service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+		err := service.Update(context.Background(), &entities.Comment{ID: "comment-1", Body: "Hijacked"}, entities.UserRef{ID: "joe"})
+		assert.ErrorIs(t, err, domain.ErrNotCommentAuthor)
+		commentRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("FindByID", "comment-1").Return(entities.Comment{ID: "comment-1", Author: entities.UserRef{ID: "jane"}}, nil)
+	commentRepo.On("Delete", "comment-1").Return(nil).Once()
+	service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+	assert.ErrorIs(t, service.Delete(context.Background(), "comment-1", entities.UserRef{ID: "joe"}), domain.ErrNotCommentAuthor)
+	require.NoError(t, service.Delete(context.Background(), "comment-1", entities.UserRef{ID: "jane"}))
+	commentRepo.AssertExpectations(t)
+}
+
+func TestCommentService_FindByTaskID(t *testing.T) {
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("FindByTaskID", "task-1", 2, 0).Return([]entities.Comment{
+		{ID: "a", TaskID: "task-1", Body: "First"},
+		{ID: "b", TaskID: "task-1", Body: "Second"},
+	}, int64(3), nil)
+	commentRepo.On("FindReplies", []string{"a", "b"}).Return([]entities.Comment{
+		{ID: "a1", TaskID: "task-1", ParentID: "a", Body: "Reply"},
+	}, nil)
+	service := domain.NewCommentService(commentRepo, commentedTask(), projectMembers())
+
+	threads, total, err := service.FindByTaskID(context.Background(), "task-1", 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, threads, 2)
+	assert.Equal(t, "<p>First</p>\n", threads[0].HTML)
+	require.Len(t, threads[0].Replies, 1)
+	assert.Equal(t, "<p>Reply</p>\n", threads[0].Replies[0].HTML)
+	assert.NotNil(t, threads[1].Replies)
+	assert.Empty(t, threads[1].Replies)
+
+	_, _, err = service.FindByTaskID(context.Background(), "task-9", 2, 0)
+	assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+}
+
+func TestTaskService_CommentCountsAndCascade(t *testing.T) {
+	t.Run("counts are set on read", func(t *testing.T) {
+		commentRepo := new(MockCommentRepository)
+		commentRepo.On("CountByTaskIDs", []string{"story"}).Return(map[string]int{"story": 4}, nil).Once()
+		commentRepo.On("CountByTaskIDs", []string{"step"}).Return(map[string]int{}, nil).Once()
+		repo := testRepository(hierarchyRepo(), projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.CommentRepository = commentRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		task, err := service.FindByID(context.Background(), "story")
+		require.NoError(t, err)
+		assert.Equal(t, 4, task.CommentCount)
+
+		children, err := service.Children(context.Background(), "story")
+		require.NoError(t, err)
+		require.Len(t, children, 1)
+		assert.Equal(t, 0, children[0].CommentCount)
+		commentRepo.AssertExpectations(t)
+	})
+
+	t.Run("deleting a task removes the comments of its subtasks", func(t *testing.T) {
+		taskRepo := hierarchyRepo()
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"step"}).Return([]error{nil}, nil).Once()
+		taskRepo.On("Delete", "story").Return(nil).Once()
+		commentRepo := new(MockCommentRepository)
+		commentRepo.On("DeleteByTaskIDs", []string{"step", "story"}).Return(nil).Once()
+		repo := testRepository(taskRepo, nil)
+		repo.CommentRepository = commentRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		require.NoError(t, service.Delete(context.Background(), "story"))
+		commentRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxCustomFieldNameLength limits the length of custom field names
+const maxCustomFieldNameLength = 100
+
+type customFieldService struct {
+	fieldRepo   storage.CustomFieldRepository
+	taskRepo    storage.TaskRepository
+	projectRepo storage.ProjectRepository
+}
+
+func NewCustomFieldService(fieldRepo storage.CustomFieldRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository) *customFieldService {
+	return &customFieldService{
+		fieldRepo:   fieldRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// Insert defines a field; it returns ErrProjectNotFound for unknown projects
+func (s *customFieldService) Insert(ctx context.Context, field *entities.CustomField) error {
+	if err := s.validate(ctx, field); err != nil {
+		return err
+	}
+	if _, err := s.projectRepo.FindByID(ctx, field.ProjectID); err != nil {
+		return fmt.Errorf("%w: %v", ErrProjectNotFound, err)
+	}
+	return s.fieldRepo.Insert(ctx, field)
+}
+
+// Update renames a field or changes its options. The type cannot change, and
+// options cannot be removed while tasks use them, because stored values would
+// no longer match the field.
+func (s *customFieldService) Update(ctx context.Context, field *entities.CustomField) error {
+	existing, err := s.fieldRepo.FindByID(ctx, field.ID)
+	if err != nil {
+		return err
+	}
+	if field.Type != existing.Type {
+		return invalidf("type", "cannot be changed")
+	}
+	if err := s.validate(ctx, field); err != nil {
+		return err
+	}
+
+	if field.Type == entities.CustomFieldTypeSingleSelect && removesOptions(existing.Options, field.Options) {
+		counts, err := s.taskRepo.CountByCustomFieldValue(ctx, existing.ProjectID, field.ID)
+		if err != nil {
+			return err
+		}
+		for _, option := range existing.Options {
+			if count := counts[option]; count > 0 && !slices.Contains(field.Options, option) {
+				return invalidf("options", "%q is still used by %d tasks, change their value first", option, count)
+			}
+		}
+	}
+
+	return s.fieldRepo.Update(ctx, field)
+}
+
+// removesOptions reports whether an option of before is missing in after
+func removesOptions(before, after []string) bool {
+	for _, option := range before {
+		if !slices.Contains(after, option) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delete removes the field's values from all tasks of its project before deleting it
+func (s *customFieldService) Delete(ctx context.Context, id string) error {
+	field, err := s.fieldRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.taskRepo.RemoveCustomField(ctx, field.ProjectID, id); err != nil {
+		return err
+	}
+	return s.fieldRepo.Delete(ctx, id)
+}
+
+func (s *customFieldService) FindByID(ctx context.Context, id string) (entities.CustomField, error) {
+	return s.fieldRepo.FindByID(ctx, id)
+}
+
+func (s *customFieldService) FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error) {
+	return s.fieldRepo.FindByProjectID(ctx, projectID)
+}
+
+// validate checks name, type and options; names are unique per project regardless of case
+func (s *customFieldService) validate(ctx context.Context, field *entities.CustomField) error {
+	field.Name = strings.TrimSpace(field.Name)
+	if field.Name == "" {
+		return invalidf("name", "is required")
+	}
+	if len(field.Name) > maxCustomFieldNameLength {
+		return invalidf("name", "must not be longer than %d characters", maxCustomFieldNameLength)
+	}
+
+	switch field.Type {
+	case entities.CustomFieldTypeSingleSelect:
+		if len(field.Options) == 0 {
+			return invalidf("options", "are required for single_select fields")
+		}
+		seen := make(map[string]bool, len(field.Options))
+		for i, option := range field.Options {
+			option = strings.TrimSpace(option)
+			if option == "" {
+				return invalidf("options", "must not be empty")
+			}
+			if seen[option] {
+				return invalidf("options", "%q is listed twice", option)
+			}
+			seen[option] = true
+			field.Options[i] = option
+		}
+	case entities.CustomFieldTypeText, entities.CustomFieldTypeNumber, entities.CustomFieldTypeDate, entities.CustomFieldTypeUser:
+		if len(field.Options) > 0 {
+			return invalidf("options", "are only allowed for single_select fields")
+		}
+	default:
+		return invalidf("type", "must be one of text, number, date, single_select, user")
+	}
+
+	existing, err := s.fieldRepo.FindByProjectID(ctx, field.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID != field.ID && strings.EqualFold(other.Name, field.Name) {
+			return invalidf("name", "custom field %q already exists", other.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCustomFieldRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomFieldRepository) Insert(ctx context.Context, field *entities.CustomField) error {
+	args := m.Called(field)
+	return args.Error(0)
+}
+
+func (m *MockCustomFieldRepository) Update(ctx context.Context, field *entities.CustomField) error {
+	args := m.Called(field)
+	return args.Error(0)
+}
+
+func (m *MockCustomFieldRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCustomFieldRepository) FindByID(ctx context.Context, id string) (entities.CustomField, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.CustomField), args.Error(1)
+}
+
+func (m *MockCustomFieldRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.CustomField), args.Error(1)
+}
+
+func (m *MockCustomFieldRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noCustomFields returns a custom field repository for projects without custom fields
+func noCustomFields() *MockCustomFieldRepository {
+	fieldRepo := new(MockCustomFieldRepository)
+	fieldRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return fieldRepo
+}
+
+func TestCustomFieldService_Insert(t *testing.T) {
+	tests := []struct {
+		name          string
+		field         entities.CustomField
+		expectedError string
+	}{
+		{
+			name:  "number field",
+			field: entities.CustomField{ProjectID: "project-1", Name: "Story points", Type: entities.CustomFieldTypeNumber},
+		},
+		{
+			name:  "single select with options",
+			field: entities.CustomField{ProjectID: "project-1", Name: "Team", Type: entities.CustomFieldTypeSingleSelect, Options: []string{"web", " mobile "}},
+		},
+		{
+			name:          "unknown type",
+			field:         entities.CustomField{ProjectID: "project-1", Name: "Team", Type: "checkbox"},
+			expectedError: "type: must be one of text, number, date, single_select, user",
+		},
+		{
+			name:          "single select without options",
+			field:         entities.CustomField{ProjectID: "project-1", Name: "Team", Type: entities.CustomFieldTypeSingleSelect},
+			expectedError: "options: are required for single_select fields",
+		},
+		{
+			name:          "duplicate option",
+			field:         entities.CustomField{ProjectID: "project-1", Name: "Team", Type: entities.CustomFieldTypeSingleSelect, Options: []string{"web", "web"}},
+			expectedError: `options: "web" is listed twice`,
+		},
+		{
+			name:          "options on text field",
+			field:         entities.CustomField{ProjectID: "project-1", Name: "Notes", Type: entities.CustomFieldTypeText, Options: []string{"a"}},
+			expectedError: "options: are only allowed for single_select fields",
+		},
+		{
+			name:          "duplicate name",
+			field:         entities.CustomField{ProjectID: "project-1", Name: "estimate", Type: entities.CustomFieldTypeNumber},
+			expectedError: `name: custom field "Estimate" already exists`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldRepo := new(MockCustomFieldRepository)
+			fieldRepo.On("FindByProjectID", "project-1").Return([]entities.CustomField{{ID: "field-1", Name: "Estimate"}}, nil).Maybe()
+			fieldRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+
+			service := domain.NewCustomFieldService(fieldRepo, new(MockTaskRepository), projectWithWorkflow(entities.DefaultWorkflow()))
+			field := tt.field
+			err := service.Insert(context.Background(), &field)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				fieldRepo.AssertNotCalled(t, "Insert", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			fieldRepo.AssertCalled(t, "Insert", &field)
+			if field.Type == entities.CustomFieldTypeSingleSelect {
+				assert.Equal(t, []string{"web", "mobile"}, field.Options)
+			}
+		})
+	}
+}
+
+func TestCustomFieldService_CreateInUnknownProject(t *testing.T) {
+	fieldRepo := new(MockCustomFieldRepository)
+	fieldRepo.On("FindByProjectID", "missing").Return([]entities.CustomField{}, nil)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("FindByID", "missing").Return(entities.Project{}, errors.New("project not found"))
+
+	service := domain.NewCustomFieldService(fieldRepo, new(MockTaskRepository), projectRepo)
+	err := service.Insert(context.Background(), &entities.CustomField{ProjectID: "missing", Name: "Team", Type: entities.CustomFieldTypeText})
+
+	assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+	fieldRepo.AssertNotCalled(t, "Insert", mock.Anything)
+}
+
+func TestCustomFieldService_UpdateRejectsTypeChange(t *testing.T) {
+	fieldRepo := new(MockCustomFieldRepository)
+	fieldRepo.On("FindByID", "field-1").Return(entities.CustomField{ID: "field-1", ProjectID: "project-1", Name: "Estimate", Type: entities.CustomFieldTypeNumber}, nil)
+
+	service := domain.NewCustomFieldService(fieldRepo, new(MockTaskRepository), projectWithWorkflow(entities.DefaultWorkflow()))
+	field := entities.CustomField{ID: "field-1", ProjectID: "project-1", Name: "Estimate", Type: entities.CustomFieldTypeText}
+
+	assert.EqualError(t, service.Update(context.Background(), &field), "type: cannot be changed")
+	fieldRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestCustomFieldService_UpdateKeepsUsedOptions(t *testing.T) {
+	team := entities.CustomField{ID: "field-1", ProjectID: "project-1", Name: "Team", Type: entities.CustomFieldTypeSingleSelect, Options: []string{"web", "mobile", "backend"}}
+
+	tests := []struct {
+		name          string
+		options       []string
+		expectedError string
+	}{
+		{name: "unused option removed", options: []string{"web", "mobile"}},
+		{name: "option added", options: []string{"web", "mobile", "backend", "desktop"}},
+		{name: "used option removed", options: []string{"web", "backend"}, expectedError: `options: "mobile" is still used by 2 tasks, change their value first`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fieldRepo := new(MockCustomFieldRepository)
+			fieldRepo.On("FindByID", "field-1").Return(team, nil)
+			fieldRepo.On("FindByProjectID", "project-1").Return([]entities.CustomField{team}, nil)
+			fieldRepo.On("Update", mock.Anything).Return(nil).Maybe()
+			taskRepo := new(MockTaskRepository)
+			taskRepo.On("CountByCustomFieldValue", "project-1", "field-1").Return(map[string]int{"web": 3, "mobile": 2}, nil).Maybe()
+
+			service := domain.NewCustomFieldService(fieldRepo, taskRepo, nil)
+			field := entities.CustomField{ID: "field-1", ProjectID: "project-1", Name: "Team", Type: entities.CustomFieldTypeSingleSelect, Options: tt.options}
+			err := service.Update(context.Background(), &field)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				fieldRepo.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			fieldRepo.AssertCalled(t, "Update", &field)
+		})
+	}
+}
+
+func TestCustomFieldService_DeleteRemovesValues(t *testing.T) {
+	fieldRepo := new(MockCustomFieldRepository)
+	taskRepo := new(MockTaskRepository)
+	fieldRepo.On("FindByID", "field-1").Return(entities.CustomField{ID: "field-1", ProjectID: "project-1"}, nil)
+	taskRepo.On("RemoveCustomField", "project-1", "field-1").Return(nil).Once()
+	fieldRepo.On("Delete", "field-1").Return(nil).Once()
+
+	service := domain.NewCustomFieldService(fieldRepo, taskRepo, nil)
+
+	assert.NoError(t, service.Delete(context.Background(), "field-1"))
+	fieldRepo.AssertExpectations(t)
+	taskRepo.AssertExpectations(t)
+}
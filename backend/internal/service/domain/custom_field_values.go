@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTextFieldLength limits the length of text custom field values
+const maxTextFieldLength = 1000
+
+// dateLayouts are accepted for date custom fields, full timestamps first
+var dateLayouts = []string{time.RFC3339, time.DateOnly}
+
+// normalizeFieldValue checks a value against the type of its field and
+// converts it to the representation documented on entities.CustomFieldValues
+func normalizeFieldValue(field entities.CustomField, value interface{}) (interface{}, error) {
+	switch field.Type {
+	case entities.CustomFieldTypeText:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		if len(text) > maxTextFieldLength {
+			return nil, fmt.Errorf("must not be longer than %d characters", maxTextFieldLength)
+		}
+		return text, nil
+	case entities.CustomFieldTypeNumber:
+		number, ok := value.(float64)
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return number, nil
+	case entities.CustomFieldTypeDate:
+		switch v := value.(type) {
+		case time.Time:
+			return v.UTC(), nil
+		case string:
+			for _, layout := range dateLayouts {
+				if date, err := time.Parse(layout, v); err == nil {
+					return date.UTC(), nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+	case entities.CustomFieldTypeSingleSelect:
+		option, ok := value.(string)
+		if !ok || !slices.Contains(field.Options, option) {
+			return nil, fmt.Errorf("must be one of %s", strings.Join(field.Options, ", "))
+		}
+		return option, nil
+	case entities.CustomFieldTypeUser:
+		user, ok := value.(string)
+		if !ok || strings.TrimSpace(user) == "" {
+			return nil, fmt.Errorf("must be a user ID")
+		}
+		return user, nil
+	default:
+		return nil, fmt.Errorf("has unknown type %q", field.Type)
+	}
+}
+
+// parseFieldValue converts a value given as text, e.g. in a query parameter,
+// and normalizes it like normalizeFieldValue
+func parseFieldValue(field entities.CustomField, raw string) (interface{}, error) {
+	if field.Type == entities.CustomFieldTypeNumber {
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return normalizeFieldValue(field, number)
+	}
+	return normalizeFieldValue(field, raw)
+}
+
+// normalizeFieldValues validates values against the custom fields of a
+// project. Fields set to nil are dropped. parse selects how values are read.
+func normalizeFieldValues(values entities.CustomFieldValues, fields []entities.CustomField, parse bool) (entities.CustomFieldValues, error) {
+	byID := make(map[string]entities.CustomField, len(fields))
+	for _, field := range fields {
+		byID[field.ID] = field
+	}
+
+	normalized := make(entities.CustomFieldValues, len(values))
+	for fieldID, value := range values {
+		field, ok := byID[fieldID]
+		if !ok {
+			return nil, invalidf("customFields."+fieldID, "is not a custom field of the project")
+		}
+		if value == nil {
+			continue
+		}
+
+		var err error
+		if raw, isString := value.(string); parse && isString {
+			value, err = parseFieldValue(field, raw)
+		} else {
+			value, err = normalizeFieldValue(field, value)
+		}
+		if err != nil {
+			return nil, invalidf("customFields."+fieldID, "%s %s", field.Name, err.Error())
+		}
+		normalized[fieldID] = value
+	}
+	return normalized, nil
+}
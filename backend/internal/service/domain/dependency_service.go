@@ -0,0 +1,403 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDependencyCycle is returned when a new link would make a task wait for
+	// itself. Handlers answer it with 409 Conflict.
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	// ErrOpenBlockers is returned when a task would move to a status of the done
+	// category while tasks blocking it are not done. Handlers answer it with 422
+	// Unprocessable Entity.
+	ErrOpenBlockers = errors.New("task is blocked by open tasks")
+)
+
+type dependencyService struct {
+	dependencyRepo storage.DependencyRepository
+	taskRepo       storage.TaskRepository
+	projectRepo    storage.ProjectRepository
+}
+
+func NewDependencyService(dependencyRepo storage.DependencyRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository) *dependencyService {
+	return &dependencyService{
+		dependencyRepo: dependencyRepo,
+		taskRepo:       taskRepo,
+		projectRepo:    projectRepo,
+	}
+}
+
+// Add records that the blocker task blocks the blocked task. The tasks may
+// belong to different projects. Links that would close a cycle are rejected
+// with ErrDependencyCycle.
+func (s *dependencyService) Add(ctx context.Context, blockerID, blockedID string) (entities.TaskDependency, error) {
+	if blockerID == blockedID {
+		return entities.TaskDependency{}, invalidf("blockerId", "a task cannot block itself")
+	}
+	blocker, err := s.taskRepo.FindByID(ctx, blockerID)
+	if err != nil {
+		return entities.TaskDependency{}, invalidf("blockerId", "task %s not found", blockerID)
+	}
+	blocked, err := s.taskRepo.FindByID(ctx, blockedID)
+	if err != nil {
+		return entities.TaskDependency{}, invalidf("blockedId", "task %s not found", blockedID)
+	}
+
+	existing, err := s.dependencyRepo.FindByBlockers(ctx, []string{blockerID})
+	if err != nil {
+		return entities.TaskDependency{}, err
+	}
+	for _, dependency := range existing {
+		if dependency.BlockedID == blockedID {
+			return entities.TaskDependency{}, invalidf("blockedId", "%q is already blocked by %q", blocked.Title, blocker.Title)
+		}
+	}
+
+	// The new link closes a cycle if the blocker already waits for the blocked task
+	waiting, err := s.reachable(ctx, blockedID, blockerID)
+	if err != nil {
+		return entities.TaskDependency{}, err
+	}
+	if waiting {
+		return entities.TaskDependency{}, fmt.Errorf("%w: %q already waits for %q", ErrDependencyCycle, blocker.Title, blocked.Title)
+	}
+
+	dependency := entities.TaskDependency{
+		BlockerID:        blocker.ID,
+		BlockerProjectID: blocker.ProjectID,
+		BlockedID:        blocked.ID,
+		BlockedProjectID: blocked.ProjectID,
+	}
+	if err := s.dependencyRepo.Insert(ctx, &dependency); err != nil {
+		return entities.TaskDependency{}, err
+	}
+	return dependency, nil
+}
+
+// reachable walks the blocks links breadth-first and reports whether target
+// can be reached from start
+func (s *dependencyService) reachable(ctx context.Context, start, target string) (bool, error) {
+	seen := map[string]bool{start: true}
+	frontier := []string{start}
+	for len(frontier) > 0 {
+		links, err := s.dependencyRepo.FindByBlockers(ctx, frontier)
+		if err != nil {
+			return false, err
+		}
+		frontier = nil
+		for _, link := range links {
+			if link.BlockedID == target {
+				return true, nil
+			}
+			if !seen[link.BlockedID] {
+				seen[link.BlockedID] = true
+				frontier = append(frontier, link.BlockedID)
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *dependencyService) Remove(ctx context.Context, blockerID, blockedID string) error {
+	return s.dependencyRepo.Delete(ctx, blockerID, blockedID)
+}
+
+// FindByTaskID lists the links of a task in both directions
+func (s *dependencyService) FindByTaskID(ctx context.Context, taskID string) (entities.TaskDependencies, error) {
+	if _, err := s.taskRepo.FindByID(ctx, taskID); err != nil {
+		return entities.TaskDependencies{}, err
+	}
+	blockedBy, err := s.dependencyRepo.FindByBlocked(ctx, []string{taskID})
+	if err != nil {
+		return entities.TaskDependencies{}, err
+	}
+	blocks, err := s.dependencyRepo.FindByBlockers(ctx, []string{taskID})
+	if err != nil {
+		return entities.TaskDependencies{}, err
+	}
+	return entities.TaskDependencies{BlockedBy: nonNil(blockedBy), Blocks: nonNil(blocks)}, nil
+}
+
+func nonNil(dependencies []entities.TaskDependency) []entities.TaskDependency {
+	if dependencies == nil {
+		return []entities.TaskDependency{}
+	}
+	return dependencies
+}
+
+// Graph returns the dependency DAG of a project's tasks, including the tasks
+// of other projects they are linked to
+func (s *dependencyService) Graph(ctx context.Context, projectID string) (entities.TaskGraph, error) {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return entities.TaskGraph{}, err
+	}
+	tasks, err := s.taskRepo.FindByProjectID(ctx, projectID, entities.TaskFilter{})
+	if err != nil {
+		return entities.TaskGraph{}, err
+	}
+	links, err := s.dependencyRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return entities.TaskGraph{}, err
+	}
+
+	graph := entities.TaskGraph{
+		Nodes:        make([]entities.TaskGraphNode, 0, len(tasks)),
+		Edges:        []entities.TaskDependency{},
+		CriticalPath: []string{},
+	}
+	nodes := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		graph.Nodes = append(graph.Nodes, graphNode(task, isDone(project.Workflow, task.Status), false))
+		nodes[task.ID] = true
+	}
+
+	external := make(map[string][]string)
+	for _, link := range links {
+		if !nodes[link.BlockerID] {
+			external[link.BlockerProjectID] = append(external[link.BlockerProjectID], link.BlockerID)
+		}
+		if !nodes[link.BlockedID] {
+			external[link.BlockedProjectID] = append(external[link.BlockedProjectID], link.BlockedID)
+		}
+	}
+	linked, err := loadLinkedTasks(ctx, s.taskRepo, s.projectRepo, external)
+	if err != nil {
+		return entities.TaskGraph{}, err
+	}
+	for _, task := range linked {
+		if !nodes[task.ID] {
+			graph.Nodes = append(graph.Nodes, graphNode(task.Task, task.done, true))
+			nodes[task.ID] = true
+		}
+	}
+
+	for _, link := range links {
+		// Links to tasks that no longer exist are left out
+		if nodes[link.BlockerID] && nodes[link.BlockedID] {
+			graph.Edges = append(graph.Edges, link)
+		}
+	}
+
+	graph.Order = topologicalOrder(graph.Nodes, graph.Edges)
+	graph.CriticalPath = criticalPath(graph.Nodes, graph.Edges, graph.Order)
+	return graph, nil
+}
+
+func graphNode(task entities.Task, done, external bool) entities.TaskGraphNode {
+	return entities.TaskGraphNode{
+		ID:        task.ID,
+		ProjectID: task.ProjectID,
+		Title:     task.Title,
+		Status:    task.Status,
+		DueDate:   task.DueDate,
+		Done:      done,
+		External:  external,
+	}
+}
+
+// topologicalOrder sorts the nodes so that every blocker precedes the tasks it
+// blocks. Among tasks that are ready at the same time, earlier due dates come
+// first, then the node order. Nodes on a cycle, which the service never
+// creates, are appended in node order.
+func topologicalOrder(nodes []entities.TaskGraphNode, edges []entities.TaskDependency) []string {
+	index := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		index[node.ID] = i
+	}
+	blocking := make(map[string]int, len(nodes))
+	blocks := make(map[string][]string, len(nodes))
+	for _, edge := range edges {
+		blocking[edge.BlockedID]++
+		blocks[edge.BlockerID] = append(blocks[edge.BlockerID], edge.BlockedID)
+	}
+
+	before := func(a, b string) bool {
+		dueA, dueB := nodes[index[a]].DueDate, nodes[index[b]].DueDate
+		switch {
+		case dueA != nil && dueB != nil && !dueA.Equal(*dueB):
+			return dueA.Before(*dueB)
+		case (dueA == nil) != (dueB == nil):
+			return dueA != nil
+		default:
+			return index[a] < index[b]
+		}
+	}
+
+	var ready []string
+	for _, node := range nodes {
+		if blocking[node.ID] == 0 {
+			ready = append(ready, node.ID)
+		}
+	}
+
+	order := make([]string, 0, len(nodes))
+	placed := make(map[string]bool, len(nodes))
+	for len(ready) > 0 {
+		sort.SliceStable(ready, func(i, j int) bool { return before(ready[i], ready[j]) })
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+		placed[id] = true
+		for _, blocked := range blocks[id] {
+			blocking[blocked]--
+			if blocking[blocked] == 0 {
+				ready = append(ready, blocked)
+			}
+		}
+	}
+	for _, node := range nodes {
+		if !placed[node.ID] {
+			order = append(order, node.ID)
+		}
+	}
+	return order
+}
+
+// criticalPath finds the chain of open tasks whose due dates span the longest
+// time. Each link counts the time from the blocker's due date to the blocked
+// task's due date; links without both due dates count as zero. Ties go to the
+// longer chain. Chains need at least two tasks.
+func criticalPath(nodes []entities.TaskGraphNode, edges []entities.TaskDependency, order []string) []string {
+	byID := make(map[string]entities.TaskGraphNode, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+	blockedBy := make(map[string][]string, len(nodes))
+	for _, edge := range edges {
+		if !byID[edge.BlockerID].Done && !byID[edge.BlockedID].Done {
+			blockedBy[edge.BlockedID] = append(blockedBy[edge.BlockedID], edge.BlockerID)
+		}
+	}
+
+	type chain struct {
+		span   time.Duration
+		length int
+		prev   string
+	}
+	longer := func(a, b chain) bool {
+		return a.span > b.span || (a.span == b.span && a.length > b.length)
+	}
+
+	chains := make(map[string]chain, len(nodes))
+	var end string
+	for _, id := range order {
+		node := byID[id]
+		if node.Done {
+			continue
+		}
+		best := chain{length: 1}
+		for _, blockerID := range blockedBy[id] {
+			blocker, ok := chains[blockerID]
+			if !ok {
+				continue
+			}
+			candidate := chain{span: blocker.span + dueGap(byID[blockerID], node), length: blocker.length + 1, prev: blockerID}
+			if longer(candidate, best) {
+				best = candidate
+			}
+		}
+		chains[id] = best
+		if end == "" || longer(best, chains[end]) {
+			end = id
+		}
+	}
+
+	if end == "" || chains[end].length < 2 {
+		return []string{}
+	}
+	path := make([]string, chains[end].length)
+	for i, id := len(path)-1, end; i >= 0; i, id = i-1, chains[id].prev {
+		path[i] = id
+	}
+	return path
+}
+
+// dueGap is the time from the blocker's due date to the blocked task's, or zero
+func dueGap(blocker, blocked entities.TaskGraphNode) time.Duration {
+	if blocker.DueDate == nil || blocked.DueDate == nil || !blocked.DueDate.After(*blocker.DueDate) {
+		return 0
+	}
+	return blocked.DueDate.Sub(*blocker.DueDate)
+}
+
+// linkedTask is a task at one end of a dependency link
+type linkedTask struct {
+	entities.Task
+	// done is set if the task is in a done status of its project's workflow
+	done bool
+}
+
+// loadLinkedTasks reads tasks of any project, given as task IDs per project ID
+func loadLinkedTasks(ctx context.Context, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository, idsByProject map[string][]string) ([]linkedTask, error) {
+	projectIDs := make([]string, 0, len(idsByProject))
+	for projectID := range idsByProject {
+		projectIDs = append(projectIDs, projectID)
+	}
+	sort.Strings(projectIDs)
+
+	var linked []linkedTask
+	for _, projectID := range projectIDs {
+		project, err := projectRepo.FindByID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		tasks, err := taskRepo.FindByProjectID(ctx, projectID, entities.TaskFilter{IDs: idsByProject[projectID]})
+		if err != nil {
+			return nil, err
+		}
+		for _, task := range tasks {
+			linked = append(linked, linkedTask{Task: task, done: isDone(project.Workflow, task.Status)})
+		}
+	}
+	return linked, nil
+}
+
+// openBlockers returns the tasks that block the given tasks and are not done,
+// keyed by the ID of the blocked task
+func openBlockers(ctx context.Context, dependencyRepo storage.DependencyRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository, taskIDs []string) (map[string][]entities.Task, error) {
+	links, err := dependencyRepo.FindByBlocked(ctx, taskIDs)
+	if err != nil || len(links) == 0 {
+		return nil, err
+	}
+
+	idsByProject := make(map[string][]string)
+	for _, link := range links {
+		idsByProject[link.BlockerProjectID] = append(idsByProject[link.BlockerProjectID], link.BlockerID)
+	}
+	blockers, err := loadLinkedTasks(ctx, taskRepo, projectRepo, idsByProject)
+	if err != nil {
+		return nil, err
+	}
+	open := make(map[string]entities.Task, len(blockers))
+	for _, blocker := range blockers {
+		if !blocker.done {
+			open[blocker.ID] = blocker.Task
+		}
+	}
+
+	result := make(map[string][]entities.Task)
+	for _, link := range links {
+		if blocker, ok := open[link.BlockerID]; ok {
+			result[link.BlockedID] = append(result[link.BlockedID], blocker)
+		}
+	}
+	return result, nil
+}
+
+// blockedError describes the open blockers of a task as ErrOpenBlockers
+func blockedError(blockers []entities.Task) error {
+	titles := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		titles[i] = fmt.Sprintf("%q", blocker.Title)
+	}
+	return fmt.Errorf("%w: %s", ErrOpenBlockers, strings.Join(titles, ", "))
+}
@@ -0,0 +1,245 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockDependencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockDependencyRepository) Insert(ctx context.Context, dependency *entities.TaskDependency) error {
+	args := m.Called(dependency)
+	return args.Error(0)
+}
+
+func (m *MockDependencyRepository) Delete(ctx context.Context, blockerID, blockedID string) error {
+	args := m.Called(blockerID, blockedID)
+	return args.Error(0)
+}
+
+func (m *MockDependencyRepository) FindByBlockers(ctx context.Context, taskIDs []string) ([]entities.TaskDependency, error) {
+	args := m.Called(taskIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TaskDependency), args.Error(1)
+}
+
+func (m *MockDependencyRepository) FindByBlocked(ctx context.Context, taskIDs []string) ([]entities.TaskDependency, error) {
+	args := m.Called(taskIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TaskDependency), args.Error(1)
+}
+
+func (m *MockDependencyRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.TaskDependency, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TaskDependency), args.Error(1)
+}
+
+func (m *MockDependencyRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockDependencyRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noDependencies returns a dependency repository without any links
+func noDependencies() *MockDependencyRepository {
+	dependencyRepo := new(MockDependencyRepository)
+	dependencyRepo.On("FindByBlockers", mock.Anything).Return(nil, nil).Maybe()
+	dependencyRepo.On("FindByBlocked", mock.Anything).Return(nil, nil).Maybe()
+	dependencyRepo.On("FindByProjectID", mock.Anything).Return(nil, nil).Maybe()
+	dependencyRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	dependencyRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return dependencyRepo
+}
+
+func link(blockerID, blockedID string) entities.TaskDependency {
+	return entities.TaskDependency{BlockerID: blockerID, BlockerProjectID: "project-1", BlockedID: blockedID, BlockedProjectID: "project-1"}
+}
+
+func TestDependencyService_Add(t *testing.T) {
+	tasks := map[string]entities.Task{
+		"design": {ID: "design", ProjectID: "project-1", Title: "Design"},
+		"build":  {ID: "build", ProjectID: "project-1", Title: "Build"},
+		"ship":   {ID: "ship", ProjectID: "project-2", Title: "Ship"},
+	}
+	taskRepo := new(MockTaskRepository)
+	for id, task := range tasks {
+		taskRepo.On("FindByID", id).Return(task, nil).Maybe()
+	}
+	taskRepo.On("FindByID", "unknown").Return(nil, errors.New("task not found")).Maybe()
+
+	// design blocks build, build blocks ship
+	dependencyRepo := new(MockDependencyRepository)
+	dependencyRepo.On("FindByBlockers", []string{"design"}).Return([]entities.TaskDependency{link("design", "build")}, nil).Maybe()
+	dependencyRepo.On("FindByBlockers", []string{"build"}).Return([]entities.TaskDependency{link("build", "ship")}, nil).Maybe()
+	dependencyRepo.On("FindByBlockers", []string{"ship"}).Return(nil, nil).Maybe()
+	service := domain.NewDependencyService(dependencyRepo, taskRepo, nil)
+
+	tests := []struct {
+		name          string
+		blockerID     string
+		blockedID     string
+		expectedError string
+	}{
+		{name: "self", blockerID: "build", blockedID: "build", expectedError: "blockerId: a task cannot block itself"},
+		{name: "unknown blocker", blockerID: "unknown", blockedID: "build", expectedError: "blockerId: task unknown not found"},
+		{name: "duplicate", blockerID: "design", blockedID: "build", expectedError: `blockedId: "Build" is already blocked by "Design"`},
+		{name: "direct cycle", blockerID: "build", blockedID: "design", expectedError: domain.ErrDependencyCycle.Error()},
+		{name: "transitive cycle", blockerID: "ship", blockedID: "design", expectedError: domain.ErrDependencyCycle.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Add(context.Background(), tt.blockerID, tt.blockedID)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedError)
+		})
+	}
+
+	t.Run("across projects", func(t *testing.T) {
+		dependencyRepo.On("Insert", mock.Anything).Return(nil).Once()
+
+		dependency, err := service.Add(context.Background(), "design", "ship")
+
+		require.NoError(t, err)
+		assert.Equal(t, "project-1", dependency.BlockerProjectID)
+		assert.Equal(t, "project-2", dependency.BlockedProjectID)
+		dependencyRepo.AssertExpectations(t)
+	})
+}
+
+// blockedTaskRepo serves a task of project-1 that is blocked by a task of project-2
+func blockedTaskRepo(blockerStatus entities.TaskStatus) (*MockTaskRepository, *MockDependencyRepository) {
+	blocked := entities.Task{ID: "release", ProjectID: "project-1", Title: "Release", Status: entities.TaskStatusInProgress}
+	blocker := entities.Task{ID: "audit", ProjectID: "project-2", Title: "Audit", Status: blockerStatus}
+
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByID", "release").Return(blocked, nil).Maybe()
+	taskRepo.On("FindByProjectID", "project-1").Return([]entities.Task{blocked}, nil).Maybe()
+	taskRepo.On("FindByProjectID", "project-2").Return([]entities.Task{blocker}, nil).Maybe()
+
+	dependencyRepo := new(MockDependencyRepository)
+	dependencyRepo.On("FindByBlocked", []string{"release"}).Return([]entities.TaskDependency{
+		{BlockerID: "audit", BlockerProjectID: "project-2", BlockedID: "release", BlockedProjectID: "project-1"},
+	}, nil)
+	return taskRepo, dependencyRepo
+}
+
+func TestTaskService_UpdateChecksBlockers(t *testing.T) {
+	t.Run("open blocker", func(t *testing.T) {
+		taskRepo, dependencyRepo := blockedTaskRepo(entities.TaskStatusInProgress)
+		repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.DependencyRepository = dependencyRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		task := entities.Task{ID: "release", ProjectID: "project-1", Title: "Release", Status: entities.TaskStatusDone}
+		err := service.Update(context.Background(), &task)
+
+		assert.ErrorIs(t, err, domain.ErrOpenBlockers)
+		assert.Contains(t, err.Error(), `"Audit"`)
+		taskRepo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("done blocker", func(t *testing.T) {
+		taskRepo, dependencyRepo := blockedTaskRepo(entities.TaskStatusDone)
+		taskRepo.On("Update", mock.Anything).Return(nil).Once()
+		repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.DependencyRepository = dependencyRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		task := entities.Task{ID: "release", ProjectID: "project-1", Title: "Release", Status: entities.TaskStatusDone}
+		require.NoError(t, service.Update(context.Background(), &task))
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		taskRepo, dependencyRepo := blockedTaskRepo(entities.TaskStatusTodo)
+		taskRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{}, nil)
+		taskRepo.On("UpdateMany", mock.Anything, "project-1", []entities.TaskPatch(nil)).Return([]error{}, nil).Once()
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", mock.Anything).Return([]error{}, nil)
+		repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.DependencyRepository = dependencyRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		done := entities.TaskStatusDone
+		ops := []entities.TaskBatchOperation{{Op: entities.TaskBatchOpUpdate, ID: "release", Patch: &entities.TaskPatch{Status: &done}}}
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+
+		require.NoError(t, err)
+		assert.Equal(t, entities.TaskBatchStatusFailed, results[0].Status)
+		assert.Contains(t, results[0].Error, domain.ErrOpenBlockers.Error())
+		taskRepo.AssertExpectations(t)
+	})
+}
+
+func TestDependencyService_Graph(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	// spec -> api -> ui -> launch, spec -> docs -> launch, legal (project-2) -> launch
+	tasks := []entities.Task{
+		{ID: "launch", ProjectID: "project-1", Title: "Launch", Status: entities.TaskStatusTodo, DueDate: day(20)},
+		{ID: "docs", ProjectID: "project-1", Title: "Docs", Status: entities.TaskStatusTodo, DueDate: day(12)},
+		{ID: "ui", ProjectID: "project-1", Title: "UI", Status: entities.TaskStatusTodo, DueDate: day(15)},
+		{ID: "api", ProjectID: "project-1", Title: "API", Status: entities.TaskStatusInProgress, DueDate: day(8)},
+		{ID: "spec", ProjectID: "project-1", Title: "Spec", Status: entities.TaskStatusDone, DueDate: day(1)},
+		{ID: "idea", ProjectID: "project-1", Title: "Idea", Status: entities.TaskStatusTodo},
+	}
+	legal := entities.Task{ID: "legal", ProjectID: "project-2", Title: "Legal", Status: entities.TaskStatusTodo, DueDate: day(18)}
+
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByProjectID", "project-1").Return(tasks, nil)
+	taskRepo.On("FindByProjectID", "project-2").Return([]entities.Task{legal}, nil)
+	dependencyRepo := new(MockDependencyRepository)
+	dependencyRepo.On("FindByProjectID", "project-1").Return([]entities.TaskDependency{
+		link("spec", "api"), link("api", "ui"), link("ui", "launch"), link("spec", "docs"), link("docs", "launch"),
+		{BlockerID: "legal", BlockerProjectID: "project-2", BlockedID: "launch", BlockedProjectID: "project-1"},
+	}, nil)
+	service := domain.NewDependencyService(dependencyRepo, taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+
+	graph, err := service.Graph(context.Background(), "project-1")
+
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 7)
+	assert.Equal(t, "legal", graph.Nodes[6].ID)
+	assert.True(t, graph.Nodes[6].External)
+	assert.True(t, graph.Nodes[4].Done)
+	assert.Len(t, graph.Edges, 6)
+	assert.Equal(t, []string{"spec", "api", "docs", "ui", "legal", "launch", "idea"}, graph.Order)
+	// spec is done, so the path starts at api and spans 12 days
+	assert.Equal(t, []string{"api", "ui", "launch"}, graph.CriticalPath)
+}
+
+func TestTaskService_DeleteRemovesDependencies(t *testing.T) {
+	taskRepo := hierarchyRepo()
+	taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"step"}).Return([]error{nil}, nil).Once()
+	taskRepo.On("Delete", "story").Return(nil).Once()
+	dependencyRepo := new(MockDependencyRepository)
+	dependencyRepo.On("DeleteByTaskIDs", []string{"step", "story"}).Return(nil).Once()
+	repo := testRepository(taskRepo, nil)
+	repo.DependencyRepository = dependencyRepo
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	require.NoError(t, service.Delete(context.Background(), "story"))
+	dependencyRepo.AssertExpectations(t)
+}
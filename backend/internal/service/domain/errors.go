@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrProjectNotFound is returned when something is added to, or reported for,
+// a project that does not exist
+var ErrProjectNotFound = errors.New("project not found")
+
+// ValidationError reports input that violates a domain rule. Handlers answer
+// it with 400 Bad Request and the message.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func invalidf(field, format string, args ...interface{}) error {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxLabelNameLength limits the length of label names
+const maxLabelNameLength = 50
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type labelService struct {
+	labelRepo   storage.LabelRepository
+	taskRepo    storage.TaskRepository
+	projectRepo storage.ProjectRepository
+}
+
+func NewLabelService(labelRepo storage.LabelRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository) *labelService {
+	return &labelService{
+		labelRepo:   labelRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// Insert creates a label; it returns ErrProjectNotFound for unknown projects
+func (s *labelService) Insert(ctx context.Context, label *entities.Label) error {
+	if err := s.validate(ctx, label); err != nil {
+		return err
+	}
+	if _, err := s.projectRepo.FindByID(ctx, label.ProjectID); err != nil {
+		return fmt.Errorf("%w: %v", ErrProjectNotFound, err)
+	}
+	return s.labelRepo.Insert(ctx, label)
+}
+
+func (s *labelService) Update(ctx context.Context, label *entities.Label) error {
+	if err := s.validate(ctx, label); err != nil {
+		return err
+	}
+	return s.labelRepo.Update(ctx, label)
+}
+
+// Delete removes the label from all tasks of its project before deleting it
+func (s *labelService) Delete(ctx context.Context, id string) error {
+	label, err := s.labelRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.taskRepo.RemoveLabel(ctx, label.ProjectID, id); err != nil {
+		return err
+	}
+	return s.labelRepo.Delete(ctx, id)
+}
+
+func (s *labelService) FindByID(ctx context.Context, id string) (entities.Label, error) {
+	return s.labelRepo.FindByID(ctx, id)
+}
+
+func (s *labelService) FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error) {
+	return s.labelRepo.FindByProjectID(ctx, projectID)
+}
+
+// validate checks name and colour; names are unique per project regardless of case
+func (s *labelService) validate(ctx context.Context, label *entities.Label) error {
+	label.Name = strings.TrimSpace(label.Name)
+	if label.Name == "" {
+		return invalidf("name", "is required")
+	}
+	if len(label.Name) > maxLabelNameLength {
+		return invalidf("name", "must not be longer than %d characters", maxLabelNameLength)
+	}
+	if !labelColorPattern.MatchString(label.Color) {
+		return invalidf("color", "must be a hex colour like #d73a4a")
+	}
+	label.Color = strings.ToLower(label.Color)
+
+	existing, err := s.labelRepo.FindByProjectID(ctx, label.ProjectID)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID != label.ID && strings.EqualFold(other.Name, label.Name) {
+			return invalidf("name", "label %q already exists", other.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLabelRepository struct {
+	mock.Mock
+}
+
+func (m *MockLabelRepository) Insert(ctx context.Context, label *entities.Label) error {
+	args := m.Called(label)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepository) Update(ctx context.Context, label *entities.Label) error {
+	args := m.Called(label)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockLabelRepository) FindByID(ctx context.Context, id string) (entities.Label, error) {
+	args := m.Called(id)
+	return args.Get(0).(entities.Label), args.Error(1)
+}
+
+func (m *MockLabelRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Label), args.Error(1)
+}
+
+func (m *MockLabelRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noLabels returns a label repository for projects without labels
+func noLabels() *MockLabelRepository {
+	labelRepo := new(MockLabelRepository)
+	labelRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return labelRepo
+}
+
+func TestLabelService_Insert(t *testing.T) {
+	existing := []entities.Label{{ID: "label-1", ProjectID: "project-1", Name: "Bug", Color: "#d73a4a"}}
+
+	tests := []struct {
+		name          string
+		label         entities.Label
+		expectedError string
+	}{
+		{
+			name:  "valid label",
+			label: entities.Label{ProjectID: "project-1", Name: "  frontend ", Color: "#A2EEEF"},
+		},
+		{
+			name:          "missing name",
+			label:         entities.Label{ProjectID: "project-1", Name: " ", Color: "#a2eeef"},
+			expectedError: "name: is required",
+		},
+		{
+			name:          "invalid colour",
+			label:         entities.Label{ProjectID: "project-1", Name: "frontend", Color: "blue"},
+			expectedError: "color: must be a hex colour like #d73a4a",
+		},
+		{
+			name:          "duplicate name ignoring case",
+			label:         entities.Label{ProjectID: "project-1", Name: "bug", Color: "#a2eeef"},
+			expectedError: `name: label "Bug" already exists`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labelRepo := new(MockLabelRepository)
+			labelRepo.On("FindByProjectID", "project-1").Return(existing, nil).Maybe()
+			labelRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+
+			service := domain.NewLabelService(labelRepo, new(MockTaskRepository), projectWithWorkflow(entities.DefaultWorkflow()))
+			label := tt.label
+			err := service.Insert(context.Background(), &label)
+
+			if tt.expectedError != "" {
+				var validationErr *domain.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.EqualError(t, err, tt.expectedError)
+				labelRepo.AssertNotCalled(t, "Insert", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "frontend", label.Name)
+			assert.Equal(t, "#a2eeef", label.Color)
+			labelRepo.AssertCalled(t, "Insert", &label)
+		})
+	}
+}
+
+func TestLabelService_CreateInUnknownProject(t *testing.T) {
+	labelRepo := new(MockLabelRepository)
+	labelRepo.On("FindByProjectID", "missing").Return([]entities.Label{}, nil)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("FindByID", "missing").Return(entities.Project{}, errors.New("project not found"))
+
+	service := domain.NewLabelService(labelRepo, new(MockTaskRepository), projectRepo)
+	err := service.Insert(context.Background(), &entities.Label{ProjectID: "missing", Name: "bug", Color: "#d73a4a"})
+
+	assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+	labelRepo.AssertNotCalled(t, "Insert", mock.Anything)
+}
+
+func TestLabelService_UpdateKeepsOwnName(t *testing.T) {
+	label := entities.Label{ID: "label-1", ProjectID: "project-1", Name: "Bug", Color: "#000000"}
+	labelRepo := new(MockLabelRepository)
+	labelRepo.On("FindByProjectID", "project-1").Return([]entities.Label{{ID: "label-1", ProjectID: "project-1", Name: "bug"}}, nil)
+	labelRepo.On("Update", &label).Return(nil)
+
+	service := domain.NewLabelService(labelRepo, new(MockTaskRepository), projectWithWorkflow(entities.DefaultWorkflow()))
+
+	assert.NoError(t, service.Update(context.Background(), &label))
+	labelRepo.AssertExpectations(t)
+}
+
+func TestLabelService_DeleteDetachesFromTasks(t *testing.T) {
+	labelRepo := new(MockLabelRepository)
+	taskRepo := new(MockTaskRepository)
+	labelRepo.On("FindByID", "label-1").Return(entities.Label{ID: "label-1", ProjectID: "project-1"}, nil)
+	taskRepo.On("RemoveLabel", "project-1", "label-1").Return(nil).Once()
+	labelRepo.On("Delete", "label-1").Return(nil).Once()
+
+	service := domain.NewLabelService(labelRepo, taskRepo, nil)
+
+	assert.NoError(t, service.Delete(context.Background(), "label-1"))
+	labelRepo.AssertExpectations(t)
+	taskRepo.AssertExpectations(t)
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"bytes"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"html"
+)
+
+var (
+	// markdown renders GitHub flavored Markdown. Raw HTML in the source is
+	// dropped by the renderer before the output is sanitized.
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	// htmlPolicy allows the formatting user generated content needs and strips
+	// scripts, styles and unsafe URLs
+	htmlPolicy = bluemonday.UGCPolicy().RequireNoReferrerOnLinks(true).AddTargetBlankToFullyQualifiedLinks(true)
+)
+
+// renderMarkdown converts a Markdown source to sanitized HTML
+func renderMarkdown(source string) string {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "<p>" + html.EscapeString(source) + "</p>"
+	}
+	return htmlPolicy.Sanitize(buf.String())
+}
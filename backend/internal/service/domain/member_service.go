@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"context"
+	"fmt"
+	"strings"
+)
+
+type memberService struct {
+	memberRepo  storage.MemberRepository
+	taskRepo    storage.TaskRepository
+	projectRepo storage.ProjectRepository
+}
+
+func NewMemberService(memberRepo storage.MemberRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository) *memberService {
+	return &memberService{
+		memberRepo:  memberRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+// Add makes a user a member of a project. Adding an existing member refreshes
+// the name and email stored for them.
+func (s *memberService) Add(ctx context.Context, member *entities.ProjectMember) error {
+	member.UserID = strings.TrimSpace(member.UserID)
+	if member.UserID == "" {
+		return invalidf("userId", "is required")
+	}
+	if _, err := s.projectRepo.FindByID(ctx, member.ProjectID); err != nil {
+		return fmt.Errorf("%w: %v", ErrProjectNotFound, err)
+	}
+	member.Name = strings.TrimSpace(member.Name)
+	member.Email = strings.TrimSpace(member.Email)
+	return s.memberRepo.Upsert(ctx, member)
+}
+
+// Remove takes a user out of a project, unassigning them from its tasks and
+// removing them as watcher first
+func (s *memberService) Remove(ctx context.Context, projectID, userID string) error {
+	if err := s.taskRepo.RemoveUser(ctx, projectID, userID); err != nil {
+		return err
+	}
+	return s.memberRepo.Delete(ctx, projectID, userID)
+}
+
+func (s *memberService) FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error) {
+	return s.memberRepo.FindByProjectID(ctx, projectID)
+}
+
+// resolveUsers checks that every user is a member of the project and returns
+// the users without duplicates. Users given with a name or email keep it,
+// the others get the snapshot stored with their membership.
+func resolveUsers(field string, users []entities.UserRef, members map[string]entities.ProjectMember) ([]entities.UserRef, error) {
+	resolved := make([]entities.UserRef, 0, len(users))
+	seen := make(map[string]bool, len(users))
+	for _, user := range users {
+		member, ok := members[user.ID]
+		if !ok {
+			return nil, invalidf(field, "%s is not a member of the project", user.ID)
+		}
+		if seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		if user.Name == "" && user.Email == "" {
+			user = member.Ref()
+		}
+		resolved = append(resolved, user)
+	}
+	return resolved, nil
+}
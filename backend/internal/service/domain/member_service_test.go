@@ -0,0 +1,166 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockMemberRepository struct {
+	mock.Mock
+}
+
+func (m *MockMemberRepository) Upsert(ctx context.Context, member *entities.ProjectMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockMemberRepository) Delete(ctx context.Context, projectID, userID string) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockMemberRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.ProjectMember), args.Error(1)
+}
+
+func (m *MockMemberRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noMembers returns a member repository for projects without members
+func noMembers() *MockMemberRepository {
+	memberRepo := new(MockMemberRepository)
+	memberRepo.On("FindByProjectID", mock.Anything).Return(nil, nil).Maybe()
+	memberRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return memberRepo
+}
+
+// projectMembers returns a member repository in which jane and joe are members of project-1
+func projectMembers() *MockMemberRepository {
+	memberRepo := new(MockMemberRepository)
+	memberRepo.On("FindByProjectID", "project-1").Return([]entities.ProjectMember{
+		{ProjectID: "project-1", UserID: "jane", Name: "Jane", Email: "jane@example.com"},
+		{ProjectID: "project-1", UserID: "joe", Name: "Joe"},
+	}, nil).Maybe()
+	return memberRepo
+}
+
+func TestMemberService_Add(t *testing.T) {
+	memberRepo := new(MockMemberRepository)
+	memberRepo.On("Upsert", mock.Anything).Return(nil).Once()
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("FindByID", "project-1").Return(entities.Project{ID: "project-1"}, nil)
+	projectRepo.On("FindByID", "project-2").Return(entities.Project{}, errors.New("project not found"))
+	service := domain.NewMemberService(memberRepo, nil, projectRepo)
+
+	err := service.Add(context.Background(), &entities.ProjectMember{ProjectID: "project-1", UserID: "  "})
+	assert.EqualError(t, err, "userId: is required")
+
+	err = service.Add(context.Background(), &entities.ProjectMember{ProjectID: "project-2", UserID: "jane"})
+	assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+
+	member := &entities.ProjectMember{ProjectID: "project-1", UserID: " jane ", Name: " Jane "}
+	require.NoError(t, service.Add(context.Background(), member))
+	assert.Equal(t, "jane", member.UserID)
+	assert.Equal(t, "Jane", member.Name)
+	memberRepo.AssertExpectations(t)
+}
+
+func TestMemberService_RemoveUnassignsFromTasks(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("RemoveUser", "project-1", "jane").Return(nil).Once()
+	memberRepo := new(MockMemberRepository)
+	memberRepo.On("Delete", "project-1", "jane").Return(nil).Once()
+	service := domain.NewMemberService(memberRepo, taskRepo, nil)
+
+	require.NoError(t, service.Remove(context.Background(), "project-1", "jane"))
+	taskRepo.AssertExpectations(t)
+	memberRepo.AssertExpectations(t)
+}
+
+func TestTaskService_AssigneesMustBeMembers(t *testing.T) {
+	tests := []struct {
+		name              string
+		assignees         []entities.UserRef
+		watchers          []entities.UserRef
+		expectedError     string
+		expectedAssignees []entities.UserRef
+	}{
+		{
+			name:              "snapshot from membership",
+			assignees:         []entities.UserRef{{ID: "jane"}, {ID: "jane"}},
+			expectedAssignees: []entities.UserRef{{ID: "jane", Name: "Jane", Email: "jane@example.com"}},
+		},
+		{
+			name:              "snapshot from token",
+			assignees:         []entities.UserRef{{ID: "joe", Name: "Joe Bloggs", Email: "joe@example.com"}},
+			expectedAssignees: []entities.UserRef{{ID: "joe", Name: "Joe Bloggs", Email: "joe@example.com"}},
+		},
+		{
+			name:          "assignee not a member",
+			assignees:     []entities.UserRef{{ID: "bob"}},
+			expectedError: "assignees: bob is not a member of the project",
+		},
+		{
+			name:          "watcher not a member",
+			watchers:      []entities.UserRef{{ID: "bob"}},
+			expectedError: "watchers: bob is not a member of the project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(MockTaskRepository)
+			taskRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+			repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+			repo.MemberRepository = projectMembers()
+			service := domain.NewTaskService(repo, testMaxDepth)
+
+			task := &entities.Task{ProjectID: "project-1", Title: "Task", Assignees: tt.assignees, Watchers: tt.watchers}
+			err := service.Insert(context.Background(), task)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				taskRepo.AssertNotCalled(t, "Insert", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedAssignees, task.Assignees)
+		})
+	}
+}
+
+func TestTaskService_FindByAssignee(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByAssignee", "jane").Return([]entities.Task{
+		{ID: "story", ProjectID: "project-1", ParentID: "epic", Title: "Story"},
+		{ID: "other", ProjectID: "project-2", Title: "Other"},
+		{ID: "epic", ProjectID: "project-1", Title: "Epic"},
+	}, nil)
+	taskRepo.On("FindByProjectID", "project-1").Return([]entities.Task{
+		{ID: "epic", ProjectID: "project-1", Title: "Epic"},
+		{ID: "story", ProjectID: "project-1", ParentID: "epic", Title: "Story", Status: entities.TaskStatusDone},
+	}, nil)
+	taskRepo.On("FindByProjectID", "project-2").Return([]entities.Task{{ID: "other", ProjectID: "project-2", Title: "Other"}}, nil)
+	service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+
+	tasks, err := service.FindByAssignee(context.Background(), "jane")
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, []string{"story", "other", "epic"}, []string{tasks[0].ID, tasks[1].ID, tasks[2].ID})
+	assert.Equal(t, &entities.TaskProgress{Done: 1, Total: 1}, tasks[2].Progress)
+	assert.Nil(t, tasks[0].Progress)
+}
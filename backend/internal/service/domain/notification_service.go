@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/notify"
+	"boilerplate/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// maxRemindBefore bounds how many hours ahead of the due date users can be reminded
+	maxRemindBefore = 7 * 24
+	// overdueLookback bounds how long after the due date an overdue reminder is
+	// still sent, so that enabling reminders does not flood users with old tasks
+	overdueLookback = 7 * 24 * time.Hour
+	// maxDeliveriesPerRun bounds how many notifications a single delivery run sends out
+	maxDeliveriesPerRun = 100
+)
+
+// ErrNotificationNotFound is returned when a user has no notification with the given ID
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type notificationService struct {
+	notificationRepo storage.NotificationRepository
+	preferenceRepo   storage.NotificationPreferenceRepository
+	taskRepo         storage.TaskRepository
+	projectRepo      storage.ProjectRepository
+	channels         map[entities.NotificationChannel]notify.Channel
+	maxAttempts      int
+}
+
+// NewNotificationService creates the service sending due date reminders.
+// channels holds the configured delivery channels besides the inbox, and
+// maxAttempts bounds how often a failing delivery is tried.
+func NewNotificationService(notificationRepo storage.NotificationRepository, preferenceRepo storage.NotificationPreferenceRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository, channels map[entities.NotificationChannel]notify.Channel, maxAttempts int) *notificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		preferenceRepo:   preferenceRepo,
+		taskRepo:         taskRepo,
+		projectRepo:      projectRepo,
+		channels:         channels,
+		maxAttempts:      maxAttempts,
+	}
+}
+
+// FindByUserID returns a page of the inbox of a user, newest first, and the
+// total number of matching notifications
+func (s *notificationService) FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error) {
+	return s.notificationRepo.FindByUserID(ctx, userID, unreadOnly, limit, offset)
+}
+
+func (s *notificationService) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkRead sets or clears the read state of a notification in the inbox of a user
+func (s *notificationService) MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, error) {
+	notification, found, err := s.notificationRepo.MarkRead(ctx, userID, id, read)
+	if err != nil {
+		return entities.Notification{}, err
+	}
+	if !found {
+		return entities.Notification{}, ErrNotificationNotFound
+	}
+	return notification, nil
+}
+
+// MarkAllRead marks the whole inbox of a user as read and returns the number
+// of notifications that were unread
+func (s *notificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+// Preferences returns the reminder settings of a user, or the defaults if the
+// user has not changed them
+func (s *notificationService) Preferences(ctx context.Context, userID string) (entities.NotificationPreferences, error) {
+	stored, err := s.preferenceRepo.FindByUserIDs(ctx, []string{userID})
+	if err != nil {
+		return entities.NotificationPreferences{}, err
+	}
+	if len(stored) == 0 {
+		return entities.DefaultNotificationPreferences(userID), nil
+	}
+	return stored[0], nil
+}
+
+// UpdatePreferences validates and stores the reminder settings of a user.
+// Email and webhooks can only be chosen when their channel is configured.
+func (s *notificationService) UpdatePreferences(ctx context.Context, preferences *entities.NotificationPreferences) error {
+	if preferences.RemindBefore < 0 || preferences.RemindBefore > maxRemindBefore {
+		return invalidf("remindBefore", "must be between 0 and %d hours", maxRemindBefore)
+	}
+	if preferences.Email && s.channels[entities.NotificationChannelEmail] == nil {
+		return invalidf("email", "is not available, no mail server is configured")
+	}
+
+	preferences.WebhookURL = strings.TrimSpace(preferences.WebhookURL)
+	if preferences.WebhookURL != "" {
+		if s.channels[entities.NotificationChannelWebhook] == nil {
+			return invalidf("webhookUrl", "is not available, webhooks are disabled")
+		}
+		u, err := url.Parse(preferences.WebhookURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return invalidf("webhookUrl", "must be an http or https URL")
+		}
+	}
+
+	return s.preferenceRepo.Upsert(ctx, preferences)
+}
+
+// SendReminders puts a reminder into the inbox of every assignee of an open
+// task that is due within their remind window or overdue at now, and queues
+// its email and webhook deliveries. Every reminder is sent once per due date,
+// also when runs overlap. It returns the number of new notifications; failing
+// projects are skipped and their errors joined.
+func (s *notificationService) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := s.taskRepo.FindDue(ctx, now.Add(-overdueLookback), now.Add(maxRemindBefore*time.Hour))
+	if err != nil {
+		return 0, err
+	}
+
+	preferences, err := s.preferencesOf(ctx, tasks)
+	if err != nil {
+		return 0, err
+	}
+
+	workflows := make(map[string]entities.Workflow)
+	failed := make(map[string]bool)
+	created := 0
+	var errs []error
+	for _, task := range tasks {
+		if failed[task.ProjectID] {
+			continue
+		}
+		workflow, ok := workflows[task.ProjectID]
+		if !ok {
+			project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+			if err != nil {
+				failed[task.ProjectID] = true
+				errs = append(errs, fmt.Errorf("project %s: %w", task.ProjectID, err))
+				continue
+			}
+			workflow = project.Workflow
+			workflows[task.ProjectID] = workflow
+		}
+		if isDone(workflow, task.Status) {
+			continue
+		}
+
+		for _, assignee := range task.Assignees {
+			kind, ok := reminderKind(preferences[assignee.ID], *task.DueDate, now)
+			if !ok {
+				continue
+			}
+			notification := &entities.Notification{
+				UserID:     assignee.ID,
+				Kind:       kind,
+				Key:        entities.NotificationKey(kind, task.ID, *task.DueDate),
+				TaskID:     task.ID,
+				ProjectID:  task.ProjectID,
+				TaskTitle:  task.Title,
+				DueDate:    *task.DueDate,
+				Deliveries: s.deliveries(preferences[assignee.ID], assignee),
+			}
+			inserted, err := s.notificationRepo.Insert(ctx, notification)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("task %s: %w", task.ID, err))
+				continue
+			}
+			if inserted {
+				created++
+			}
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+// preferencesOf returns the reminder settings of every assignee of the tasks
+func (s *notificationService) preferencesOf(ctx context.Context, tasks []entities.Task) (map[string]entities.NotificationPreferences, error) {
+	preferences := make(map[string]entities.NotificationPreferences)
+	var userIDs []string
+	for _, task := range tasks {
+		for _, assignee := range task.Assignees {
+			if _, ok := preferences[assignee.ID]; !ok {
+				preferences[assignee.ID] = entities.DefaultNotificationPreferences(assignee.ID)
+				userIDs = append(userIDs, assignee.ID)
+			}
+		}
+	}
+
+	stored, err := s.preferenceRepo.FindByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range stored {
+		preferences[p.UserID] = p
+	}
+	return preferences, nil
+}
+
+// reminderKind tells which reminder a user wants at now for a task due at due
+func reminderKind(preferences entities.NotificationPreferences, due, now time.Time) (entities.NotificationKind, bool) {
+	if !due.After(now) {
+		return entities.NotificationOverdue, preferences.Overdue
+	}
+	if preferences.RemindBefore > 0 && due.Sub(now) <= time.Duration(preferences.RemindBefore)*time.Hour {
+		return entities.NotificationDueSoon, true
+	}
+	return "", false
+}
+
+// deliveries returns the pending deliveries of a new reminder for a user.
+// Emails go to the address the user was assigned with.
+func (s *notificationService) deliveries(preferences entities.NotificationPreferences, user entities.UserRef) []entities.NotificationDelivery {
+	var deliveries []entities.NotificationDelivery
+	if preferences.Email && user.Email != "" && s.channels[entities.NotificationChannelEmail] != nil {
+		deliveries = append(deliveries, entities.NotificationDelivery{
+			Channel: entities.NotificationChannelEmail,
+			Target:  user.Email,
+			Status:  entities.DeliveryPending,
+		})
+	}
+	if preferences.WebhookURL != "" && s.channels[entities.NotificationChannelWebhook] != nil {
+		deliveries = append(deliveries, entities.NotificationDelivery{
+			Channel: entities.NotificationChannelWebhook,
+			Target:  preferences.WebhookURL,
+			Status:  entities.DeliveryPending,
+		})
+	}
+	return deliveries
+}
+
+// DeliverPending sends the pending email and webhook deliveries of the
+// notifications. A failed delivery is tried again on the next run until it
+// has failed maxAttempts times. It returns the number of sent deliveries;
+// delivery errors are recorded on the notification and joined.
+func (s *notificationService) DeliverPending(ctx context.Context) (int, error) {
+	notifications, err := s.notificationRepo.FindPending(ctx, maxDeliveriesPerRun)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, notification := range notifications {
+		for i := range notification.Deliveries {
+			delivery := &notification.Deliveries[i]
+			if delivery.Status != entities.DeliveryPending {
+				continue
+			}
+			channel := s.channels[delivery.Channel]
+			if channel == nil {
+				// The channel was switched off after the notification was created
+				delivery.Status = entities.DeliveryFailed
+				delivery.LastError = "channel is not configured"
+				continue
+			}
+
+			delivery.Attempts++
+			if err := channel.Send(ctx, delivery.Target, notification); err != nil {
+				delivery.LastError = err.Error()
+				if delivery.Attempts >= s.maxAttempts {
+					delivery.Status = entities.DeliveryFailed
+				}
+				errs = append(errs, fmt.Errorf("notification %s via %s: %w", notification.ID, delivery.Channel, err))
+				continue
+			}
+			sentAt := time.Now()
+			delivery.Status = entities.DeliverySent
+			delivery.LastError = ""
+			delivery.SentAt = &sentAt
+			sent++
+		}
+
+		if err := s.notificationRepo.UpdateDeliveries(ctx, notification.ID, notification.Deliveries); err != nil {
+			errs = append(errs, fmt.Errorf("notification %s: %w", notification.ID, err))
+		}
+	}
+	return sent, errors.Join(errs...)
+}
@@ -0,0 +1,397 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/notify"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Insert(ctx context.Context, notification *entities.Notification) (bool, error) {
+	args := m.Called(notification)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error) {
+	args := m.Called(userID, unreadOnly, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entities.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, bool, error) {
+	args := m.Called(userID, id, read)
+	return args.Get(0).(entities.Notification), args.Bool(1), args.Error(2)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) FindPending(ctx context.Context, limit int) ([]entities.Notification, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateDeliveries(ctx context.Context, id string, deliveries []entities.NotificationDelivery) error {
+	args := m.Called(id, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noNotifications returns a notification repository for tasks without notifications
+func noNotifications() *MockNotificationRepository {
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	notificationRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return notificationRepo
+}
+
+type MockNotificationPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationPreferenceRepository) FindByUserIDs(ctx context.Context, userIDs []string) ([]entities.NotificationPreferences, error) {
+	args := m.Called(userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationPreferenceRepository) Upsert(ctx context.Context, preferences *entities.NotificationPreferences) error {
+	args := m.Called(preferences)
+	return args.Error(0)
+}
+
+// recordingChannel is a notify.Channel that records the targets it sent to and fails with err
+type recordingChannel struct {
+	err     error
+	targets []string
+}
+
+func (c *recordingChannel) Send(ctx context.Context, target string, notification entities.Notification) error {
+	c.targets = append(c.targets, target)
+	return c.err
+}
+
+func allChannels() map[entities.NotificationChannel]notify.Channel {
+	return map[entities.NotificationChannel]notify.Channel{
+		entities.NotificationChannelEmail:   &recordingChannel{},
+		entities.NotificationChannelWebhook: &recordingChannel{},
+	}
+}
+
+// withKey matches the notification with the given reminder key
+func withKey(key string) interface{} {
+	return mock.MatchedBy(func(n *entities.Notification) bool { return n.Key == key })
+}
+
+func TestNotificationService_SendReminders(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	in2h, in30m, ago1h := now.Add(2*time.Hour), now.Add(30*time.Minute), now.Add(-time.Hour)
+	jane := entities.UserRef{ID: "jane", Name: "Jane", Email: "jane@example.com"}
+	joe := entities.UserRef{ID: "joe", Name: "Joe", Email: "joe@example.com"}
+	tasks := []entities.Task{
+		{ID: "task-1", ProjectID: "project-1", Title: "Soon", Status: entities.TaskStatusTodo, DueDate: &in2h, Assignees: []entities.UserRef{jane, joe}},
+		{ID: "task-2", ProjectID: "project-1", Title: "Very soon", Status: entities.TaskStatusInProgress, DueDate: &in30m, Assignees: []entities.UserRef{joe}},
+		{ID: "task-3", ProjectID: "project-1", Title: "Late", Status: entities.TaskStatusTodo, DueDate: &ago1h, Assignees: []entities.UserRef{jane, joe}},
+		{ID: "task-4", ProjectID: "project-1", Title: "Finished", Status: entities.TaskStatusDone, DueDate: &ago1h, Assignees: []entities.UserRef{jane}},
+	}
+
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindDue", now.Add(-7*24*time.Hour), now.Add(7*24*time.Hour)).Return(tasks, nil).Once()
+	preferenceRepo := new(MockNotificationPreferenceRepository)
+	// jane keeps the defaults, joe wants reminders an hour ahead by email and webhook but none when overdue
+	preferenceRepo.On("FindByUserIDs", []string{"jane", "joe"}).Return([]entities.NotificationPreferences{
+		{UserID: "joe", RemindBefore: 1, Email: true, WebhookURL: "https://hooks.example.com/joe"},
+	}, nil).Once()
+
+	notificationRepo := new(MockNotificationRepository)
+	dueSoon := entities.NotificationKey(entities.NotificationDueSoon, "task-1", in2h)
+	notificationRepo.On("Insert", mock.MatchedBy(func(n *entities.Notification) bool {
+		return n.Key == dueSoon && n.UserID == "jane" && n.TaskTitle == "Soon" && len(n.Deliveries) == 0
+	})).Return(true, nil).Once()
+	notificationRepo.On("Insert", mock.MatchedBy(func(n *entities.Notification) bool {
+		return n.Key == entities.NotificationKey(entities.NotificationDueSoon, "task-2", in30m) && n.UserID == "joe" &&
+			len(n.Deliveries) == 2 &&
+			n.Deliveries[0] == entities.NotificationDelivery{Channel: entities.NotificationChannelEmail, Target: "joe@example.com", Status: entities.DeliveryPending} &&
+			n.Deliveries[1] == entities.NotificationDelivery{Channel: entities.NotificationChannelWebhook, Target: "https://hooks.example.com/joe", Status: entities.DeliveryPending}
+	})).Return(true, nil).Once()
+	// The overdue reminder was already sent by an earlier run
+	notificationRepo.On("Insert", mock.MatchedBy(func(n *entities.Notification) bool {
+		return n.Key == entities.NotificationKey(entities.NotificationOverdue, "task-3", ago1h) && n.UserID == "jane"
+	})).Return(false, nil).Once()
+
+	service := domain.NewNotificationService(notificationRepo, preferenceRepo, taskRepo, projectWithWorkflow(entities.DefaultWorkflow()), allChannels(), 5)
+
+	created, err := service.SendReminders(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+	notificationRepo.AssertExpectations(t)
+	notificationRepo.AssertNumberOfCalls(t, "Insert", 3)
+}
+
+func TestNotificationService_SendRemindersSkipsFailingProject(t *testing.T) {
+	now := time.Now()
+	due := now.Add(time.Hour)
+	jane := entities.UserRef{ID: "jane"}
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindDue", mock.Anything, mock.Anything).Return([]entities.Task{
+		{ID: "task-1", ProjectID: "missing", Title: "Orphan", DueDate: &due, Assignees: []entities.UserRef{jane}},
+		{ID: "task-2", ProjectID: "missing", Title: "Orphan too", DueDate: &due, Assignees: []entities.UserRef{jane}},
+		{ID: "task-3", ProjectID: "project-1", Title: "Soon", Status: entities.TaskStatusTodo, DueDate: &due, Assignees: []entities.UserRef{jane}},
+	}, nil)
+	preferenceRepo := new(MockNotificationPreferenceRepository)
+	preferenceRepo.On("FindByUserIDs", []string{"jane"}).Return(nil, nil)
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("FindByID", "missing").Return(entities.Project{}, errors.New("project not found")).Once()
+	projectRepo.On("FindByID", "project-1").Return(entities.Project{ID: "project-1", Workflow: entities.DefaultWorkflow()}, nil)
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("Insert", withKey(entities.NotificationKey(entities.NotificationDueSoon, "task-3", due))).Return(true, nil).Once()
+
+	service := domain.NewNotificationService(notificationRepo, preferenceRepo, taskRepo, projectRepo, nil, 5)
+
+	created, err := service.SendReminders(context.Background(), now)
+
+	assert.ErrorContains(t, err, "project missing")
+	assert.Equal(t, 1, created)
+	projectRepo.AssertExpectations(t)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_SendRemindersDisabled(t *testing.T) {
+	now := time.Now()
+	soon, late := now.Add(time.Hour), now.Add(-time.Hour)
+	jane := entities.UserRef{ID: "jane", Email: "jane@example.com"}
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindDue", mock.Anything, mock.Anything).Return([]entities.Task{
+		{ID: "task-1", ProjectID: "project-1", Status: entities.TaskStatusTodo, DueDate: &soon, Assignees: []entities.UserRef{jane}},
+		{ID: "task-2", ProjectID: "project-1", Status: entities.TaskStatusTodo, DueDate: &late, Assignees: []entities.UserRef{jane}},
+	}, nil)
+	preferenceRepo := new(MockNotificationPreferenceRepository)
+	preferenceRepo.On("FindByUserIDs", []string{"jane"}).Return([]entities.NotificationPreferences{
+		{UserID: "jane", RemindBefore: 0, Overdue: false, Email: true},
+	}, nil)
+	notificationRepo := new(MockNotificationRepository)
+
+	service := domain.NewNotificationService(notificationRepo, preferenceRepo, taskRepo, projectWithWorkflow(entities.DefaultWorkflow()), allChannels(), 5)
+
+	created, err := service.SendReminders(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Zero(t, created)
+	notificationRepo.AssertNotCalled(t, "Insert", mock.Anything)
+}
+
+func TestNotificationService_DeliverPending(t *testing.T) {
+	sentAt := time.Now().Add(-time.Minute)
+	notifications := []entities.Notification{
+		{ID: "n-1", UserID: "joe", Kind: entities.NotificationDueSoon, Deliveries: []entities.NotificationDelivery{
+			{Channel: entities.NotificationChannelEmail, Target: "joe@example.com", Status: entities.DeliveryPending, Attempts: 1, LastError: "connection refused"},
+			{Channel: entities.NotificationChannelWebhook, Target: "https://hooks.example.com/joe", Status: entities.DeliveryPending, Attempts: 4},
+		}},
+		{ID: "n-2", UserID: "joe", Kind: entities.NotificationOverdue, Deliveries: []entities.NotificationDelivery{
+			{Channel: entities.NotificationChannelEmail, Target: "joe@example.com", Status: entities.DeliverySent, Attempts: 1, SentAt: &sentAt},
+			{Channel: entities.NotificationChannelWebhook, Target: "https://hooks.example.com/joe", Status: entities.DeliveryPending},
+		}},
+	}
+	email := &recordingChannel{}
+	webhook := &recordingChannel{err: errors.New("webhook responded with status 502")}
+	channels := map[entities.NotificationChannel]notify.Channel{
+		entities.NotificationChannelEmail:   email,
+		entities.NotificationChannelWebhook: webhook,
+	}
+
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("FindPending", 100).Return(notifications, nil).Once()
+	notificationRepo.On("UpdateDeliveries", "n-1", mock.MatchedBy(func(d []entities.NotificationDelivery) bool {
+		return d[0].Status == entities.DeliverySent && d[0].Attempts == 2 && d[0].LastError == "" && d[0].SentAt != nil &&
+			d[1].Status == entities.DeliveryFailed && d[1].Attempts == 5 && d[1].LastError == "webhook responded with status 502"
+	})).Return(nil).Once()
+	notificationRepo.On("UpdateDeliveries", "n-2", mock.MatchedBy(func(d []entities.NotificationDelivery) bool {
+		return d[0].Status == entities.DeliverySent && d[0].Attempts == 1 &&
+			d[1].Status == entities.DeliveryPending && d[1].Attempts == 1
+	})).Return(nil).Once()
+
+	service := domain.NewNotificationService(notificationRepo, nil, nil, nil, channels, 5)
+
+	sent, err := service.DeliverPending(context.Background())
+
+	assert.ErrorContains(t, err, "notification n-1 via webhook")
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"joe@example.com"}, email.targets, "sent deliveries must not be repeated")
+	assert.Len(t, webhook.targets, 2)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_DeliverPendingWithoutChannel(t *testing.T) {
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("FindPending", mock.Anything).Return([]entities.Notification{
+		{ID: "n-1", Deliveries: []entities.NotificationDelivery{
+			{Channel: entities.NotificationChannelEmail, Target: "joe@example.com", Status: entities.DeliveryPending},
+		}},
+	}, nil)
+	notificationRepo.On("UpdateDeliveries", "n-1", []entities.NotificationDelivery{
+		{Channel: entities.NotificationChannelEmail, Target: "joe@example.com", Status: entities.DeliveryFailed, LastError: "channel is not configured"},
+	}).Return(nil).Once()
+
+	service := domain.NewNotificationService(notificationRepo, nil, nil, nil, nil, 5)
+
+	sent, err := service.DeliverPending(context.Background())
+
+	assert.NoError(t, err)
+	assert.Zero(t, sent)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("MarkRead", "jane", "n-1", true).Return(entities.Notification{ID: "n-1", UserID: "jane"}, true, nil).Once()
+	notificationRepo.On("MarkRead", "joe", "n-1", true).Return(entities.Notification{}, false, nil).Once()
+	service := domain.NewNotificationService(notificationRepo, nil, nil, nil, nil, 5)
+
+	notification, err := service.MarkRead(context.Background(), "jane", "n-1", true)
+	assert.NoError(t, err)
+	assert.Equal(t, "n-1", notification.ID)
+
+	_, err = service.MarkRead(context.Background(), "joe", "n-1", true)
+	assert.ErrorIs(t, err, domain.ErrNotificationNotFound, "users must not change the notifications of others")
+}
+
+func TestNotificationService_Preferences(t *testing.T) {
+	preferenceRepo := new(MockNotificationPreferenceRepository)
+	preferenceRepo.On("FindByUserIDs", []string{"jane"}).Return([]entities.NotificationPreferences{}, nil).Once()
+	preferenceRepo.On("FindByUserIDs", []string{"joe"}).Return([]entities.NotificationPreferences{{UserID: "joe", RemindBefore: 2}}, nil).Once()
+	service := domain.NewNotificationService(nil, preferenceRepo, nil, nil, nil, 5)
+
+	preferences, err := service.Preferences(context.Background(), "jane")
+	assert.NoError(t, err)
+	assert.Equal(t, entities.DefaultNotificationPreferences("jane"), preferences)
+
+	preferences, err = service.Preferences(context.Background(), "joe")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, preferences.RemindBefore)
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	webhookOnly := map[entities.NotificationChannel]notify.Channel{entities.NotificationChannelWebhook: &recordingChannel{}}
+	emailOnly := map[entities.NotificationChannel]notify.Channel{entities.NotificationChannelEmail: &recordingChannel{}}
+	tests := []struct {
+		name        string
+		channels    map[entities.NotificationChannel]notify.Channel
+		preferences entities.NotificationPreferences
+		field       string
+	}{
+		{name: "valid", channels: allChannels(), preferences: entities.NotificationPreferences{RemindBefore: 48, Email: true, WebhookURL: " https://hooks.example.com/jane "}},
+		{name: "negative remind window", channels: allChannels(), preferences: entities.NotificationPreferences{RemindBefore: -1}, field: "remindBefore"},
+		{name: "remind window over a week", channels: allChannels(), preferences: entities.NotificationPreferences{RemindBefore: 169}, field: "remindBefore"},
+		{name: "email without mail server", channels: webhookOnly, preferences: entities.NotificationPreferences{Email: true}, field: "email"},
+		{name: "webhook disabled", channels: emailOnly, preferences: entities.NotificationPreferences{WebhookURL: "https://hooks.example.com"}, field: "webhookUrl"},
+		{name: "webhook without scheme", channels: allChannels(), preferences: entities.NotificationPreferences{WebhookURL: "hooks.example.com/jane"}, field: "webhookUrl"},
+		{name: "webhook with other scheme", channels: allChannels(), preferences: entities.NotificationPreferences{WebhookURL: "ftp://hooks.example.com"}, field: "webhookUrl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preferenceRepo := new(MockNotificationPreferenceRepository)
+			preferenceRepo.On("Upsert", mock.Anything).Return(nil).Maybe()
+			service := domain.NewNotificationService(nil, preferenceRepo, nil, nil, tt.channels, 5)
+
+			preferences := tt.preferences
+			preferences.UserID = "jane"
+			err := service.UpdatePreferences(context.Background(), &preferences)
+
+			if tt.field == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "https://hooks.example.com/jane", preferences.WebhookURL)
+				preferenceRepo.AssertCalled(t, "Upsert", &preferences)
+				return
+			}
+			var validationErr *domain.ValidationError
+			if assert.ErrorAs(t, err, &validationErr) {
+				assert.Equal(t, tt.field, validationErr.Field)
+			}
+			preferenceRepo.AssertNotCalled(t, "Upsert", mock.Anything)
+		})
+	}
+}
+
+func TestNotifications_DeletedWithTheirTasks(t *testing.T) {
+	t.Run("deleting a task removes the notifications of its subtree", func(t *testing.T) {
+		taskRepo := hierarchyRepo()
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"step"}).Return([]error{nil}, nil).Once()
+		taskRepo.On("Delete", "story").Return(nil).Once()
+		notificationRepo := new(MockNotificationRepository)
+		notificationRepo.On("DeleteByTaskIDs", []string{"step", "story"}).Return(nil).Once()
+		repo := testRepository(taskRepo, nil)
+		repo.NotificationRepository = notificationRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		assert.NoError(t, service.Delete(context.Background(), "story"))
+		notificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("batch deletes remove the notifications", func(t *testing.T) {
+		taskRepo := hierarchyRepo()
+		taskRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{}, nil)
+		taskRepo.On("UpdateMany", mock.Anything, "project-1", mock.Anything).Return([]error{}, nil)
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"story", "step"}).Return([]error{nil, nil}, nil).Once()
+		notificationRepo := new(MockNotificationRepository)
+		notificationRepo.On("DeleteByTaskIDs", []string{"story", "step"}).Return(nil).Once()
+		repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.NotificationRepository = notificationRepo
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		ops := []entities.TaskBatchOperation{{Op: entities.TaskBatchOpDelete, ID: "story"}}
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+		assert.NoError(t, err)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[0].Status)
+		notificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("deleting a project removes all notifications", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		projectRepo.On("Delete", "project-1").Return(nil)
+		taskRepo := new(MockTaskRepository)
+		taskRepo.On("DeleteByProjectID", "project-1").Return(nil)
+		notificationRepo := new(MockNotificationRepository)
+		notificationRepo.On("DeleteByProjectID", "project-1").Return(nil).Once()
+		repo := testRepository(taskRepo, projectRepo)
+		repo.NotificationRepository = notificationRepo
+		service := domain.NewProjectService(repo)
+
+		assert.NoError(t, service.Delete(context.Background(), "project-1"))
+		notificationRepo.AssertExpectations(t)
+	})
+}
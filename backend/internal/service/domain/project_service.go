@@ -3,38 +3,124 @@ package domain
 import (
 	"boilerplate/internal/entities"
 	"boilerplate/internal/storage"
+	"context"
 )
 
 type projectService struct {
-	projectRepo storage.ProjectRepository
+	projectRepo      storage.ProjectRepository
+	taskRepo         storage.TaskRepository
+	labelRepo        storage.LabelRepository
+	fieldRepo        storage.CustomFieldRepository
+	dependencyRepo   storage.DependencyRepository
+	memberRepo       storage.MemberRepository
+	commentRepo      storage.CommentRepository
+	attachmentRepo   storage.AttachmentRepository
+	timeEntryRepo    storage.TimeEntryRepository
+	notificationRepo storage.NotificationRepository
+	blobs            storage.BlobStore
 }
 
-func NewProjectService(projectRepo storage.ProjectRepository) *projectService {
+// NewProjectService creates the project service; deleting a project removes
+// its members, tasks and everything attached to them from repo.
+func NewProjectService(repo storage.Repository) *projectService {
 	return &projectService{
-		projectRepo: projectRepo,
+		projectRepo:      repo.ProjectRepository,
+		taskRepo:         repo.TaskRepository,
+		labelRepo:        repo.LabelRepository,
+		fieldRepo:        repo.CustomFieldRepository,
+		dependencyRepo:   repo.DependencyRepository,
+		memberRepo:       repo.MemberRepository,
+		commentRepo:      repo.CommentRepository,
+		attachmentRepo:   repo.AttachmentRepository,
+		timeEntryRepo:    repo.TimeEntryRepository,
+		notificationRepo: repo.NotificationRepository,
+		blobs:            repo.BlobStore,
 	}
 }
 
-func (s *projectService) Insert(project *entities.Project) error {
-	return s.projectRepo.Insert(project)
+// Insert creates a project; projects without a workflow get the default one
+func (s *projectService) Insert(ctx context.Context, project *entities.Project) error {
+	if len(project.Workflow.Statuses) == 0 {
+		project.Workflow = entities.DefaultWorkflow()
+	}
+	if err := validateWorkflow(&project.Workflow); err != nil {
+		return err
+	}
+	return s.projectRepo.Insert(ctx, project)
 }
 
-func (s *projectService) Update(project *entities.Project) error {
-	return s.projectRepo.Update(project)
+// Update replaces a project. A workflow change must keep every status that
+// tasks of the project are still in.
+func (s *projectService) Update(ctx context.Context, project *entities.Project) error {
+	if len(project.Workflow.Statuses) == 0 {
+		project.Workflow = entities.DefaultWorkflow()
+	}
+	if err := validateWorkflow(&project.Workflow); err != nil {
+		return err
+	}
+
+	counts, err := s.taskRepo.CountByStatus(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	for status, count := range counts {
+		if _, ok := project.Workflow.Status(status); !ok && count > 0 {
+			return invalidf("workflow.statuses", "%s is still used by %d tasks, move them to another status first", status, count)
+		}
+	}
+
+	return s.projectRepo.Update(ctx, project)
 }
 
-func (s *projectService) Delete(id string) error {
-	return s.projectRepo.Delete(id)
+// Delete removes a project together with its members, labels, custom fields,
+// all of its tasks, their comments, time entries, notifications and dependency links,
+// including links to tasks of other projects
+func (s *projectService) Delete(ctx context.Context, id string) error {
+	if err := s.projectRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := s.taskRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.dependencyRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.commentRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.timeEntryRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.notificationRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	attachments, err := s.attachmentRepo.FindByProjectID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.memberRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.labelRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.fieldRepo.DeleteByProjectID(ctx, id); err != nil {
+		return err
+	}
+	return deleteAttachmentBlobs(ctx, s.blobs, attachments)
 }
 
-func (s *projectService) FindByID(id string) (entities.Project, error) {
-	return s.projectRepo.FindByID(id)
+func (s *projectService) FindByID(ctx context.Context, id string) (entities.Project, error) {
+	return s.projectRepo.FindByID(ctx, id)
 }
 
-func (s *projectService) FindAll() ([]entities.Project, error) {
-	return s.projectRepo.FindAll()
+func (s *projectService) FindAll(ctx context.Context) ([]entities.Project, error) {
+	return s.projectRepo.FindAll(ctx)
 }
 
-func (s *projectService) FindAllPaginated(limit, offset int) ([]entities.Project, int64, error) {
-	return s.projectRepo.FindAllPaginated(limit, offset)
+func (s *projectService) FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error) {
+	return s.projectRepo.FindAllPaginated(ctx, limit, offset)
 }
@@ -4,6 +4,7 @@ import (
 	"boilerplate/internal/entities"
 	"boilerplate/internal/service/domain"
 	"boilerplate/internal/storage"
+	"context"
 	"errors"
 	"testing"
 
@@ -15,28 +16,28 @@ type MockProjectRepository struct {
 	mock.Mock
 }
 
-func (m *MockProjectRepository) Insert(project *entities.Project) error {
+func (m *MockProjectRepository) Insert(ctx context.Context, project *entities.Project) error {
 	args := m.Called(project)
 	// Die Methode gibt nur den Fehler zurück, der in den Testfällen definiert wurde
 	return args.Error(0)
 }
 
-func (m *MockProjectRepository) Update(project *entities.Project) error {
+func (m *MockProjectRepository) Update(ctx context.Context, project *entities.Project) error {
 	args := m.Called(project)
 	return args.Error(0)
 }
 
-func (m *MockProjectRepository) Delete(id string) error {
+func (m *MockProjectRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockProjectRepository) FindByID(id string) (entities.Project, error) {
+func (m *MockProjectRepository) FindByID(ctx context.Context, id string) (entities.Project, error) {
 	args := m.Called(id)
 	return args.Get(0).(entities.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) FindAll() ([]entities.Project, error) {
+func (m *MockProjectRepository) FindAll(ctx context.Context) ([]entities.Project, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -44,7 +45,7 @@ func (m *MockProjectRepository) FindAll() ([]entities.Project, error) {
 	return args.Get(0).([]entities.Project), args.Error(1)
 }
 
-func (m *MockProjectRepository) FindAllPaginated(limit, offset int) ([]entities.Project, int64, error) {
+func (m *MockProjectRepository) FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error) {
 	args := m.Called(limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(int64), args.Error(2)
@@ -52,6 +53,16 @@ func (m *MockProjectRepository) FindAllPaginated(limit, offset int) ([]entities.
 	return args.Get(0).([]entities.Project), args.Get(1).(int64), args.Error(2)
 }
 
+// tasksByStatus returns a task repository reporting the given task counts per status
+func tasksByStatus(counts map[entities.TaskStatus]int) *MockTaskRepository {
+	if counts == nil {
+		counts = map[entities.TaskStatus]int{}
+	}
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("CountByStatus", mock.Anything).Return(counts, nil).Maybe()
+	return taskRepo
+}
+
 func createTestProject() entities.Project {
 	return entities.Project{
 		ID:   "test-id",
@@ -106,9 +117,9 @@ func TestProjectService_CreateProject(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.project)
 			}
 
-			service := domain.NewProjectService(mockRepo)
+			service := domain.NewProjectService(testRepository(tasksByStatus(nil), mockRepo))
 			projectToCreate := tt.project // Create a copy to avoid modifying the test case
-			err := service.Insert(&projectToCreate)
+			err := service.Insert(context.Background(), &projectToCreate)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -164,8 +175,8 @@ func TestProjectService_GetProject(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.projectID)
 			}
 
-			service := domain.NewProjectService(mockRepo)
-			project, err := service.FindByID(tt.projectID)
+			service := domain.NewProjectService(testRepository(tasksByStatus(nil), mockRepo))
+			project, err := service.FindByID(context.Background(), tt.projectID)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
@@ -220,9 +231,9 @@ func TestProjectService_UpdateProject(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.project)
 			}
 
-			service := domain.NewProjectService(mockRepo)
+			service := domain.NewProjectService(testRepository(tasksByStatus(nil), mockRepo))
 			projectToUpdate := tt.project // Create a copy to avoid modifying the test case
-			err := service.Update(&projectToUpdate)
+			err := service.Update(context.Background(), &projectToUpdate)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
@@ -272,8 +283,10 @@ func TestProjectService_DeleteProject(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.projectID)
 			}
 
-			service := domain.NewProjectService(mockRepo)
-			err := service.Delete(tt.projectID)
+			taskRepo := new(MockTaskRepository)
+			taskRepo.On("DeleteByProjectID", tt.projectID).Return(nil).Maybe()
+			service := domain.NewProjectService(testRepository(taskRepo, mockRepo))
+			err := service.Delete(context.Background(), tt.projectID)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
@@ -286,6 +299,25 @@ func TestProjectService_DeleteProject(t *testing.T) {
 	}
 }
 
+func TestProjectService_DeleteRemovesLabelsAndCustomFields(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("Delete", "project-1").Return(nil)
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("DeleteByProjectID", "project-1").Return(nil)
+	labelRepo := new(MockLabelRepository)
+	labelRepo.On("DeleteByProjectID", "project-1").Return(nil).Once()
+	fieldRepo := new(MockCustomFieldRepository)
+	fieldRepo.On("DeleteByProjectID", "project-1").Return(nil).Once()
+	repo := testRepository(taskRepo, projectRepo)
+	repo.LabelRepository = labelRepo
+	repo.CustomFieldRepository = fieldRepo
+	service := domain.NewProjectService(repo)
+
+	assert.NoError(t, service.Delete(context.Background(), "project-1"))
+	labelRepo.AssertExpectations(t)
+	fieldRepo.AssertExpectations(t)
+}
+
 func setupMockForListProjects(t *testing.T, mockRepo *MockProjectRepository, returnProjects []entities.Project, returnErr error) {
 	t.Helper()
 	mockRepo.On("FindAll").Return(returnProjects, returnErr)
@@ -336,8 +368,8 @@ func TestProjectService_ListProjects(t *testing.T) {
 				tt.setupMock(t, mockRepo)
 			}
 
-			service := domain.NewProjectService(mockRepo)
-			projects, err := service.FindAll()
+			service := domain.NewProjectService(testRepository(tasksByStatus(nil), mockRepo))
+			projects, err := service.FindAll(context.Background())
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
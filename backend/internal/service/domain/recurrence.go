@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/teambition/rrule-go"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxOccurrencesPerRun bounds how many occurrences of one series a single
+// materialization creates, e.g. when catching up after a long downtime
+const maxOccurrencesPerRun = 50
+
+// prepareRecurrence normalizes and validates the recurrence of a task. Without
+// a start the series continues the previous recurrence, or starts at the due
+// date or now. A task that starts recurring becomes the first occurrence of a
+// new series: the occurrence of the current period, and due then unless it
+// has a due date.
+func prepareRecurrence(task *entities.Task, previous *entities.TaskRecurrence, now time.Time) error {
+	recurrence := task.Recurrence
+	if recurrence == nil {
+		return nil
+	}
+
+	recurrence.RRule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(recurrence.RRule)), "RRULE:")
+	if recurrence.Timezone == "" {
+		recurrence.Timezone = "UTC"
+	}
+	if recurrence.Start.IsZero() {
+		switch {
+		case previous != nil:
+			recurrence.Start = previous.Start
+		case task.DueDate != nil:
+			recurrence.Start = *task.DueDate
+		default:
+			recurrence.Start = now
+		}
+	}
+	// Rules have a resolution of seconds
+	recurrence.Start = recurrence.Start.UTC().Truncate(time.Second)
+
+	rule, err := recurrenceRule(*recurrence)
+	if err != nil {
+		return err
+	}
+	if task.SeriesID != "" {
+		return nil
+	}
+
+	first := rule.After(recurrence.Start, true)
+	if first.IsZero() {
+		return invalidf("recurrence.rrule", "has no occurrences after the start")
+	}
+	// A series started in the past begins with the current period rather than a backlog
+	if first.Before(now) {
+		if current := rule.Before(now, true); !current.IsZero() {
+			first = current
+		}
+	}
+	first = first.UTC()
+	task.SeriesID = rand.Text()
+	task.Occurrence = &first
+	if task.DueDate == nil {
+		due := first
+		task.DueDate = &due
+	}
+	return nil
+}
+
+// recurrenceRule parses the RRULE of a recurrence and anchors it at its start
+// in its time zone
+func recurrenceRule(recurrence entities.TaskRecurrence) (*rrule.RRule, error) {
+	if recurrence.Timezone == "Local" {
+		return nil, invalidf("recurrence.timezone", "must name a time zone")
+	}
+	location, err := time.LoadLocation(recurrence.Timezone)
+	if err != nil {
+		return nil, invalidf("recurrence.timezone", "%q is not a known time zone", recurrence.Timezone)
+	}
+
+	if strings.ContainsAny(recurrence.RRule, "\r\n") || strings.Contains(recurrence.RRule, "DTSTART") {
+		return nil, invalidf("recurrence.rrule", "must not contain DTSTART, set start instead")
+	}
+	options, err := rrule.StrToROptionInLocation(recurrence.RRule, location)
+	if err != nil {
+		return nil, invalidf("recurrence.rrule", "is not a valid RFC 5545 rule: %v", err)
+	}
+	// Frequencies are ordered from YEARLY to SECONDLY
+	if options.Freq > rrule.DAILY {
+		return nil, invalidf("recurrence.rrule", "must repeat at most daily")
+	}
+	options.Dtstart = recurrence.Start.In(location)
+
+	rule, err := rrule.NewRRule(*options)
+	if err != nil {
+		return nil, invalidf("recurrence.rrule", "is not a valid RFC 5545 rule: %v", err)
+	}
+	return rule, nil
+}
+
+// nextOccurrence builds the occurrence following task in its series. It keeps
+// the distance between occurrence and due date, and returns nil when the rule
+// has no further occurrences.
+func nextOccurrence(task entities.Task, status entities.TaskStatus) (*entities.Task, error) {
+	rule, err := recurrenceRule(*task.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	at := rule.After(*task.Occurrence, false)
+	if at.IsZero() {
+		return nil, nil
+	}
+	at = at.UTC()
+
+	recurrence := *task.Recurrence
+	next := &entities.Task{
+		ProjectID:    task.ProjectID,
+		ParentID:     task.ParentID,
+		Title:        task.Title,
+		Status:       status,
+		Priority:     task.Priority,
+		Description:  task.Description,
+		LabelIDs:     slices.Clone(task.LabelIDs),
+		CustomFields: maps.Clone(task.CustomFields),
+		Assignees:    slices.Clone(task.Assignees),
+		Watchers:     slices.Clone(task.Watchers),
+		Estimate:     task.Estimate,
+		Recurrence:   &recurrence,
+		SeriesID:     task.SeriesID,
+		Occurrence:   &at,
+	}
+	if task.DueDate != nil {
+		due := at.Add(task.DueDate.Sub(*task.Occurrence))
+		next.DueDate = &due
+	}
+	return next, nil
+}
+
+// advanceSeries creates the occurrence following head, the latest occurrence
+// of its series, and moves the recurrence over to it. Occurrences starting
+// after until are left for later; a zero until creates the next occurrence
+// whenever it starts. It returns the created occurrence, or nil if there is
+// nothing to create yet, the rule has ended or the series already has it.
+func (s *taskService) advanceSeries(ctx context.Context, head entities.Task, initial entities.TaskStatus, until time.Time) (*entities.Task, error) {
+	next, err := nextOccurrence(head, initial)
+	if err != nil {
+		return nil, err
+	}
+	if next == nil {
+		// The rule has ended and so has the series
+		return nil, s.taskRepo.ClearRecurrence(ctx, head.ID)
+	}
+	if !until.IsZero() && next.Occurrence.After(until) {
+		return nil, nil
+	}
+
+	created, err := s.taskRepo.InsertOccurrence(ctx, next)
+	if err != nil {
+		return nil, err
+	}
+	// Also when another replica created the occurrence first, so that a
+	// failure to clear after inserting is repaired by the next run
+	if err := s.taskRepo.ClearRecurrence(ctx, head.ID); err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, nil
+	}
+	return next, nil
+}
+
+// MaterializeRecurrences creates the occurrences of recurring tasks that start
+// up to horizon, plus the next occurrence of every series whose latest
+// occurrence is done, e.g. because it was completed in a batch. It is safe to
+// run concurrently since a series never gets the same occurrence twice. It
+// returns the number of created tasks; failing series are skipped and their
+// errors joined.
+func (s *taskService) MaterializeRecurrences(ctx context.Context, horizon time.Time) (int, error) {
+	heads, err := s.taskRepo.FindRecurring(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	workflows := make(map[string]entities.Workflow)
+	created := 0
+	var errs []error
+	for _, head := range heads {
+		workflow, ok := workflows[head.ProjectID]
+		if !ok {
+			if workflow, err = s.workflow(ctx, head.ProjectID); err != nil {
+				errs = append(errs, fmt.Errorf("series %s: %w", head.SeriesID, err))
+				continue
+			}
+			workflows[head.ProjectID] = workflow
+		}
+
+		for range maxOccurrencesPerRun {
+			until := horizon
+			if isDone(workflow, head.Status) {
+				until = time.Time{}
+			}
+			next, err := s.advanceSeries(ctx, head, workflow.Initial(), until)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("series %s: %w", head.SeriesID, err))
+				break
+			}
+			if next == nil {
+				break
+			}
+			created++
+			head = *next
+		}
+	}
+	return created, errors.Join(errs...)
+}
@@ -0,0 +1,243 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mondayMorning is 09:00 in Berlin on the last Monday before daylight saving time starts
+var mondayMorning = time.Date(2030, 3, 25, 8, 0, 0, 0, time.UTC)
+
+func weeklyOnMonday() *entities.TaskRecurrence {
+	return &entities.TaskRecurrence{RRule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "Europe/Berlin", Start: mondayMorning}
+}
+
+func TestTaskService_InsertRecurringTask(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("Insert", mock.Anything).Return(nil)
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	task := entities.Task{Title: "Weekly report", ProjectID: "project-1", SeriesID: "forged", Recurrence: &entities.TaskRecurrence{RRule: "rrule:freq=weekly;byday=mo", Timezone: "Europe/Berlin", Start: mondayMorning}}
+	require.NoError(t, service.Insert(context.Background(), &task))
+
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", task.Recurrence.RRule)
+	assert.NotEmpty(t, task.SeriesID)
+	assert.NotEqual(t, "forged", task.SeriesID, "the series is assigned by the service")
+	require.NotNil(t, task.Occurrence)
+	assert.Equal(t, mondayMorning, *task.Occurrence)
+	require.NotNil(t, task.DueDate)
+	assert.Equal(t, mondayMorning, *task.DueDate, "a recurring task without due date is due when it starts")
+}
+
+func TestTaskService_InsertRecurringTaskStartsAtDueDate(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("Insert", mock.Anything).Return(nil)
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	due := time.Date(2030, 1, 31, 17, 0, 0, 0, time.UTC)
+	task := entities.Task{Title: "Monthly review", ProjectID: "project-1", DueDate: &due, Recurrence: &entities.TaskRecurrence{RRule: "FREQ=MONTHLY;BYMONTHDAY=-1"}}
+	require.NoError(t, service.Insert(context.Background(), &task))
+
+	assert.Equal(t, "UTC", task.Recurrence.Timezone)
+	assert.Equal(t, due, task.Recurrence.Start)
+	assert.Equal(t, due, *task.Occurrence)
+}
+
+func TestTaskService_InsertRecurringTaskValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		recurrence    entities.TaskRecurrence
+		expectedField string
+	}{
+		{name: "unknown time zone", recurrence: entities.TaskRecurrence{RRule: "FREQ=DAILY", Timezone: "Mars/Olympus"}, expectedField: "recurrence.timezone"},
+		{name: "server time zone", recurrence: entities.TaskRecurrence{RRule: "FREQ=DAILY", Timezone: "Local"}, expectedField: "recurrence.timezone"},
+		{name: "not a rule", recurrence: entities.TaskRecurrence{RRule: "every monday"}, expectedField: "recurrence.rrule"},
+		{name: "more often than daily", recurrence: entities.TaskRecurrence{RRule: "FREQ=HOURLY"}, expectedField: "recurrence.rrule"},
+		{name: "DTSTART in the rule", recurrence: entities.TaskRecurrence{RRule: "DTSTART:20300101T090000Z\nRRULE:FREQ=DAILY"}, expectedField: "recurrence.rrule"},
+		{name: "no occurrences", recurrence: entities.TaskRecurrence{RRule: "FREQ=DAILY;UNTIL=20300101T000000Z", Start: mondayMorning}, expectedField: "recurrence.rrule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(MockTaskRepository)
+			repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+			repo.MemberRepository = projectMembers()
+			service := domain.NewTaskService(repo, testMaxDepth)
+
+			recurrence := tt.recurrence
+			task := entities.Task{Title: "Report", ProjectID: "project-1", Recurrence: &recurrence}
+			err := service.Insert(context.Background(), &task)
+
+			var validationErr *domain.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.expectedField, validationErr.Field)
+			taskRepo.AssertNotCalled(t, "Insert", mock.Anything)
+		})
+	}
+}
+
+// recurringHead is the latest occurrence of a weekly series, due two hours after it starts
+func recurringHead() entities.Task {
+	occurrence := mondayMorning
+	due := mondayMorning.Add(2 * time.Hour)
+	return entities.Task{
+		ID:          "head",
+		ProjectID:   "project-1",
+		Title:       "Weekly report",
+		Status:      entities.TaskStatusInProgress,
+		Priority:    entities.TaskPriorityHigh,
+		DueDate:     &due,
+		Description: "Numbers of the week",
+		Assignees:   []entities.UserRef{{ID: "jane", Name: "Jane", Email: "jane@example.com"}},
+		Recurrence:  weeklyOnMonday(),
+		SeriesID:    "series-1",
+		Occurrence:  &occurrence,
+	}
+}
+
+func TestTaskService_CompletingRecurringTaskCreatesNext(t *testing.T) {
+	head := recurringHead()
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByID", "head").Return(head, nil)
+	taskRepo.On("Update", mock.Anything).Return(nil)
+	var next *entities.Task
+	taskRepo.On("InsertOccurrence", mock.Anything).Run(func(args mock.Arguments) {
+		next = args.Get(0).(*entities.Task)
+	}).Return(true, nil)
+	taskRepo.On("ClearRecurrence", "head").Return(nil)
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	update := recurringHead()
+	update.Status = entities.TaskStatusDone
+	update.SeriesID = "forged"
+	require.NoError(t, service.Update(context.Background(), &update))
+
+	assert.Equal(t, "series-1", update.SeriesID, "the series cannot be changed by the caller")
+	assert.Nil(t, update.Recurrence, "the recurrence moves on to the next occurrence")
+	require.NotNil(t, next)
+	// 09:00 in Berlin is 07:00 UTC once daylight saving time has started
+	nextMonday := time.Date(2030, 4, 1, 7, 0, 0, 0, time.UTC)
+	assert.Equal(t, nextMonday, *next.Occurrence)
+	assert.Equal(t, nextMonday.Add(2*time.Hour), *next.DueDate)
+	assert.Equal(t, entities.TaskStatusTodo, next.Status)
+	assert.Equal(t, "series-1", next.SeriesID)
+	assert.Equal(t, weeklyOnMonday(), next.Recurrence)
+	assert.Equal(t, head.Title, next.Title)
+	assert.Equal(t, head.Priority, next.Priority)
+	assert.Equal(t, head.Description, next.Description)
+	assert.Equal(t, head.Assignees, next.Assignees)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestTaskService_CompletingRecurringTaskTwice(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByID", "head").Return(recurringHead(), nil)
+	taskRepo.On("Update", mock.Anything).Return(nil)
+	// Another replica generated the occurrence first
+	taskRepo.On("InsertOccurrence", mock.Anything).Return(false, nil)
+	taskRepo.On("ClearRecurrence", "head").Return(nil)
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	update := recurringHead()
+	update.Status = entities.TaskStatusDone
+	require.NoError(t, service.Update(context.Background(), &update))
+	taskRepo.AssertExpectations(t)
+}
+
+func TestTaskService_CompletingRecurringTaskFailsToCreateNext(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByID", "head").Return(recurringHead(), nil)
+	taskRepo.On("Update", mock.Anything).Return(nil)
+	taskRepo.On("InsertOccurrence", mock.Anything).Return(false, errors.New("connection reset"))
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	update := recurringHead()
+	update.Status = entities.TaskStatusDone
+	// The scheduler picks up the done occurrence later
+	require.NoError(t, service.Update(context.Background(), &update))
+	assert.NotNil(t, update.Recurrence)
+	taskRepo.AssertNotCalled(t, "ClearRecurrence", mock.Anything)
+}
+
+func TestTaskService_MaterializeRecurrences(t *testing.T) {
+	daily := recurringHead()
+	daily.ID = "daily"
+	daily.SeriesID = "daily-series"
+	daily.Recurrence = &entities.TaskRecurrence{RRule: "FREQ=DAILY", Timezone: "UTC", Start: mondayMorning}
+
+	completed := recurringHead()
+	completed.ID = "completed"
+	completed.SeriesID = "completed-series"
+	completed.Status = entities.TaskStatusDone
+
+	ended := recurringHead()
+	ended.ID = "ended"
+	ended.SeriesID = "ended-series"
+	ended.Recurrence = &entities.TaskRecurrence{RRule: "FREQ=DAILY;COUNT=1", Timezone: "UTC", Start: mondayMorning}
+
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindRecurring").Return([]entities.Task{daily, completed, ended}, nil)
+	created := map[string][]time.Time{}
+	taskRepo.On("InsertOccurrence", mock.Anything).Run(func(args mock.Arguments) {
+		task := args.Get(0).(*entities.Task)
+		task.ID = task.SeriesID + "-" + task.Occurrence.Format("0102")
+		created[task.SeriesID] = append(created[task.SeriesID], *task.Occurrence)
+	}).Return(true, nil)
+	taskRepo.On("ClearRecurrence", mock.Anything).Return(nil)
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	horizon := mondayMorning.Add(72*time.Hour + time.Minute)
+	count, err := service.MaterializeRecurrences(context.Background(), horizon)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []time.Time{mondayMorning.Add(24 * time.Hour), mondayMorning.Add(48 * time.Hour), mondayMorning.Add(72 * time.Hour)}, created["daily-series"],
+		"open series are materialized up to the horizon")
+	assert.Len(t, created["completed-series"], 1, "a done occurrence is followed by the next one beyond the horizon")
+	assert.Empty(t, created["ended-series"])
+	taskRepo.AssertCalled(t, "ClearRecurrence", "ended")
+	taskRepo.AssertCalled(t, "ClearRecurrence", "daily")
+	taskRepo.AssertCalled(t, "ClearRecurrence", "daily-series-0326")
+}
+
+func TestTaskService_MaterializeRecurrencesSkipsFailingSeries(t *testing.T) {
+	broken := recurringHead()
+	broken.Recurrence = &entities.TaskRecurrence{RRule: "FREQ=DAILY", Timezone: "Gone/Zone", Start: mondayMorning}
+	completed := recurringHead()
+	completed.ID = "completed"
+	completed.SeriesID = "completed-series"
+	completed.Status = entities.TaskStatusDone
+
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindRecurring").Return([]entities.Task{broken, completed}, nil)
+	taskRepo.On("InsertOccurrence", mock.Anything).Return(true, nil)
+	taskRepo.On("ClearRecurrence", "completed").Return(nil)
+	repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+	repo.MemberRepository = projectMembers()
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	count, err := service.MaterializeRecurrences(context.Background(), mondayMorning)
+
+	assert.Equal(t, 1, count)
+	assert.ErrorContains(t, err, "series-1")
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+)
+
+// hierarchy indexes the parent links between the tasks of a project. Tasks
+// whose parent is missing are treated as top-level tasks.
+type hierarchy struct {
+	tasks    map[string]entities.Task
+	children map[string][]string
+}
+
+func newHierarchy(tasks []entities.Task) *hierarchy {
+	h := &hierarchy{
+		tasks:    make(map[string]entities.Task, len(tasks)),
+		children: make(map[string][]string),
+	}
+	for _, task := range tasks {
+		h.tasks[task.ID] = task
+	}
+	for _, task := range tasks {
+		if _, ok := h.tasks[task.ParentID]; ok {
+			h.children[task.ParentID] = append(h.children[task.ParentID], task.ID)
+		}
+	}
+	return h
+}
+
+// hasSubtasks reports whether any task of the project has a parent
+func (h *hierarchy) hasSubtasks() bool {
+	return len(h.children) > 0
+}
+
+// depth counts the ancestors of a task; top-level tasks have depth 0
+func (h *hierarchy) depth(id string) int {
+	depth := 0
+	for parent, ok := h.tasks[h.tasks[id].ParentID]; ok; parent, ok = h.tasks[parent.ParentID] {
+		depth++
+		// Stored cycles cannot be created through the service, but must not hang it
+		if depth > len(h.tasks) {
+			break
+		}
+	}
+	return depth
+}
+
+// height counts the levels of subtasks below a task
+func (h *hierarchy) height(id string) int {
+	height := 0
+	level := h.children[id]
+	seen := map[string]bool{id: true}
+	for len(level) > 0 {
+		height++
+		var next []string
+		for _, child := range level {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			next = append(next, h.children[child]...)
+		}
+		level = next
+	}
+	return height
+}
+
+// descendants returns the IDs of all subtasks below a task, parents before their children
+func (h *hierarchy) descendants(id string) []string {
+	var ids []string
+	seen := map[string]bool{id: true}
+	queue := append([]string(nil), h.children[id]...)
+	for len(queue) > 0 {
+		child := queue[0]
+		queue = queue[1:]
+		if seen[child] {
+			continue
+		}
+		seen[child] = true
+		ids = append(ids, child)
+		queue = append(queue, h.children[child]...)
+	}
+	return ids
+}
+
+// isDescendant reports whether id is a subtask at any level below ancestor
+func (h *hierarchy) isDescendant(id, ancestor string) bool {
+	for _, descendant := range h.descendants(ancestor) {
+		if descendant == id {
+			return true
+		}
+	}
+	return false
+}
+
+// progress rolls up the subtasks of a task, or returns nil if it has none
+func (h *hierarchy) progress(id string, workflow entities.Workflow) *entities.TaskProgress {
+	descendants := h.descendants(id)
+	if len(descendants) == 0 {
+		return nil
+	}
+	progress := &entities.TaskProgress{Total: len(descendants)}
+	for _, descendant := range descendants {
+		if isDone(workflow, h.tasks[descendant].Status) {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+// withProgress returns the task with its rolled up progress
+func (h *hierarchy) withProgress(task entities.Task, workflow entities.Workflow) entities.Task {
+	task.Progress = h.progress(task.ID, workflow)
+	return task
+}
+
+// tree returns a task with its subtasks nested below it
+func (h *hierarchy) tree(id string, workflow entities.Workflow) entities.TaskTree {
+	return h.subtree(id, workflow, map[string]bool{})
+}
+
+func (h *hierarchy) subtree(id string, workflow entities.Workflow, seen map[string]bool) entities.TaskTree {
+	seen[id] = true
+	node := entities.TaskTree{Task: h.withProgress(h.tasks[id], workflow), Subtasks: []entities.TaskTree{}}
+	for _, child := range h.children[id] {
+		if !seen[child] {
+			node.Subtasks = append(node.Subtasks, h.subtree(child, workflow, seen))
+		}
+	}
+	return node
+}
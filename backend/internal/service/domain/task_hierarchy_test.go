@@ -0,0 +1,213 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testMaxDepth is the subtask nesting limit of the task services under test
+const testMaxDepth = 3
+
+// hierarchyTasks returns a project with the tree
+//
+//	epic
+//	├── story (DONE)
+//	│   └── step
+//	└── spike
+//	loose
+func hierarchyTasks() []entities.Task {
+	return []entities.Task{
+		{ID: "epic", ProjectID: "project-1", Title: "Epic", Status: entities.TaskStatusInProgress},
+		{ID: "story", ProjectID: "project-1", ParentID: "epic", Title: "Story", Status: entities.TaskStatusDone},
+		{ID: "step", ProjectID: "project-1", ParentID: "story", Title: "Step", Status: entities.TaskStatusTodo},
+		{ID: "spike", ProjectID: "project-1", ParentID: "epic", Title: "Spike", Status: entities.TaskStatusTodo},
+		{ID: "loose", ProjectID: "project-1", Title: "Loose", Status: entities.TaskStatusTodo},
+	}
+}
+
+// hierarchyRepo returns a task repository serving hierarchyTasks
+func hierarchyRepo() *MockTaskRepository {
+	tasks := hierarchyTasks()
+	taskRepo := new(MockTaskRepository)
+	for _, task := range tasks {
+		taskRepo.On("FindByID", task.ID).Return(task, nil).Maybe()
+	}
+	taskRepo.On("FindByProjectID", "project-1").Return(tasks, nil).Maybe()
+	return taskRepo
+}
+
+func TestTaskService_InsertSubtask(t *testing.T) {
+	tests := []struct {
+		name          string
+		parentID      string
+		expectedError string
+	}{
+		{name: "below top-level task", parentID: "loose"},
+		{name: "at the depth limit", parentID: "step"},
+		{name: "unknown parent", parentID: "other", expectedError: "parentId: other is not a task of the project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := hierarchyRepo()
+			taskRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+			service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+
+			task := entities.Task{Title: "New", ProjectID: "project-1", ParentID: tt.parentID}
+			err := service.Insert(context.Background(), &task)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+			taskRepo.AssertNotCalled(t, "Insert", mock.Anything)
+		})
+	}
+
+	t.Run("beyond the depth limit", func(t *testing.T) {
+		taskRepo := hierarchyRepo()
+		service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow())), 2)
+
+		task := entities.Task{Title: "New", ProjectID: "project-1", ParentID: "step"}
+		assert.EqualError(t, service.Insert(context.Background(), &task), "parentId: subtasks can be nested at most 2 levels deep")
+	})
+}
+
+func TestTaskService_MoveSubtree(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		parentID      string
+		maxDepth      int
+		expectedError string
+	}{
+		{name: "to another parent", id: "spike", parentID: "story"},
+		{name: "to the top level", id: "story", parentID: ""},
+		{name: "below itself", id: "epic", parentID: "epic", expectedError: "parentId: a task cannot be moved below itself or one of its subtasks"},
+		{name: "below its own subtask", id: "epic", parentID: "step", expectedError: "parentId: a task cannot be moved below itself or one of its subtasks"},
+		{name: "with subtasks at the depth limit", id: "story", parentID: "spike"},
+		{name: "subtasks would exceed the depth limit", id: "story", parentID: "spike", maxDepth: 2, expectedError: "parentId: subtasks can be nested at most 2 levels deep"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := hierarchyRepo()
+			taskRepo.On("Update", mock.Anything).Return(nil).Maybe()
+			maxDepth := tt.maxDepth
+			if maxDepth == 0 {
+				maxDepth = testMaxDepth
+			}
+			service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow())), maxDepth)
+
+			var task entities.Task
+			for _, existing := range hierarchyTasks() {
+				if existing.ID == tt.id {
+					task = existing
+				}
+			}
+			task.ParentID = tt.parentID
+			err := service.Update(context.Background(), &task)
+
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+			taskRepo.AssertNotCalled(t, "Update", mock.Anything)
+		})
+	}
+}
+
+func TestTaskService_SubtaskProgress(t *testing.T) {
+	service := domain.NewTaskService(testRepository(hierarchyRepo(), projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+	ctx := context.Background()
+
+	epic, err := service.FindByID(ctx, "epic")
+	require.NoError(t, err)
+	assert.Equal(t, &entities.TaskProgress{Done: 1, Total: 3}, epic.Progress)
+
+	children, err := service.Children(ctx, "epic")
+	require.NoError(t, err)
+	require.Len(t, children, 2)
+	assert.Equal(t, "story", children[0].ID)
+	assert.Equal(t, &entities.TaskProgress{Done: 0, Total: 1}, children[0].Progress)
+	assert.Nil(t, children[1].Progress, "tasks without subtasks have no progress")
+
+	tree, err := service.Subtree(ctx, "epic")
+	require.NoError(t, err)
+	require.Len(t, tree.Subtasks, 2)
+	assert.Equal(t, "step", tree.Subtasks[0].Subtasks[0].ID)
+	assert.Empty(t, tree.Subtasks[1].Subtasks)
+
+	tasks, err := service.FindByProjectID(ctx, "project-1", entities.TaskFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, epic.Progress, tasks[0].Progress)
+	assert.Nil(t, tasks[4].Progress)
+}
+
+func TestTaskService_DeleteRemovesSubtasks(t *testing.T) {
+	t.Run("single task", func(t *testing.T) {
+		taskRepo := hierarchyRepo()
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"story", "spike", "step"}).Return([]error{nil, nil, nil}, nil).Once()
+		taskRepo.On("Delete", "epic").Return(nil).Once()
+		service := domain.NewTaskService(testRepository(taskRepo, nil), testMaxDepth)
+
+		require.NoError(t, service.Delete(context.Background(), "epic"))
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		taskRepo := hierarchyRepo()
+		taskRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{}, nil)
+		taskRepo.On("UpdateMany", mock.Anything, "project-1", mock.Anything).Return([]error{}, nil)
+		taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"story", "loose", "step"}).Return([]error{nil, nil, nil}, nil).Once()
+		service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+
+		ops := []entities.TaskBatchOperation{
+			{Op: entities.TaskBatchOpDelete, ID: "story"},
+			{Op: entities.TaskBatchOpDelete, ID: "loose"},
+		}
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[0].Status)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[1].Status)
+		taskRepo.AssertExpectations(t)
+	})
+}
+
+func TestProjectService_DeleteRemovesTasks(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("Delete", "project-1").Return(nil)
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("DeleteByProjectID", "project-1").Return(nil)
+	dependencyRepo := new(MockDependencyRepository)
+	dependencyRepo.On("DeleteByProjectID", "project-1").Return(nil)
+	memberRepo := new(MockMemberRepository)
+	memberRepo.On("DeleteByProjectID", "project-1").Return(nil)
+	commentRepo := new(MockCommentRepository)
+	commentRepo.On("DeleteByProjectID", "project-1").Return(nil)
+	timeEntryRepo := new(MockTimeEntryRepository)
+	timeEntryRepo.On("DeleteByProjectID", "project-1").Return(nil)
+	repo := testRepository(taskRepo, projectRepo)
+	repo.DependencyRepository = dependencyRepo
+	repo.MemberRepository = memberRepo
+	repo.CommentRepository = commentRepo
+	repo.TimeEntryRepository = timeEntryRepo
+	service := domain.NewProjectService(repo)
+
+	require.NoError(t, service.Delete(context.Background(), "project-1"))
+	taskRepo.AssertExpectations(t)
+	dependencyRepo.AssertExpectations(t)
+	memberRepo.AssertExpectations(t)
+	commentRepo.AssertExpectations(t)
+	timeEntryRepo.AssertExpectations(t)
+}
@@ -3,38 +3,898 @@ package domain
 import (
 	"boilerplate/internal/entities"
 	"boilerplate/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"time"
 )
 
+// ErrBatchAborted is returned when an atomic batch was rolled back because at least one operation failed
+var ErrBatchAborted = errors.New("batch aborted")
+
 type taskService struct {
-	taskRepo storage.TaskRepository
+	taskRepo         storage.TaskRepository
+	projectRepo      storage.ProjectRepository
+	labelRepo        storage.LabelRepository
+	fieldRepo        storage.CustomFieldRepository
+	dependencyRepo   storage.DependencyRepository
+	memberRepo       storage.MemberRepository
+	commentRepo      storage.CommentRepository
+	attachmentRepo   storage.AttachmentRepository
+	timeEntryRepo    storage.TimeEntryRepository
+	notificationRepo storage.NotificationRepository
+	blobs            storage.BlobStore
+	transactor       storage.Transactor
+	// maxDepth limits the levels of subtasks below a top-level task
+	maxDepth int
 }
 
-func NewTaskService(taskRepo storage.TaskRepository) *taskService {
+// NewTaskService creates the task service. Next to the task and project
+// repositories it uses the labels, custom fields and members of repo for
+// validation and removes the dependency links, comments, time entries and
+// attachments of deleted tasks.
+func NewTaskService(repo storage.Repository, maxDepth int) *taskService {
 	return &taskService{
-		taskRepo: taskRepo,
+		taskRepo:         repo.TaskRepository,
+		projectRepo:      repo.ProjectRepository,
+		labelRepo:        repo.LabelRepository,
+		fieldRepo:        repo.CustomFieldRepository,
+		dependencyRepo:   repo.DependencyRepository,
+		memberRepo:       repo.MemberRepository,
+		commentRepo:      repo.CommentRepository,
+		attachmentRepo:   repo.AttachmentRepository,
+		timeEntryRepo:    repo.TimeEntryRepository,
+		notificationRepo: repo.NotificationRepository,
+		blobs:            repo.BlobStore,
+		transactor:       repo.Transactor,
+		maxDepth:         maxDepth,
+	}
+}
+
+// Insert creates a task. Tasks without a status start in the first status of
+// their project's workflow; subtasks must fit below their parent's depth. A
+// task with a recurrence becomes the first occurrence of a new series.
+func (s *taskService) Insert(ctx context.Context, task *entities.Task) error {
+	workflow, err := s.workflow(ctx, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	if task.Status == "" {
+		task.Status = workflow.Initial()
+	}
+	if err := checkStatus(workflow, task.Status); err != nil {
+		return err
+	}
+	if task.ParentID != "" {
+		tree, err := s.hierarchy(ctx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkParent(task, tree); err != nil {
+			return err
+		}
+	}
+	if err := s.validate(ctx, task); err != nil {
+		return err
+	}
+	task.SeriesID, task.Occurrence = "", nil
+	if err := prepareRecurrence(task, nil, time.Now()); err != nil {
+		return err
+	}
+	return s.taskRepo.Insert(ctx, task)
+}
+
+// Update replaces a task. A status change must be allowed by the workflow of
+// the task's project, otherwise ErrTransitionNotAllowed is returned. A task
+// cannot be moved to a done status while tasks blocking it are open, which
+// returns ErrOpenBlockers. Moving the task to another parent moves its
+// subtasks along. Completing the latest occurrence of a recurring task
+// creates the next one.
+func (s *taskService) Update(ctx context.Context, task *entities.Task) error {
+	existing, err := s.taskRepo.FindByID(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+	var (
+		workflow  entities.Workflow
+		completed bool
+	)
+	if task.Status != existing.Status {
+		workflow, err = s.workflow(ctx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err := checkStatus(workflow, task.Status); err != nil {
+			return err
+		}
+		if err := checkTransition(workflow, existing.Status, task.Status); err != nil {
+			return err
+		}
+		if isDone(workflow, task.Status) && !isDone(workflow, existing.Status) {
+			blockers, err := openBlockers(ctx, s.dependencyRepo, s.taskRepo, s.projectRepo, []string{task.ID})
+			if err != nil {
+				return err
+			}
+			if len(blockers[task.ID]) > 0 {
+				return blockedError(blockers[task.ID])
+			}
+			completed = true
+		}
+	}
+	if task.ParentID != existing.ParentID && task.ParentID != "" {
+		tree, err := s.hierarchy(ctx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkParent(task, tree); err != nil {
+			return err
+		}
+	}
+	if err := s.validate(ctx, task); err != nil {
+		return err
+	}
+	// The series of a task is managed here, not by the caller
+	task.SeriesID, task.Occurrence = existing.SeriesID, existing.Occurrence
+	if err := prepareRecurrence(task, existing.Recurrence, time.Now()); err != nil {
+		return err
+	}
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return err
+	}
+
+	if completed && task.Recurrence != nil {
+		// The task is saved either way; should this fail, the scheduler
+		// creates the next occurrence since the latest one is done
+		if _, err := s.advanceSeries(ctx, *task, workflow.Initial(), time.Time{}); err == nil {
+			task.Recurrence = nil
+		}
+	}
+	return nil
+}
+
+// hierarchy loads the parent links of the tasks of a project
+func (s *taskService) hierarchy(ctx context.Context, projectID string) (*hierarchy, error) {
+	tasks, err := s.taskRepo.FindByProjectID(ctx, projectID, entities.TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return newHierarchy(tasks), nil
+}
+
+// checkParent rejects parents outside the task's project, moves below the task
+// itself and nesting deeper than the configured limit
+func (s *taskService) checkParent(task *entities.Task, tree *hierarchy) error {
+	if _, ok := tree.tasks[task.ParentID]; !ok {
+		return invalidf("parentId", "%s is not a task of the project", task.ParentID)
+	}
+	if task.ID != "" && (task.ParentID == task.ID || tree.isDescendant(task.ParentID, task.ID)) {
+		return invalidf("parentId", "a task cannot be moved below itself or one of its subtasks")
+	}
+
+	// Existing tasks bring their subtasks along
+	levels := tree.depth(task.ParentID) + 1
+	if task.ID != "" {
+		levels += tree.height(task.ID)
+	}
+	if levels > s.maxDepth {
+		return invalidf("parentId", "subtasks can be nested at most %d levels deep", s.maxDepth)
+	}
+	return nil
+}
+
+// workflow returns the workflow of a project
+func (s *taskService) workflow(ctx context.Context, projectID string) (entities.Workflow, error) {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return entities.Workflow{}, err
+	}
+	return project.Workflow, nil
+}
+
+// validate checks the priority, estimate, labels, custom fields, assignees and
+// watchers of a task against its project and normalizes them in place
+func (s *taskService) validate(ctx context.Context, task *entities.Task) error {
+	if !validPriority(task.Priority) {
+		return invalidf("priority", "is not a valid priority")
+	}
+	if task.Estimate < 0 {
+		return invalidf("estimate", "must not be negative")
+	}
+
+	if len(task.LabelIDs) > 0 {
+		labels, err := s.labelRepo.FindByProjectID(ctx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		known := make(map[string]bool, len(labels))
+		for _, label := range labels {
+			known[label.ID] = true
+		}
+
+		labelIDs := make([]string, 0, len(task.LabelIDs))
+		seen := make(map[string]bool, len(task.LabelIDs))
+		for _, id := range task.LabelIDs {
+			if !known[id] {
+				return invalidf("labelIds", "%s is not a label of the project", id)
+			}
+			if !seen[id] {
+				seen[id] = true
+				labelIDs = append(labelIDs, id)
+			}
+		}
+		task.LabelIDs = labelIDs
+	}
+
+	if len(task.CustomFields) > 0 {
+		fields, err := s.fieldRepo.FindByProjectID(ctx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		values, err := normalizeFieldValues(task.CustomFields, fields, false)
+		if err != nil {
+			return err
+		}
+		task.CustomFields = values
+	}
+
+	if len(task.Assignees) > 0 || len(task.Watchers) > 0 {
+		members, err := s.memberRepo.FindByProjectID(ctx, task.ProjectID)
+		if err != nil {
+			return err
+		}
+		byUser := make(map[string]entities.ProjectMember, len(members))
+		for _, member := range members {
+			byUser[member.UserID] = member
+		}
+		if task.Assignees, err = resolveUsers("assignees", task.Assignees, byUser); err != nil {
+			return err
+		}
+		if task.Watchers, err = resolveUsers("watchers", task.Watchers, byUser); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes a task together with all of its subtasks, their dependency
+// links, comments, time entries and attachments
+func (s *taskService) Delete(ctx context.Context, id string) error {
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	tree, err := s.hierarchy(ctx, task.ProjectID)
+	if err != nil {
+		return err
+	}
+
+	// Subtasks go first so that a failure never leaves subtasks without their parent
+	descendants := tree.descendants(id)
+	if len(descendants) > 0 {
+		if _, err := s.taskRepo.DeleteMany(ctx, task.ProjectID, descendants); err != nil {
+			return err
+		}
+	}
+	if err := s.taskRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	deleted := append(descendants, id)
+	if err := s.dependencyRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+		return err
+	}
+	if err := s.commentRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+		return err
+	}
+	if err := s.timeEntryRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+		return err
+	}
+	if err := s.notificationRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+		return err
+	}
+	attachments, err := s.attachmentRepo.FindByTaskIDs(ctx, deleted)
+	if err != nil {
+		return err
+	}
+	if err := s.attachmentRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+		return err
+	}
+	return deleteAttachmentBlobs(ctx, s.blobs, attachments)
+}
+
+// FindByID returns a task with the progress of its subtasks and its comment count
+func (s *taskService) FindByID(ctx context.Context, id string) (entities.Task, error) {
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return entities.Task{}, err
+	}
+	tasks, err := s.withProgress(ctx, task.ProjectID, []entities.Task{task}, nil)
+	if err != nil {
+		return entities.Task{}, err
+	}
+	if err := s.withCommentCounts(ctx, tasks); err != nil {
+		return entities.Task{}, err
+	}
+	return tasks[0], nil
+}
+
+// Children lists the direct subtasks of a task
+func (s *taskService) Children(ctx context.Context, id string) ([]entities.Task, error) {
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.hierarchy(ctx, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]entities.Task, 0, len(tree.children[id]))
+	for _, childID := range tree.children[id] {
+		children = append(children, tree.tasks[childID])
+	}
+	if len(children) == 0 {
+		return children, nil
+	}
+	if children, err = s.withProgress(ctx, task.ProjectID, children, tree); err != nil {
+		return nil, err
+	}
+	return children, s.withCommentCounts(ctx, children)
+}
+
+// Subtree returns a task with all of its subtasks nested below it and their comment counts
+func (s *taskService) Subtree(ctx context.Context, id string) (entities.TaskTree, error) {
+	task, err := s.taskRepo.FindByID(ctx, id)
+	if err != nil {
+		return entities.TaskTree{}, err
+	}
+	tree, err := s.hierarchy(ctx, task.ProjectID)
+	if err != nil {
+		return entities.TaskTree{}, err
+	}
+	workflow, err := s.workflow(ctx, task.ProjectID)
+	if err != nil {
+		return entities.TaskTree{}, err
+	}
+
+	subtree := tree.tree(id, workflow)
+	counts, err := s.commentRepo.CountByTaskIDs(ctx, append(tree.descendants(id), id))
+	if err != nil {
+		return entities.TaskTree{}, err
+	}
+	setCommentCounts(&subtree, counts)
+	return subtree, nil
+}
+
+func setCommentCounts(node *entities.TaskTree, counts map[string]int) {
+	node.CommentCount = counts[node.ID]
+	for i := range node.Subtasks {
+		setCommentCounts(&node.Subtasks[i], counts)
+	}
+}
+
+// withCommentCounts sets the number of comments on each of the given tasks
+func (s *taskService) withCommentCounts(ctx context.Context, tasks []entities.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	counts, err := s.commentRepo.CountByTaskIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		tasks[i].CommentCount = counts[tasks[i].ID]
+	}
+	return nil
+}
+
+// withProgress sets the progress of the given tasks of a project. tree may be
+// nil, then the hierarchy is loaded; the workflow is only read if the project
+// has subtasks at all.
+func (s *taskService) withProgress(ctx context.Context, projectID string, tasks []entities.Task, tree *hierarchy) ([]entities.Task, error) {
+	if tree == nil {
+		var err error
+		if tree, err = s.hierarchy(ctx, projectID); err != nil {
+			return nil, err
+		}
+	}
+	if !tree.hasSubtasks() {
+		return tasks, nil
+	}
+
+	workflow, err := s.workflow(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i] = tree.withProgress(tasks[i], workflow)
 	}
+	return tasks, nil
 }
 
-func (s *taskService) Insert(task *entities.Task) error {
-	return s.taskRepo.Insert(task)
+func (s *taskService) FindAll(ctx context.Context) ([]entities.Task, error) {
+	return s.taskRepo.FindAll(ctx)
 }
 
-func (s *taskService) Update(task *entities.Task) error {
-	return s.taskRepo.Update(task)
+// FindByAssignee lists the tasks of all projects assigned to a user, with the
+// progress of their subtasks and their comment counts
+func (s *taskService) FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error) {
+	tasks, err := s.taskRepo.FindByAssignee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byProject := make(map[string][]int)
+	var projectIDs []string
+	for i, task := range tasks {
+		if _, ok := byProject[task.ProjectID]; !ok {
+			projectIDs = append(projectIDs, task.ProjectID)
+		}
+		byProject[task.ProjectID] = append(byProject[task.ProjectID], i)
+	}
+	for _, projectID := range projectIDs {
+		indexes := byProject[projectID]
+		projectTasks := make([]entities.Task, len(indexes))
+		for n, i := range indexes {
+			projectTasks[n] = tasks[i]
+		}
+		if projectTasks, err = s.withProgress(ctx, projectID, projectTasks, nil); err != nil {
+			return nil, err
+		}
+		for n, i := range indexes {
+			tasks[i] = projectTasks[n]
+		}
+	}
+	return tasks, s.withCommentCounts(ctx, tasks)
 }
 
-func (s *taskService) Delete(id string) error {
-	return s.taskRepo.Delete(id)
+// FindByProjectID lists the tasks of a project matching filter, with the
+// progress of their subtasks and their comment counts. Custom field values in
+// the filter may be given as text and are parsed by field type.
+func (s *taskService) FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
+	if len(filter.CustomFields) > 0 {
+		fields, err := s.fieldRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		values, err := normalizeFieldValues(filter.CustomFields, fields, true)
+		if err != nil {
+			return nil, err
+		}
+		filter.CustomFields = values
+	}
+
+	tasks, err := s.taskRepo.FindByProjectID(ctx, projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// An unfiltered list already holds the whole hierarchy
+	var tree *hierarchy
+	if isEmptyFilter(filter) {
+		tree = newHierarchy(tasks)
+	}
+	if tasks, err = s.withProgress(ctx, projectID, tasks, tree); err != nil {
+		return nil, err
+	}
+	return tasks, s.withCommentCounts(ctx, tasks)
 }
 
-func (s *taskService) FindByID(id string) (entities.Task, error) {
-	return s.taskRepo.FindByID(id)
+func isEmptyFilter(filter entities.TaskFilter) bool {
+	return len(filter.IDs) == 0 && filter.Status == nil && len(filter.Priorities) == 0 &&
+		len(filter.LabelIDs) == 0 && len(filter.CustomFields) == 0 && filter.AssigneeID == ""
+}
+
+// ExecuteBatch groups the operations by type and runs them as one bulk write per type:
+// creates first, then updates, then deletes. Since that does not keep the request
+// order, a task may only be changed by one operation of a batch. Results are
+// reported in request order.
+func (s *taskService) ExecuteBatch(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error) {
+	workflow, err := s.workflow(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]entities.TaskBatchResult, len(ops))
+	opErrs := validateBatch(ops, workflow)
+	invalid := false
+	for i, op := range ops {
+		results[i] = entities.TaskBatchResult{Index: i, Op: op.Op, ID: op.ID}
+		if opErrs[i] != nil {
+			setFailed(&results[i], opErrs[i])
+			invalid = true
+		}
+	}
+
+	if atomic && invalid {
+		markRolledBack(results)
+		return results, ErrBatchAborted
+	}
+
+	// Attachment content cannot be rolled back, so it is deleted once the batch is committed
+	var removed []entities.Attachment
+	run := func(ctx context.Context) error {
+		var err error
+		if removed, err = s.runBatch(ctx, projectID, workflow, ops, opErrs, results, atomic); err != nil {
+			return err
+		}
+		if atomic && hasFailures(results) {
+			return ErrBatchAborted
+		}
+		return nil
+	}
+
+	if !atomic {
+		if err := run(ctx); err != nil {
+			return nil, err
+		}
+		s.deleteBatchBlobs(ctx, removed)
+		return results, nil
+	}
+
+	if s.transactor == nil {
+		return nil, errors.New("transactions are not supported")
+	}
+
+	if err := s.transactor.WithTransaction(ctx, run); err != nil {
+		if !errors.Is(err, ErrBatchAborted) {
+			return nil, err
+		}
+		markRolledBack(results)
+		return results, ErrBatchAborted
+	}
+
+	s.deleteBatchBlobs(ctx, removed)
+	return results, nil
 }
 
-func (s *taskService) FindAll() ([]entities.Task, error) {
-	return s.taskRepo.FindAll()
+// deleteBatchBlobs removes the content of the attachments of tasks deleted in
+// a batch. The outcome of the batch is reported per operation, so a blob that
+// could not be deleted is left behind rather than failing the request.
+func (s *taskService) deleteBatchBlobs(ctx context.Context, attachments []entities.Attachment) {
+	_ = deleteAttachmentBlobs(ctx, s.blobs, attachments)
 }
 
-func (s *taskService) FindByProjectID(projectID string) ([]entities.Task, error) {
-	return s.taskRepo.FindByProjectID(projectID)
+// runBatch executes the operations without an error in opErrs and records
+// their outcome in results. It may be called more than once when a transaction
+// is retried, so it resets every result it is responsible for before writing.
+// A failed write aborts a transaction on the server, so in atomic mode no
+// further writes are issued after one and ErrBatchAborted is returned. It
+// returns the attachments of the deleted tasks, whose content is still stored.
+func (s *taskService) runBatch(ctx context.Context, projectID string, workflow entities.Workflow, ops []entities.TaskBatchOperation, opErrs []error, results []entities.TaskBatchResult, atomic bool) ([]entities.Attachment, error) {
+	var (
+		creates   []*entities.Task
+		createIdx []int
+		patches   []entities.TaskPatch
+		updateIdx []int
+		deleteIDs []string
+		deleteIdx []int
+	)
+
+	current, err := s.currentStatuses(ctx, projectID, workflow, ops)
+	if err != nil {
+		return nil, err
+	}
+	blockers, err := s.batchBlockers(ctx, projectID, workflow, ops, current)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		// Invalid operations keep the result recorded during validation
+		if opErrs[i] != nil {
+			continue
+		}
+		results[i] = entities.TaskBatchResult{Index: i, Op: op.Op, ID: op.ID}
+
+		switch op.Op {
+		case entities.TaskBatchOpCreate:
+			task := *op.Task
+			task.ProjectID = projectID
+			if task.Status == "" {
+				task.Status = workflow.Initial()
+			}
+			creates = append(creates, &task)
+			createIdx = append(createIdx, i)
+		case entities.TaskBatchOpUpdate:
+			patch := *op.Patch
+			patch.ID = op.ID
+			// Unknown tasks are left to UpdateMany, which reports them
+			if from, ok := current[op.ID]; ok && patch.Status != nil {
+				if err := checkTransition(workflow, from, *patch.Status); err != nil {
+					setFailed(&results[i], err)
+					continue
+				}
+			}
+			if len(blockers[op.ID]) > 0 {
+				setFailed(&results[i], blockedError(blockers[op.ID]))
+				continue
+			}
+			patches = append(patches, patch)
+			updateIdx = append(updateIdx, i)
+		case entities.TaskBatchOpDelete:
+			deleteIDs = append(deleteIDs, op.ID)
+			deleteIdx = append(deleteIdx, i)
+		}
+	}
+
+	if atomic && hasFailures(results) {
+		return nil, ErrBatchAborted
+	}
+
+	createErrs, err := s.taskRepo.InsertMany(ctx, creates)
+	if err != nil {
+		return nil, err
+	}
+	for n, i := range createIdx {
+		if createErrs[n] != nil {
+			setFailed(&results[i], createErrs[n])
+			continue
+		}
+		results[i].Status = entities.TaskBatchStatusOK
+		results[i].ID = creates[n].ID
+		results[i].Task = creates[n]
+	}
+	if atomic && hasFailures(results) {
+		return nil, ErrBatchAborted
+	}
+
+	updateErrs, err := s.taskRepo.UpdateMany(ctx, projectID, patches)
+	if err != nil {
+		return nil, err
+	}
+	for n, i := range updateIdx {
+		if updateErrs[n] != nil {
+			setFailed(&results[i], updateErrs[n])
+			continue
+		}
+		results[i].Status = entities.TaskBatchStatusOK
+	}
+	if atomic && hasFailures(results) {
+		return nil, ErrBatchAborted
+	}
+
+	// Subtasks of deleted tasks are deleted along; their outcome is not reported
+	if len(deleteIDs) > 0 {
+		subtasks, err := s.subtasksOf(ctx, projectID, deleteIDs)
+		if err != nil {
+			return nil, err
+		}
+		deleteIDs = append(deleteIDs, subtasks...)
+	}
+	deleteErrs, err := s.taskRepo.DeleteMany(ctx, projectID, deleteIDs)
+	if err != nil {
+		return nil, err
+	}
+	// Links, comments, time entries, notifications and attachments of tasks that could not be deleted, e.g. of other projects, are kept
+	var (
+		deleted     []string
+		attachments []entities.Attachment
+	)
+	for n, id := range deleteIDs {
+		if deleteErrs[n] == nil {
+			deleted = append(deleted, id)
+		}
+	}
+	if len(deleted) > 0 {
+		if err := s.dependencyRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+			return nil, err
+		}
+		if err := s.commentRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+			return nil, err
+		}
+		if err := s.timeEntryRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+			return nil, err
+		}
+		if err := s.notificationRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+			return nil, err
+		}
+		if attachments, err = s.attachmentRepo.FindByTaskIDs(ctx, deleted); err != nil {
+			return nil, err
+		}
+		if err := s.attachmentRepo.DeleteByTaskIDs(ctx, deleted); err != nil {
+			return nil, err
+		}
+	}
+	for n, i := range deleteIdx {
+		if deleteErrs[n] != nil {
+			setFailed(&results[i], deleteErrs[n])
+			continue
+		}
+		results[i].Status = entities.TaskBatchStatusOK
+	}
+
+	return attachments, nil
+}
+
+// currentStatuses loads the statuses of the tasks whose status the batch changes,
+// so that the transitions can be checked. Workflows without transitions allow
+// every move and need no lookup.
+func (s *taskService) currentStatuses(ctx context.Context, projectID string, workflow entities.Workflow, ops []entities.TaskBatchOperation) (map[string]entities.TaskStatus, error) {
+	if len(workflow.Transitions) == 0 {
+		return nil, nil
+	}
+
+	var ids []string
+	for _, op := range ops {
+		if op.Op == entities.TaskBatchOpUpdate && op.Patch != nil && op.Patch.Status != nil {
+			ids = append(ids, op.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	tasks, err := s.taskRepo.FindByProjectID(ctx, projectID, entities.TaskFilter{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[string]entities.TaskStatus, len(tasks))
+	for _, task := range tasks {
+		statuses[task.ID] = task.Status
+	}
+	return statuses, nil
+}
+
+// batchBlockers returns the open blockers of the tasks the batch moves to a
+// done status, keyed by task ID. Tasks that are done already are skipped; their
+// statuses are only loaded if current lacks them and they have open blockers.
+func (s *taskService) batchBlockers(ctx context.Context, projectID string, workflow entities.Workflow, ops []entities.TaskBatchOperation, current map[string]entities.TaskStatus) (map[string][]entities.Task, error) {
+	var ids []string
+	for _, op := range ops {
+		if op.Op == entities.TaskBatchOpUpdate && op.Patch != nil && op.Patch.Status != nil && isDone(workflow, *op.Patch.Status) {
+			ids = append(ids, op.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	blockers, err := openBlockers(ctx, s.dependencyRepo, s.taskRepo, s.projectRepo, ids)
+	if err != nil || len(blockers) == 0 {
+		return nil, err
+	}
+
+	if current == nil {
+		blockedIDs := make([]string, 0, len(blockers))
+		for id := range blockers {
+			blockedIDs = append(blockedIDs, id)
+		}
+		tasks, err := s.taskRepo.FindByProjectID(ctx, projectID, entities.TaskFilter{IDs: blockedIDs})
+		if err != nil {
+			return nil, err
+		}
+		current = make(map[string]entities.TaskStatus, len(tasks))
+		for _, task := range tasks {
+			current[task.ID] = task.Status
+		}
+	}
+	for id := range blockers {
+		// Unknown tasks are left to UpdateMany, which reports them
+		if from, ok := current[id]; !ok || isDone(workflow, from) {
+			delete(blockers, id)
+		}
+	}
+	return blockers, nil
+}
+
+// subtasksOf returns the subtasks at every level below the given tasks that are not listed themselves
+func (s *taskService) subtasksOf(ctx context.Context, projectID string, ids []string) ([]string, error) {
+	tree, err := s.hierarchy(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !tree.hasSubtasks() {
+		return nil, nil
+	}
+
+	listed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		listed[id] = true
+	}
+	var subtasks []string
+	for _, id := range ids {
+		for _, descendant := range tree.descendants(id) {
+			if !listed[descendant] {
+				listed[descendant] = true
+				subtasks = append(subtasks, descendant)
+			}
+		}
+	}
+	return subtasks, nil
+}
+
+// validateBatch checks every operation of a batch on its own and rejects
+// operations on a task that an earlier operation of the batch changes already
+func validateBatch(ops []entities.TaskBatchOperation, workflow entities.Workflow) []error {
+	errs := make([]error, len(ops))
+	first := make(map[string]int)
+	for i, op := range ops {
+		if errs[i] = validateBatchOperation(op, workflow); errs[i] != nil || op.Op == entities.TaskBatchOpCreate {
+			continue
+		}
+		if n, ok := first[op.ID]; ok {
+			errs[i] = fmt.Errorf("task %s is already changed by operation %d of the batch", op.ID, n)
+			continue
+		}
+		first[op.ID] = i
+	}
+	return errs
+}
+
+func validateBatchOperation(op entities.TaskBatchOperation, workflow entities.Workflow) error {
+	switch op.Op {
+	case entities.TaskBatchOpCreate:
+		if op.Task == nil || op.Task.Title == "" {
+			return errors.New("title is required")
+		}
+		if op.Task.Status != "" {
+			if err := checkStatus(workflow, op.Task.Status); err != nil {
+				return err
+			}
+		}
+		if !validPriority(op.Task.Priority) {
+			return errors.New("invalid priority")
+		}
+	case entities.TaskBatchOpUpdate:
+		if op.ID == "" {
+			return errors.New("id is required")
+		}
+		if op.Patch == nil {
+			return errors.New("no fields to update")
+		}
+		if op.Patch.Title != nil && *op.Patch.Title == "" {
+			return errors.New("title cannot be empty")
+		}
+		if op.Patch.Status != nil {
+			if err := checkStatus(workflow, *op.Patch.Status); err != nil {
+				return err
+			}
+		}
+		if op.Patch.Priority != nil && !validPriority(*op.Patch.Priority) {
+			return errors.New("invalid priority")
+		}
+	case entities.TaskBatchOpDelete:
+		if op.ID == "" {
+			return errors.New("id is required")
+		}
+	default:
+		return fmt.Errorf("unknown operation: %q", op.Op)
+	}
+	return nil
+}
+
+func validPriority(priority entities.TaskPriority) bool {
+	return priority >= entities.TaskPriorityNone && priority <= entities.TaskPriorityUrgent
+}
+
+func setFailed(result *entities.TaskBatchResult, err error) {
+	result.Status = entities.TaskBatchStatusFailed
+	result.Error = err.Error()
+}
+
+func hasFailures(results []entities.TaskBatchResult) bool {
+	for _, result := range results {
+		if result.Status == entities.TaskBatchStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// markRolledBack flags every operation that did not fail itself as rolled back
+func markRolledBack(results []entities.TaskBatchResult) {
+	for i := range results {
+		if results[i].Status == entities.TaskBatchStatusFailed {
+			continue
+		}
+		results[i].Status = entities.TaskBatchStatusRolledBack
+		results[i].Task = nil
+		if results[i].Op == entities.TaskBatchOpCreate {
+			results[i].ID = ""
+		}
+	}
 }
@@ -3,8 +3,11 @@ package domain_test
 import (
 	"boilerplate/internal/entities"
 	"boilerplate/internal/service/domain"
+	"boilerplate/internal/storage"
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -14,22 +17,22 @@ type MockTaskRepository struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepository) Insert(task *entities.Task) error {
+func (m *MockTaskRepository) Insert(ctx context.Context, task *entities.Task) error {
 	args := m.Called(task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) Update(task *entities.Task) error {
+func (m *MockTaskRepository) Update(ctx context.Context, task *entities.Task) error {
 	args := m.Called(task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) Delete(id string) error {
+func (m *MockTaskRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) FindByID(id string) (entities.Task, error) {
+func (m *MockTaskRepository) FindByID(ctx context.Context, id string) (entities.Task, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return entities.Task{}, args.Error(1)
@@ -37,7 +40,7 @@ func (m *MockTaskRepository) FindByID(id string) (entities.Task, error) {
 	return args.Get(0).(entities.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) FindAll() ([]entities.Task, error) {
+func (m *MockTaskRepository) FindAll(ctx context.Context) ([]entities.Task, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -45,7 +48,7 @@ func (m *MockTaskRepository) FindAll() ([]entities.Task, error) {
 	return args.Get(0).([]entities.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) FindByProjectID(projectID string) ([]entities.Task, error) {
+func (m *MockTaskRepository) FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
 	args := m.Called(projectID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -53,6 +56,135 @@ func (m *MockTaskRepository) FindByProjectID(projectID string) ([]entities.Task,
 	return args.Get(0).([]entities.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) CountByStatus(ctx context.Context, projectID string) (map[entities.TaskStatus]int, error) {
+	args := m.Called(projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[entities.TaskStatus]int), args.Error(1)
+}
+
+func (m *MockTaskRepository) RemoveLabel(ctx context.Context, projectID, labelID string) error {
+	args := m.Called(projectID, labelID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) CountByCustomFieldValue(ctx context.Context, projectID, fieldID string) (map[string]int, error) {
+	args := m.Called(projectID, fieldID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockTaskRepository) RemoveCustomField(ctx context.Context, projectID, fieldID string) error {
+	args := m.Called(projectID, fieldID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveUser(ctx context.Context, projectID, userID string) error {
+	args := m.Called(projectID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) InsertOccurrence(ctx context.Context, task *entities.Task) (bool, error) {
+	args := m.Called(task)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) ClearRecurrence(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) FindRecurring(ctx context.Context) ([]entities.Task, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindDue(ctx context.Context, from, to time.Time) ([]entities.Task, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) InsertMany(ctx context.Context, tasks []*entities.Task) ([]error, error) {
+	args := m.Called(ctx, tasks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockTaskRepository) UpdateMany(ctx context.Context, projectID string, patches []entities.TaskPatch) ([]error, error) {
+	args := m.Called(ctx, projectID, patches)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockTaskRepository) DeleteMany(ctx context.Context, projectID string, ids []string) ([]error, error) {
+	args := m.Called(ctx, projectID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+// fakeTransactor runs the function directly and reports whether it was used
+type fakeTransactor struct {
+	calls int
+}
+
+func (f *fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
+// projectWithWorkflow returns a project repository whose projects all use workflow
+func projectWithWorkflow(workflow entities.Workflow) *MockProjectRepository {
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("FindByID", mock.Anything).Return(entities.Project{ID: "project-1", Workflow: workflow}, nil).Maybe()
+	return projectRepo
+}
+
+// testRepository returns the repositories of the task and project services in
+// which the cascading lookups and deletes find nothing; tests replace the ones
+// they check
+func testRepository(taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository) storage.Repository {
+	return storage.Repository{
+		TaskRepository:         taskRepo,
+		ProjectRepository:      projectRepo,
+		LabelRepository:        noLabels(),
+		CustomFieldRepository:  noCustomFields(),
+		DependencyRepository:   noDependencies(),
+		MemberRepository:       noMembers(),
+		CommentRepository:      noComments(),
+		AttachmentRepository:   noAttachments(),
+		TimeEntryRepository:    noTimeEntries(),
+		NotificationRepository: noNotifications(),
+	}
+}
+
 func createTestTask() entities.Task {
 	return entities.Task{
 		ID:        "test-task-id",
@@ -125,6 +257,16 @@ func TestTaskService_CreateTask(t *testing.T) {
 				setupMockForCreateTask(t, m, task, entities.Task{}, errors.New("project ID is required"))
 			},
 		},
+		{
+			name: "negative estimate",
+			task: entities.Task{
+				Title:     "Task with negative estimate",
+				ProjectID: "project-1",
+				Status:    entities.TaskStatusTodo,
+				Estimate:  -60,
+			},
+			expectedError: errors.New("estimate: must not be negative"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -134,9 +276,9 @@ func TestTaskService_CreateTask(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.task)
 			}
 
-			service := domain.NewTaskService(mockRepo)
+			service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
 			taskToCreate := tt.task // Create a copy to avoid modifying the test case
-			err := service.Insert(&taskToCreate)
+			err := service.Insert(context.Background(), &taskToCreate)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -155,6 +297,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 func setupMockForGetTask(t *testing.T, mockRepo *MockTaskRepository, taskID string, returnTask entities.Task, returnErr error) {
 	t.Helper()
 	mockRepo.On("FindByID", taskID).Return(returnTask, returnErr)
+	mockRepo.On("FindByProjectID", returnTask.ProjectID).Return([]entities.Task{returnTask}, nil).Maybe()
 }
 
 func TestTaskService_GetTask(t *testing.T) {
@@ -202,8 +345,8 @@ func TestTaskService_GetTask(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.taskID)
 			}
 
-			service := domain.NewTaskService(mockRepo)
-			task, err := service.FindByID(tt.taskID)
+			service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+			task, err := service.FindByID(context.Background(), tt.taskID)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -219,6 +362,7 @@ func TestTaskService_GetTask(t *testing.T) {
 
 func setupMockForUpdateTask(t *testing.T, mockRepo *MockTaskRepository, task entities.Task, returnErr error) {
 	t.Helper()
+	mockRepo.On("FindByID", task.ID).Return(task, nil)
 	mockRepo.On("Update", mock.MatchedBy(func(t *entities.Task) bool {
 		return t.ID == task.ID
 	})).Return(returnErr)
@@ -254,7 +398,7 @@ func TestTaskService_UpdateTask(t *testing.T) {
 			},
 			expectedError: errors.New("task not found"),
 			setupMock: func(t *testing.T, m *MockTaskRepository, task entities.Task) {
-				setupMockForUpdateTask(t, m, task, errors.New("task not found"))
+				m.On("FindByID", task.ID).Return(nil, errors.New("task not found"))
 			},
 		},
 	}
@@ -266,9 +410,9 @@ func TestTaskService_UpdateTask(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.task)
 			}
 
-			service := domain.NewTaskService(mockRepo)
+			service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
 			taskToUpdate := tt.task // Create a copy to avoid modifying the test case
-			err := service.Update(&taskToUpdate)
+			err := service.Update(context.Background(), &taskToUpdate)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -283,6 +427,9 @@ func TestTaskService_UpdateTask(t *testing.T) {
 
 func setupMockForDeleteTask(t *testing.T, mockRepo *MockTaskRepository, taskID string, returnErr error) {
 	t.Helper()
+	task := entities.Task{ID: taskID, ProjectID: "project-1"}
+	mockRepo.On("FindByID", taskID).Return(task, nil)
+	mockRepo.On("FindByProjectID", task.ProjectID).Return([]entities.Task{task}, nil)
 	mockRepo.On("Delete", taskID).Return(returnErr)
 }
 
@@ -318,8 +465,8 @@ func TestTaskService_DeleteTask(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.taskID)
 			}
 
-			service := domain.NewTaskService(mockRepo)
-			err := service.Delete(tt.taskID)
+			service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+			err := service.Delete(context.Background(), tt.taskID)
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -383,8 +530,8 @@ func TestTaskService_ListTasks(t *testing.T) {
 				tt.setupMock(t, mockRepo)
 			}
 
-			service := domain.NewTaskService(mockRepo)
-			tasks, err := service.FindAll()
+			service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+			tasks, err := service.FindAll(context.Background())
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -453,8 +600,8 @@ func TestTaskService_FindByProjectID(t *testing.T) {
 				tt.setupMock(t, mockRepo, tt.projectID)
 			}
 
-			service := domain.NewTaskService(mockRepo)
-			tasks, err := service.FindByProjectID(tt.projectID)
+			service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+			tasks, err := service.FindByProjectID(context.Background(), tt.projectID, entities.TaskFilter{})
 
 			if tt.expectedError != nil {
 				assert.EqualError(t, err, tt.expectedError.Error())
@@ -467,3 +614,234 @@ func TestTaskService_FindByProjectID(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskService_ExecuteBatch(t *testing.T) {
+	title := "Renamed"
+	done := entities.TaskStatusDone
+
+	ops := []entities.TaskBatchOperation{
+		{Op: entities.TaskBatchOpCreate, Task: &entities.Task{Title: "New Task"}},
+		{Op: entities.TaskBatchOpUpdate, ID: "task-1", Patch: &entities.TaskPatch{Title: &title, Status: &done}},
+		{Op: entities.TaskBatchOpDelete, ID: "task-2"},
+	}
+
+	t.Run("best effort reports per-operation results", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("InsertMany", mock.Anything, mock.MatchedBy(func(tasks []*entities.Task) bool {
+			if len(tasks) != 1 || tasks[0].ProjectID != "project-1" {
+				return false
+			}
+			tasks[0].ID = "created-id"
+			return true
+		})).Return([]error{nil}, nil)
+		mockRepo.On("UpdateMany", mock.Anything, "project-1", mock.MatchedBy(func(patches []entities.TaskPatch) bool {
+			return len(patches) == 1 && patches[0].ID == "task-1"
+		})).Return([]error{nil}, nil)
+		mockRepo.On("DeleteMany", mock.Anything, "project-1", []string{"task-2"}).Return([]error{errors.New("task not found")}, nil)
+		mockRepo.On("FindByProjectID", "project-1").Return([]entities.Task{}, nil)
+
+		service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[0].Status)
+		assert.Equal(t, "created-id", results[0].ID)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[1].Status)
+		assert.Equal(t, entities.TaskBatchStatusFailed, results[2].Status)
+		assert.Equal(t, "task not found", results[2].Error)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("atomic mode rolls back on failure", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{nil}, nil)
+		mockRepo.On("UpdateMany", mock.Anything, "project-1", mock.Anything).Return([]error{nil}, nil)
+		mockRepo.On("DeleteMany", mock.Anything, "project-1", mock.Anything).Return([]error{errors.New("task not found")}, nil)
+		mockRepo.On("FindByProjectID", "project-1").Return([]entities.Task{}, nil)
+
+		tx := &fakeTransactor{}
+		repo := testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.Transactor = tx
+		service := domain.NewTaskService(repo, testMaxDepth)
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, true)
+
+		assert.ErrorIs(t, err, domain.ErrBatchAborted)
+		assert.Equal(t, 1, tx.calls)
+		assert.Equal(t, entities.TaskBatchStatusRolledBack, results[0].Status)
+		assert.Empty(t, results[0].ID)
+		assert.Equal(t, entities.TaskBatchStatusRolledBack, results[1].Status)
+		assert.Equal(t, entities.TaskBatchStatusFailed, results[2].Status)
+	})
+
+	t.Run("atomic mode rejects invalid operations without writing", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		tx := &fakeTransactor{}
+		repo := testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.Transactor = tx
+		service := domain.NewTaskService(repo, testMaxDepth)
+
+		invalid := append([]entities.TaskBatchOperation{{Op: "archive", ID: "task-3"}}, ops...)
+		results, err := service.ExecuteBatch(context.Background(), "project-1", invalid, true)
+
+		assert.ErrorIs(t, err, domain.ErrBatchAborted)
+		assert.Equal(t, 0, tx.calls)
+		assert.Equal(t, entities.TaskBatchStatusFailed, results[0].Status)
+		for _, result := range results[1:] {
+			assert.Equal(t, entities.TaskBatchStatusRolledBack, result.Status)
+		}
+		mockRepo.AssertNotCalled(t, "InsertMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a task is changed by one operation only", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{}, nil)
+		mockRepo.On("UpdateMany", mock.Anything, "project-1", mock.Anything).Return([]error{}, nil)
+		mockRepo.On("DeleteMany", mock.Anything, "project-1", []string{"task-2"}).Return([]error{nil}, nil).Once()
+		mockRepo.On("FindByProjectID", "project-1").Return([]entities.Task{}, nil)
+
+		service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+		results, err := service.ExecuteBatch(context.Background(), "project-1", []entities.TaskBatchOperation{
+			{Op: entities.TaskBatchOpDelete, ID: "task-2"},
+			{Op: entities.TaskBatchOpUpdate, ID: "task-2", Patch: &entities.TaskPatch{Title: &title}},
+		}, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entities.TaskBatchStatusOK, results[0].Status)
+		assert.Equal(t, entities.TaskBatchStatusFailed, results[1].Status)
+		assert.Equal(t, "task task-2 is already changed by operation 0 of the batch", results[1].Error)
+		mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, "project-1", mock.MatchedBy(func(patches []entities.TaskPatch) bool { return len(patches) > 0 }))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("atomic mode stops writing after a failed write", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{errors.New("duplicate key")}, nil).Once()
+
+		tx := &fakeTransactor{}
+		repo := testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+		repo.Transactor = tx
+		service := domain.NewTaskService(repo, testMaxDepth)
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, true)
+
+		assert.ErrorIs(t, err, domain.ErrBatchAborted)
+		assert.Equal(t, entities.TaskBatchStatusFailed, results[0].Status)
+		assert.Equal(t, entities.TaskBatchStatusRolledBack, results[1].Status)
+		assert.Equal(t, entities.TaskBatchStatusRolledBack, results[2].Status)
+		mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "DeleteMany", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository failure aborts the whole batch", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("InsertMany", mock.Anything, mock.Anything).Return(nil, errors.New("connection lost"))
+
+		service := domain.NewTaskService(testRepository(mockRepo, projectWithWorkflow(entities.DefaultWorkflow())), testMaxDepth)
+		results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+
+		assert.EqualError(t, err, "connection lost")
+		assert.Nil(t, results)
+	})
+}
+
+func TestTaskService_ValidatesLabelsAndCustomFields(t *testing.T) {
+	labels := []entities.Label{{ID: "label-1", ProjectID: "project-1", Name: "bug"}}
+	fields := []entities.CustomField{
+		{ID: "points", ProjectID: "project-1", Name: "Points", Type: entities.CustomFieldTypeNumber},
+		{ID: "team", ProjectID: "project-1", Name: "Team", Type: entities.CustomFieldTypeSingleSelect, Options: []string{"web", "mobile"}},
+		{ID: "release", ProjectID: "project-1", Name: "Release", Type: entities.CustomFieldTypeDate},
+	}
+
+	tests := []struct {
+		name          string
+		task          entities.Task
+		expectedError string
+		check         func(*testing.T, entities.Task)
+	}{
+		{
+			name: "duplicate labels are dropped and dates parsed",
+			task: entities.Task{
+				Title:        "Task",
+				ProjectID:    "project-1",
+				LabelIDs:     []string{"label-1", "label-1"},
+				CustomFields: entities.CustomFieldValues{"points": 5.0, "team": "web", "release": "2025-03-01"},
+			},
+			check: func(t *testing.T, task entities.Task) {
+				assert.Equal(t, []string{"label-1"}, task.LabelIDs)
+				assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), task.CustomFields["release"])
+			},
+		},
+		{
+			name:          "unknown custom field",
+			task:          entities.Task{Title: "Task", ProjectID: "project-1", CustomFields: entities.CustomFieldValues{"other": "x"}},
+			expectedError: "customFields.other: is not a custom field of the project",
+		},
+		{
+			name:          "label of another project",
+			task:          entities.Task{Title: "Task", ProjectID: "project-1", LabelIDs: []string{"label-2"}},
+			expectedError: "labelIds: label-2 is not a label of the project",
+		},
+		{
+			name:          "number field with text",
+			task:          entities.Task{Title: "Task", ProjectID: "project-1", CustomFields: entities.CustomFieldValues{"points": "five"}},
+			expectedError: "customFields.points: Points must be a number",
+		},
+		{
+			name:          "unknown select option",
+			task:          entities.Task{Title: "Task", ProjectID: "project-1", CustomFields: entities.CustomFieldValues{"team": "backend"}},
+			expectedError: "customFields.team: Team must be one of web, mobile",
+		},
+		{
+			name:          "invalid priority",
+			task:          entities.Task{Title: "Task", ProjectID: "project-1", Priority: entities.TaskPriority(9)},
+			expectedError: "priority: is not a valid priority",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(MockTaskRepository)
+			labelRepo := new(MockLabelRepository)
+			fieldRepo := new(MockCustomFieldRepository)
+			labelRepo.On("FindByProjectID", "project-1").Return(labels, nil).Maybe()
+			fieldRepo.On("FindByProjectID", "project-1").Return(fields, nil).Maybe()
+			taskRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+
+			repo := testRepository(taskRepo, projectWithWorkflow(entities.DefaultWorkflow()))
+			repo.LabelRepository = labelRepo
+			repo.CustomFieldRepository = fieldRepo
+			service := domain.NewTaskService(repo, testMaxDepth)
+			task := tt.task
+			err := service.Insert(context.Background(), &task)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				taskRepo.AssertNotCalled(t, "Insert", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			tt.check(t, task)
+		})
+	}
+}
+
+func TestTaskService_FindByProjectIDParsesCustomFieldFilter(t *testing.T) {
+	taskRepo := new(MockTaskRepository)
+	fieldRepo := new(MockCustomFieldRepository)
+	fieldRepo.On("FindByProjectID", "project-1").Return([]entities.CustomField{
+		{ID: "points", Name: "Points", Type: entities.CustomFieldTypeNumber},
+	}, nil)
+	taskRepo.On("FindByProjectID", "project-1").Return([]entities.Task{}, nil)
+
+	repo := testRepository(taskRepo, nil)
+	repo.CustomFieldRepository = fieldRepo
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	_, err := service.FindByProjectID(context.Background(), "project-1", entities.TaskFilter{CustomFields: entities.CustomFieldValues{"points": "3"}})
+	assert.NoError(t, err)
+
+	_, err = service.FindByProjectID(context.Background(), "project-1", entities.TaskFilter{CustomFields: entities.CustomFieldValues{"points": "many"}})
+	assert.EqualError(t, err, "customFields.points: Points must be a number")
+	// The valid filter reads the filtered list and the hierarchy for the progress rollup
+	taskRepo.AssertNumberOfCalls(t, "FindByProjectID", 2)
+}
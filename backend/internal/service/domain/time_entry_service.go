@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxEntryDuration limits the duration of a manual time entry in seconds
+	maxEntryDuration = 24 * 60 * 60
+	// maxNoteLength limits the length of time entry notes
+	maxNoteLength = 1000
+	// maxReportDays limits the number of days a time report spans
+	maxReportDays = 366
+)
+
+var (
+	// ErrTimerRunning is returned when a timer is started while the user has one
+	// running, or when a running timer is edited
+	ErrTimerRunning = errors.New("a timer is already running")
+	// ErrNoTimerRunning is returned when a timer is stopped or read that is not running
+	ErrNoTimerRunning = errors.New("no timer is running")
+	// ErrNotTimeEntryOwner is returned when a time entry is edited or deleted by someone else than its user
+	ErrNotTimeEntryOwner = errors.New("only the user who tracked the time can change it")
+)
+
+type timeEntryService struct {
+	timeEntryRepo storage.TimeEntryRepository
+	taskRepo      storage.TaskRepository
+	projectRepo   storage.ProjectRepository
+}
+
+func NewTimeEntryService(timeEntryRepo storage.TimeEntryRepository, taskRepo storage.TaskRepository, projectRepo storage.ProjectRepository) *timeEntryService {
+	return &timeEntryService{
+		timeEntryRepo: timeEntryRepo,
+		taskRepo:      taskRepo,
+		projectRepo:   projectRepo,
+	}
+}
+
+// StartTimer starts a timer for a user on a task. A user has at most one
+// running timer; starting another one fails with ErrTimerRunning.
+func (s *timeEntryService) StartTimer(ctx context.Context, taskID string, user entities.UserRef, note string) (entities.TimeEntry, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return entities.TimeEntry{}, fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+
+	entry := entities.TimeEntry{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		User:      user,
+		Start:     time.Now().UTC().Truncate(time.Second),
+		Note:      note,
+	}
+	if err := validateNote(&entry); err != nil {
+		return entities.TimeEntry{}, err
+	}
+
+	started, err := s.timeEntryRepo.InsertTimer(ctx, &entry)
+	if err != nil {
+		return entities.TimeEntry{}, err
+	}
+	if !started {
+		running, found, err := s.timeEntryRepo.FindRunning(ctx, user.ID)
+		if err != nil || !found {
+			return entities.TimeEntry{}, ErrTimerRunning
+		}
+		return entities.TimeEntry{}, fmt.Errorf("%w on task %s", ErrTimerRunning, running.TaskID)
+	}
+	return entry, nil
+}
+
+// StopTimer stops the running timer of a user on a task and records the time
+// spent. It returns ErrNoTimerRunning if the user has no timer running on the task.
+func (s *timeEntryService) StopTimer(ctx context.Context, taskID, userID string) (entities.TimeEntry, error) {
+	entry, found, err := s.timeEntryRepo.StopTimer(ctx, userID, taskID, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return entities.TimeEntry{}, err
+	}
+	if !found {
+		return entities.TimeEntry{}, fmt.Errorf("%w on task %s", ErrNoTimerRunning, taskID)
+	}
+	return entry, nil
+}
+
+// RunningTimer returns the running timer of a user, or ErrNoTimerRunning
+func (s *timeEntryService) RunningTimer(ctx context.Context, userID string) (entities.TimeEntry, error) {
+	entry, found, err := s.timeEntryRepo.FindRunning(ctx, userID)
+	if err != nil {
+		return entities.TimeEntry{}, err
+	}
+	if !found {
+		return entities.TimeEntry{}, ErrNoTimerRunning
+	}
+	return entry, nil
+}
+
+// Create adds a time entry with a duration. Without a start the work is taken
+// to have ended now.
+func (s *timeEntryService) Create(ctx context.Context, entry *entities.TimeEntry) error {
+	task, err := s.taskRepo.FindByID(ctx, entry.TaskID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+	entry.ProjectID = task.ProjectID
+
+	if entry.Start.IsZero() && entry.Duration > 0 {
+		entry.Start = time.Now().Add(-time.Duration(entry.Duration) * time.Second)
+	}
+	if err := validateEntry(entry); err != nil {
+		return err
+	}
+	return s.timeEntryRepo.Insert(ctx, entry)
+}
+
+// Update replaces the start, duration and note of a stopped time entry. Only
+// the user who tracked the time may change it.
+func (s *timeEntryService) Update(ctx context.Context, entry *entities.TimeEntry, editor entities.UserRef) error {
+	existing, err := s.timeEntryRepo.FindByID(ctx, entry.ID)
+	if err != nil {
+		return err
+	}
+	if existing.User.ID != editor.ID {
+		return ErrNotTimeEntryOwner
+	}
+	if existing.Running() {
+		return fmt.Errorf("%w, stop it first", ErrTimerRunning)
+	}
+
+	start, duration, note := entry.Start, entry.Duration, entry.Note
+	*entry = existing
+	entry.Start, entry.Duration, entry.Note = start, duration, note
+	if err := validateEntry(entry); err != nil {
+		return err
+	}
+	return s.timeEntryRepo.Update(ctx, entry)
+}
+
+// Delete removes a time entry, also a running timer. Only the user who tracked
+// the time may delete it.
+func (s *timeEntryService) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	existing, err := s.timeEntryRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing.User.ID != actor.ID {
+		return ErrNotTimeEntryOwner
+	}
+	return s.timeEntryRepo.Delete(ctx, id)
+}
+
+func (s *timeEntryService) FindByID(ctx context.Context, id string) (entities.TimeEntry, error) {
+	return s.timeEntryRepo.FindByID(ctx, id)
+}
+
+// FindByTaskID returns the time entries of a task in order of their start
+func (s *timeEntryService) FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error) {
+	if _, err := s.taskRepo.FindByID(ctx, taskID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTaskNotFound, err)
+	}
+	return s.timeEntryRepo.FindByTaskID(ctx, taskID)
+}
+
+// Report sums up the time spent on the tasks of a project between two days,
+// both given as YYYY-MM-DD and inclusive, per user, per task and per day.
+// Days are calendar days in the IANA time zone, UTC if empty; entries count
+// on the day they started. Running timers are left out.
+func (s *timeEntryService) Report(ctx context.Context, projectID, from, to, timezone string) (entities.TimeReport, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if timezone == "Local" {
+		return entities.TimeReport{}, invalidf("timezone", "must name a time zone")
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return entities.TimeReport{}, invalidf("timezone", "%q is not a known time zone", timezone)
+	}
+
+	first, err := time.ParseInLocation(time.DateOnly, from, location)
+	if err != nil {
+		return entities.TimeReport{}, invalidf("from", "must be a date in the form YYYY-MM-DD")
+	}
+	last, err := time.ParseInLocation(time.DateOnly, to, location)
+	if err != nil {
+		return entities.TimeReport{}, invalidf("to", "must be a date in the form YYYY-MM-DD")
+	}
+	if last.Before(first) {
+		return entities.TimeReport{}, invalidf("to", "must not be before from")
+	}
+	end := last.AddDate(0, 0, 1)
+	if end.After(first.AddDate(0, 0, maxReportDays)) {
+		return entities.TimeReport{}, invalidf("to", "a report may span at most %d days", maxReportDays)
+	}
+
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		return entities.TimeReport{}, fmt.Errorf("%w: %v", ErrProjectNotFound, err)
+	}
+
+	report, err := s.timeEntryRepo.Report(ctx, projectID, first, end, location.String())
+	if err != nil {
+		return entities.TimeReport{}, err
+	}
+	report.From, report.To, report.Timezone = from, to, location.String()
+	return report, nil
+}
+
+// validateEntry checks the duration, start and note of a manual time entry
+// and sets its end
+func validateEntry(entry *entities.TimeEntry) error {
+	if entry.Duration <= 0 || entry.Duration > maxEntryDuration {
+		return invalidf("duration", "must be between 1 second and 24 hours")
+	}
+	entry.Start = entry.Start.UTC().Truncate(time.Second)
+	end := entry.Start.Add(time.Duration(entry.Duration) * time.Second)
+	if end.After(time.Now().Add(time.Minute)) {
+		return invalidf("start", "the time must not end in the future")
+	}
+	entry.End = &end
+	return validateNote(entry)
+}
+
+// validateNote trims the note of a time entry and checks its length
+func validateNote(entry *entities.TimeEntry) error {
+	entry.Note = strings.TrimSpace(entry.Note)
+	if utf8.RuneCountInString(entry.Note) > maxNoteLength {
+		return invalidf("note", "must be at most %d characters", maxNoteLength)
+	}
+	return nil
+}
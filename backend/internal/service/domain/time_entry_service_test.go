@@ -0,0 +1,303 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTimeEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimeEntryRepository) Insert(ctx context.Context, entry *entities.TimeEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) InsertTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error) {
+	args := m.Called(entry)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) StopTimer(ctx context.Context, userID, taskID string, end time.Time) (entities.TimeEntry, bool, error) {
+	args := m.Called(userID, taskID, end)
+	return args.Get(0).(entities.TimeEntry), args.Bool(1), args.Error(2)
+}
+
+func (m *MockTimeEntryRepository) FindRunning(ctx context.Context, userID string) (entities.TimeEntry, bool, error) {
+	args := m.Called(userID)
+	return args.Get(0).(entities.TimeEntry), args.Bool(1), args.Error(2)
+}
+
+func (m *MockTimeEntryRepository) Update(ctx context.Context, entry *entities.TimeEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) FindByID(ctx context.Context, id string) (entities.TimeEntry, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return entities.TimeEntry{}, args.Error(1)
+	}
+	return args.Get(0).(entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Report(ctx context.Context, projectID string, from, to time.Time, timezone string) (entities.TimeReport, error) {
+	args := m.Called(projectID, from, to, timezone)
+	return args.Get(0).(entities.TimeReport), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockTimeEntryRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	args := m.Called(projectID)
+	return args.Error(0)
+}
+
+// noTimeEntries returns a time entry repository for tasks without tracked time
+func noTimeEntries() *MockTimeEntryRepository {
+	timeEntryRepo := new(MockTimeEntryRepository)
+	timeEntryRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	timeEntryRepo.On("DeleteByProjectID", mock.Anything).Return(nil).Maybe()
+	return timeEntryRepo
+}
+
+var jane = entities.UserRef{ID: "jane", Name: "Jane Doe", Email: "jane@example.com"}
+
+func TestTimeEntryService_StartTimer(t *testing.T) {
+	t.Run("starts a timer on the task", func(t *testing.T) {
+		timeEntryRepo := new(MockTimeEntryRepository)
+		timeEntryRepo.On("InsertTimer", mock.MatchedBy(func(entry *entities.TimeEntry) bool {
+			return entry.TaskID == "task-1" && entry.ProjectID == "project-1" && entry.User == jane && entry.Running()
+		})).Return(true, nil).Once()
+		service := domain.NewTimeEntryService(timeEntryRepo, commentedTask(), nil)
+
+		entry, err := service.StartTimer(context.Background(), "task-1", jane, "  Call with the client ")
+		require.NoError(t, err)
+		assert.Equal(t, "Call with the client", entry.Note)
+		assert.WithinDuration(t, time.Now(), entry.Start, 2*time.Second)
+		timeEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("one timer per user", func(t *testing.T) {
+		timeEntryRepo := new(MockTimeEntryRepository)
+		timeEntryRepo.On("InsertTimer", mock.Anything).Return(false, nil).Once()
+		timeEntryRepo.On("FindRunning", "jane").Return(entities.TimeEntry{ID: "entry-1", TaskID: "task-2"}, true, nil).Once()
+		service := domain.NewTimeEntryService(timeEntryRepo, commentedTask(), nil)
+
+		_, err := service.StartTimer(context.Background(), "task-1", jane, "")
+		assert.ErrorIs(t, err, domain.ErrTimerRunning)
+		assert.ErrorContains(t, err, "task-2")
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		service := domain.NewTimeEntryService(new(MockTimeEntryRepository), commentedTask(), nil)
+
+		_, err := service.StartTimer(context.Background(), "task-9", jane, "")
+		assert.ErrorIs(t, err, domain.ErrTaskNotFound)
+	})
+}
+
+func TestTimeEntryService_StopTimer(t *testing.T) {
+	end := time.Now()
+	stopped := entities.TimeEntry{ID: "entry-1", TaskID: "task-1", User: jane, Start: end.Add(-time.Hour), End: &end, Duration: 3600}
+	timeEntryRepo := new(MockTimeEntryRepository)
+	timeEntryRepo.On("StopTimer", "jane", "task-1", mock.Anything).Return(stopped, true, nil).Once()
+	timeEntryRepo.On("StopTimer", "jane", "task-2", mock.Anything).Return(entities.TimeEntry{}, false, nil).Once()
+	service := domain.NewTimeEntryService(timeEntryRepo, commentedTask(), nil)
+
+	entry, err := service.StopTimer(context.Background(), "task-1", "jane")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), entry.Duration)
+
+	_, err = service.StopTimer(context.Background(), "task-2", "jane")
+	assert.ErrorIs(t, err, domain.ErrNoTimerRunning)
+}
+
+func TestTimeEntryService_Create(t *testing.T) {
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		entry         entities.TimeEntry
+		expectedError string
+		expectedIs    error
+	}{
+		{name: "with start", entry: entities.TimeEntry{TaskID: "task-1", Start: yesterday, Duration: 5400, Note: "Workshop"}},
+		{name: "ending now", entry: entities.TimeEntry{TaskID: "task-1", Duration: 900}},
+		{name: "no duration", entry: entities.TimeEntry{TaskID: "task-1", Start: yesterday}, expectedError: "duration: must be between 1 second and 24 hours"},
+		{name: "longer than a day", entry: entities.TimeEntry{TaskID: "task-1", Start: yesterday, Duration: 86401}, expectedError: "duration: must be between 1 second and 24 hours"},
+		{name: "ending in the future", entry: entities.TimeEntry{TaskID: "task-1", Start: time.Now(), Duration: 3600}, expectedError: "start: the time must not end in the future"},
+		{name: "note too long", entry: entities.TimeEntry{TaskID: "task-1", Duration: 60, Note: strings.Repeat("a", 1001)}, expectedError: "note: must be at most 1000 characters"},
+		{name: "unknown task", entry: entities.TimeEntry{TaskID: "task-9", Duration: 60}, expectedIs: domain.ErrTaskNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeEntryRepo := new(MockTimeEntryRepository)
+			timeEntryRepo.On("Insert", mock.Anything).Return(nil).Maybe()
+			service := domain.NewTimeEntryService(timeEntryRepo, commentedTask(), nil)
+
+			entry := tt.entry
+			err := service.Create(context.Background(), &entry)
+
+			switch {
+			case tt.expectedIs != nil:
+				assert.ErrorIs(t, err, tt.expectedIs)
+			case tt.expectedError != "":
+				assert.EqualError(t, err, tt.expectedError)
+				timeEntryRepo.AssertNotCalled(t, "Insert", mock.Anything)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "project-1", entry.ProjectID)
+				require.NotNil(t, entry.End)
+				assert.Equal(t, time.Duration(entry.Duration)*time.Second, entry.End.Sub(entry.Start))
+				assert.False(t, entry.End.After(time.Now()), "the entry must not end in the future")
+				timeEntryRepo.AssertCalled(t, "Insert", &entry)
+			}
+		})
+	}
+}
+
+func TestTimeEntryService_UpdateAndDelete(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+	end := start.Add(time.Hour)
+	stopped := entities.TimeEntry{ID: "stopped", TaskID: "task-1", ProjectID: "project-1", User: jane, Start: start, End: &end, Duration: 3600}
+	running := entities.TimeEntry{ID: "running", TaskID: "task-1", ProjectID: "project-1", User: jane, Start: start}
+	joe := entities.UserRef{ID: "joe"}
+
+	newRepo := func() *MockTimeEntryRepository {
+		timeEntryRepo := new(MockTimeEntryRepository)
+		timeEntryRepo.On("FindByID", "stopped").Return(stopped, nil).Maybe()
+		timeEntryRepo.On("FindByID", "running").Return(running, nil).Maybe()
+		timeEntryRepo.On("Update", mock.Anything).Return(nil).Maybe()
+		timeEntryRepo.On("Delete", mock.Anything).Return(nil).Maybe()
+		return timeEntryRepo
+	}
+
+	t.Run("the owner corrects the duration", func(t *testing.T) {
+		timeEntryRepo := newRepo()
+		service := domain.NewTimeEntryService(timeEntryRepo, commentedTask(), nil)
+
+		entry := &entities.TimeEntry{ID: "stopped", Start: start, Duration: 1800, Note: "Review"}
+		require.NoError(t, service.Update(context.Background(), entry, jane))
+		assert.Equal(t, "task-1", entry.TaskID)
+		assert.Equal(t, jane, entry.User)
+		assert.Equal(t, start.Add(30*time.Minute), *entry.End)
+		timeEntryRepo.AssertCalled(t, "Update", entry)
+	})
+
+	t.Run("others cannot change the entry", func(t *testing.T) {
+		service := domain.NewTimeEntryService(newRepo(), commentedTask(), nil)
+
+		err := service.Update(context.Background(), &entities.TimeEntry{ID: "stopped", Start: start, Duration: 60}, joe)
+		assert.ErrorIs(t, err, domain.ErrNotTimeEntryOwner)
+		assert.ErrorIs(t, service.Delete(context.Background(), "stopped", joe), domain.ErrNotTimeEntryOwner)
+	})
+
+	t.Run("running timers are stopped before they are edited", func(t *testing.T) {
+		service := domain.NewTimeEntryService(newRepo(), commentedTask(), nil)
+
+		err := service.Update(context.Background(), &entities.TimeEntry{ID: "running", Start: start, Duration: 60}, jane)
+		assert.ErrorIs(t, err, domain.ErrTimerRunning)
+	})
+
+	t.Run("the owner discards a running timer", func(t *testing.T) {
+		timeEntryRepo := newRepo()
+		service := domain.NewTimeEntryService(timeEntryRepo, commentedTask(), nil)
+
+		require.NoError(t, service.Delete(context.Background(), "running", jane))
+		timeEntryRepo.AssertCalled(t, "Delete", "running")
+	})
+}
+
+func TestTimeEntryService_Report(t *testing.T) {
+	t.Run("days are calendar days in the time zone", func(t *testing.T) {
+		timeEntryRepo := new(MockTimeEntryRepository)
+		// March 2030 in Berlin starts in winter time and ends in summer time
+		from := time.Date(2030, 2, 28, 23, 0, 0, 0, time.UTC)
+		to := time.Date(2030, 3, 31, 22, 0, 0, 0, time.UTC)
+		timeEntryRepo.On("Report", "project-1", mock.MatchedBy(from.Equal), mock.MatchedBy(to.Equal), "Europe/Berlin").
+			Return(entities.TimeReport{ProjectID: "project-1", Duration: 7200, Entries: 2}, nil).Once()
+		service := domain.NewTimeEntryService(timeEntryRepo, nil, projectWithWorkflow(entities.DefaultWorkflow()))
+
+		report, err := service.Report(context.Background(), "project-1", "2030-03-01", "2030-03-31", "Europe/Berlin")
+		require.NoError(t, err)
+		assert.Equal(t, "2030-03-01", report.From)
+		assert.Equal(t, "2030-03-31", report.To)
+		assert.Equal(t, "Europe/Berlin", report.Timezone)
+		assert.Equal(t, int64(7200), report.Duration)
+		timeEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		projectRepo.On("FindByID", "project-9").Return(entities.Project{}, errors.New("project not found"))
+		service := domain.NewTimeEntryService(new(MockTimeEntryRepository), nil, projectRepo)
+
+		_, err := service.Report(context.Background(), "project-9", "2030-03-01", "2030-03-31", "")
+		assert.ErrorIs(t, err, domain.ErrProjectNotFound)
+	})
+
+	tests := []struct {
+		name          string
+		from, to      string
+		timezone      string
+		expectedError string
+	}{
+		{name: "malformed from", from: "03/01/2030", to: "2030-03-31", expectedError: "from: must be a date in the form YYYY-MM-DD"},
+		{name: "missing to", from: "2030-03-01", expectedError: "to: must be a date in the form YYYY-MM-DD"},
+		{name: "reversed range", from: "2030-03-31", to: "2030-03-01", expectedError: "to: must not be before from"},
+		{name: "longer than a year", from: "2030-01-01", to: "2031-01-02", expectedError: "to: a report may span at most 366 days"},
+		{name: "unknown time zone", from: "2030-03-01", to: "2030-03-31", timezone: "Mars/Olympus", expectedError: `timezone: "Mars/Olympus" is not a known time zone`},
+		{name: "local time zone", from: "2030-03-01", to: "2030-03-31", timezone: "Local", expectedError: "timezone: must name a time zone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := domain.NewTimeEntryService(new(MockTimeEntryRepository), nil, new(MockProjectRepository))
+
+			_, err := service.Report(context.Background(), "project-1", tt.from, tt.to, tt.timezone)
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestTaskService_DeleteRemovesTimeEntries(t *testing.T) {
+	taskRepo := hierarchyRepo()
+	taskRepo.On("DeleteMany", mock.Anything, "project-1", []string{"step"}).Return([]error{nil}, nil).Once()
+	taskRepo.On("Delete", "story").Return(nil).Once()
+	timeEntryRepo := new(MockTimeEntryRepository)
+	timeEntryRepo.On("DeleteByTaskIDs", []string{"step", "story"}).Return(nil).Once()
+	repo := testRepository(taskRepo, nil)
+	repo.TimeEntryRepository = timeEntryRepo
+	service := domain.NewTaskService(repo, testMaxDepth)
+
+	require.NoError(t, service.Delete(context.Background(), "story"))
+	timeEntryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"boilerplate/internal/entities"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrTransitionNotAllowed is returned when a task would move between two
+// statuses that its project's workflow does not connect. Handlers answer it
+// with 422 Unprocessable Entity.
+var ErrTransitionNotAllowed = errors.New("status transition not allowed")
+
+// maxWorkflowStatuses limits the number of statuses of a workflow
+const maxWorkflowStatuses = 30
+
+// validateWorkflow checks a workflow and normalizes its keys and names in place
+func validateWorkflow(workflow *entities.Workflow) error {
+	if len(workflow.Statuses) == 0 {
+		return invalidf("workflow.statuses", "at least one status is required")
+	}
+	if len(workflow.Statuses) > maxWorkflowStatuses {
+		return invalidf("workflow.statuses", "must not have more than %d statuses", maxWorkflowStatuses)
+	}
+
+	seen := make(map[entities.TaskStatus]bool, len(workflow.Statuses))
+	for i := range workflow.Statuses {
+		status := &workflow.Statuses[i]
+		key, err := entities.ParseTaskStatus(string(status.Key))
+		if err != nil {
+			return invalidf("workflow.statuses", "key %q must be upper case letters, digits and underscores", status.Key)
+		}
+		if seen[key] {
+			return invalidf("workflow.statuses", "%s is listed twice", key)
+		}
+		seen[key] = true
+		status.Key = key
+
+		status.Name = strings.TrimSpace(status.Name)
+		if status.Name == "" {
+			return invalidf("workflow.statuses", "%s needs a name", key)
+		}
+		switch status.Category {
+		case entities.StatusCategoryTodo, entities.StatusCategoryActive, entities.StatusCategoryDone:
+		default:
+			return invalidf("workflow.statuses", "%s has category %q, must be todo, active or done", key, status.Category)
+		}
+	}
+
+	transitions := make(map[entities.WorkflowTransition]bool, len(workflow.Transitions))
+	for i := range workflow.Transitions {
+		transition := &workflow.Transitions[i]
+		transition.From = entities.TaskStatus(strings.ToUpper(string(transition.From)))
+		transition.To = entities.TaskStatus(strings.ToUpper(string(transition.To)))
+		if !seen[transition.From] || !seen[transition.To] {
+			return invalidf("workflow.transitions", "%s to %s refers to an unknown status", transition.From, transition.To)
+		}
+		if transition.From == transition.To {
+			return invalidf("workflow.transitions", "%s cannot transition to itself", transition.From)
+		}
+		if transitions[*transition] {
+			return invalidf("workflow.transitions", "%s to %s is listed twice", transition.From, transition.To)
+		}
+		transitions[*transition] = true
+	}
+
+	return nil
+}
+
+// checkStatus reports a validation error unless status belongs to workflow
+func checkStatus(workflow entities.Workflow, status entities.TaskStatus) error {
+	if _, ok := workflow.Status(status); ok {
+		return nil
+	}
+	keys := make([]string, len(workflow.Statuses))
+	for i, key := range workflow.Keys() {
+		keys[i] = string(key)
+	}
+	return invalidf("status", "%s is not part of the project's workflow, must be one of %s", status, strings.Join(keys, ", "))
+}
+
+// checkTransition returns ErrTransitionNotAllowed unless workflow allows the move
+func checkTransition(workflow entities.Workflow, from, to entities.TaskStatus) error {
+	if !workflow.Allows(from, to) {
+		return fmt.Errorf("%w: %s to %s", ErrTransitionNotAllowed, from, to)
+	}
+	return nil
+}
+
+// isDone reports whether a status belongs to the done category of the workflow
+func isDone(workflow entities.Workflow, status entities.TaskStatus) bool {
+	workflowStatus, ok := workflow.Status(status)
+	return ok && workflowStatus.Category == entities.StatusCategoryDone
+}
@@ -0,0 +1,193 @@
+package domain_test
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewWorkflow only allows TODO -> IN_PROGRESS -> IN_REVIEW -> DONE and back to IN_PROGRESS from review
+func reviewWorkflow() entities.Workflow {
+	return entities.Workflow{
+		Statuses: []entities.WorkflowStatus{
+			{Key: "TODO", Name: "To Do", Category: entities.StatusCategoryTodo},
+			{Key: "IN_PROGRESS", Name: "In Progress", Category: entities.StatusCategoryActive},
+			{Key: "IN_REVIEW", Name: "In Review", Category: entities.StatusCategoryActive},
+			{Key: "DONE", Name: "Done", Category: entities.StatusCategoryDone},
+		},
+		Transitions: []entities.WorkflowTransition{
+			{From: "TODO", To: "IN_PROGRESS"},
+			{From: "IN_PROGRESS", To: "IN_REVIEW"},
+			{From: "IN_REVIEW", To: "IN_PROGRESS"},
+			{From: "IN_REVIEW", To: "DONE"},
+		},
+	}
+}
+
+func TestTaskService_InsertUsesWorkflow(t *testing.T) {
+	t.Run("tasks without status start in the first status", func(t *testing.T) {
+		taskRepo := new(MockTaskRepository)
+		taskRepo.On("Insert", mock.Anything).Return(nil)
+		service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(reviewWorkflow())), testMaxDepth)
+
+		task := entities.Task{Title: "Task", ProjectID: "project-1"}
+		require.NoError(t, service.Insert(context.Background(), &task))
+		assert.Equal(t, entities.TaskStatus("TODO"), task.Status)
+	})
+
+	t.Run("unknown status is rejected", func(t *testing.T) {
+		taskRepo := new(MockTaskRepository)
+		service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(reviewWorkflow())), testMaxDepth)
+
+		task := entities.Task{Title: "Task", ProjectID: "project-1", Status: "BLOCKED"}
+		err := service.Insert(context.Background(), &task)
+
+		var validationErr *domain.ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+		assert.EqualError(t, err, "status: BLOCKED is not part of the project's workflow, must be one of TODO, IN_PROGRESS, IN_REVIEW, DONE")
+		taskRepo.AssertNotCalled(t, "Insert", mock.Anything)
+	})
+}
+
+func TestTaskService_UpdateEnforcesTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    entities.TaskStatus
+		to      entities.TaskStatus
+		allowed bool
+	}{
+		{name: "allowed move", from: "IN_PROGRESS", to: "IN_REVIEW", allowed: true},
+		{name: "unchanged status", from: "IN_PROGRESS", to: "IN_PROGRESS", allowed: true},
+		{name: "skipping review", from: "IN_PROGRESS", to: "DONE", allowed: false},
+		{name: "reopening done task", from: "DONE", to: "TODO", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(MockTaskRepository)
+			taskRepo.On("FindByID", "task-1").Return(entities.Task{ID: "task-1", ProjectID: "project-1", Status: tt.from}, nil)
+			taskRepo.On("Update", mock.Anything).Return(nil).Maybe()
+			service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(reviewWorkflow())), testMaxDepth)
+
+			task := entities.Task{ID: "task-1", Title: "Task", ProjectID: "project-1", Status: tt.to}
+			err := service.Update(context.Background(), &task)
+
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, domain.ErrTransitionNotAllowed), "expected ErrTransitionNotAllowed, got %v", err)
+			taskRepo.AssertNotCalled(t, "Update", mock.Anything)
+		})
+	}
+}
+
+func TestTaskService_ExecuteBatchEnforcesTransitions(t *testing.T) {
+	done := entities.TaskStatus("DONE")
+	inReview := entities.TaskStatus("IN_REVIEW")
+	ops := []entities.TaskBatchOperation{
+		{Op: entities.TaskBatchOpUpdate, ID: "task-1", Patch: &entities.TaskPatch{Status: &done}},
+		{Op: entities.TaskBatchOpUpdate, ID: "task-2", Patch: &entities.TaskPatch{Status: &inReview}},
+	}
+
+	taskRepo := new(MockTaskRepository)
+	taskRepo.On("FindByProjectID", "project-1").Return([]entities.Task{
+		{ID: "task-1", Status: "TODO"},
+		{ID: "task-2", Status: "IN_PROGRESS"},
+	}, nil)
+	taskRepo.On("InsertMany", mock.Anything, mock.Anything).Return([]error{}, nil)
+	taskRepo.On("UpdateMany", mock.Anything, "project-1", mock.MatchedBy(func(patches []entities.TaskPatch) bool {
+		return len(patches) == 1 && patches[0].ID == "task-2"
+	})).Return([]error{nil}, nil)
+	taskRepo.On("DeleteMany", mock.Anything, "project-1", mock.Anything).Return([]error{}, nil)
+
+	service := domain.NewTaskService(testRepository(taskRepo, projectWithWorkflow(reviewWorkflow())), testMaxDepth)
+	results, err := service.ExecuteBatch(context.Background(), "project-1", ops, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TaskBatchStatusFailed, results[0].Status)
+	assert.Equal(t, "status transition not allowed: TODO to DONE", results[0].Error)
+	assert.Equal(t, entities.TaskBatchStatusOK, results[1].Status)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestProjectService_ValidatesWorkflow(t *testing.T) {
+	tests := []struct {
+		name          string
+		workflow      entities.Workflow
+		expectedError string
+	}{
+		{
+			name: "duplicate key",
+			workflow: entities.Workflow{Statuses: []entities.WorkflowStatus{
+				{Key: "TODO", Name: "To Do", Category: entities.StatusCategoryTodo},
+				{Key: "todo", Name: "Again", Category: entities.StatusCategoryTodo},
+			}},
+			expectedError: "workflow.statuses: TODO is listed twice",
+		},
+		{
+			name: "invalid key",
+			workflow: entities.Workflow{Statuses: []entities.WorkflowStatus{
+				{Key: "in review", Name: "In Review", Category: entities.StatusCategoryActive},
+			}},
+			expectedError: `workflow.statuses: key "in review" must be upper case letters, digits and underscores`,
+		},
+		{
+			name: "unknown category",
+			workflow: entities.Workflow{Statuses: []entities.WorkflowStatus{
+				{Key: "BLOCKED", Name: "Blocked", Category: "waiting"},
+			}},
+			expectedError: `workflow.statuses: BLOCKED has category "waiting", must be todo, active or done`,
+		},
+		{
+			name: "transition to unknown status",
+			workflow: entities.Workflow{
+				Statuses:    []entities.WorkflowStatus{{Key: "TODO", Name: "To Do", Category: entities.StatusCategoryTodo}},
+				Transitions: []entities.WorkflowTransition{{From: "TODO", To: "DONE"}},
+			},
+			expectedError: "workflow.transitions: TODO to DONE refers to an unknown status",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectRepo := new(MockProjectRepository)
+			service := domain.NewProjectService(testRepository(tasksByStatus(nil), projectRepo))
+
+			project := entities.Project{Name: "Project", Workflow: tt.workflow}
+			assert.EqualError(t, service.Insert(context.Background(), &project), tt.expectedError)
+			projectRepo.AssertNotCalled(t, "Insert", mock.Anything)
+		})
+	}
+}
+
+func TestProjectService_UpdateKeepsStatusesInUse(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("Update", mock.Anything).Return(nil).Maybe()
+	service := domain.NewProjectService(testRepository(tasksByStatus(map[entities.TaskStatus]int{"IN_REVIEW": 2, "TODO": 1}), projectRepo))
+
+	project := entities.Project{ID: "project-1", Name: "Project", Workflow: entities.DefaultWorkflow()}
+	err := service.Update(context.Background(), &project)
+
+	assert.EqualError(t, err, "workflow.statuses: IN_REVIEW is still used by 2 tasks, move them to another status first")
+	projectRepo.AssertNotCalled(t, "Update", mock.Anything)
+
+	project.Workflow = reviewWorkflow()
+	assert.NoError(t, service.Update(context.Background(), &project))
+}
+
+func TestProjectService_InsertDefaultsWorkflow(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	projectRepo.On("Insert", mock.Anything).Return(nil)
+	service := domain.NewProjectService(testRepository(tasksByStatus(nil), projectRepo))
+
+	project := entities.Project{Name: "Project"}
+	require.NoError(t, service.Insert(context.Background(), &project))
+	assert.Equal(t, entities.DefaultWorkflow(), project.Workflow)
+}
@@ -1,41 +1,203 @@
 package service
 
 import (
+	"boilerplate/internal/config"
 	"boilerplate/internal/entities"
+	"boilerplate/internal/notify"
 	"boilerplate/internal/service/domain"
 	"boilerplate/internal/storage"
+	"context"
+	"io"
+	"time"
 )
 
 // TaskService defines the interface for task-related operations
 type TaskService interface {
-	Insert(task *entities.Task) error
-	Update(task *entities.Task) error
-	Delete(id string) error
-	FindByID(id string) (entities.Task, error)
-	FindAll() ([]entities.Task, error)
-	FindByProjectID(projectID string) ([]entities.Task, error)
+	Insert(ctx context.Context, task *entities.Task) error
+	// Update returns domain.ErrTransitionNotAllowed for status changes the project's workflow forbids
+	// and domain.ErrOpenBlockers for moves to a done status while blocking tasks are open
+	Update(ctx context.Context, task *entities.Task) error
+	// Delete removes the task together with its subtasks, their dependency links, comments, time entries, notifications and attachments
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Task, error)
+	FindAll(ctx context.Context) ([]entities.Task, error)
+	// FindByAssignee lists the tasks of all projects assigned to a user
+	FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error)
+	// FindByProjectID lists the tasks of a project that match filter
+	FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error)
+	// Children lists the direct subtasks of a task
+	Children(ctx context.Context, id string) ([]entities.Task, error)
+	// Subtree returns a task with its subtasks at every level
+	Subtree(ctx context.Context, id string) (entities.TaskTree, error)
+	// ExecuteBatch applies a list of create/update/delete operations to the tasks of a project.
+	// In atomic mode either all operations are committed or none are.
+	ExecuteBatch(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error)
+	// MaterializeRecurrences creates the occurrences of recurring tasks starting up to horizon
+	// and the next occurrence of completed ones; it returns the number of created tasks
+	MaterializeRecurrences(ctx context.Context, horizon time.Time) (int, error)
 }
 
 // ProjectService defines the interface for project-related operations
 type ProjectService interface {
-	Insert(project *entities.Project) error
-	Update(project *entities.Project) error
-	Delete(id string) error
-	FindByID(id string) (entities.Project, error)
-	FindAll() ([]entities.Project, error)
-	FindAllPaginated(limit, offset int) ([]entities.Project, int64, error)
+	Insert(ctx context.Context, project *entities.Project) error
+	Update(ctx context.Context, project *entities.Project) error
+	// Delete removes the project together with its members, labels, custom fields, tasks, their comments, time entries, notifications, attachments and dependency links
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Project, error)
+	FindAll(ctx context.Context) ([]entities.Project, error)
+	FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error)
+}
+
+// LabelService defines the interface for label-related operations
+type LabelService interface {
+	// Insert returns domain.ErrProjectNotFound if the project does not exist
+	Insert(ctx context.Context, label *entities.Label) error
+	Update(ctx context.Context, label *entities.Label) error
+	// Delete removes the label from all tasks of its project
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Label, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error)
+}
+
+// CustomFieldService defines the interface for custom field definitions
+type CustomFieldService interface {
+	// Insert returns domain.ErrProjectNotFound if the project does not exist
+	Insert(ctx context.Context, field *entities.CustomField) error
+	Update(ctx context.Context, field *entities.CustomField) error
+	// Delete removes the field's values from all tasks of its project
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.CustomField, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error)
+}
+
+// MemberService defines the interface for project membership
+type MemberService interface {
+	// Add makes a user a member of a project, or refreshes their name and email.
+	// It returns domain.ErrProjectNotFound if the project does not exist.
+	Add(ctx context.Context, member *entities.ProjectMember) error
+	// Remove takes a user out of a project and off its tasks
+	Remove(ctx context.Context, projectID, userID string) error
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error)
+}
+
+// DependencyService defines the interface for "blocks" links between tasks
+type DependencyService interface {
+	// Add links two tasks, possibly of different projects. It returns
+	// domain.ErrDependencyCycle if the blocker already waits for the blocked task.
+	Add(ctx context.Context, blockerID, blockedID string) (entities.TaskDependency, error)
+	Remove(ctx context.Context, blockerID, blockedID string) error
+	// FindByTaskID lists the tasks blocking a task and the tasks it blocks
+	FindByTaskID(ctx context.Context, taskID string) (entities.TaskDependencies, error)
+	// Graph returns the dependency DAG of a project with its topological order and critical path
+	Graph(ctx context.Context, projectID string) (entities.TaskGraph, error)
+}
+
+// CommentService defines the interface for comments on tasks
+type CommentService interface {
+	// Create adds a comment or a reply to a top-level comment. It returns
+	// domain.ErrTaskNotFound if the task does not exist.
+	Create(ctx context.Context, comment *entities.Comment) error
+	// Update replaces the body of a comment and keeps the previous one in its
+	// edit history. It returns domain.ErrNotCommentAuthor for anyone but the author.
+	Update(ctx context.Context, comment *entities.Comment, editor entities.UserRef) error
+	// Delete removes a comment with its replies. It returns
+	// domain.ErrNotCommentAuthor for anyone but the author.
+	Delete(ctx context.Context, id string, actor entities.UserRef) error
+	FindByID(ctx context.Context, id string) (entities.Comment, error)
+	// FindByTaskID returns a page of the task's top-level comments with their
+	// replies, and the total number of top-level comments
+	FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.CommentThread, int64, error)
+}
+
+// AttachmentService defines the interface for files attached to tasks
+type AttachmentService interface {
+	// Upload stores the content of a new attachment. It returns
+	// domain.ErrTaskNotFound if the task does not exist, and domain.ErrFileTooLarge
+	// or domain.ErrQuotaExceeded if the content does not fit.
+	Upload(ctx context.Context, attachment *entities.Attachment, content io.Reader, checksum string) error
+	// Open returns an attachment with its content, which the caller must close
+	Open(ctx context.Context, id string) (entities.Attachment, io.ReadSeekCloser, error)
+	// Delete removes an attachment and its content
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Attachment, error)
+	// FindByTaskID lists the attachments of a task, oldest first
+	FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error)
+}
+
+// NotificationService defines the interface for due date reminders and the inboxes of the users
+type NotificationService interface {
+	// FindByUserID returns a page of the inbox of a user, newest first, and the total number of matching notifications
+	FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead sets or clears the read state of a notification. It returns
+	// domain.ErrNotificationNotFound if the user has no such notification.
+	MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, error)
+	// MarkAllRead marks the inbox of a user as read and returns the number of notifications that were unread
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	// Preferences returns the reminder settings of a user, or the defaults
+	Preferences(ctx context.Context, userID string) (entities.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, preferences *entities.NotificationPreferences) error
+	// SendReminders creates the due soon and overdue reminders due at now, each once; it returns the number of new notifications
+	SendReminders(ctx context.Context, now time.Time) (int, error)
+	// DeliverPending sends the pending email and webhook deliveries; it returns the number of sent deliveries
+	DeliverPending(ctx context.Context) (int, error)
+}
+
+// TimeEntryService defines the interface for the time users spend on tasks
+type TimeEntryService interface {
+	// StartTimer starts a timer for a user on a task. It returns domain.ErrTaskNotFound
+	// if the task does not exist and domain.ErrTimerRunning if the user has a timer running.
+	StartTimer(ctx context.Context, taskID string, user entities.UserRef, note string) (entities.TimeEntry, error)
+	// StopTimer stops the timer of a user on a task. It returns
+	// domain.ErrNoTimerRunning if the user has no timer running on the task.
+	StopTimer(ctx context.Context, taskID, userID string) (entities.TimeEntry, error)
+	// RunningTimer returns the running timer of a user, or domain.ErrNoTimerRunning
+	RunningTimer(ctx context.Context, userID string) (entities.TimeEntry, error)
+	// Create adds a time entry with a duration. It returns domain.ErrTaskNotFound
+	// if the task does not exist.
+	Create(ctx context.Context, entry *entities.TimeEntry) error
+	// Update replaces the start, duration and note of a stopped time entry. It returns
+	// domain.ErrNotTimeEntryOwner for anyone but its user and domain.ErrTimerRunning for running timers.
+	Update(ctx context.Context, entry *entities.TimeEntry, editor entities.UserRef) error
+	// Delete removes a time entry. It returns domain.ErrNotTimeEntryOwner for anyone but its user.
+	Delete(ctx context.Context, id string, actor entities.UserRef) error
+	FindByID(ctx context.Context, id string) (entities.TimeEntry, error)
+	// FindByTaskID lists the time entries of a task in order of their start
+	FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error)
+	// Report sums up the time spent on a project per user, task and day between two
+	// days, YYYY-MM-DD and inclusive, in an IANA time zone. It returns
+	// domain.ErrProjectNotFound if the project does not exist.
+	Report(ctx context.Context, projectID, from, to, timezone string) (entities.TimeReport, error)
 }
 
 // Service combines all services
 type Service struct {
-	Task    TaskService
-	Project ProjectService
+	Task         TaskService
+	Project      ProjectService
+	Label        LabelService
+	CustomField  CustomFieldService
+	Dependency   DependencyService
+	Member       MemberService
+	Comment      CommentService
+	Attachment   AttachmentService
+	Notification NotificationService
+	TimeEntry    TimeEntryService
 }
 
-// NewService creates a new service instance with the given repositories
-func NewService(repo *storage.Repository) *Service {
+// NewService creates a new service instance with the given repositories.
+// Every service call is wrapped in a tracing span.
+// Reminders are delivered through the channels enabled in notifications.
+func NewService(repo *storage.Repository, tasks config.TasksConfig, attachments config.AttachmentsConfig, notifications config.NotificationsConfig) *Service {
 	return &Service{
-		Task:    domain.NewTaskService(repo.TaskRepository),
-		Project: domain.NewProjectService(repo.ProjectRepository),
+		Task:         &tracedTaskService{next: domain.NewTaskService(*repo, tasks.MaxDepth)},
+		Project:      &tracedProjectService{next: domain.NewProjectService(*repo)},
+		Label:        &tracedLabelService{next: domain.NewLabelService(repo.LabelRepository, repo.TaskRepository, repo.ProjectRepository)},
+		CustomField:  &tracedCustomFieldService{next: domain.NewCustomFieldService(repo.CustomFieldRepository, repo.TaskRepository, repo.ProjectRepository)},
+		Dependency:   &tracedDependencyService{next: domain.NewDependencyService(repo.DependencyRepository, repo.TaskRepository, repo.ProjectRepository)},
+		Member:       &tracedMemberService{next: domain.NewMemberService(repo.MemberRepository, repo.TaskRepository, repo.ProjectRepository)},
+		Comment:      &tracedCommentService{next: domain.NewCommentService(repo.CommentRepository, repo.TaskRepository, repo.MemberRepository)},
+		Attachment:   &tracedAttachmentService{next: domain.NewAttachmentService(repo.AttachmentRepository, repo.TaskRepository, repo.BlobStore, attachments.MaxFileSize, attachments.ProjectQuota)},
+		Notification: &tracedNotificationService{next: domain.NewNotificationService(repo.NotificationRepository, repo.NotificationPreferenceRepository, repo.TaskRepository, repo.ProjectRepository, notify.New(notifications), notifications.MaxAttempts)},
+		TimeEntry:    &tracedTimeEntryService{next: domain.NewTimeEntryService(repo.TimeEntryRepository, repo.TaskRepository, repo.ProjectRepository)},
 	}
 }
@@ -0,0 +1,578 @@
+package service
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("boilerplate/internal/service")
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedTaskService wraps a TaskService and creates a span for every call
+type tracedTaskService struct {
+	next TaskService
+}
+
+func (s *tracedTaskService) Insert(ctx context.Context, task *entities.Task) error {
+	ctx, span := startSpan(ctx, "TaskService.Insert", attribute.String("project.id", task.ProjectID))
+	err := s.next.Insert(ctx, task)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedTaskService) Update(ctx context.Context, task *entities.Task) error {
+	ctx, span := startSpan(ctx, "TaskService.Update", attribute.String("task.id", task.ID))
+	err := s.next.Update(ctx, task)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedTaskService) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "TaskService.Delete", attribute.String("task.id", id))
+	err := s.next.Delete(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedTaskService) FindByID(ctx context.Context, id string) (entities.Task, error) {
+	ctx, span := startSpan(ctx, "TaskService.FindByID", attribute.String("task.id", id))
+	task, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return task, err
+}
+
+func (s *tracedTaskService) FindAll(ctx context.Context) ([]entities.Task, error) {
+	ctx, span := startSpan(ctx, "TaskService.FindAll")
+	tasks, err := s.next.FindAll(ctx)
+	endSpan(span, err)
+	return tasks, err
+}
+
+func (s *tracedTaskService) FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error) {
+	ctx, span := startSpan(ctx, "TaskService.FindByAssignee", attribute.String("user.id", userID))
+	tasks, err := s.next.FindByAssignee(ctx, userID)
+	endSpan(span, err)
+	return tasks, err
+}
+
+func (s *tracedTaskService) FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
+	ctx, span := startSpan(ctx, "TaskService.FindByProjectID", attribute.String("project.id", projectID))
+	tasks, err := s.next.FindByProjectID(ctx, projectID, filter)
+	endSpan(span, err)
+	return tasks, err
+}
+
+func (s *tracedTaskService) Children(ctx context.Context, id string) ([]entities.Task, error) {
+	ctx, span := startSpan(ctx, "TaskService.Children", attribute.String("task.id", id))
+	tasks, err := s.next.Children(ctx, id)
+	endSpan(span, err)
+	return tasks, err
+}
+
+func (s *tracedTaskService) Subtree(ctx context.Context, id string) (entities.TaskTree, error) {
+	ctx, span := startSpan(ctx, "TaskService.Subtree", attribute.String("task.id", id))
+	tree, err := s.next.Subtree(ctx, id)
+	endSpan(span, err)
+	return tree, err
+}
+
+func (s *tracedTaskService) ExecuteBatch(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error) {
+	ctx, span := startSpan(ctx, "TaskService.ExecuteBatch",
+		attribute.String("project.id", projectID),
+		attribute.Int("batch.size", len(ops)),
+		attribute.Bool("batch.atomic", atomic),
+	)
+	results, err := s.next.ExecuteBatch(ctx, projectID, ops, atomic)
+	endSpan(span, err)
+	return results, err
+}
+
+func (s *tracedTaskService) MaterializeRecurrences(ctx context.Context, horizon time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "TaskService.MaterializeRecurrences",
+		attribute.String("recurrence.horizon", horizon.Format(time.RFC3339)),
+	)
+	created, err := s.next.MaterializeRecurrences(ctx, horizon)
+	span.SetAttributes(attribute.Int("recurrence.created", created))
+	endSpan(span, err)
+	return created, err
+}
+
+// tracedProjectService wraps a ProjectService and creates a span for every call
+type tracedProjectService struct {
+	next ProjectService
+}
+
+func (s *tracedProjectService) Insert(ctx context.Context, project *entities.Project) error {
+	ctx, span := startSpan(ctx, "ProjectService.Insert")
+	err := s.next.Insert(ctx, project)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedProjectService) Update(ctx context.Context, project *entities.Project) error {
+	ctx, span := startSpan(ctx, "ProjectService.Update", attribute.String("project.id", project.ID))
+	err := s.next.Update(ctx, project)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedProjectService) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "ProjectService.Delete", attribute.String("project.id", id))
+	err := s.next.Delete(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedProjectService) FindByID(ctx context.Context, id string) (entities.Project, error) {
+	ctx, span := startSpan(ctx, "ProjectService.FindByID", attribute.String("project.id", id))
+	project, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return project, err
+}
+
+func (s *tracedProjectService) FindAll(ctx context.Context) ([]entities.Project, error) {
+	ctx, span := startSpan(ctx, "ProjectService.FindAll")
+	projects, err := s.next.FindAll(ctx)
+	endSpan(span, err)
+	return projects, err
+}
+
+func (s *tracedProjectService) FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error) {
+	ctx, span := startSpan(ctx, "ProjectService.FindAllPaginated",
+		attribute.Int("page.limit", limit),
+		attribute.Int("page.offset", offset),
+	)
+	projects, total, err := s.next.FindAllPaginated(ctx, limit, offset)
+	endSpan(span, err)
+	return projects, total, err
+}
+
+// tracedLabelService wraps a LabelService and creates a span for every call
+type tracedLabelService struct {
+	next LabelService
+}
+
+func (s *tracedLabelService) Insert(ctx context.Context, label *entities.Label) error {
+	ctx, span := startSpan(ctx, "LabelService.Insert", attribute.String("project.id", label.ProjectID))
+	err := s.next.Insert(ctx, label)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedLabelService) Update(ctx context.Context, label *entities.Label) error {
+	ctx, span := startSpan(ctx, "LabelService.Update", attribute.String("label.id", label.ID))
+	err := s.next.Update(ctx, label)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedLabelService) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "LabelService.Delete", attribute.String("label.id", id))
+	err := s.next.Delete(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedLabelService) FindByID(ctx context.Context, id string) (entities.Label, error) {
+	ctx, span := startSpan(ctx, "LabelService.FindByID", attribute.String("label.id", id))
+	label, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return label, err
+}
+
+func (s *tracedLabelService) FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error) {
+	ctx, span := startSpan(ctx, "LabelService.FindByProjectID", attribute.String("project.id", projectID))
+	labels, err := s.next.FindByProjectID(ctx, projectID)
+	endSpan(span, err)
+	return labels, err
+}
+
+// tracedCustomFieldService wraps a CustomFieldService and creates a span for every call
+type tracedCustomFieldService struct {
+	next CustomFieldService
+}
+
+func (s *tracedCustomFieldService) Insert(ctx context.Context, field *entities.CustomField) error {
+	ctx, span := startSpan(ctx, "CustomFieldService.Insert", attribute.String("project.id", field.ProjectID))
+	err := s.next.Insert(ctx, field)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCustomFieldService) Update(ctx context.Context, field *entities.CustomField) error {
+	ctx, span := startSpan(ctx, "CustomFieldService.Update", attribute.String("custom_field.id", field.ID))
+	err := s.next.Update(ctx, field)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCustomFieldService) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "CustomFieldService.Delete", attribute.String("custom_field.id", id))
+	err := s.next.Delete(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCustomFieldService) FindByID(ctx context.Context, id string) (entities.CustomField, error) {
+	ctx, span := startSpan(ctx, "CustomFieldService.FindByID", attribute.String("custom_field.id", id))
+	field, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return field, err
+}
+
+func (s *tracedCustomFieldService) FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error) {
+	ctx, span := startSpan(ctx, "CustomFieldService.FindByProjectID", attribute.String("project.id", projectID))
+	fields, err := s.next.FindByProjectID(ctx, projectID)
+	endSpan(span, err)
+	return fields, err
+}
+
+// tracedDependencyService wraps a DependencyService and creates a span for every call
+type tracedDependencyService struct {
+	next DependencyService
+}
+
+func (s *tracedDependencyService) Add(ctx context.Context, blockerID, blockedID string) (entities.TaskDependency, error) {
+	ctx, span := startSpan(ctx, "DependencyService.Add",
+		attribute.String("dependency.blocker_id", blockerID),
+		attribute.String("dependency.blocked_id", blockedID),
+	)
+	dependency, err := s.next.Add(ctx, blockerID, blockedID)
+	endSpan(span, err)
+	return dependency, err
+}
+
+func (s *tracedDependencyService) Remove(ctx context.Context, blockerID, blockedID string) error {
+	ctx, span := startSpan(ctx, "DependencyService.Remove",
+		attribute.String("dependency.blocker_id", blockerID),
+		attribute.String("dependency.blocked_id", blockedID),
+	)
+	err := s.next.Remove(ctx, blockerID, blockedID)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedDependencyService) FindByTaskID(ctx context.Context, taskID string) (entities.TaskDependencies, error) {
+	ctx, span := startSpan(ctx, "DependencyService.FindByTaskID", attribute.String("task.id", taskID))
+	dependencies, err := s.next.FindByTaskID(ctx, taskID)
+	endSpan(span, err)
+	return dependencies, err
+}
+
+func (s *tracedDependencyService) Graph(ctx context.Context, projectID string) (entities.TaskGraph, error) {
+	ctx, span := startSpan(ctx, "DependencyService.Graph", attribute.String("project.id", projectID))
+	graph, err := s.next.Graph(ctx, projectID)
+	endSpan(span, err)
+	return graph, err
+}
+
+// tracedMemberService wraps a MemberService and creates a span for every call
+type tracedMemberService struct {
+	next MemberService
+}
+
+func (s *tracedMemberService) Add(ctx context.Context, member *entities.ProjectMember) error {
+	ctx, span := startSpan(ctx, "MemberService.Add",
+		attribute.String("project.id", member.ProjectID),
+		attribute.String("user.id", member.UserID),
+	)
+	err := s.next.Add(ctx, member)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedMemberService) Remove(ctx context.Context, projectID, userID string) error {
+	ctx, span := startSpan(ctx, "MemberService.Remove",
+		attribute.String("project.id", projectID),
+		attribute.String("user.id", userID),
+	)
+	err := s.next.Remove(ctx, projectID, userID)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedMemberService) FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error) {
+	ctx, span := startSpan(ctx, "MemberService.FindByProjectID", attribute.String("project.id", projectID))
+	members, err := s.next.FindByProjectID(ctx, projectID)
+	endSpan(span, err)
+	return members, err
+}
+
+// tracedCommentService wraps a CommentService and creates a span for every call
+type tracedCommentService struct {
+	next CommentService
+}
+
+func (s *tracedCommentService) Create(ctx context.Context, comment *entities.Comment) error {
+	ctx, span := startSpan(ctx, "CommentService.Create",
+		attribute.String("task.id", comment.TaskID),
+		attribute.String("comment.parent_id", comment.ParentID),
+	)
+	err := s.next.Create(ctx, comment)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCommentService) Update(ctx context.Context, comment *entities.Comment, editor entities.UserRef) error {
+	ctx, span := startSpan(ctx, "CommentService.Update",
+		attribute.String("comment.id", comment.ID),
+		attribute.String("user.id", editor.ID),
+	)
+	err := s.next.Update(ctx, comment, editor)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCommentService) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	ctx, span := startSpan(ctx, "CommentService.Delete",
+		attribute.String("comment.id", id),
+		attribute.String("user.id", actor.ID),
+	)
+	err := s.next.Delete(ctx, id, actor)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedCommentService) FindByID(ctx context.Context, id string) (entities.Comment, error) {
+	ctx, span := startSpan(ctx, "CommentService.FindByID", attribute.String("comment.id", id))
+	comment, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return comment, err
+}
+
+func (s *tracedCommentService) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.CommentThread, int64, error) {
+	ctx, span := startSpan(ctx, "CommentService.FindByTaskID",
+		attribute.String("task.id", taskID),
+		attribute.Int("page.limit", limit),
+		attribute.Int("page.offset", offset),
+	)
+	threads, total, err := s.next.FindByTaskID(ctx, taskID, limit, offset)
+	endSpan(span, err)
+	return threads, total, err
+}
+
+// tracedAttachmentService wraps an AttachmentService and creates a span for every call
+type tracedAttachmentService struct {
+	next AttachmentService
+}
+
+func (s *tracedAttachmentService) Upload(ctx context.Context, attachment *entities.Attachment, content io.Reader, checksum string) error {
+	ctx, span := startSpan(ctx, "AttachmentService.Upload", attribute.String("task.id", attachment.TaskID))
+	err := s.next.Upload(ctx, attachment, content, checksum)
+	span.SetAttributes(
+		attribute.String("attachment.content_type", attachment.ContentType),
+		attribute.Int64("attachment.size", attachment.Size),
+	)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedAttachmentService) Open(ctx context.Context, id string) (entities.Attachment, io.ReadSeekCloser, error) {
+	ctx, span := startSpan(ctx, "AttachmentService.Open", attribute.String("attachment.id", id))
+	attachment, content, err := s.next.Open(ctx, id)
+	endSpan(span, err)
+	return attachment, content, err
+}
+
+func (s *tracedAttachmentService) Delete(ctx context.Context, id string) error {
+	ctx, span := startSpan(ctx, "AttachmentService.Delete", attribute.String("attachment.id", id))
+	err := s.next.Delete(ctx, id)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedAttachmentService) FindByID(ctx context.Context, id string) (entities.Attachment, error) {
+	ctx, span := startSpan(ctx, "AttachmentService.FindByID", attribute.String("attachment.id", id))
+	attachment, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return attachment, err
+}
+
+func (s *tracedAttachmentService) FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error) {
+	ctx, span := startSpan(ctx, "AttachmentService.FindByTaskID", attribute.String("task.id", taskID))
+	attachments, err := s.next.FindByTaskID(ctx, taskID)
+	endSpan(span, err)
+	return attachments, err
+}
+
+// tracedNotificationService wraps a NotificationService and creates a span for every call
+type tracedNotificationService struct {
+	next NotificationService
+}
+
+func (s *tracedNotificationService) FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error) {
+	ctx, span := startSpan(ctx, "NotificationService.FindByUserID",
+		attribute.String("user.id", userID),
+		attribute.Bool("notification.unread_only", unreadOnly),
+		attribute.Int("page.limit", limit),
+		attribute.Int("page.offset", offset),
+	)
+	notifications, total, err := s.next.FindByUserID(ctx, userID, unreadOnly, limit, offset)
+	endSpan(span, err)
+	return notifications, total, err
+}
+
+func (s *tracedNotificationService) CountUnread(ctx context.Context, userID string) (int64, error) {
+	ctx, span := startSpan(ctx, "NotificationService.CountUnread", attribute.String("user.id", userID))
+	count, err := s.next.CountUnread(ctx, userID)
+	endSpan(span, err)
+	return count, err
+}
+
+func (s *tracedNotificationService) MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, error) {
+	ctx, span := startSpan(ctx, "NotificationService.MarkRead",
+		attribute.String("user.id", userID),
+		attribute.String("notification.id", id),
+		attribute.Bool("notification.read", read),
+	)
+	notification, err := s.next.MarkRead(ctx, userID, id, read)
+	endSpan(span, err)
+	return notification, err
+}
+
+func (s *tracedNotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	ctx, span := startSpan(ctx, "NotificationService.MarkAllRead", attribute.String("user.id", userID))
+	count, err := s.next.MarkAllRead(ctx, userID)
+	span.SetAttributes(attribute.Int64("notification.count", count))
+	endSpan(span, err)
+	return count, err
+}
+
+func (s *tracedNotificationService) Preferences(ctx context.Context, userID string) (entities.NotificationPreferences, error) {
+	ctx, span := startSpan(ctx, "NotificationService.Preferences", attribute.String("user.id", userID))
+	preferences, err := s.next.Preferences(ctx, userID)
+	endSpan(span, err)
+	return preferences, err
+}
+
+func (s *tracedNotificationService) UpdatePreferences(ctx context.Context, preferences *entities.NotificationPreferences) error {
+	ctx, span := startSpan(ctx, "NotificationService.UpdatePreferences", attribute.String("user.id", preferences.UserID))
+	err := s.next.UpdatePreferences(ctx, preferences)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedNotificationService) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := startSpan(ctx, "NotificationService.SendReminders")
+	created, err := s.next.SendReminders(ctx, now)
+	span.SetAttributes(attribute.Int("notification.created", created))
+	endSpan(span, err)
+	return created, err
+}
+
+func (s *tracedNotificationService) DeliverPending(ctx context.Context) (int, error) {
+	ctx, span := startSpan(ctx, "NotificationService.DeliverPending")
+	sent, err := s.next.DeliverPending(ctx)
+	span.SetAttributes(attribute.Int("notification.sent", sent))
+	endSpan(span, err)
+	return sent, err
+}
+
+// tracedTimeEntryService wraps a TimeEntryService and creates a span for every call
+type tracedTimeEntryService struct {
+	next TimeEntryService
+}
+
+func (s *tracedTimeEntryService) StartTimer(ctx context.Context, taskID string, user entities.UserRef, note string) (entities.TimeEntry, error) {
+	ctx, span := startSpan(ctx, "TimeEntryService.StartTimer",
+		attribute.String("task.id", taskID),
+		attribute.String("user.id", user.ID),
+	)
+	entry, err := s.next.StartTimer(ctx, taskID, user, note)
+	endSpan(span, err)
+	return entry, err
+}
+
+func (s *tracedTimeEntryService) StopTimer(ctx context.Context, taskID, userID string) (entities.TimeEntry, error) {
+	ctx, span := startSpan(ctx, "TimeEntryService.StopTimer",
+		attribute.String("task.id", taskID),
+		attribute.String("user.id", userID),
+	)
+	entry, err := s.next.StopTimer(ctx, taskID, userID)
+	span.SetAttributes(attribute.Int64("time_entry.duration", entry.Duration))
+	endSpan(span, err)
+	return entry, err
+}
+
+func (s *tracedTimeEntryService) RunningTimer(ctx context.Context, userID string) (entities.TimeEntry, error) {
+	ctx, span := startSpan(ctx, "TimeEntryService.RunningTimer", attribute.String("user.id", userID))
+	entry, err := s.next.RunningTimer(ctx, userID)
+	endSpan(span, err)
+	return entry, err
+}
+
+func (s *tracedTimeEntryService) Create(ctx context.Context, entry *entities.TimeEntry) error {
+	ctx, span := startSpan(ctx, "TimeEntryService.Create",
+		attribute.String("task.id", entry.TaskID),
+		attribute.String("user.id", entry.User.ID),
+		attribute.Int64("time_entry.duration", entry.Duration),
+	)
+	err := s.next.Create(ctx, entry)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedTimeEntryService) Update(ctx context.Context, entry *entities.TimeEntry, editor entities.UserRef) error {
+	ctx, span := startSpan(ctx, "TimeEntryService.Update",
+		attribute.String("time_entry.id", entry.ID),
+		attribute.String("user.id", editor.ID),
+	)
+	err := s.next.Update(ctx, entry, editor)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedTimeEntryService) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	ctx, span := startSpan(ctx, "TimeEntryService.Delete",
+		attribute.String("time_entry.id", id),
+		attribute.String("user.id", actor.ID),
+	)
+	err := s.next.Delete(ctx, id, actor)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedTimeEntryService) FindByID(ctx context.Context, id string) (entities.TimeEntry, error) {
+	ctx, span := startSpan(ctx, "TimeEntryService.FindByID", attribute.String("time_entry.id", id))
+	entry, err := s.next.FindByID(ctx, id)
+	endSpan(span, err)
+	return entry, err
+}
+
+func (s *tracedTimeEntryService) FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error) {
+	ctx, span := startSpan(ctx, "TimeEntryService.FindByTaskID", attribute.String("task.id", taskID))
+	entries, err := s.next.FindByTaskID(ctx, taskID)
+	endSpan(span, err)
+	return entries, err
+}
+
+func (s *tracedTimeEntryService) Report(ctx context.Context, projectID, from, to, timezone string) (entities.TimeReport, error) {
+	ctx, span := startSpan(ctx, "TimeEntryService.Report",
+		attribute.String("project.id", projectID),
+		attribute.String("report.from", from),
+		attribute.String("report.to", to),
+		attribute.String("report.timezone", timezone),
+	)
+	report, err := s.next.Report(ctx, projectID, from, to, timezone)
+	span.SetAttributes(attribute.Int("report.entries", report.Entries))
+	endSpan(span, err)
+	return report, err
+}
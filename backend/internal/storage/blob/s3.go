@@ -0,0 +1,103 @@
+package blob
+
+import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/storage"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// uploadPartSize is the part size of multipart uploads of unknown length. The
+// client buffers one part in memory, so it bounds the memory per upload.
+const uploadPartSize = 5 << 20
+
+// S3Store keeps blobs as objects in a bucket of an S3-compatible store such as
+// AWS S3 or MinIO
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store connects to the object store and, if configured, creates the bucket
+func NewS3Store(ctx context.Context, cfg config.S3StoreConfig) (*S3Store, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	store := &S3Store{client: client, bucket: cfg.Bucket}
+	if cfg.CreateBucket {
+		exists, err := client.BucketExists(ctx, cfg.Bucket)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check bucket %s: %w", cfg.Bucket, err)
+		}
+		if !exists {
+			if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+				return nil, fmt.Errorf("failed to create bucket %s: %w", cfg.Bucket, err)
+			}
+		}
+	}
+	return store, nil
+}
+
+// Put uploads the blob; a size of -1 streams it in parts of uploadPartSize
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	opts := minio.PutObjectOptions{ContentType: contentType}
+	if size < 0 {
+		opts.PartSize = uploadPartSize
+	}
+	if _, err := s.client.PutObject(ctx, s.bucket, key, r, size, opts); err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	return nil
+}
+
+// Open returns the object of the blob, or storage.ErrNotFound if it does not
+// exist. Seeking issues ranged requests, so only the bytes read are transferred.
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s.mapError(err)
+	}
+	// GetObject is lazy; Stat surfaces a missing object before the first read
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		return nil, s.mapError(err)
+	}
+	return object, nil
+}
+
+// Delete removes the blob; deleting a missing blob is no error
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return s.mapError(err)
+	}
+	return nil
+}
+
+// Check verifies that the bucket is reachable
+func (s *S3Store) Check(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
+
+func (s *S3Store) mapError(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return storage.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,29 @@
+// Package blob stores the content of task attachments outside of MongoDB,
+// either on the local filesystem or in an S3-compatible object store.
+package blob
+
+import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/storage"
+	"context"
+	"fmt"
+)
+
+// Store is a storage.BlobStore that can report whether it is reachable
+type Store interface {
+	storage.BlobStore
+	// Check verifies that blobs can be stored, for the readiness probe
+	Check(ctx context.Context) error
+}
+
+// New creates the blob store selected in the configuration
+func New(ctx context.Context, cfg config.AttachmentsConfig) (Store, error) {
+	switch cfg.Store {
+	case "local":
+		return NewLocalStore(cfg.Local.Path)
+	case "s3":
+		return NewS3Store(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", cfg.Store)
+	}
+}
@@ -0,0 +1,123 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"boilerplate/internal/config"
+	"boilerplate/internal/storage"
+	"boilerplate/internal/storage/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// testStore checks the behaviour every blob store must share
+func testStore(t *testing.T, store blob.Store) {
+	ctx := context.Background()
+	require.NoError(t, store.Check(ctx))
+
+	content := strings.Repeat("0123456789", 1000)
+
+	t.Run("Put and Open with unknown size", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "project/one", strings.NewReader(content), -1, "text/plain"))
+
+		r, err := store.Open(ctx, "project/one")
+		require.NoError(t, err)
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+
+	t.Run("Open seeks for ranges", func(t *testing.T) {
+		r, err := store.Open(ctx, "project/one")
+		require.NoError(t, err)
+		defer r.Close()
+
+		size, err := r.Seek(0, io.SeekEnd)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(content)), size)
+
+		_, err = r.Seek(9995, io.SeekStart)
+		require.NoError(t, err)
+		tail, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "56789", string(tail))
+	})
+
+	t.Run("Put replaces a blob", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, "project/one", bytes.NewReader([]byte("new")), 3, "text/plain"))
+		r, err := store.Open(ctx, "project/one")
+		require.NoError(t, err)
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(data))
+	})
+
+	t.Run("Delete is idempotent", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, "project/one"))
+		require.NoError(t, store.Delete(ctx, "project/one"))
+
+		_, err := store.Open(ctx, "project/one")
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := blob.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	testStore(t, store)
+
+	t.Run("keys cannot escape the root", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Error(t, store.Put(ctx, "../outside", strings.NewReader("x"), 1, "text/plain"))
+		_, err := store.Open(ctx, "/etc/passwd")
+		assert.Error(t, err)
+	})
+}
+
+func TestS3Store_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+	ctx := context.Background()
+
+	container, err := tc.GenericContainer(ctx, tc.GenericContainerRequest{
+		ContainerRequest: tc.ContainerRequest{
+			Image:        "minio/minio:latest",
+			Cmd:          []string{"server", "/data"},
+			Env:          map[string]string{"MINIO_ROOT_USER": "minioadmin", "MINIO_ROOT_PASSWORD": "minioadmin"},
+			ExposedPorts: []string{"9000/tcp"},
+			WaitingFor:   wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err, "failed to start container")
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Errorf("failed to terminate container: %s", err)
+		}
+	})
+
+	endpoint, err := container.PortEndpoint(ctx, "9000/tcp", "")
+	require.NoError(t, err)
+
+	store, err := blob.New(ctx, config.AttachmentsConfig{Store: "s3", S3: config.S3StoreConfig{
+		Endpoint:     endpoint,
+		Bucket:       "attachments",
+		AccessKey:    "minioadmin",
+		SecretKey:    "minioadmin",
+		CreateBucket: true,
+	}})
+	require.NoError(t, err)
+
+	testStore(t, store)
+}
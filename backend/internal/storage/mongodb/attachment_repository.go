@@ -0,0 +1,221 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbAttachment struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID      primitive.ObjectID `bson:"task_id"`
+	ProjectID   primitive.ObjectID `bson:"project_id"`
+	FileName    string             `bson:"file_name"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+	SHA256      string             `bson:"sha256"`
+	StorageKey  string             `bson:"storage_key"`
+	UploadedBy  mongoDbUserRef     `bson:"uploaded_by"`
+	CreatedAt   time.Time          `bson:"created_at"`
+}
+
+type mongoDbAttachmentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAttachmentRepository(client *mongo.Client, database string) *mongoDbAttachmentRepository {
+	collection := client.Database(database).Collection("attachments")
+	return &mongoDbAttachmentRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoDbAttachmentRepository) Insert(ctx context.Context, attachment *entities.Attachment) error {
+	if attachment == nil {
+		return errors.New("attachment cannot be nil")
+	}
+
+	oids, err := toObjectIDs([]string{attachment.TaskID, attachment.ProjectID}, "invalid task or project ID format")
+	if err != nil {
+		return err
+	}
+	mongoAttachment := mongoDbAttachment{
+		ID:          primitive.NewObjectID(),
+		TaskID:      oids[0],
+		ProjectID:   oids[1],
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.SHA256,
+		StorageKey:  attachment.StorageKey,
+		UploadedBy:  mongoDbUserRef{ID: attachment.UploadedBy.ID, Name: attachment.UploadedBy.Name, Email: attachment.UploadedBy.Email},
+		CreatedAt:   time.Now(),
+	}
+
+	if _, err := r.collection.InsertOne(ctx, mongoAttachment); err != nil {
+		return err
+	}
+
+	attachment.ID = mongoAttachment.ID.Hex()
+	attachment.CreatedAt = mongoAttachment.CreatedAt
+	return nil
+}
+
+func (r *mongoDbAttachmentRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid attachment ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("no attachment found with the given ID")
+	}
+
+	return nil
+}
+
+func (r *mongoDbAttachmentRepository) FindByID(ctx context.Context, id string) (entities.Attachment, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Attachment{}, errors.New("invalid attachment ID format")
+	}
+
+	var mongoAttachment mongoDbAttachment
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&mongoAttachment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.Attachment{}, errors.New("attachment not found")
+		}
+		return entities.Attachment{}, err
+	}
+
+	return fromMongoAttachment(mongoAttachment), nil
+}
+
+// FindByTaskID returns the attachments of a task, oldest first
+func (r *mongoDbAttachmentRepository) FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error) {
+	return r.FindByTaskIDs(ctx, []string{taskID})
+}
+
+// FindByTaskIDs returns the attachments of any of the tasks, oldest first
+func (r *mongoDbAttachmentRepository) FindByTaskIDs(ctx context.Context, taskIDs []string) ([]entities.Attachment, error) {
+	if len(taskIDs) == 0 {
+		return []entities.Attachment{}, nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.find(ctx, bson.M{"task_id": bson.M{"$in": oids}})
+}
+
+func (r *mongoDbAttachmentRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.Attachment, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	return r.find(ctx, bson.M{"project_id": projectOid})
+}
+
+// SizeByProjectID sums the sizes of the attachments of a project
+func (r *mongoDbAttachmentRepository) SizeByProjectID(ctx context.Context, projectID string) (int64, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return 0, errors.New("invalid project ID format")
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"project_id": projectOid}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "size": bson.M{"$sum": "$size"}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Size int64 `bson:"size"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Size, nil
+}
+
+// DeleteByTaskIDs removes the attachments of the tasks
+func (r *mongoDbAttachmentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": oids}})
+	return err
+}
+
+// DeleteByProjectID removes the attachments of all tasks of a project
+func (r *mongoDbAttachmentRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func (r *mongoDbAttachmentRepository) find(ctx context.Context, filter bson.M) ([]entities.Attachment, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoAttachments []mongoDbAttachment
+	if err := cursor.All(ctx, &mongoAttachments); err != nil {
+		return nil, err
+	}
+
+	attachments := make([]entities.Attachment, len(mongoAttachments))
+	for i, mongoAttachment := range mongoAttachments {
+		attachments[i] = fromMongoAttachment(mongoAttachment)
+	}
+
+	return attachments, nil
+}
+
+func fromMongoAttachment(attachment mongoDbAttachment) entities.Attachment {
+	return entities.Attachment{
+		ID:          attachment.ID.Hex(),
+		TaskID:      attachment.TaskID.Hex(),
+		ProjectID:   attachment.ProjectID.Hex(),
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.SHA256,
+		StorageKey:  attachment.StorageKey,
+		UploadedBy:  entities.UserRef{ID: attachment.UploadedBy.ID, Name: attachment.UploadedBy.Name, Email: attachment.UploadedBy.Email},
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
@@ -0,0 +1,83 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongoDbAttachmentRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	repo := mongodb.NewAttachmentRepository(client, testDBName)
+	ctx := context.Background()
+
+	projectID := primitive.NewObjectID().Hex()
+	taskID := primitive.NewObjectID().Hex()
+	otherTaskID := primitive.NewObjectID().Hex()
+
+	insert := func(taskID, fileName string, size int64) entities.Attachment {
+		attachment := &entities.Attachment{TaskID: taskID, ProjectID: projectID, FileName: fileName, Size: size,
+			ContentType: "image/png", SHA256: "abc", StorageKey: projectID + "/" + fileName,
+			UploadedBy: entities.UserRef{ID: "jane", Name: "Jane"}}
+		require.NoError(t, repo.Insert(ctx, attachment))
+		return *attachment
+	}
+	first := insert(taskID, "first.png", 100)
+	insert(taskID, "second.png", 200)
+	insert(otherTaskID, "other.png", 50)
+
+	t.Run("FindByID keeps the storage key", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.StorageKey, found.StorageKey)
+		assert.Equal(t, entities.UserRef{ID: "jane", Name: "Jane"}, found.UploadedBy)
+		assert.Equal(t, int64(100), found.Size)
+	})
+
+	t.Run("FindByTaskID lists the oldest first", func(t *testing.T) {
+		attachments, err := repo.FindByTaskID(ctx, taskID)
+		require.NoError(t, err)
+		require.Len(t, attachments, 2)
+		assert.Equal(t, "first.png", attachments[0].FileName)
+		assert.Equal(t, "second.png", attachments[1].FileName)
+	})
+
+	t.Run("SizeByProjectID sums all tasks", func(t *testing.T) {
+		size, err := repo.SizeByProjectID(ctx, projectID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(350), size)
+
+		size, err = repo.SizeByProjectID(ctx, primitive.NewObjectID().Hex())
+		require.NoError(t, err)
+		assert.Zero(t, size)
+	})
+
+	t.Run("Delete by ID, task and project", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, first.ID))
+		_, err := repo.FindByID(ctx, first.ID)
+		assert.Error(t, err)
+
+		require.NoError(t, repo.DeleteByTaskIDs(ctx, []string{otherTaskID}))
+		attachments, err := repo.FindByProjectID(ctx, projectID)
+		require.NoError(t, err)
+		require.Len(t, attachments, 1)
+		assert.Equal(t, "second.png", attachments[0].FileName)
+
+		require.NoError(t, repo.DeleteByProjectID(ctx, projectID))
+		attachments, err = repo.FindByTaskIDs(ctx, []string{taskID, otherTaskID})
+		require.NoError(t, err)
+		assert.Empty(t, attachments)
+	})
+}
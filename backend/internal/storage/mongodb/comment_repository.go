@@ -0,0 +1,317 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbComment struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	// ParentID is unset on top-level comments so that they match {parent_id: null}
+	ParentID  primitive.ObjectID   `bson:"parent_id,omitempty"`
+	Author    mongoDbUserRef       `bson:"author"`
+	Body      string               `bson:"body"`
+	Mentions  []mongoDbUserRef     `bson:"mentions,omitempty"`
+	Edits     []mongoDbCommentEdit `bson:"edits,omitempty"`
+	CreatedAt time.Time            `bson:"created_at"`
+	UpdatedAt time.Time            `bson:"updated_at"`
+}
+
+type mongoDbCommentEdit struct {
+	Body     string    `bson:"body"`
+	EditedAt time.Time `bson:"edited_at"`
+}
+
+type mongoDbCommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(client *mongo.Client, database string) *mongoDbCommentRepository {
+	collection := client.Database(database).Collection("comments")
+	return &mongoDbCommentRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoDbCommentRepository) Insert(ctx context.Context, comment *entities.Comment) error {
+	if comment == nil {
+		return errors.New("comment cannot be nil")
+	}
+
+	mongoComment, err := toMongoComment(*comment)
+	if err != nil {
+		return err
+	}
+	mongoComment.ID = primitive.NewObjectID()
+	mongoComment.CreatedAt = time.Now()
+	mongoComment.UpdatedAt = mongoComment.CreatedAt
+
+	if _, err := r.collection.InsertOne(ctx, mongoComment); err != nil {
+		return err
+	}
+
+	comment.ID = mongoComment.ID.Hex()
+	comment.CreatedAt = mongoComment.CreatedAt
+	comment.UpdatedAt = mongoComment.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbCommentRepository) Update(ctx context.Context, comment *entities.Comment) error {
+	if comment == nil {
+		return errors.New("comment cannot be nil")
+	}
+
+	if comment.ID == "" {
+		return errors.New("comment has no ID, use Insert instead")
+	}
+
+	mongoComment, err := toMongoComment(*comment)
+	if err != nil {
+		return err
+	}
+	mongoComment.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": mongoComment.ID}, mongoComment)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("no comment found with the given ID")
+	}
+
+	comment.UpdatedAt = mongoComment.UpdatedAt
+	return nil
+}
+
+// Delete removes a comment together with its replies
+func (r *mongoDbCommentRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid comment ID format")
+	}
+
+	result, err := r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"_id": oid},
+		bson.M{"parent_id": oid},
+	}})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("no comment found with the given ID")
+	}
+
+	return nil
+}
+
+func (r *mongoDbCommentRepository) FindByID(ctx context.Context, id string) (entities.Comment, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Comment{}, errors.New("invalid comment ID format")
+	}
+
+	var mongoComment mongoDbComment
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&mongoComment)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.Comment{}, errors.New("comment not found")
+		}
+		return entities.Comment{}, err
+	}
+
+	return fromMongoComment(mongoComment), nil
+}
+
+// FindByTaskID returns a page of the top-level comments of a task, oldest
+// first, and the total number of top-level comments
+func (r *mongoDbCommentRepository) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.Comment, int64, error) {
+	taskOid, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, 0, errors.New("invalid task ID format")
+	}
+	filter := bson.M{"task_id": taskOid, "parent_id": nil}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	comments, err := r.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// FindReplies returns the replies to any of the comments, oldest first
+func (r *mongoDbCommentRepository) FindReplies(ctx context.Context, parentIDs []string) ([]entities.Comment, error) {
+	if len(parentIDs) == 0 {
+		return []entities.Comment{}, nil
+	}
+	oids, err := toObjectIDs(parentIDs, "invalid comment ID format")
+	if err != nil {
+		return nil, err
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	return r.find(ctx, bson.M{"parent_id": bson.M{"$in": oids}}, findOptions)
+}
+
+// CountByTaskIDs counts the comments and replies per task. Tasks without
+// comments are left out.
+func (r *mongoDbCommentRepository) CountByTaskIDs(ctx context.Context, taskIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(taskIDs) == 0 {
+		return counts, nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"task_id": bson.M{"$in": oids}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$task_id", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		TaskID primitive.ObjectID `bson:"_id"`
+		Count  int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		counts[result.TaskID.Hex()] = result.Count
+	}
+	return counts, nil
+}
+
+// DeleteByTaskIDs removes the comments of the tasks
+func (r *mongoDbCommentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": oids}})
+	return err
+}
+
+// DeleteByProjectID removes the comments of all tasks of a project
+func (r *mongoDbCommentRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func (r *mongoDbCommentRepository) find(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]entities.Comment, error) {
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoComments []mongoDbComment
+	if err := cursor.All(ctx, &mongoComments); err != nil {
+		return nil, err
+	}
+
+	comments := make([]entities.Comment, len(mongoComments))
+	for i, mongoComment := range mongoComments {
+		comments[i] = fromMongoComment(mongoComment)
+	}
+
+	return comments, nil
+}
+
+func toMongoComment(comment entities.Comment) (*mongoDbComment, error) {
+	mongoComment := &mongoDbComment{
+		Author:    mongoDbUserRef{ID: comment.Author.ID, Name: comment.Author.Name, Email: comment.Author.Email},
+		Body:      comment.Body,
+		Mentions:  toMongoUserRefs(comment.Mentions),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+
+	if comment.ID != "" {
+		oid, err := primitive.ObjectIDFromHex(comment.ID)
+		if err != nil {
+			return nil, errors.New("invalid comment ID format")
+		}
+		mongoComment.ID = oid
+	}
+
+	oids, err := toObjectIDs([]string{comment.TaskID, comment.ProjectID}, "invalid task or project ID format")
+	if err != nil {
+		return nil, err
+	}
+	mongoComment.TaskID = oids[0]
+	mongoComment.ProjectID = oids[1]
+
+	if comment.ParentID != "" {
+		parentOid, err := primitive.ObjectIDFromHex(comment.ParentID)
+		if err != nil {
+			return nil, errors.New("invalid parent comment ID format")
+		}
+		mongoComment.ParentID = parentOid
+	}
+
+	for _, edit := range comment.Edits {
+		mongoComment.Edits = append(mongoComment.Edits, mongoDbCommentEdit{Body: edit.Body, EditedAt: edit.EditedAt})
+	}
+
+	return mongoComment, nil
+}
+
+func fromMongoComment(comment mongoDbComment) entities.Comment {
+	result := entities.Comment{
+		ID:        comment.ID.Hex(),
+		TaskID:    comment.TaskID.Hex(),
+		ProjectID: comment.ProjectID.Hex(),
+		Author:    entities.UserRef{ID: comment.Author.ID, Name: comment.Author.Name, Email: comment.Author.Email},
+		Body:      comment.Body,
+		Mentions:  fromMongoUserRefs(comment.Mentions),
+		Edits:     make([]entities.CommentEdit, len(comment.Edits)),
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+
+	if !comment.ParentID.IsZero() {
+		result.ParentID = comment.ParentID.Hex()
+	}
+
+	for i, edit := range comment.Edits {
+		result.Edits[i] = entities.CommentEdit{Body: edit.Body, EditedAt: edit.EditedAt}
+	}
+
+	return result
+}
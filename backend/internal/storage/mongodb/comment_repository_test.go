@@ -0,0 +1,92 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongoDbCommentRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	repo := mongodb.NewCommentRepository(client, testDBName)
+	ctx := context.Background()
+
+	projectID := primitive.NewObjectID().Hex()
+	taskID := primitive.NewObjectID().Hex()
+	otherTaskID := primitive.NewObjectID().Hex()
+
+	insert := func(taskID, parentID, body string) entities.Comment {
+		comment := &entities.Comment{TaskID: taskID, ProjectID: projectID, ParentID: parentID, Body: body,
+			Author: entities.UserRef{ID: "jane", Name: "Jane"}}
+		require.NoError(t, repo.Insert(ctx, comment))
+		return *comment
+	}
+	first := insert(taskID, "", "First")
+	second := insert(taskID, "", "Second")
+	reply := insert(taskID, first.ID, "Reply")
+	insert(otherTaskID, "", "Elsewhere")
+
+	t.Run("FindByTaskID pages top-level comments", func(t *testing.T) {
+		page, total, err := repo.FindByTaskID(ctx, taskID, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, page, 1)
+		assert.Equal(t, second.ID, page[0].ID)
+		assert.Empty(t, page[0].ParentID)
+
+		replies, err := repo.FindReplies(ctx, []string{first.ID, second.ID})
+		require.NoError(t, err)
+		require.Len(t, replies, 1)
+		assert.Equal(t, first.ID, replies[0].ParentID)
+	})
+
+	t.Run("Update keeps the edit history", func(t *testing.T) {
+		edited := reply
+		edited.Body = "Reply, edited"
+		edited.Edits = []entities.CommentEdit{{Body: reply.Body, EditedAt: reply.CreatedAt}}
+		edited.Mentions = []entities.UserRef{{ID: "joe", Name: "Joe"}}
+		require.NoError(t, repo.Update(ctx, &edited))
+
+		found, err := repo.FindByID(ctx, reply.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Reply, edited", found.Body)
+		require.Len(t, found.Edits, 1)
+		assert.Equal(t, "Reply", found.Edits[0].Body)
+		assert.Equal(t, edited.Mentions, found.Mentions)
+		assert.Equal(t, entities.UserRef{ID: "jane", Name: "Jane"}, found.Author)
+	})
+
+	t.Run("CountByTaskIDs counts replies", func(t *testing.T) {
+		counts, err := repo.CountByTaskIDs(ctx, []string{taskID, otherTaskID, primitive.NewObjectID().Hex()})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{taskID: 3, otherTaskID: 1}, counts)
+	})
+
+	t.Run("Delete removes the replies", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, first.ID))
+		_, err := repo.FindByID(ctx, reply.ID)
+		assert.Error(t, err)
+
+		require.NoError(t, repo.DeleteByTaskIDs(ctx, []string{otherTaskID}))
+		counts, err := repo.CountByTaskIDs(ctx, []string{taskID, otherTaskID})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{taskID: 1}, counts)
+
+		require.NoError(t, repo.DeleteByProjectID(ctx, projectID))
+		_, total, err := repo.FindByTaskID(ctx, taskID, 10, 0)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+	})
+}
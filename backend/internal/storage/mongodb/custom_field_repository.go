@@ -0,0 +1,200 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbCustomField struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	Name      string             `bson:"name"`
+	Type      string             `bson:"type"`
+	Options   []string           `bson:"options,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+type mongoDbCustomFieldRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCustomFieldRepository(client *mongo.Client, database string) *mongoDbCustomFieldRepository {
+	collection := client.Database(database).Collection("custom_fields")
+	return &mongoDbCustomFieldRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoDbCustomFieldRepository) Insert(ctx context.Context, field *entities.CustomField) error {
+	if field == nil {
+		return errors.New("custom field cannot be nil")
+	}
+
+	if field.ID != "" {
+		return errors.New("custom field already has an ID, use Update instead")
+	}
+
+	mongoField, err := toMongoCustomField(*field)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	mongoField.CreatedAt = now
+	mongoField.UpdatedAt = now
+
+	if _, err := r.collection.InsertOne(ctx, mongoField); err != nil {
+		return err
+	}
+
+	field.ID = mongoField.ID.Hex()
+	field.CreatedAt = mongoField.CreatedAt
+	field.UpdatedAt = mongoField.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbCustomFieldRepository) Update(ctx context.Context, field *entities.CustomField) error {
+	if field == nil {
+		return errors.New("custom field cannot be nil")
+	}
+
+	if field.ID == "" {
+		return errors.New("custom field has no ID, use Insert instead")
+	}
+
+	mongoField, err := toMongoCustomField(*field)
+	if err != nil {
+		return err
+	}
+	mongoField.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": mongoField.ID}, mongoField)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("no custom field found with the given ID")
+	}
+
+	field.UpdatedAt = mongoField.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbCustomFieldRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("no custom field found with the given ID")
+	}
+
+	return nil
+}
+
+func (r *mongoDbCustomFieldRepository) FindByID(ctx context.Context, id string) (entities.CustomField, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.CustomField{}, err
+	}
+
+	var mongoField mongoDbCustomField
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&mongoField)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.CustomField{}, errors.New("custom field not found")
+		}
+		return entities.CustomField{}, err
+	}
+
+	return fromMongoCustomField(mongoField), nil
+}
+
+// FindByProjectID returns the custom fields of a project sorted by name
+func (r *mongoDbCustomFieldRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectOid}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoFields []mongoDbCustomField
+	if err := cursor.All(ctx, &mongoFields); err != nil {
+		return nil, err
+	}
+
+	fields := make([]entities.CustomField, len(mongoFields))
+	for i, mongoField := range mongoFields {
+		fields[i] = fromMongoCustomField(mongoField)
+	}
+
+	return fields, nil
+}
+
+// DeleteByProjectID removes all custom fields of a project
+func (r *mongoDbCustomFieldRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func toMongoCustomField(field entities.CustomField) (*mongoDbCustomField, error) {
+	oid := primitive.NewObjectID()
+	if field.ID != "" {
+		var err error
+		oid, err = primitive.ObjectIDFromHex(field.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	projectOid, err := primitive.ObjectIDFromHex(field.ProjectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	return &mongoDbCustomField{
+		ID:        oid,
+		ProjectID: projectOid,
+		Name:      field.Name,
+		Type:      string(field.Type),
+		Options:   field.Options,
+		CreatedAt: field.CreatedAt,
+		UpdatedAt: field.UpdatedAt,
+	}, nil
+}
+
+func fromMongoCustomField(field mongoDbCustomField) entities.CustomField {
+	return entities.CustomField{
+		ID:        field.ID.Hex(),
+		ProjectID: field.ProjectID.Hex(),
+		Name:      field.Name,
+		Type:      entities.CustomFieldType(field.Type),
+		Options:   field.Options,
+		CreatedAt: field.CreatedAt,
+		UpdatedAt: field.UpdatedAt,
+	}
+}
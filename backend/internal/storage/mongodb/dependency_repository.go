@@ -0,0 +1,183 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbDependency struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty"`
+	BlockerID        primitive.ObjectID `bson:"blocker_id"`
+	BlockerProjectID primitive.ObjectID `bson:"blocker_project_id"`
+	BlockedID        primitive.ObjectID `bson:"blocked_id"`
+	BlockedProjectID primitive.ObjectID `bson:"blocked_project_id"`
+	CreatedAt        time.Time          `bson:"created_at"`
+}
+
+type mongoDbDependencyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDependencyRepository(client *mongo.Client, database string) *mongoDbDependencyRepository {
+	collection := client.Database(database).Collection("task_dependencies")
+	return &mongoDbDependencyRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoDbDependencyRepository) Insert(ctx context.Context, dependency *entities.TaskDependency) error {
+	if dependency == nil {
+		return errors.New("dependency cannot be nil")
+	}
+
+	mongoDependency, err := toMongoDependency(*dependency)
+	if err != nil {
+		return err
+	}
+	mongoDependency.CreatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, mongoDependency); err != nil {
+		return err
+	}
+
+	dependency.CreatedAt = mongoDependency.CreatedAt
+	return nil
+}
+
+func (r *mongoDbDependencyRepository) Delete(ctx context.Context, blockerID, blockedID string) error {
+	blockerOid, err := primitive.ObjectIDFromHex(blockerID)
+	if err != nil {
+		return errors.New("invalid task ID format")
+	}
+	blockedOid, err := primitive.ObjectIDFromHex(blockedID)
+	if err != nil {
+		return errors.New("invalid task ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"blocker_id": blockerOid, "blocked_id": blockedOid})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("dependency not found")
+	}
+
+	return nil
+}
+
+// FindByBlockers returns the links starting at any of the tasks
+func (r *mongoDbDependencyRepository) FindByBlockers(ctx context.Context, taskIDs []string) ([]entities.TaskDependency, error) {
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"blocker_id": bson.M{"$in": oids}})
+}
+
+// FindByBlocked returns the links ending at any of the tasks
+func (r *mongoDbDependencyRepository) FindByBlocked(ctx context.Context, taskIDs []string) ([]entities.TaskDependency, error) {
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return nil, err
+	}
+	return r.find(ctx, bson.M{"blocked_id": bson.M{"$in": oids}})
+}
+
+// FindByProjectID returns the links with at least one end in the project
+func (r *mongoDbDependencyRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.TaskDependency, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+	return r.find(ctx, bson.M{"$or": bson.A{
+		bson.M{"blocker_project_id": projectOid},
+		bson.M{"blocked_project_id": projectOid},
+	}})
+}
+
+// DeleteByTaskIDs removes every link with an end at one of the tasks
+func (r *mongoDbDependencyRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"blocker_id": bson.M{"$in": oids}},
+		bson.M{"blocked_id": bson.M{"$in": oids}},
+	}})
+	return err
+}
+
+// DeleteByProjectID removes every link with an end in the project
+func (r *mongoDbDependencyRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"blocker_project_id": projectOid},
+		bson.M{"blocked_project_id": projectOid},
+	}})
+	return err
+}
+
+// find returns the matching links, oldest first
+func (r *mongoDbDependencyRepository) find(ctx context.Context, filter bson.M) ([]entities.TaskDependency, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoDependencies []mongoDbDependency
+	if err := cursor.All(ctx, &mongoDependencies); err != nil {
+		return nil, err
+	}
+
+	dependencies := make([]entities.TaskDependency, len(mongoDependencies))
+	for i, mongoDependency := range mongoDependencies {
+		dependencies[i] = fromMongoDependency(mongoDependency)
+	}
+
+	return dependencies, nil
+}
+
+func toMongoDependency(dependency entities.TaskDependency) (*mongoDbDependency, error) {
+	ids := []string{dependency.BlockerID, dependency.BlockerProjectID, dependency.BlockedID, dependency.BlockedProjectID}
+	oids, err := toObjectIDs(ids, "invalid task or project ID format")
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoDbDependency{
+		ID:               primitive.NewObjectID(),
+		BlockerID:        oids[0],
+		BlockerProjectID: oids[1],
+		BlockedID:        oids[2],
+		BlockedProjectID: oids[3],
+		CreatedAt:        dependency.CreatedAt,
+	}, nil
+}
+
+func fromMongoDependency(dependency mongoDbDependency) entities.TaskDependency {
+	return entities.TaskDependency{
+		BlockerID:        dependency.BlockerID.Hex(),
+		BlockerProjectID: dependency.BlockerProjectID.Hex(),
+		BlockedID:        dependency.BlockedID.Hex(),
+		BlockedProjectID: dependency.BlockedProjectID.Hex(),
+		CreatedAt:        dependency.CreatedAt,
+	}
+}
@@ -0,0 +1,197 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbLabel struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	Name      string             `bson:"name"`
+	Color     string             `bson:"color"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+type mongoDbLabelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLabelRepository(client *mongo.Client, database string) *mongoDbLabelRepository {
+	collection := client.Database(database).Collection("labels")
+	return &mongoDbLabelRepository{
+		collection: collection,
+	}
+}
+
+func (r *mongoDbLabelRepository) Insert(ctx context.Context, label *entities.Label) error {
+	if label == nil {
+		return errors.New("label cannot be nil")
+	}
+
+	if label.ID != "" {
+		return errors.New("label already has an ID, use Update instead")
+	}
+
+	mongoLabel, err := toMongoLabel(*label)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	mongoLabel.CreatedAt = now
+	mongoLabel.UpdatedAt = now
+
+	if _, err := r.collection.InsertOne(ctx, mongoLabel); err != nil {
+		return err
+	}
+
+	label.ID = mongoLabel.ID.Hex()
+	label.CreatedAt = mongoLabel.CreatedAt
+	label.UpdatedAt = mongoLabel.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbLabelRepository) Update(ctx context.Context, label *entities.Label) error {
+	if label == nil {
+		return errors.New("label cannot be nil")
+	}
+
+	if label.ID == "" {
+		return errors.New("label has no ID, use Insert instead")
+	}
+
+	mongoLabel, err := toMongoLabel(*label)
+	if err != nil {
+		return err
+	}
+	mongoLabel.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": mongoLabel.ID}, mongoLabel)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("no label found with the given ID")
+	}
+
+	label.UpdatedAt = mongoLabel.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbLabelRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("no label found with the given ID")
+	}
+
+	return nil
+}
+
+func (r *mongoDbLabelRepository) FindByID(ctx context.Context, id string) (entities.Label, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Label{}, err
+	}
+
+	var mongoLabel mongoDbLabel
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&mongoLabel)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.Label{}, errors.New("label not found")
+		}
+		return entities.Label{}, err
+	}
+
+	return fromMongoLabel(mongoLabel), nil
+}
+
+// FindByProjectID returns the labels of a project sorted by name
+func (r *mongoDbLabelRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectOid}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoLabels []mongoDbLabel
+	if err := cursor.All(ctx, &mongoLabels); err != nil {
+		return nil, err
+	}
+
+	labels := make([]entities.Label, len(mongoLabels))
+	for i, mongoLabel := range mongoLabels {
+		labels[i] = fromMongoLabel(mongoLabel)
+	}
+
+	return labels, nil
+}
+
+// DeleteByProjectID removes all labels of a project
+func (r *mongoDbLabelRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func toMongoLabel(label entities.Label) (*mongoDbLabel, error) {
+	oid := primitive.NewObjectID()
+	if label.ID != "" {
+		var err error
+		oid, err = primitive.ObjectIDFromHex(label.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	projectOid, err := primitive.ObjectIDFromHex(label.ProjectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	return &mongoDbLabel{
+		ID:        oid,
+		ProjectID: projectOid,
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}, nil
+}
+
+func fromMongoLabel(label mongoDbLabel) entities.Label {
+	return entities.Label{
+		ID:        label.ID.Hex(),
+		ProjectID: label.ProjectID.Hex(),
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}
+}
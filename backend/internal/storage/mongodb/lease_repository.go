@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LeaseCollection holds one document per lease, keyed by the lease name
+const LeaseCollection = "leases"
+
+type mongoDbLeaseRepository struct {
+	collection *mongo.Collection
+	// holder identifies this process as the holder of its leases
+	holder string
+}
+
+func NewLeaseRepository(client *mongo.Client, database string) *mongoDbLeaseRepository {
+	return &mongoDbLeaseRepository{
+		collection: client.Database(database).Collection(LeaseCollection),
+		holder:     lockOwner(),
+	}
+}
+
+// Acquire takes the lease if it is expired or already held by this process.
+// A missing lease document is created by the upsert; when two processes race
+// to create it, the loser fails on the unique _id and does not get the lease.
+func (r *mongoDbLeaseRepository) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": now}},
+			bson.M{"holder": r.holder},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": r.holder, "expires_at": now.Add(ttl)}}
+
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Release removes the lease if this process holds it
+func (r *mongoDbLeaseRepository) Release(ctx context.Context, name string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": name, "holder": r.holder})
+	return err
+}
@@ -0,0 +1,63 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMongoDbLeaseRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	// Each repository stands for another replica
+	first := mongodb.NewLeaseRepository(client, testDBName)
+	second := mongodb.NewLeaseRepository(client, testDBName)
+	ctx := context.Background()
+
+	t.Run("one holder at a time", func(t *testing.T) {
+		acquired, err := first.Acquire(ctx, "job", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = second.Acquire(ctx, "job", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		acquired, err = first.Acquire(ctx, "job", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired, "the holder may renew its lease")
+	})
+
+	t.Run("released and expired leases are free", func(t *testing.T) {
+		require.NoError(t, second.Release(ctx, "job"), "releasing a lease held by another process is a no-op")
+		acquired, err := second.Acquire(ctx, "job", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, acquired)
+
+		require.NoError(t, first.Release(ctx, "job"))
+		acquired, err = second.Acquire(ctx, "job", time.Millisecond)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+
+		time.Sleep(10 * time.Millisecond)
+		acquired, err = first.Acquire(ctx, "job", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("leases are independent", func(t *testing.T) {
+		acquired, err := second.Acquire(ctx, "other-job", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+}
@@ -0,0 +1,129 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbMember struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	UserID    string             `bson:"user_id"`
+	Name      string             `bson:"name,omitempty"`
+	Email     string             `bson:"email,omitempty"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+type mongoDbMemberRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMemberRepository(client *mongo.Client, database string) *mongoDbMemberRepository {
+	collection := client.Database(database).Collection("project_members")
+	return &mongoDbMemberRepository{
+		collection: collection,
+	}
+}
+
+// Upsert adds a user to a project or refreshes the name and email of an existing member
+func (r *mongoDbMemberRepository) Upsert(ctx context.Context, member *entities.ProjectMember) error {
+	if member == nil {
+		return errors.New("member cannot be nil")
+	}
+
+	projectOid, err := primitive.ObjectIDFromHex(member.ProjectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	now := time.Now()
+	filter := bson.M{"project_id": projectOid, "user_id": member.UserID}
+	update := bson.M{
+		"$set":         bson.M{"name": member.Name, "email": member.Email, "updated_at": now},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var mongoMember mongoDbMember
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&mongoMember); err != nil {
+		return err
+	}
+
+	member.CreatedAt = mongoMember.CreatedAt
+	member.UpdatedAt = mongoMember.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbMemberRepository) Delete(ctx context.Context, projectID, userID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"project_id": projectOid, "user_id": userID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("member not found")
+	}
+
+	return nil
+}
+
+// FindByProjectID returns the members of a project sorted by name
+func (r *mongoDbMemberRepository) FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "user_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"project_id": projectOid}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoMembers []mongoDbMember
+	if err := cursor.All(ctx, &mongoMembers); err != nil {
+		return nil, err
+	}
+
+	members := make([]entities.ProjectMember, len(mongoMembers))
+	for i, mongoMember := range mongoMembers {
+		members[i] = fromMongoMember(mongoMember)
+	}
+
+	return members, nil
+}
+
+// DeleteByProjectID removes all members of a project
+func (r *mongoDbMemberRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func fromMongoMember(member mongoDbMember) entities.ProjectMember {
+	return entities.ProjectMember{
+		ProjectID: member.ProjectID.Hex(),
+		UserID:    member.UserID,
+		Name:      member.Name,
+		Email:     member.Email,
+		CreatedAt: member.CreatedAt,
+		UpdatedAt: member.UpdatedAt,
+	}
+}
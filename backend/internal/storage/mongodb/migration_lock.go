@@ -0,0 +1,162 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrationLockCollection holds the lock document that keeps concurrent
+// migration runs, e.g. from several replicas starting at once, apart
+const MigrationLockCollection = "schema_migrations_lock"
+
+const (
+	migrationLockID = "migrations"
+
+	// defaultLockTTL lets a lock left behind by a crashed run be taken over.
+	// A running migration renews its lock well before the TTL runs out.
+	defaultLockTTL = 10 * time.Minute
+	// defaultLockWait bounds how long a run waits for another to finish
+	defaultLockWait  = 2 * time.Minute
+	lockPollInterval = time.Second
+)
+
+// ErrMigrationLocked is returned when another run holds the migration lock
+// for longer than the migrator is willing to wait
+var ErrMigrationLocked = errors.New("migrations are locked by another run")
+
+// ErrMigrationLockLost is returned when the migration lock expired or was taken
+// over by another run while migrations were running
+var ErrMigrationLockLost = errors.New("migration lock was lost")
+
+type migrationLockDocument struct {
+	ID        string    `bson:"_id"`
+	Owner     string    `bson:"owner"`
+	LockedAt  time.Time `bson:"locked_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// migrationLock is a lease on a single document; it expires after ttl so that
+// a crashed run does not block migrations forever, and is renewed while held
+type migrationLock struct {
+	collection *mongo.Collection
+	owner      string
+	ttl        time.Duration
+	wait       time.Duration
+}
+
+func newMigrationLock(db *mongo.Database) *migrationLock {
+	return &migrationLock{
+		collection: db.Collection(MigrationLockCollection),
+		owner:      lockOwner(),
+		ttl:        defaultLockTTL,
+		wait:       defaultLockWait,
+	}
+}
+
+// acquire takes the lock, waiting for up to l.wait while another run holds it
+func (l *migrationLock) acquire(ctx context.Context) error {
+	deadline := time.Now().Add(l.wait)
+	for {
+		ok, err := l.tryAcquire(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		if ok {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrMigrationLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// tryAcquire inserts the lock document, or takes it over once it has expired
+func (l *migrationLock) tryAcquire(ctx context.Context) (bool, error) {
+	now := time.Now().UTC()
+	lock := migrationLockDocument{ID: migrationLockID, Owner: l.owner, LockedAt: now, ExpiresAt: now.Add(l.ttl)}
+
+	_, err := l.collection.InsertOne(ctx, lock)
+	if err == nil {
+		return true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return false, err
+	}
+
+	result, err := l.collection.ReplaceOne(ctx, bson.M{"_id": migrationLockID, "expires_at": bson.M{"$lt": now}}, lock)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// renew extends the lease of the lock; it reports false if the lock is no
+// longer held by this run
+func (l *migrationLock) renew(ctx context.Context) (bool, error) {
+	expiresAt := time.Now().UTC().Add(l.ttl)
+	result, err := l.collection.UpdateOne(ctx, bson.M{"_id": migrationLockID, "owner": l.owner}, bson.M{"$set": bson.M{"expires_at": expiresAt}})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// keepAlive renews the lease a few times per ttl until ctx is done. It cancels
+// ctx with ErrMigrationLockLost once the lock is held by someone else, or when
+// failed renewals would let it expire before the next attempt.
+func (l *migrationLock) keepAlive(ctx context.Context, cancel context.CancelCauseFunc) {
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		held, err := l.renew(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err == nil && held:
+			renewed = time.Now()
+		case err == nil:
+			cancel(ErrMigrationLockLost)
+			return
+		case time.Since(renewed)+interval >= l.ttl:
+			cancel(fmt.Errorf("%w: %v", ErrMigrationLockLost, err))
+			return
+		}
+	}
+}
+
+// release removes the lock if it is still held by this run
+func (l *migrationLock) release(ctx context.Context) error {
+	_, err := l.collection.DeleteOne(ctx, bson.M{"_id": migrationLockID, "owner": l.owner})
+	return err
+}
+
+// lockOwner identifies this process in the lock document for troubleshooting
+func lockOwner() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s/%d/%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
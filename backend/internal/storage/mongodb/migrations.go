@@ -0,0 +1,220 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migrations are the schema migrations of the application. New migrations are
+// appended with the next version; applied migrations must never be changed.
+var Migrations = []Migration{
+	{
+		Version:     1,
+		Description: "index tasks by project",
+		Up:          createIndex("tasks", "project_id_1", bson.D{{Key: "project_id", Value: 1}}),
+		Down:        dropIndex("tasks", "project_id_1"),
+	},
+	{
+		Version:     2,
+		Description: "index tasks by status and due date",
+		Up:          createIndex("tasks", "status_1_due_date_1", bson.D{{Key: "status", Value: 1}, {Key: "due_date", Value: 1}}),
+		Down:        dropIndex("tasks", "status_1_due_date_1"),
+	},
+	{
+		Version:     3,
+		Description: "index projects by creation date for pagination",
+		Up:          createIndex("projects", "created_at_-1", bson.D{{Key: "created_at", Value: -1}}),
+		Down:        dropIndex("projects", "created_at_-1"),
+	},
+	{
+		Version:     4,
+		Description: "index labels and custom fields by project",
+		Up: steps(
+			createIndex("labels", "project_id_1_name_1", bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}}),
+			createIndex("custom_fields", "project_id_1_name_1", bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("labels", "project_id_1_name_1"),
+			dropIndex("custom_fields", "project_id_1_name_1"),
+		),
+	},
+	{
+		// Reverting only restores the default statuses; tasks in statuses of
+		// custom workflows keep their keys
+		Version:     5,
+		Description: "store task statuses as workflow keys",
+		Up:          convertTaskStatuses(true),
+		Down:        convertTaskStatuses(false),
+	},
+	{
+		Version:     6,
+		Description: "index task dependencies by both ends",
+		Up: steps(
+			createUniqueIndex("task_dependencies", "blocker_id_1_blocked_id_1", bson.D{{Key: "blocker_id", Value: 1}, {Key: "blocked_id", Value: 1}}),
+			createIndex("task_dependencies", "blocked_id_1", bson.D{{Key: "blocked_id", Value: 1}}),
+			createIndex("task_dependencies", "blocker_project_id_1", bson.D{{Key: "blocker_project_id", Value: 1}}),
+			createIndex("task_dependencies", "blocked_project_id_1", bson.D{{Key: "blocked_project_id", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("task_dependencies", "blocker_id_1_blocked_id_1"),
+			dropIndex("task_dependencies", "blocked_id_1"),
+			dropIndex("task_dependencies", "blocker_project_id_1"),
+			dropIndex("task_dependencies", "blocked_project_id_1"),
+		),
+	},
+	{
+		Version:     7,
+		Description: "index project members and task assignees",
+		Up: steps(
+			createUniqueIndex("project_members", "project_id_1_user_id_1", bson.D{{Key: "project_id", Value: 1}, {Key: "user_id", Value: 1}}),
+			createIndex("tasks", "assignees.id_1_due_date_1", bson.D{{Key: "assignees.id", Value: 1}, {Key: "due_date", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("project_members", "project_id_1_user_id_1"),
+			dropIndex("tasks", "assignees.id_1_due_date_1"),
+		),
+	},
+	{
+		Version:     8,
+		Description: "index comments by task, thread and project",
+		Up: steps(
+			createIndex("comments", "task_id_1_parent_id_1_created_at_1", bson.D{{Key: "task_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}}),
+			createIndex("comments", "parent_id_1_created_at_1", bson.D{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}}),
+			createIndex("comments", "project_id_1", bson.D{{Key: "project_id", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("comments", "task_id_1_parent_id_1_created_at_1"),
+			dropIndex("comments", "parent_id_1_created_at_1"),
+			dropIndex("comments", "project_id_1"),
+		),
+	},
+	{
+		Version:     9,
+		Description: "index attachments by task and project",
+		Up: steps(
+			createIndex("attachments", "task_id_1_created_at_1", bson.D{{Key: "task_id", Value: 1}, {Key: "created_at", Value: 1}}),
+			createIndex("attachments", "project_id_1", bson.D{{Key: "project_id", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("attachments", "task_id_1_created_at_1"),
+			dropIndex("attachments", "project_id_1"),
+		),
+	},
+	{
+		// The unique index lets replicas generate the same occurrence of a
+		// recurring task without creating it twice
+		Version:     10,
+		Description: "index recurring task series by occurrence",
+		Up:          createPartialUniqueIndex("tasks", "series_id_1_occurrence_1", bson.D{{Key: "series_id", Value: 1}, {Key: "occurrence", Value: 1}}, bson.M{"series_id": bson.M{"$exists": true}}),
+		Down:        dropIndex("tasks", "series_id_1_occurrence_1"),
+	},
+	{
+		// The unique key makes every reminder fire once, also when several
+		// replicas send reminders at the same time
+		Version:     11,
+		Description: "index notifications and tasks by due date",
+		Up: steps(
+			createUniqueIndex("notifications", "user_id_1_key_1", bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}}),
+			createIndex("notifications", "user_id_1_created_at_-1", bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}),
+			createIndex("notifications", "deliveries.status_1_created_at_1", bson.D{{Key: "deliveries.status", Value: 1}, {Key: "created_at", Value: 1}}),
+			createIndex("tasks", "due_date_1", bson.D{{Key: "due_date", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("notifications", "user_id_1_key_1"),
+			dropIndex("notifications", "user_id_1_created_at_-1"),
+			dropIndex("notifications", "deliveries.status_1_created_at_1"),
+			dropIndex("tasks", "due_date_1"),
+		),
+	},
+	{
+		// The partial unique index allows one running timer per user, also
+		// when timers are started concurrently
+		Version:     12,
+		Description: "index time entries by running timer, task and project",
+		Up: steps(
+			createPartialUniqueIndex("time_entries", "user.id_1_running", bson.D{{Key: "user.id", Value: 1}}, bson.M{"running": true}),
+			createIndex("time_entries", "task_id_1_start_1", bson.D{{Key: "task_id", Value: 1}, {Key: "start", Value: 1}}),
+			createIndex("time_entries", "project_id_1_start_1", bson.D{{Key: "project_id", Value: 1}, {Key: "start", Value: 1}}),
+		),
+		Down: steps(
+			dropIndex("time_entries", "user.id_1_running"),
+			dropIndex("time_entries", "task_id_1_start_1"),
+			dropIndex("time_entries", "project_id_1_start_1"),
+		),
+	},
+}
+
+// steps combines migration steps that run in order
+func steps(fns ...func(ctx context.Context, db *mongo.Database) error) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, fn := range fns {
+			if err := fn(ctx, db); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// convertTaskStatuses returns a migration step that rewrites the default task
+// statuses from their former int encoding to keys, or back
+func convertTaskStatuses(toKeys bool) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		tasks := db.Collection("tasks")
+		for legacy, key := range legacyTaskStatuses {
+			from, to := interface{}(legacy), interface{}(string(key))
+			if !toKeys {
+				from, to = to, from
+			}
+			if _, err := tasks.UpdateMany(ctx, bson.M{"status": from}, bson.M{"$set": bson.M{"status": to}}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// createIndex returns a migration step that creates a named index
+func createIndex(collection, name string, keys bson.D) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name),
+		})
+		return err
+	}
+}
+
+// createUniqueIndex returns a migration step that creates a named unique index
+func createUniqueIndex(collection, name string, keys bson.D) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name).SetUnique(true),
+		})
+		return err
+	}
+}
+
+// createPartialUniqueIndex returns a migration step that creates a named unique
+// index over the documents matching filter
+func createPartialUniqueIndex(collection, name string, keys bson.D, filter bson.M) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    keys,
+			Options: options.Index().SetName(name).SetUnique(true).SetPartialFilterExpression(filter),
+		})
+		return err
+	}
+}
+
+// dropIndex returns a migration step that drops a named index
+func dropIndex(collection, name string) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().DropOne(ctx, name)
+		return err
+	}
+}
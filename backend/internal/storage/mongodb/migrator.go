@@ -0,0 +1,225 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationsCollection records the applied migrations, one document per version
+const MigrationsCollection = "schema_migrations"
+
+// Migration changes the database from the previous version to Version. Down
+// reverts the change; it may be nil for migrations that cannot be reverted.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+	Down        func(ctx context.Context, db *mongo.Database) error
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time
+}
+
+// Applied reports whether the migration has been applied
+func (s MigrationStatus) Applied() bool {
+	return s.AppliedAt != nil
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Migrator applies and reverts migrations in version order. Up and Down hold
+// a lock in the database, so concurrent runs wait for each other.
+type Migrator struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+	migrations []Migration
+	lock       *migrationLock
+}
+
+// NewMigrator creates a migrator for the given migrations, which are sorted by version
+func NewMigrator(client *mongo.Client, database string, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	db := client.Database(database)
+	return &Migrator{
+		db:         db,
+		collection: db.Collection(MigrationsCollection),
+		migrations: sorted,
+		lock:       newMigrationLock(db),
+	}
+}
+
+// WithLockWait sets how long Up and Down wait for a concurrent run to release
+// the lock before failing with ErrMigrationLocked
+func (m *Migrator) WithLockWait(wait time.Duration) *Migrator {
+	m.lock.wait = wait
+	return m
+}
+
+// WithLockTTL sets how long the lock outlives a run that stopped renewing it
+func (m *Migrator) WithLockTTL(ttl time.Duration) *Migrator {
+	m.lock.ttl = ttl
+	return m
+}
+
+// locked runs fn while holding the migration lock. The context passed to fn is
+// cancelled if the lock is lost, and locked then returns ErrMigrationLockLost.
+func (m *Migrator) locked(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.lock.acquire(ctx); err != nil {
+		return err
+	}
+
+	lockCtx, stop := context.WithCancelCause(ctx)
+	renewing := make(chan struct{})
+	go func() {
+		defer close(renewing)
+		m.lock.keepAlive(lockCtx, stop)
+	}()
+	defer func() {
+		stop(nil)
+		<-renewing
+		// Release even if ctx was cancelled, otherwise the lock blocks runs until it expires
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		m.lock.release(releaseCtx)
+	}()
+
+	err := fn(lockCtx)
+	if cause := context.Cause(lockCtx); errors.Is(cause, ErrMigrationLockLost) {
+		return cause
+	}
+	return err
+}
+
+// Status returns every known migration with the time it was applied, if it was
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Description: migration.Description}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations up to and including target, or all of
+// them when target is 0. It returns the migrations that were applied.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(ctx context.Context) error {
+		var err error
+		done, err = m.up(ctx, target)
+		return err
+	})
+	return done, err
+}
+
+func (m *Migrator) up(ctx context.Context, target int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		if err := migration.Up(ctx, m.db); err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+		record := appliedMigration{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now().UTC()}
+		if _, err := m.collection.InsertOne(ctx, record); err != nil {
+			return done, fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down reverts the given number of most recently applied migrations and
+// returns the migrations that were reverted
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.locked(ctx, func(ctx context.Context) error {
+		var err error
+		done, err = m.down(ctx, steps)
+		return err
+	})
+	return done, err
+}
+
+func (m *Migrator) down(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return done, fmt.Errorf("migration %d (%s) cannot be reverted", migration.Version, migration.Description)
+		}
+
+		if err := migration.Down(ctx, m.db); err != nil {
+			return done, fmt.Errorf("reverting migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+		if _, err := m.collection.DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+			return done, fmt.Errorf("failed to remove record of migration %d: %w", migration.Version, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// applied returns the recorded migrations by version
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	cursor, err := m.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,182 @@
+package mongodb_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexNames returns the names of the indexes of a collection
+func indexNames(t *testing.T, collection *mongo.Collection) []string {
+	cursor, err := collection.Indexes().List(context.Background())
+	require.NoError(t, err)
+
+	var indexes []bson.M
+	require.NoError(t, cursor.All(context.Background(), &indexes))
+
+	names := make([]string, len(indexes))
+	for i, index := range indexes {
+		names[i] = index["name"].(string)
+	}
+	return names
+}
+
+func TestMigrator_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	db := client.Database("migratordb")
+	ctx := context.Background()
+
+	t.Run("Up applies all migrations and records them", func(t *testing.T) {
+		migrator := mongodb.NewMigrator(client, "migratordb", mongodb.Migrations)
+
+		applied, err := migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, applied, len(mongodb.Migrations))
+
+		assert.Contains(t, indexNames(t, db.Collection("tasks")), "project_id_1")
+		assert.Contains(t, indexNames(t, db.Collection("tasks")), "status_1_due_date_1")
+		assert.Contains(t, indexNames(t, db.Collection("projects")), "created_at_-1")
+		assert.Contains(t, indexNames(t, db.Collection("labels")), "project_id_1_name_1")
+		assert.Contains(t, indexNames(t, db.Collection("custom_fields")), "project_id_1_name_1")
+		assert.Contains(t, indexNames(t, db.Collection("task_dependencies")), "blocker_id_1_blocked_id_1")
+		assert.Contains(t, indexNames(t, db.Collection("project_members")), "project_id_1_user_id_1")
+		assert.Contains(t, indexNames(t, db.Collection("comments")), "task_id_1_parent_id_1_created_at_1")
+		assert.Contains(t, indexNames(t, db.Collection("attachments")), "task_id_1_created_at_1")
+		assert.Contains(t, indexNames(t, db.Collection("tasks")), "series_id_1_occurrence_1")
+		assert.Contains(t, indexNames(t, db.Collection("notifications")), "user_id_1_key_1")
+		assert.Contains(t, indexNames(t, db.Collection("time_entries")), "user.id_1_running")
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			assert.True(t, status.Applied(), "migration %d should be applied", status.Version)
+		}
+
+		applied, err = migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, applied, "a second run should have nothing to do")
+	})
+
+	t.Run("Down reverts the latest migrations", func(t *testing.T) {
+		migrator := mongodb.NewMigrator(client, "migratordb", mongodb.Migrations)
+		tasks := db.Collection("tasks")
+		_, err := tasks.InsertOne(ctx, bson.M{"_id": "done-task", "status": "DONE"})
+		require.NoError(t, err)
+
+		reverted, err := migrator.Down(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, reverted, 1)
+		assert.Equal(t, mongodb.Migrations[len(mongodb.Migrations)-1].Version, reverted[0].Version)
+		assert.NotContains(t, indexNames(t, db.Collection("time_entries")), "user.id_1_running")
+		assert.NotContains(t, indexNames(t, db.Collection("time_entries")), "project_id_1_start_1")
+		assert.Contains(t, indexNames(t, db.Collection("notifications")), "user_id_1_key_1")
+
+		statuses, err := migrator.Status(ctx)
+		require.NoError(t, err)
+		assert.False(t, statuses[len(statuses)-1].Applied())
+
+		// Migration 11 drops the notification and due date indexes, 10 the series index, 9 the attachment indexes, 8 the comment indexes, 7 the member indexes, 6 the dependency indexes and 5 converts the statuses back to ints
+		_, err = migrator.Down(ctx, 7)
+		require.NoError(t, err)
+		assert.NotContains(t, indexNames(t, db.Collection("notifications")), "user_id_1_key_1")
+		assert.NotContains(t, indexNames(t, tasks), "due_date_1")
+		assert.NotContains(t, indexNames(t, tasks), "series_id_1_occurrence_1")
+		assert.NotContains(t, indexNames(t, db.Collection("attachments")), "task_id_1_created_at_1")
+		assert.NotContains(t, indexNames(t, db.Collection("comments")), "task_id_1_parent_id_1_created_at_1")
+		assert.NotContains(t, indexNames(t, db.Collection("project_members")), "project_id_1_user_id_1")
+		assert.NotContains(t, indexNames(t, tasks), "assignees.id_1_due_date_1")
+		assert.NotContains(t, indexNames(t, db.Collection("task_dependencies")), "blocker_id_1_blocked_id_1")
+		var task bson.M
+		require.NoError(t, tasks.FindOne(ctx, bson.M{"_id": "done-task"}).Decode(&task))
+		assert.EqualValues(t, 2, task["status"], "statuses should be stored as ints again")
+
+		_, err = migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		require.NoError(t, tasks.FindOne(ctx, bson.M{"_id": "done-task"}).Decode(&task))
+		assert.Equal(t, "DONE", task["status"])
+	})
+
+	t.Run("Up stops at the target version", func(t *testing.T) {
+		database := client.Database("targetdb")
+		migrator := mongodb.NewMigrator(client, "targetdb", mongodb.Migrations)
+
+		applied, err := migrator.Up(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, applied, 1)
+		assert.Contains(t, indexNames(t, database.Collection("tasks")), "project_id_1")
+		assert.NotContains(t, indexNames(t, database.Collection("tasks")), "status_1_due_date_1")
+	})
+
+	t.Run("concurrent runs apply each migration once", func(t *testing.T) {
+		var calls int
+		var mu sync.Mutex
+		migrations := []mongodb.Migration{{
+			Version:     1,
+			Description: "slow migration",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				time.Sleep(200 * time.Millisecond)
+				return nil
+			},
+		}}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := mongodb.NewMigrator(client, "concurrentdb", migrations).Up(ctx, 0)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("held lock blocks until the wait expires", func(t *testing.T) {
+		locks := client.Database("lockeddb").Collection(mongodb.MigrationLockCollection)
+		_, err := locks.InsertOne(ctx, bson.M{"_id": "migrations", "owner": "other", "expires_at": time.Now().Add(time.Hour)})
+		require.NoError(t, err)
+
+		migrator := mongodb.NewMigrator(client, "lockeddb", mongodb.Migrations).WithLockWait(100 * time.Millisecond)
+		_, err = migrator.Up(ctx, 0)
+		assert.ErrorIs(t, err, mongodb.ErrMigrationLocked)
+	})
+
+	t.Run("expired lock is taken over", func(t *testing.T) {
+		locks := client.Database("expireddb").Collection(mongodb.MigrationLockCollection)
+		_, err := locks.InsertOne(ctx, bson.M{"_id": "migrations", "owner": "crashed", "expires_at": time.Now().Add(-time.Minute)})
+		require.NoError(t, err)
+
+		migrator := mongodb.NewMigrator(client, "expireddb", mongodb.Migrations).WithLockWait(0)
+		applied, err := migrator.Up(ctx, 0)
+		require.NoError(t, err)
+		assert.Len(t, applied, len(mongodb.Migrations))
+
+		count, err := locks.CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Zero(t, count, "the lock should be released after the run")
+	})
+}
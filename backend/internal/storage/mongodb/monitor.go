@@ -0,0 +1,43 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// CombineMonitors returns a command monitor that forwards every event to all
+// given monitors. The driver accepts only one monitor per client, but metrics
+// and tracing both need to observe commands. Nil monitors are skipped.
+func CombineMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	active := make([]*event.CommandMonitor, 0, len(monitors))
+	for _, monitor := range monitors {
+		if monitor != nil {
+			active = append(active, monitor)
+		}
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, monitor := range active {
+				if monitor.Started != nil {
+					monitor.Started(ctx, evt)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, monitor := range active {
+				if monitor.Succeeded != nil {
+					monitor.Succeeded(ctx, evt)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, monitor := range active {
+				if monitor.Failed != nil {
+					monitor.Failed(ctx, evt)
+				}
+			}
+		},
+	}
+}
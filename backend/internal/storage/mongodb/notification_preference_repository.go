@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+// mongoDbNotificationPreferences is keyed by the user ID, a user has at most one
+type mongoDbNotificationPreferences struct {
+	UserID       string    `bson:"_id"`
+	RemindBefore int       `bson:"remind_before"`
+	Overdue      bool      `bson:"overdue"`
+	Email        bool      `bson:"email"`
+	WebhookURL   string    `bson:"webhook_url,omitempty"`
+	UpdatedAt    time.Time `bson:"updated_at"`
+}
+
+type mongoDbNotificationPreferenceRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationPreferenceRepository(client *mongo.Client, database string) *mongoDbNotificationPreferenceRepository {
+	collection := client.Database(database).Collection("notification_preferences")
+	return &mongoDbNotificationPreferenceRepository{
+		collection: collection,
+	}
+}
+
+// FindByUserIDs returns the stored preferences of the users; users without
+// stored preferences are left out
+func (r *mongoDbNotificationPreferenceRepository) FindByUserIDs(ctx context.Context, userIDs []string) ([]entities.NotificationPreferences, error) {
+	if len(userIDs) == 0 {
+		return []entities.NotificationPreferences{}, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoPreferences []mongoDbNotificationPreferences
+	if err := cursor.All(ctx, &mongoPreferences); err != nil {
+		return nil, err
+	}
+
+	preferences := make([]entities.NotificationPreferences, len(mongoPreferences))
+	for i, p := range mongoPreferences {
+		preferences[i] = entities.NotificationPreferences{
+			UserID:       p.UserID,
+			RemindBefore: p.RemindBefore,
+			Overdue:      p.Overdue,
+			Email:        p.Email,
+			WebhookURL:   p.WebhookURL,
+			UpdatedAt:    p.UpdatedAt,
+		}
+	}
+
+	return preferences, nil
+}
+
+// Upsert stores the preferences of a user, replacing previous ones
+func (r *mongoDbNotificationPreferenceRepository) Upsert(ctx context.Context, preferences *entities.NotificationPreferences) error {
+	if preferences == nil {
+		return errors.New("preferences cannot be nil")
+	}
+	if preferences.UserID == "" {
+		return errors.New("preferences have no user ID")
+	}
+
+	mongoPreferences := mongoDbNotificationPreferences{
+		UserID:       preferences.UserID,
+		RemindBefore: preferences.RemindBefore,
+		Overdue:      preferences.Overdue,
+		Email:        preferences.Email,
+		WebhookURL:   preferences.WebhookURL,
+		UpdatedAt:    time.Now(),
+	}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": preferences.UserID}, mongoPreferences, opts); err != nil {
+		return err
+	}
+
+	preferences.UpdatedAt = mongoPreferences.UpdatedAt
+	return nil
+}
@@ -0,0 +1,279 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbNotification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    string             `bson:"user_id"`
+	Kind      string             `bson:"kind"`
+	Key       string             `bson:"key"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	TaskTitle string             `bson:"task_title"`
+	DueDate   time.Time          `bson:"due_date"`
+	// ReadAt is unset on unread notifications so that they match {read_at: null}
+	ReadAt     *time.Time                    `bson:"read_at,omitempty"`
+	Deliveries []mongoDbNotificationDelivery `bson:"deliveries,omitempty"`
+	CreatedAt  time.Time                     `bson:"created_at"`
+}
+
+type mongoDbNotificationDelivery struct {
+	Channel   string     `bson:"channel"`
+	Target    string     `bson:"target"`
+	Status    string     `bson:"status"`
+	Attempts  int        `bson:"attempts"`
+	LastError string     `bson:"last_error,omitempty"`
+	SentAt    *time.Time `bson:"sent_at,omitempty"`
+}
+
+type mongoDbNotificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewNotificationRepository(client *mongo.Client, database string) *mongoDbNotificationRepository {
+	collection := client.Database(database).Collection("notifications")
+	return &mongoDbNotificationRepository{
+		collection: collection,
+	}
+}
+
+// Insert adds a notification. It reports false without inserting when the user
+// already has a notification with the same key, e.g. because another replica
+// sent the reminder first.
+func (r *mongoDbNotificationRepository) Insert(ctx context.Context, notification *entities.Notification) (bool, error) {
+	if notification == nil {
+		return false, errors.New("notification cannot be nil")
+	}
+
+	mongoNotification, err := toMongoNotification(*notification)
+	if err != nil {
+		return false, err
+	}
+	mongoNotification.ID = primitive.NewObjectID()
+	mongoNotification.CreatedAt = time.Now()
+
+	if _, err := r.collection.InsertOne(ctx, mongoNotification); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	notification.ID = mongoNotification.ID.Hex()
+	notification.CreatedAt = mongoNotification.CreatedAt
+	return true, nil
+}
+
+// FindByUserID returns a page of the notifications of a user, newest first,
+// and the total number of matching notifications
+func (r *mongoDbNotificationRepository) FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetSkip(int64(offset))
+	notifications, err := r.find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *mongoDbNotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+// MarkRead sets or clears the read state of a notification of a user. It
+// reports false if the user has no notification with the ID.
+func (r *mongoDbNotificationRepository) MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.Notification{}, false, nil
+	}
+
+	var update interface{} = bson.M{"$unset": bson.M{"read_at": ""}}
+	if read {
+		// An update pipeline keeps the time a notification was first read
+		update = mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}}},
+		}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var mongoNotification mongoDbNotification
+	err = r.collection.FindOneAndUpdate(ctx, bson.M{"_id": oid, "user_id": userID}, update, opts).Decode(&mongoNotification)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.Notification{}, false, nil
+		}
+		return entities.Notification{}, false, err
+	}
+
+	return fromMongoNotification(mongoNotification), true, nil
+}
+
+// MarkAllRead marks the unread notifications of a user as read and returns their number
+func (r *mongoDbNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "read_at": nil},
+		bson.M{"$set": bson.M{"read_at": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindPending returns up to limit notifications with pending deliveries, oldest first
+func (r *mongoDbNotificationRepository) FindPending(ctx context.Context, limit int) ([]entities.Notification, error) {
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+	return r.find(ctx, bson.M{"deliveries.status": string(entities.DeliveryPending)}, findOptions)
+}
+
+// UpdateDeliveries replaces the delivery states of a notification
+func (r *mongoDbNotificationRepository) UpdateDeliveries(ctx context.Context, id string, deliveries []entities.NotificationDelivery) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid notification ID format")
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"deliveries": toMongoDeliveries(deliveries)}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("no notification found with the given ID")
+	}
+
+	return nil
+}
+
+// DeleteByTaskIDs removes the notifications about the tasks
+func (r *mongoDbNotificationRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": oids}})
+	return err
+}
+
+// DeleteByProjectID removes the notifications about all tasks of a project
+func (r *mongoDbNotificationRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func (r *mongoDbNotificationRepository) find(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]entities.Notification, error) {
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoNotifications []mongoDbNotification
+	if err := cursor.All(ctx, &mongoNotifications); err != nil {
+		return nil, err
+	}
+
+	notifications := make([]entities.Notification, len(mongoNotifications))
+	for i, mongoNotification := range mongoNotifications {
+		notifications[i] = fromMongoNotification(mongoNotification)
+	}
+
+	return notifications, nil
+}
+
+func toMongoNotification(notification entities.Notification) (*mongoDbNotification, error) {
+	oids, err := toObjectIDs([]string{notification.TaskID, notification.ProjectID}, "invalid task or project ID format")
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoDbNotification{
+		UserID:     notification.UserID,
+		Kind:       string(notification.Kind),
+		Key:        notification.Key,
+		TaskID:     oids[0],
+		ProjectID:  oids[1],
+		TaskTitle:  notification.TaskTitle,
+		DueDate:    notification.DueDate,
+		ReadAt:     notification.ReadAt,
+		Deliveries: toMongoDeliveries(notification.Deliveries),
+		CreatedAt:  notification.CreatedAt,
+	}, nil
+}
+
+func toMongoDeliveries(deliveries []entities.NotificationDelivery) []mongoDbNotificationDelivery {
+	var result []mongoDbNotificationDelivery
+	for _, delivery := range deliveries {
+		result = append(result, mongoDbNotificationDelivery{
+			Channel:   string(delivery.Channel),
+			Target:    delivery.Target,
+			Status:    string(delivery.Status),
+			Attempts:  delivery.Attempts,
+			LastError: delivery.LastError,
+			SentAt:    delivery.SentAt,
+		})
+	}
+	return result
+}
+
+func fromMongoNotification(notification mongoDbNotification) entities.Notification {
+	result := entities.Notification{
+		ID:        notification.ID.Hex(),
+		UserID:    notification.UserID,
+		Kind:      entities.NotificationKind(notification.Kind),
+		Key:       notification.Key,
+		TaskID:    notification.TaskID.Hex(),
+		ProjectID: notification.ProjectID.Hex(),
+		TaskTitle: notification.TaskTitle,
+		DueDate:   notification.DueDate,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+
+	for _, delivery := range notification.Deliveries {
+		result.Deliveries = append(result.Deliveries, entities.NotificationDelivery{
+			Channel:   entities.NotificationChannel(delivery.Channel),
+			Target:    delivery.Target,
+			Status:    entities.DeliveryStatus(delivery.Status),
+			Attempts:  delivery.Attempts,
+			LastError: delivery.LastError,
+			SentAt:    delivery.SentAt,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,182 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongoDbNotificationRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	ctx := context.Background()
+	// The unique reminder key index is created by a migration
+	_, err := mongodb.NewMigrator(client, testDBName, mongodb.Migrations).Up(ctx, 0)
+	require.NoError(t, err)
+	repo := mongodb.NewNotificationRepository(client, testDBName)
+
+	projectID := primitive.NewObjectID().Hex()
+	taskID := primitive.NewObjectID().Hex()
+	due := time.Date(2030, 3, 25, 8, 0, 0, 0, time.UTC)
+	notification := func(userID string, kind entities.NotificationKind, deliveries ...entities.NotificationDelivery) *entities.Notification {
+		return &entities.Notification{UserID: userID, Kind: kind, Key: entities.NotificationKey(kind, taskID, due),
+			TaskID: taskID, ProjectID: projectID, TaskTitle: "Renew certificate", DueDate: due, Deliveries: deliveries}
+	}
+
+	dueSoon := notification("jane", entities.NotificationDueSoon, entities.NotificationDelivery{
+		Channel: entities.NotificationChannelEmail, Target: "jane@example.com", Status: entities.DeliveryPending,
+	})
+	inserted, err := repo.Insert(ctx, dueSoon)
+	require.NoError(t, err)
+	require.True(t, inserted)
+	overdue := notification("jane", entities.NotificationOverdue)
+	_, err = repo.Insert(ctx, overdue)
+	require.NoError(t, err)
+	_, err = repo.Insert(ctx, notification("joe", entities.NotificationDueSoon))
+	require.NoError(t, err)
+
+	t.Run("Insert fires each reminder once per user", func(t *testing.T) {
+		duplicate := notification("jane", entities.NotificationDueSoon)
+		inserted, err := repo.Insert(ctx, duplicate)
+		require.NoError(t, err)
+		assert.False(t, inserted)
+		assert.Empty(t, duplicate.ID)
+	})
+
+	t.Run("FindByUserID lists the newest first", func(t *testing.T) {
+		notifications, total, err := repo.FindByUserID(ctx, "jane", false, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, overdue.ID, notifications[0].ID)
+		assert.Equal(t, dueSoon.ID, notifications[1].ID)
+		assert.True(t, due.Equal(notifications[1].DueDate))
+		assert.Equal(t, dueSoon.Deliveries, notifications[1].Deliveries)
+	})
+
+	t.Run("MarkRead is limited to the recipient", func(t *testing.T) {
+		_, found, err := repo.MarkRead(ctx, "joe", dueSoon.ID, true)
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		read, found, err := repo.MarkRead(ctx, "jane", dueSoon.ID, true)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.NotNil(t, read.ReadAt)
+
+		again, _, err := repo.MarkRead(ctx, "jane", dueSoon.ID, true)
+		require.NoError(t, err)
+		assert.True(t, read.ReadAt.Equal(*again.ReadAt), "marking as read again keeps the first read time")
+
+		unread, err := repo.CountUnread(ctx, "jane")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), unread)
+
+		notifications, total, err := repo.FindByUserID(ctx, "jane", true, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, overdue.ID, notifications[0].ID)
+
+		unreadAgain, found, err := repo.MarkRead(ctx, "jane", dueSoon.ID, false)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Nil(t, unreadAgain.ReadAt)
+	})
+
+	t.Run("MarkAllRead", func(t *testing.T) {
+		updated, err := repo.MarkAllRead(ctx, "jane")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated)
+
+		unread, err := repo.CountUnread(ctx, "jane")
+		require.NoError(t, err)
+		assert.Zero(t, unread)
+		unread, err = repo.CountUnread(ctx, "joe")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), unread, "other inboxes are left alone")
+	})
+
+	t.Run("FindPending and UpdateDeliveries", func(t *testing.T) {
+		pending, err := repo.FindPending(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, dueSoon.ID, pending[0].ID)
+
+		sentAt := time.Now().UTC().Truncate(time.Millisecond)
+		deliveries := []entities.NotificationDelivery{{Channel: entities.NotificationChannelEmail, Target: "jane@example.com",
+			Status: entities.DeliverySent, Attempts: 1, SentAt: &sentAt}}
+		require.NoError(t, repo.UpdateDeliveries(ctx, dueSoon.ID, deliveries))
+
+		pending, err = repo.FindPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("DeleteByTaskIDs and DeleteByProjectID cancel pending deliveries", func(t *testing.T) {
+		otherProject := primitive.NewObjectID().Hex()
+		otherTask := primitive.NewObjectID().Hex()
+		other := &entities.Notification{UserID: "jane", Kind: entities.NotificationDueSoon,
+			Key: entities.NotificationKey(entities.NotificationDueSoon, otherTask, due), TaskID: otherTask, ProjectID: otherProject,
+			TaskTitle: "Pay invoice", DueDate: due, Deliveries: []entities.NotificationDelivery{{
+				Channel: entities.NotificationChannelEmail, Target: "jane@example.com", Status: entities.DeliveryPending,
+			}}}
+		_, err := repo.Insert(ctx, other)
+		require.NoError(t, err)
+
+		require.NoError(t, repo.DeleteByTaskIDs(ctx, []string{taskID}))
+		notifications, total, err := repo.FindByUserID(ctx, "jane", false, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, other.ID, notifications[0].ID)
+		_, total, err = repo.FindByUserID(ctx, "joe", false, 10, 0)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+
+		require.NoError(t, repo.DeleteByProjectID(ctx, otherProject))
+		pending, err := repo.FindPending(ctx, 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+}
+
+func TestMongoDbNotificationPreferenceRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	repo := mongodb.NewNotificationPreferenceRepository(client, testDBName)
+	ctx := context.Background()
+
+	preferences := &entities.NotificationPreferences{UserID: "jane", RemindBefore: 24, Overdue: true, Email: true}
+	require.NoError(t, repo.Upsert(ctx, preferences))
+	assert.False(t, preferences.UpdatedAt.IsZero())
+
+	preferences.RemindBefore = 2
+	preferences.WebhookURL = "https://hooks.example.com/jane"
+	require.NoError(t, repo.Upsert(ctx, preferences), "a user has one set of preferences")
+
+	found, err := repo.FindByUserIDs(ctx, []string{"jane", "joe"})
+	require.NoError(t, err)
+	require.Len(t, found, 1, "users without preferences are left out")
+	assert.Equal(t, "jane", found[0].UserID)
+	assert.Equal(t, 2, found[0].RemindBefore)
+	assert.Equal(t, "https://hooks.example.com/jane", found[0].WebhookURL)
+	assert.True(t, found[0].Email)
+}
@@ -15,24 +15,39 @@ type mongoDbProject struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty"`
 	Name        string             `bson:"name"`
 	Description string             `bson:"description,omitempty"`
+	Workflow    *mongoDbWorkflow   `bson:"workflow,omitempty"`
 	CreatedAt   time.Time          `bson:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at"`
 }
 
+type mongoDbWorkflow struct {
+	Statuses    []mongoDbWorkflowStatus     `bson:"statuses"`
+	Transitions []mongoDbWorkflowTransition `bson:"transitions,omitempty"`
+}
+
+type mongoDbWorkflowStatus struct {
+	Key      string `bson:"key"`
+	Name     string `bson:"name"`
+	Category string `bson:"category"`
+}
+
+type mongoDbWorkflowTransition struct {
+	From string `bson:"from"`
+	To   string `bson:"to"`
+}
+
 type mongoDbProjectRepository struct {
 	collection *mongo.Collection
-	ctx        context.Context
 }
 
 func NewProjectRepository(client *mongo.Client, database string) *mongoDbProjectRepository {
 	collection := client.Database(database).Collection("projects")
 	return &mongoDbProjectRepository{
 		collection: collection,
-		ctx:        context.Background(),
 	}
 }
 
-func (r *mongoDbProjectRepository) Insert(project *entities.Project) error {
+func (r *mongoDbProjectRepository) Insert(ctx context.Context, project *entities.Project) error {
 	if project == nil {
 		return errors.New("project cannot be nil")
 	}
@@ -46,11 +61,12 @@ func (r *mongoDbProjectRepository) Insert(project *entities.Project) error {
 		ID:          primitive.NewObjectID(),
 		Name:        project.Name,
 		Description: project.Description,
+		Workflow:    toMongoWorkflow(project.Workflow),
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
 
-	_, err := r.collection.InsertOne(r.ctx, mongoProject)
+	_, err := r.collection.InsertOne(ctx, mongoProject)
 	if err != nil {
 		return err
 	}
@@ -61,7 +77,7 @@ func (r *mongoDbProjectRepository) Insert(project *entities.Project) error {
 	return nil
 }
 
-func (r *mongoDbProjectRepository) Update(project *entities.Project) error {
+func (r *mongoDbProjectRepository) Update(ctx context.Context, project *entities.Project) error {
 	if project == nil {
 		return errors.New("project cannot be nil")
 	}
@@ -79,7 +95,7 @@ func (r *mongoDbProjectRepository) Update(project *entities.Project) error {
 	mongoProject.UpdatedAt = time.Now()
 
 	filter := bson.M{"_id": mongoProject.ID}
-	result, err := r.collection.ReplaceOne(r.ctx, filter, mongoProject)
+	result, err := r.collection.ReplaceOne(ctx, filter, mongoProject)
 	if err != nil {
 		return err
 	}
@@ -91,14 +107,14 @@ func (r *mongoDbProjectRepository) Update(project *entities.Project) error {
 	return nil
 }
 
-func (r *mongoDbProjectRepository) Delete(id string) error {
+func (r *mongoDbProjectRepository) Delete(ctx context.Context, id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
 	filter := bson.M{"_id": oid}
-	result, err := r.collection.DeleteOne(r.ctx, filter)
+	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
@@ -110,7 +126,7 @@ func (r *mongoDbProjectRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *mongoDbProjectRepository) FindByID(id string) (entities.Project, error) {
+func (r *mongoDbProjectRepository) FindByID(ctx context.Context, id string) (entities.Project, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return entities.Project{}, err
@@ -118,7 +134,7 @@ func (r *mongoDbProjectRepository) FindByID(id string) (entities.Project, error)
 
 	filter := bson.M{"_id": oid}
 	var mongoProject mongoDbProject
-	err = r.collection.FindOne(r.ctx, filter).Decode(&mongoProject)
+	err = r.collection.FindOne(ctx, filter).Decode(&mongoProject)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return entities.Project{}, errors.New("project not found")
@@ -129,15 +145,15 @@ func (r *mongoDbProjectRepository) FindByID(id string) (entities.Project, error)
 	return fromMongoProject(mongoProject), nil
 }
 
-func (r *mongoDbProjectRepository) FindAll() ([]entities.Project, error) {
-	cursor, err := r.collection.Find(r.ctx, bson.M{})
+func (r *mongoDbProjectRepository) FindAll(ctx context.Context) ([]entities.Project, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(r.ctx)
+	defer cursor.Close(ctx)
 
 	var mongoProjects []mongoDbProject
-	if err := cursor.All(r.ctx, &mongoProjects); err != nil {
+	if err := cursor.All(ctx, &mongoProjects); err != nil {
 		return nil, err
 	}
 
@@ -149,9 +165,9 @@ func (r *mongoDbProjectRepository) FindAll() ([]entities.Project, error) {
 	return projects, nil
 }
 
-func (r *mongoDbProjectRepository) FindAllPaginated(limit, offset int) ([]entities.Project, int64, error) {
+func (r *mongoDbProjectRepository) FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error) {
 	// Get total count
-	total, err := r.collection.CountDocuments(r.ctx, bson.M{})
+	total, err := r.collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
 		return nil, 0, err
 	}
@@ -162,14 +178,14 @@ func (r *mongoDbProjectRepository) FindAllPaginated(limit, offset int) ([]entiti
 	findOptions.SetSkip(int64(offset))
 	findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}}) // Sort by creation date, newest first
 
-	cursor, err := r.collection.Find(r.ctx, bson.M{}, findOptions)
+	cursor, err := r.collection.Find(ctx, bson.M{}, findOptions)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(r.ctx)
+	defer cursor.Close(ctx)
 
 	var mongoProjects []mongoDbProject
-	if err := cursor.All(r.ctx, &mongoProjects); err != nil {
+	if err := cursor.All(ctx, &mongoProjects); err != nil {
 		return nil, 0, err
 	}
 
@@ -181,8 +197,8 @@ func (r *mongoDbProjectRepository) FindAllPaginated(limit, offset int) ([]entiti
 	return projects, total, nil
 }
 
-func (r *mongoDbProjectRepository) FindByProjectID(projectID string) (entities.Project, error) {
-	return r.FindByID(projectID)
+func (r *mongoDbProjectRepository) FindByProjectID(ctx context.Context, projectID string) (entities.Project, error) {
+	return r.FindByID(ctx, projectID)
 }
 
 func toMongoProject(project entities.Project) (*mongoDbProject, error) {
@@ -202,16 +218,57 @@ func toMongoProject(project entities.Project) (*mongoDbProject, error) {
 		ID:          oid,
 		Name:        project.Name,
 		Description: project.Description,
+		Workflow:    toMongoWorkflow(project.Workflow),
 		CreatedAt:   project.CreatedAt,
 		UpdatedAt:   project.UpdatedAt,
 	}, nil
 }
 
+// toMongoWorkflow returns nil for an empty workflow, which is read back as the default workflow
+func toMongoWorkflow(workflow entities.Workflow) *mongoDbWorkflow {
+	if len(workflow.Statuses) == 0 {
+		return nil
+	}
+
+	mongoWorkflow := &mongoDbWorkflow{Statuses: make([]mongoDbWorkflowStatus, len(workflow.Statuses))}
+	for i, status := range workflow.Statuses {
+		mongoWorkflow.Statuses[i] = mongoDbWorkflowStatus{Key: string(status.Key), Name: status.Name, Category: string(status.Category)}
+	}
+	for _, transition := range workflow.Transitions {
+		mongoWorkflow.Transitions = append(mongoWorkflow.Transitions, mongoDbWorkflowTransition{From: string(transition.From), To: string(transition.To)})
+	}
+	return mongoWorkflow
+}
+
+// fromMongoWorkflow gives projects stored before workflows existed the default workflow
+func fromMongoWorkflow(workflow *mongoDbWorkflow) entities.Workflow {
+	if workflow == nil || len(workflow.Statuses) == 0 {
+		return entities.DefaultWorkflow()
+	}
+
+	result := entities.Workflow{Statuses: make([]entities.WorkflowStatus, len(workflow.Statuses))}
+	for i, status := range workflow.Statuses {
+		result.Statuses[i] = entities.WorkflowStatus{
+			Key:      entities.TaskStatus(status.Key),
+			Name:     status.Name,
+			Category: entities.StatusCategory(status.Category),
+		}
+	}
+	for _, transition := range workflow.Transitions {
+		result.Transitions = append(result.Transitions, entities.WorkflowTransition{
+			From: entities.TaskStatus(transition.From),
+			To:   entities.TaskStatus(transition.To),
+		})
+	}
+	return result
+}
+
 func fromMongoProject(project mongoDbProject) entities.Project {
 	return entities.Project{
 		ID:          project.ID.Hex(),
 		Name:        project.Name,
 		Description: project.Description,
+		Workflow:    fromMongoWorkflow(project.Workflow),
 		CreatedAt:   project.CreatedAt,
 		UpdatedAt:   project.UpdatedAt,
 	}
@@ -84,6 +84,7 @@ func TestMongoDbProjectRepository_Integration(t *testing.T) {
 
 	// Create repository
 	repo := mongodb.NewProjectRepository(client, testDBName)
+	ctx := context.Background()
 
 	// Test data - don't set ID, let Insert generate it
 	testProject := &entities.Project{
@@ -93,12 +94,12 @@ func TestMongoDbProjectRepository_Integration(t *testing.T) {
 
 	t.Run("Insert and FindByID", func(t *testing.T) {
 		// Insert project
-		err := repo.Insert(testProject)
+		err := repo.Insert(ctx, testProject)
 		require.NoError(t, err, "failed to insert project")
 		require.NotEmpty(t, testProject.ID, "expected ID to be set after insert")
 
 		// Find project by ID
-		found, err := repo.FindByID(testProject.ID)
+		found, err := repo.FindByID(ctx, testProject.ID)
 		require.NoError(t, err, "failed to find project by ID")
 		assert.Equal(t, testProject.ID, found.ID)
 		assert.Equal(t, testProject.Name, found.Name)
@@ -120,11 +121,11 @@ func TestMongoDbProjectRepository_Integration(t *testing.T) {
 		// Small delay to ensure UpdatedAt changes
 		time.Sleep(10 * time.Millisecond)
 
-		err := repo.Update(testProject)
+		err := repo.Update(ctx, testProject)
 		require.NoError(t, err, "failed to update project")
 
 		// Verify update
-		found, err := repo.FindByID(testProject.ID)
+		found, err := repo.FindByID(ctx, testProject.ID)
 		require.NoError(t, err)
 		assert.Equal(t, updatedName, found.Name)
 		assert.Equal(t, updatedDesc, found.Description)
@@ -133,25 +134,25 @@ func TestMongoDbProjectRepository_Integration(t *testing.T) {
 
 	t.Run("FindAll", func(t *testing.T) {
 		// Find all projects
-		projects, err := repo.FindAll()
+		projects, err := repo.FindAll(ctx)
 		require.NoError(t, err, "failed to find all projects")
 		assert.GreaterOrEqual(t, len(projects), 1, "expected at least one project")
 	})
 
 	t.Run("Delete", func(t *testing.T) {
 		// Delete project
-		err := repo.Delete(testProject.ID)
+		err := repo.Delete(ctx, testProject.ID)
 		require.NoError(t, err, "failed to delete project")
 
 		// Verify deletion
-		_, err = repo.FindByID(testProject.ID)
+		_, err = repo.FindByID(ctx, testProject.ID)
 		require.Error(t, err, "expected error when finding deleted project")
 	})
 
 	t.Run("FindByID_NonExistent", func(t *testing.T) {
 		// Try to find non-existent project
 		nonExistentID := primitive.NewObjectID().Hex()
-		_, err := repo.FindByID(nonExistentID)
+		_, err := repo.FindByID(ctx, nonExistentID)
 		require.Error(t, err, "expected error for non-existent project")
 	})
 
@@ -162,14 +163,14 @@ func TestMongoDbProjectRepository_Integration(t *testing.T) {
 			Name:        "Non-existent Project",
 			Description: "This project does not exist",
 		}
-		err := repo.Update(nonExistentProject)
+		err := repo.Update(ctx, nonExistentProject)
 		require.Error(t, err, "expected error when updating non-existent project")
 	})
 
 	t.Run("Delete_NonExistent", func(t *testing.T) {
 		// Try to delete non-existent project
 		nonExistentID := primitive.NewObjectID().Hex()
-		err := repo.Delete(nonExistentID)
+		err := repo.Delete(ctx, nonExistentID)
 		require.Error(t, err, "expected error when updating non-existent project")
 	})
 }
@@ -4,37 +4,99 @@ import (
 	"boilerplate/internal/entities"
 	"context"
 	"errors"
+	"fmt"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"time"
 )
 
 type MongoDbTask struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty"`
-	ProjectID   primitive.ObjectID `bson:"project_id,omitempty"`
-	Title       string             `bson:"title"`
-	Status      int                `bson:"status"` // Store as int for efficiency
-	DueDate     *time.Time         `bson:"due_date,omitempty"`
-	Description string             `bson:"description,omitempty"`
-	CreatedAt   time.Time          `bson:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at"`
+	ID          primitive.ObjectID   `bson:"_id,omitempty"`
+	ProjectID   primitive.ObjectID   `bson:"project_id,omitempty"`
+	ParentID    primitive.ObjectID   `bson:"parent_id,omitempty"`
+	Title       string               `bson:"title"`
+	Status      MongoDbTaskStatus    `bson:"status"`
+	Priority    int                  `bson:"priority"` // Store as int so that sorting follows the rank
+	DueDate     *time.Time           `bson:"due_date,omitempty"`
+	Description string               `bson:"description,omitempty"`
+	LabelIDs    []primitive.ObjectID `bson:"label_ids,omitempty"`
+	// CustomFields is keyed by the hex ID of the custom field
+	CustomFields bson.M             `bson:"custom_fields,omitempty"`
+	Assignees    []mongoDbUserRef   `bson:"assignees,omitempty"`
+	Watchers     []mongoDbUserRef   `bson:"watchers,omitempty"`
+	Recurrence   *mongoDbRecurrence `bson:"recurrence,omitempty"`
+	SeriesID     string             `bson:"series_id,omitempty"`
+	Occurrence   *time.Time         `bson:"occurrence,omitempty"`
+	Estimate     int64              `bson:"estimate,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at"`
+}
+
+type mongoDbRecurrence struct {
+	RRule    string    `bson:"rrule"`
+	Timezone string    `bson:"timezone"`
+	Start    time.Time `bson:"start"`
+}
+
+// mongoDbUserRef stores a user reference; ID is the Keycloak subject, not an ObjectID
+type mongoDbUserRef struct {
+	ID    string `bson:"id"`
+	Name  string `bson:"name,omitempty"`
+	Email string `bson:"email,omitempty"`
+}
+
+// MongoDbTaskStatus stores the workflow status key of a task. Before projects
+// had workflows, the default statuses were stored as 0 (TODO), 1 (IN_PROGRESS)
+// and 2 (DONE); such documents are still decoded, and migration 5 rewrites them.
+type MongoDbTaskStatus string
+
+// legacyTaskStatuses maps the former int encoding to status keys
+var legacyTaskStatuses = []entities.TaskStatus{
+	entities.TaskStatusTodo,
+	entities.TaskStatusInProgress,
+	entities.TaskStatusDone,
+}
+
+func (s *MongoDbTaskStatus) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	value := bson.RawValue{Type: t, Value: data}
+	if key, ok := value.StringValueOK(); ok {
+		*s = MongoDbTaskStatus(key)
+		return nil
+	}
+
+	var legacy int64
+	switch t {
+	case bsontype.Int32:
+		legacy = int64(value.Int32())
+	case bsontype.Int64:
+		legacy = value.Int64()
+	case bsontype.Double:
+		legacy = int64(value.Double())
+	default:
+		return fmt.Errorf("cannot decode task status from BSON type %s", t)
+	}
+	if legacy < 0 || legacy >= int64(len(legacyTaskStatuses)) {
+		return fmt.Errorf("unknown legacy task status %d", legacy)
+	}
+	*s = MongoDbTaskStatus(legacyTaskStatuses[legacy])
+	return nil
 }
 
 type mongoDbTaskRepository struct {
 	collection *mongo.Collection
-	ctx        context.Context
 }
 
 func NewTaskRepository(client *mongo.Client, database string) *mongoDbTaskRepository {
 	collection := client.Database(database).Collection("tasks")
 	return &mongoDbTaskRepository{
 		collection: collection,
-		ctx:        context.Background(),
 	}
 }
 
-func (r *mongoDbTaskRepository) Insert(task *entities.Task) error {
+func (r *mongoDbTaskRepository) Insert(ctx context.Context, task *entities.Task) error {
 	if task == nil {
 		return errors.New("task cannot be nil")
 	}
@@ -43,27 +105,15 @@ func (r *mongoDbTaskRepository) Insert(task *entities.Task) error {
 		return errors.New("task already has an ID, use Update instead")
 	}
 
-	now := time.Now()
-	mongoTask := &MongoDbTask{
-		ID:          primitive.NewObjectID(),
-		Title:       task.Title,
-		Status:      int(task.Status),
-		DueDate:     task.DueDate,
-		Description: task.Description,
-		CreatedAt:   now,
-		UpdatedAt:   now,
-	}
-
-	if task.ProjectID != "" {
-		projectOid, err := primitive.ObjectIDFromHex(task.ProjectID)
-		if err != nil {
-			return errors.New("invalid project ID format")
-		}
-		mongoTask.ProjectID = projectOid
+	mongoTask, err := toMongo(*task)
+	if err != nil {
+		return err
 	}
+	now := time.Now()
+	mongoTask.CreatedAt = now
+	mongoTask.UpdatedAt = now
 
-	_, err := r.collection.InsertOne(r.ctx, mongoTask)
-	if err != nil {
+	if _, err := r.collection.InsertOne(ctx, mongoTask); err != nil {
 		return err
 	}
 
@@ -73,7 +123,7 @@ func (r *mongoDbTaskRepository) Insert(task *entities.Task) error {
 	return nil
 }
 
-func (r *mongoDbTaskRepository) Update(task *entities.Task) error {
+func (r *mongoDbTaskRepository) Update(ctx context.Context, task *entities.Task) error {
 	if task == nil {
 		return errors.New("task cannot be nil")
 	}
@@ -91,7 +141,7 @@ func (r *mongoDbTaskRepository) Update(task *entities.Task) error {
 	mongoTask.UpdatedAt = time.Now()
 
 	filter := bson.M{"_id": mongoTask.ID}
-	result, err := r.collection.ReplaceOne(r.ctx, filter, mongoTask)
+	result, err := r.collection.ReplaceOne(ctx, filter, mongoTask)
 	if err != nil {
 		return err
 	}
@@ -103,14 +153,71 @@ func (r *mongoDbTaskRepository) Update(task *entities.Task) error {
 	return nil
 }
 
-func (r *mongoDbTaskRepository) Delete(id string) error {
+// InsertOccurrence inserts the next occurrence of a recurring task. It reports
+// false without inserting when the series already has the occurrence, e.g.
+// because another replica generated it first.
+func (r *mongoDbTaskRepository) InsertOccurrence(ctx context.Context, task *entities.Task) (bool, error) {
+	if task != nil && (task.SeriesID == "" || task.Occurrence == nil) {
+		return false, errors.New("task is not an occurrence of a series")
+	}
+	if err := r.Insert(ctx, task); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ClearRecurrence removes the recurrence from a task, leaving the other fields untouched
+func (r *mongoDbTaskRepository) ClearRecurrence(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid ID format")
+	}
+
+	update := bson.M{"$unset": bson.M{"recurrence": ""}, "$set": bson.M{"updated_at": time.Now()}}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	return err
+}
+
+// FindRecurring returns the latest occurrence of every series that still has a recurrence
+func (r *mongoDbTaskRepository) FindRecurring(ctx context.Context) ([]entities.Task, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"series_id": bson.M{"$exists": true}}}},
+		// Walks the series_id_1_occurrence_1 index backwards
+		{{Key: "$sort", Value: bson.D{{Key: "series_id", Value: -1}, {Key: "occurrence", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$series_id", "latest": bson.M{"$first": "$$ROOT"}}}},
+		{{Key: "$replaceWith", Value: "$latest"}},
+		{{Key: "$match", Value: bson.M{"recurrence": bson.M{"$exists": true}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoTasks []MongoDbTask
+	if err := cursor.All(ctx, &mongoTasks); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]entities.Task, len(mongoTasks))
+	for i, mongoTask := range mongoTasks {
+		tasks[i] = fromMongo(mongoTask)
+	}
+
+	return tasks, nil
+}
+
+func (r *mongoDbTaskRepository) Delete(ctx context.Context, id string) error {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
 	filter := bson.M{"_id": oid}
-	result, err := r.collection.DeleteOne(r.ctx, filter)
+	result, err := r.collection.DeleteOne(ctx, filter)
 	if err != nil {
 		return err
 	}
@@ -122,7 +229,7 @@ func (r *mongoDbTaskRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *mongoDbTaskRepository) FindByID(id string) (entities.Task, error) {
+func (r *mongoDbTaskRepository) FindByID(ctx context.Context, id string) (entities.Task, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return entities.Task{}, err
@@ -130,7 +237,7 @@ func (r *mongoDbTaskRepository) FindByID(id string) (entities.Task, error) {
 
 	filter := bson.M{"_id": oid}
 	var mongoTask MongoDbTask
-	err = r.collection.FindOne(r.ctx, filter).Decode(&mongoTask)
+	err = r.collection.FindOne(ctx, filter).Decode(&mongoTask)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return entities.Task{}, errors.New("task not found")
@@ -141,15 +248,15 @@ func (r *mongoDbTaskRepository) FindByID(id string) (entities.Task, error) {
 	return fromMongo(mongoTask), nil
 }
 
-func (r *mongoDbTaskRepository) FindAll() ([]entities.Task, error) {
-	cursor, err := r.collection.Find(r.ctx, bson.M{})
+func (r *mongoDbTaskRepository) FindAll(ctx context.Context) ([]entities.Task, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{})
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(r.ctx)
+	defer cursor.Close(ctx)
 
 	var mongoTasks []MongoDbTask
-	if err := cursor.All(r.ctx, &mongoTasks); err != nil {
+	if err := cursor.All(ctx, &mongoTasks); err != nil {
 		return nil, err
 	}
 
@@ -161,21 +268,89 @@ func (r *mongoDbTaskRepository) FindAll() ([]entities.Task, error) {
 	return tasks, nil
 }
 
-func (r *mongoDbTaskRepository) FindByProjectID(projectID string) ([]entities.Task, error) {
+func (r *mongoDbTaskRepository) FindByProjectID(ctx context.Context, projectID string, taskFilter entities.TaskFilter) ([]entities.Task, error) {
 	projectOid, err := primitive.ObjectIDFromHex(projectID)
 	if err != nil {
 		return nil, errors.New("invalid project ID format")
 	}
 
+	filter, err := toMongoFilter(projectOid, taskFilter)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoTasks []MongoDbTask
+	if err := cursor.All(ctx, &mongoTasks); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]entities.Task, len(mongoTasks))
+	for i, mongoTask := range mongoTasks {
+		tasks[i] = fromMongo(mongoTask)
+	}
+
+	return tasks, nil
+}
+
+// toMongoFilter translates a task filter into a query on the tasks of a project
+func toMongoFilter(projectOid primitive.ObjectID, taskFilter entities.TaskFilter) (bson.M, error) {
 	filter := bson.M{"project_id": projectOid}
-	cursor, err := r.collection.Find(r.ctx, filter)
+	if len(taskFilter.IDs) > 0 {
+		oids := make([]primitive.ObjectID, 0, len(taskFilter.IDs))
+		for _, id := range taskFilter.IDs {
+			if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+				oids = append(oids, oid)
+			}
+		}
+		filter["_id"] = bson.M{"$in": oids}
+	}
+	if taskFilter.Status != nil {
+		filter["status"] = string(*taskFilter.Status)
+	}
+	if len(taskFilter.Priorities) > 0 {
+		priorities := make([]int, len(taskFilter.Priorities))
+		for i, priority := range taskFilter.Priorities {
+			priorities[i] = int(priority)
+		}
+		filter["priority"] = bson.M{"$in": priorities}
+	}
+	if len(taskFilter.LabelIDs) > 0 {
+		labelOids, err := toObjectIDs(taskFilter.LabelIDs, "invalid label ID format")
+		if err != nil {
+			return nil, err
+		}
+		filter["label_ids"] = bson.M{"$all": labelOids}
+	}
+	for fieldID, value := range taskFilter.CustomFields {
+		filter["custom_fields."+fieldID] = value
+	}
+	if taskFilter.AssigneeID != "" {
+		filter["assignees.id"] = taskFilter.AssigneeID
+	}
+	return filter, nil
+}
+
+// FindByAssignee returns the tasks of all projects assigned to a user, earliest due date first
+func (r *mongoDbTaskRepository) FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error) {
+	// Tasks without a due date sort before all others in MongoDB, so they are moved to the end
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"assignees.id": userID}}},
+		{{Key: "$addFields", Value: bson.M{"no_due_date": bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$due_date", nil}}, nil}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "no_due_date", Value: 1}, {Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(r.ctx)
+	defer cursor.Close(ctx)
 
 	var mongoTasks []MongoDbTask
-	if err := cursor.All(r.ctx, &mongoTasks); err != nil {
+	if err := cursor.All(ctx, &mongoTasks); err != nil {
 		return nil, err
 	}
 
@@ -187,6 +362,377 @@ func (r *mongoDbTaskRepository) FindByProjectID(projectID string) ([]entities.Ta
 	return tasks, nil
 }
 
+// FindDue returns the assigned tasks of all projects due between from and to, earliest due date first
+func (r *mongoDbTaskRepository) FindDue(ctx context.Context, from, to time.Time) ([]entities.Task, error) {
+	filter := bson.M{
+		"due_date":    bson.M{"$gte": from, "$lte": to},
+		"assignees.0": bson.M{"$exists": true},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoTasks []MongoDbTask
+	if err := cursor.All(ctx, &mongoTasks); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]entities.Task, len(mongoTasks))
+	for i, mongoTask := range mongoTasks {
+		tasks[i] = fromMongo(mongoTask)
+	}
+
+	return tasks, nil
+}
+
+// CountByStatus counts the tasks of a project per status
+func (r *mongoDbTaskRepository) CountByStatus(ctx context.Context, projectID string) (map[entities.TaskStatus]int, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"project_id": projectOid}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status MongoDbTaskStatus `bson:"_id"`
+		Count  int               `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entities.TaskStatus]int, len(groups))
+	for _, group := range groups {
+		// Legacy and migrated documents of the same status form separate groups
+		counts[entities.TaskStatus(group.Status)] += group.Count
+	}
+	return counts, nil
+}
+
+// CountByCustomFieldValue counts the tasks of a project per value of a custom
+// field; values that are not text, e.g. of number fields, are left out
+func (r *mongoDbTaskRepository) CountByCustomFieldValue(ctx context.Context, projectID, fieldID string) (map[string]int, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	key := "custom_fields." + fieldID
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"project_id": projectOid, key: bson.M{"$type": "string"}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + key, "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Value string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(groups))
+	for _, group := range groups {
+		counts[group.Value] = group.Count
+	}
+	return counts, nil
+}
+
+// RemoveLabel removes a label from every task of the project
+func (r *mongoDbTaskRepository) RemoveLabel(ctx context.Context, projectID, labelID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+	labelOid, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return errors.New("invalid label ID format")
+	}
+
+	filter := bson.M{"project_id": projectOid, "label_ids": labelOid}
+	update := bson.M{"$pull": bson.M{"label_ids": labelOid}, "$set": bson.M{"updated_at": time.Now()}}
+	_, err = r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// RemoveCustomField removes the value of a custom field from every task of the project
+func (r *mongoDbTaskRepository) RemoveCustomField(ctx context.Context, projectID, fieldID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	key := "custom_fields." + fieldID
+	filter := bson.M{"project_id": projectOid, key: bson.M{"$exists": true}}
+	update := bson.M{"$unset": bson.M{key: ""}, "$set": bson.M{"updated_at": time.Now()}}
+	_, err = r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+func (r *mongoDbTaskRepository) InsertMany(ctx context.Context, tasks []*entities.Task) ([]error, error) {
+	errs := make([]error, len(tasks))
+	if len(tasks) == 0 {
+		return errs, nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(tasks))
+	docIndex := make([]int, 0, len(tasks))
+	mongoTasks := make([]*MongoDbTask, len(tasks))
+	for i, task := range tasks {
+		if task == nil {
+			errs[i] = errors.New("task cannot be nil")
+			continue
+		}
+		if task.ID != "" {
+			errs[i] = errors.New("task already has an ID, use Update instead")
+			continue
+		}
+
+		mongoTask, err := toMongo(*task)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		mongoTask.CreatedAt = now
+		mongoTask.UpdatedAt = now
+
+		mongoTasks[i] = mongoTask
+		docs = append(docs, mongoTask)
+		docIndex = append(docIndex, i)
+	}
+
+	if len(docs) == 0 {
+		return errs, nil
+	}
+
+	_, err := r.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err := mapBulkWriteErrors(err, docIndex, errs); err != nil {
+		return nil, err
+	}
+
+	for i, mongoTask := range mongoTasks {
+		if mongoTask == nil || errs[i] != nil {
+			continue
+		}
+		tasks[i].ID = mongoTask.ID.Hex()
+		tasks[i].CreatedAt = mongoTask.CreatedAt
+		tasks[i].UpdatedAt = mongoTask.UpdatedAt
+	}
+
+	return errs, nil
+}
+
+func (r *mongoDbTaskRepository) UpdateMany(ctx context.Context, projectID string, patches []entities.TaskPatch) ([]error, error) {
+	errs := make([]error, len(patches))
+	if len(patches) == 0 {
+		return errs, nil
+	}
+
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	ids := make([]string, len(patches))
+	for i, patch := range patches {
+		ids[i] = patch.ID
+	}
+	oids, err := r.resolveProjectTaskIDs(ctx, projectOid, ids, errs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(patches))
+	modelIndex := make([]int, 0, len(patches))
+	for i, patch := range patches {
+		if errs[i] != nil {
+			continue
+		}
+
+		set := bson.M{"updated_at": now}
+		if patch.Title != nil {
+			set["title"] = *patch.Title
+		}
+		if patch.Status != nil {
+			set["status"] = string(*patch.Status)
+		}
+		if patch.Priority != nil {
+			set["priority"] = int(*patch.Priority)
+		}
+		if patch.DueDate != nil {
+			set["due_date"] = *patch.DueDate
+		}
+		if patch.Description != nil {
+			set["description"] = *patch.Description
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": oids[i], "project_id": projectOid}).
+			SetUpdate(bson.M{"$set": set}))
+		modelIndex = append(modelIndex, i)
+	}
+
+	if len(models) == 0 {
+		return errs, nil
+	}
+
+	_, err = r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err := mapBulkWriteErrors(err, modelIndex, errs); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+func (r *mongoDbTaskRepository) DeleteMany(ctx context.Context, projectID string, ids []string) ([]error, error) {
+	errs := make([]error, len(ids))
+	if len(ids) == 0 {
+		return errs, nil
+	}
+
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return nil, errors.New("invalid project ID format")
+	}
+
+	oids, err := r.resolveProjectTaskIDs(ctx, projectOid, ids, errs)
+	if err != nil {
+		return nil, err
+	}
+
+	toDelete := make([]primitive.ObjectID, 0, len(ids))
+	for i, oid := range oids {
+		if errs[i] == nil {
+			toDelete = append(toDelete, oid)
+		}
+	}
+
+	if len(toDelete) == 0 {
+		return errs, nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": toDelete}, "project_id": projectOid}
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return errs, nil
+}
+
+// RemoveUser unassigns a user from all tasks of a project and stops them watching
+func (r *mongoDbTaskRepository) RemoveUser(ctx context.Context, projectID, userID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	filter := bson.M{"project_id": projectOid, "$or": bson.A{bson.M{"assignees.id": userID}, bson.M{"watchers.id": userID}}}
+	update := bson.M{
+		"$pull": bson.M{"assignees": bson.M{"id": userID}, "watchers": bson.M{"id": userID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	_, err = r.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// DeleteByProjectID removes all tasks of a project
+func (r *mongoDbTaskRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+// resolveProjectTaskIDs parses the given IDs and checks with a single query which
+// of them belong to the project. Unknown or malformed IDs are recorded in errs.
+func (r *mongoDbTaskRepository) resolveProjectTaskIDs(ctx context.Context, projectOid primitive.ObjectID, ids []string, errs []error) ([]primitive.ObjectID, error) {
+	oids := make([]primitive.ObjectID, len(ids))
+	lookup := make([]primitive.ObjectID, 0, len(ids))
+	for i, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			errs[i] = errors.New("invalid task ID format")
+			continue
+		}
+		oids[i] = oid
+		lookup = append(lookup, oid)
+	}
+
+	if len(lookup) == 0 {
+		return oids, nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": lookup}, "project_id": projectOid}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[primitive.ObjectID]bool, len(found))
+	for _, doc := range found {
+		existing[doc.ID] = true
+	}
+
+	for i, oid := range oids {
+		if errs[i] == nil && !existing[oid] {
+			errs[i] = errors.New("task not found")
+		}
+	}
+
+	return oids, nil
+}
+
+// mapBulkWriteErrors copies per-document write errors back to the caller's
+// positions. Errors that are not tied to a single document are returned.
+func mapBulkWriteErrors(err error, index []int, errs []error) error {
+	if err == nil {
+		return nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return err
+	}
+
+	for _, writeErr := range bulkErr.WriteErrors {
+		if writeErr.Index >= 0 && writeErr.Index < len(index) {
+			errs[index[writeErr.Index]] = errors.New(writeErr.Message)
+		}
+	}
+
+	return nil
+}
+
 func toMongo(task entities.Task) (*MongoDbTask, error) {
 	var oid primitive.ObjectID
 	var err error
@@ -208,32 +754,154 @@ func toMongo(task entities.Task) (*MongoDbTask, error) {
 		}
 	}
 
+	var parentOid primitive.ObjectID
+	if task.ParentID != "" {
+		parentOid, err = primitive.ObjectIDFromHex(task.ParentID)
+		if err != nil {
+			return nil, errors.New("invalid parent ID format")
+		}
+	}
+
+	labelOids, err := toObjectIDs(task.LabelIDs, "invalid label ID format")
+	if err != nil {
+		return nil, err
+	}
+
+	var customFields bson.M
+	if len(task.CustomFields) > 0 {
+		customFields = make(bson.M, len(task.CustomFields))
+		for fieldID, value := range task.CustomFields {
+			customFields[fieldID] = value
+		}
+	}
+
 	return &MongoDbTask{
-		ID:          oid,
-		ProjectID:   projectOid,
-		Title:       task.Title,
-		Status:      int(task.Status),
-		DueDate:     task.DueDate,
-		Description: task.Description,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+		ID:           oid,
+		ProjectID:    projectOid,
+		ParentID:     parentOid,
+		Title:        task.Title,
+		Status:       MongoDbTaskStatus(task.Status),
+		Priority:     int(task.Priority),
+		DueDate:      task.DueDate,
+		Description:  task.Description,
+		LabelIDs:     labelOids,
+		CustomFields: customFields,
+		Assignees:    toMongoUserRefs(task.Assignees),
+		Watchers:     toMongoUserRefs(task.Watchers),
+		Recurrence:   toMongoRecurrence(task.Recurrence),
+		SeriesID:     task.SeriesID,
+		Occurrence:   task.Occurrence,
+		Estimate:     task.Estimate,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
 	}, nil
 }
 
+func toMongoRecurrence(recurrence *entities.TaskRecurrence) *mongoDbRecurrence {
+	if recurrence == nil {
+		return nil
+	}
+	return &mongoDbRecurrence{RRule: recurrence.RRule, Timezone: recurrence.Timezone, Start: recurrence.Start}
+}
+
+func fromMongoRecurrence(recurrence *mongoDbRecurrence) *entities.TaskRecurrence {
+	if recurrence == nil {
+		return nil
+	}
+	return &entities.TaskRecurrence{RRule: recurrence.RRule, Timezone: recurrence.Timezone, Start: recurrence.Start.UTC()}
+}
+
+func toMongoUserRefs(users []entities.UserRef) []mongoDbUserRef {
+	if len(users) == 0 {
+		return nil
+	}
+	refs := make([]mongoDbUserRef, len(users))
+	for i, user := range users {
+		refs[i] = mongoDbUserRef{ID: user.ID, Name: user.Name, Email: user.Email}
+	}
+	return refs
+}
+
+func fromMongoUserRefs(refs []mongoDbUserRef) []entities.UserRef {
+	users := make([]entities.UserRef, len(refs))
+	for i, ref := range refs {
+		users[i] = entities.UserRef{ID: ref.ID, Name: ref.Name, Email: ref.Email}
+	}
+	return users
+}
+
+// toObjectIDs parses hex IDs, returning message as error for malformed ones
+func toObjectIDs(ids []string, message string) ([]primitive.ObjectID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	oids := make([]primitive.ObjectID, len(ids))
+	for i, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, errors.New(message)
+		}
+		oids[i] = oid
+	}
+	return oids, nil
+}
+
+// fromMongoValue converts a decoded custom field value to the type used by entities.CustomFieldValues
+func fromMongoValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case primitive.DateTime:
+		return v.Time().UTC()
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
 func fromMongo(task MongoDbTask) entities.Task {
 	var projectID string
 	if !task.ProjectID.IsZero() {
 		projectID = task.ProjectID.Hex()
 	}
 
+	var parentID string
+	if !task.ParentID.IsZero() {
+		parentID = task.ParentID.Hex()
+	}
+
+	labelIDs := make([]string, len(task.LabelIDs))
+	for i, labelOid := range task.LabelIDs {
+		labelIDs[i] = labelOid.Hex()
+	}
+
+	var customFields entities.CustomFieldValues
+	if len(task.CustomFields) > 0 {
+		customFields = make(entities.CustomFieldValues, len(task.CustomFields))
+		for fieldID, value := range task.CustomFields {
+			customFields[fieldID] = fromMongoValue(value)
+		}
+	}
+
 	return entities.Task{
-		ID:          task.ID.Hex(),
-		ProjectID:   projectID,
-		Title:       task.Title,
-		Status:      entities.TaskStatus(task.Status), // Convert int back to TaskStatus
-		DueDate:     task.DueDate,
-		Description: task.Description,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+		ID:           task.ID.Hex(),
+		ProjectID:    projectID,
+		ParentID:     parentID,
+		Title:        task.Title,
+		Status:       entities.TaskStatus(task.Status),
+		Priority:     entities.TaskPriority(task.Priority),
+		DueDate:      task.DueDate,
+		Description:  task.Description,
+		LabelIDs:     labelIDs,
+		CustomFields: customFields,
+		Assignees:    fromMongoUserRefs(task.Assignees),
+		Watchers:     fromMongoUserRefs(task.Watchers),
+		Recurrence:   fromMongoRecurrence(task.Recurrence),
+		SeriesID:     task.SeriesID,
+		Occurrence:   task.Occurrence,
+		Estimate:     task.Estimate,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
 	}
 }
@@ -0,0 +1,306 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongoDbTaskStatus_DecodesLegacyInts(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   interface{}
+		expected entities.TaskStatus
+	}{
+		{name: "key", status: "IN_REVIEW", expected: "IN_REVIEW"},
+		{name: "int32", status: int32(0), expected: entities.TaskStatusTodo},
+		{name: "int64", status: int64(1), expected: entities.TaskStatusInProgress},
+		{name: "double", status: float64(2), expected: entities.TaskStatusDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(bson.M{"status": tt.status})
+			require.NoError(t, err)
+
+			var task mongodb.MongoDbTask
+			require.NoError(t, bson.Unmarshal(data, &task))
+			assert.Equal(t, mongodb.MongoDbTaskStatus(tt.expected), task.Status)
+		})
+	}
+
+	data, err := bson.Marshal(bson.M{"status": int32(7)})
+	require.NoError(t, err)
+	var task mongodb.MongoDbTask
+	assert.Error(t, bson.Unmarshal(data, &task), "unknown legacy statuses should not decode")
+}
+
+func TestMongoDbTaskRepository_BulkIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	// Set up MongoDB container
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	repo := mongodb.NewTaskRepository(client, testDBName)
+	ctx := context.Background()
+
+	projectID := primitive.NewObjectID().Hex()
+	otherProjectID := primitive.NewObjectID().Hex()
+
+	tasks := []*entities.Task{
+		{ProjectID: projectID, Title: "First", Status: entities.TaskStatusTodo},
+		{ProjectID: projectID, Title: "Second", Status: entities.TaskStatusTodo},
+		{ProjectID: otherProjectID, Title: "Other project", Status: entities.TaskStatusTodo},
+	}
+
+	t.Run("InsertMany", func(t *testing.T) {
+		errs, err := repo.InsertMany(ctx, tasks)
+		require.NoError(t, err)
+		require.Len(t, errs, 3)
+		for i, task := range tasks {
+			assert.NoError(t, errs[i])
+			assert.NotEmpty(t, task.ID, "expected ID to be set after insert")
+			assert.False(t, task.CreatedAt.IsZero(), "expected CreatedAt to be set")
+		}
+	})
+
+	t.Run("UpdateMany", func(t *testing.T) {
+		title := "First renamed"
+		done := entities.TaskStatusDone
+		patches := []entities.TaskPatch{
+			{ID: tasks[0].ID, Title: &title, Status: &done},
+			{ID: tasks[2].ID, Status: &done}, // belongs to another project
+			{ID: "not-an-id", Status: &done},
+		}
+
+		errs, err := repo.UpdateMany(ctx, projectID, patches)
+		require.NoError(t, err)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1], "expected tasks of other projects to be rejected")
+		assert.Error(t, errs[2], "expected malformed IDs to be rejected")
+
+		found, err := repo.FindByID(ctx, tasks[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, title, found.Title)
+		assert.Equal(t, entities.TaskStatusDone, found.Status)
+
+		other, err := repo.FindByID(ctx, tasks[2].ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.TaskStatusTodo, other.Status)
+	})
+
+	t.Run("DeleteMany", func(t *testing.T) {
+		ids := []string{tasks[0].ID, tasks[1].ID, primitive.NewObjectID().Hex()}
+
+		errs, err := repo.DeleteMany(ctx, projectID, ids)
+		require.NoError(t, err)
+		assert.NoError(t, errs[0])
+		assert.NoError(t, errs[1])
+		assert.Error(t, errs[2], "expected unknown task to be reported")
+
+		remaining, err := repo.FindByProjectID(ctx, projectID, entities.TaskFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, remaining)
+	})
+}
+
+func TestMongoDbTaskRepository_FilterIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	repo := mongodb.NewTaskRepository(client, testDBName)
+	ctx := context.Background()
+
+	projectID := primitive.NewObjectID().Hex()
+	bug := primitive.NewObjectID().Hex()
+	frontend := primitive.NewObjectID().Hex()
+	points := primitive.NewObjectID().Hex()
+	release := primitive.NewObjectID().Hex()
+	team := primitive.NewObjectID().Hex()
+	releaseDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tasks := []*entities.Task{
+		{ProjectID: projectID, Title: "Urgent bug", Priority: entities.TaskPriorityUrgent, LabelIDs: []string{bug, frontend},
+			CustomFields: entities.CustomFieldValues{points: 3.0, release: releaseDate, team: "web"}},
+		{ProjectID: projectID, Title: "Low bug", Priority: entities.TaskPriorityLow, LabelIDs: []string{bug},
+			CustomFields: entities.CustomFieldValues{team: "web"}},
+		{ProjectID: projectID, Title: "Unlabelled", Status: entities.TaskStatusDone},
+	}
+	for _, task := range tasks {
+		require.NoError(t, repo.Insert(ctx, task))
+	}
+
+	titles := func(filter entities.TaskFilter) []string {
+		found, err := repo.FindByProjectID(ctx, projectID, filter)
+		require.NoError(t, err)
+		var titles []string
+		for _, task := range found {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+
+	done := entities.TaskStatusDone
+	assert.ElementsMatch(t, []string{"Unlabelled"}, titles(entities.TaskFilter{Status: &done}))
+	assert.ElementsMatch(t, []string{"Urgent bug", "Low bug"}, titles(entities.TaskFilter{Priorities: []entities.TaskPriority{entities.TaskPriorityUrgent, entities.TaskPriorityLow}}))
+	assert.ElementsMatch(t, []string{"Urgent bug"}, titles(entities.TaskFilter{LabelIDs: []string{bug, frontend}}))
+	assert.ElementsMatch(t, []string{"Urgent bug"}, titles(entities.TaskFilter{CustomFields: entities.CustomFieldValues{points: 3.0}}))
+	assert.ElementsMatch(t, []string{"Urgent bug"}, titles(entities.TaskFilter{CustomFields: entities.CustomFieldValues{release: releaseDate}}))
+
+	found, err := repo.FindByID(ctx, tasks[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.TaskPriorityUrgent, found.Priority)
+	assert.Equal(t, releaseDate, found.CustomFields[release])
+	assert.Equal(t, 3.0, found.CustomFields[points])
+
+	t.Run("CountByCustomFieldValue", func(t *testing.T) {
+		counts, err := repo.CountByCustomFieldValue(ctx, projectID, team)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"web": 2}, counts)
+
+		counts, err = repo.CountByCustomFieldValue(ctx, projectID, points)
+		require.NoError(t, err)
+		assert.Empty(t, counts, "number values are not counted")
+	})
+
+	t.Run("RemoveLabel and RemoveCustomField", func(t *testing.T) {
+		require.NoError(t, repo.RemoveLabel(ctx, projectID, bug))
+		require.NoError(t, repo.RemoveCustomField(ctx, projectID, points))
+
+		found, err := repo.FindByID(ctx, tasks[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{frontend}, found.LabelIDs)
+		assert.NotContains(t, found.CustomFields, points)
+		assert.Contains(t, found.CustomFields, release)
+	})
+
+	t.Run("FindDue returns assigned tasks in the window", func(t *testing.T) {
+		jane := []entities.UserRef{{ID: "jane"}}
+		at := func(days int) *time.Time {
+			due := releaseDate.AddDate(0, 0, days)
+			return &due
+		}
+		due := []*entities.Task{
+			{ProjectID: projectID, Title: "Due tomorrow", DueDate: at(1), Assignees: jane},
+			{ProjectID: projectID, Title: "Due today", DueDate: at(0), Assignees: jane},
+			{ProjectID: projectID, Title: "Unassigned", DueDate: at(0)},
+			{ProjectID: projectID, Title: "Due next week", DueDate: at(7), Assignees: jane},
+		}
+		for _, task := range due {
+			require.NoError(t, repo.Insert(ctx, task))
+		}
+
+		found, err := repo.FindDue(ctx, releaseDate, releaseDate.AddDate(0, 0, 2))
+		require.NoError(t, err)
+		var titles []string
+		for _, task := range found {
+			titles = append(titles, task.Title)
+		}
+		assert.Equal(t, []string{"Due today", "Due tomorrow"}, titles)
+	})
+
+	t.Run("parent links and DeleteByProjectID", func(t *testing.T) {
+		subtask := &entities.Task{ProjectID: projectID, ParentID: tasks[0].ID, Title: "Subtask"}
+		require.NoError(t, repo.Insert(ctx, subtask))
+
+		found, err := repo.FindByID(ctx, subtask.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tasks[0].ID, found.ParentID)
+
+		other := &entities.Task{ProjectID: primitive.NewObjectID().Hex(), Title: "Other project"}
+		require.NoError(t, repo.Insert(ctx, other))
+
+		require.NoError(t, repo.DeleteByProjectID(ctx, projectID))
+		assert.Empty(t, titles(entities.TaskFilter{}))
+		_, err = repo.FindByID(ctx, other.ID)
+		assert.NoError(t, err, "tasks of other projects must be kept")
+	})
+}
+
+func TestMongoDbTaskRepository_RecurrenceIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	ctx := context.Background()
+	// The unique series index is created by a migration
+	_, err := mongodb.NewMigrator(client, testDBName, mongodb.Migrations).Up(ctx, 0)
+	require.NoError(t, err)
+	repo := mongodb.NewTaskRepository(client, testDBName)
+
+	projectID := primitive.NewObjectID().Hex()
+	start := time.Date(2030, 3, 25, 8, 0, 0, 0, time.UTC)
+	occurrence := func(series string, days int, recurring bool) *entities.Task {
+		at := start.AddDate(0, 0, days)
+		task := &entities.Task{ProjectID: projectID, Title: series, Status: entities.TaskStatusTodo, SeriesID: series, Occurrence: &at}
+		if recurring {
+			task.Recurrence = &entities.TaskRecurrence{RRule: "FREQ=DAILY", Timezone: "Europe/Berlin", Start: start}
+		}
+		return task
+	}
+
+	first := occurrence("daily", 0, true)
+	require.NoError(t, repo.Insert(ctx, first))
+	plain := &entities.Task{ProjectID: projectID, Title: "Not recurring"}
+	require.NoError(t, repo.Insert(ctx, plain))
+	require.NoError(t, repo.Insert(ctx, &entities.Task{ProjectID: projectID, Title: "Also not recurring"}), "tasks without series must not collide")
+
+	t.Run("recurrence round trip", func(t *testing.T) {
+		found, err := repo.FindByID(ctx, first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, first.Recurrence, found.Recurrence)
+		assert.Equal(t, "daily", found.SeriesID)
+		assert.True(t, start.Equal(*found.Occurrence))
+	})
+
+	t.Run("InsertOccurrence skips duplicates", func(t *testing.T) {
+		created, err := repo.InsertOccurrence(ctx, occurrence("daily", 1, true))
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		duplicate := occurrence("daily", 1, true)
+		created, err = repo.InsertOccurrence(ctx, duplicate)
+		require.NoError(t, err)
+		assert.False(t, created)
+		assert.Empty(t, duplicate.ID)
+
+		_, err = repo.InsertOccurrence(ctx, plain)
+		assert.Error(t, err, "tasks outside a series are no occurrences")
+	})
+
+	t.Run("FindRecurring returns the latest occurrence per series", func(t *testing.T) {
+		require.NoError(t, repo.Insert(ctx, occurrence("ended", 0, true)))
+		require.NoError(t, repo.Insert(ctx, occurrence("ended", 1, false)))
+
+		heads, err := repo.FindRecurring(ctx)
+		require.NoError(t, err)
+		require.Len(t, heads, 1)
+		assert.Equal(t, "daily", heads[0].SeriesID)
+		assert.True(t, start.AddDate(0, 0, 1).Equal(*heads[0].Occurrence))
+
+		require.NoError(t, repo.ClearRecurrence(ctx, heads[0].ID))
+		heads, err = repo.FindRecurring(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, heads)
+	})
+}
@@ -0,0 +1,417 @@
+package mongodb
+
+import (
+	"boilerplate/internal/entities"
+	"context"
+	"errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"time"
+)
+
+type mongoDbTimeEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	ProjectID primitive.ObjectID `bson:"project_id"`
+	User      mongoDbUserRef     `bson:"user"`
+	Start     time.Time          `bson:"start"`
+	End       *time.Time         `bson:"end,omitempty"`
+	Duration  int64              `bson:"duration"`
+	Note      string             `bson:"note,omitempty"`
+	// Running is only stored on running timers; a partial unique index on
+	// user.id over them allows one running timer per user
+	Running   bool      `bson:"running,omitempty"`
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+type mongoDbTimeEntryRepository struct {
+	collection *mongo.Collection
+}
+
+func NewTimeEntryRepository(client *mongo.Client, database string) *mongoDbTimeEntryRepository {
+	collection := client.Database(database).Collection("time_entries")
+	return &mongoDbTimeEntryRepository{
+		collection: collection,
+	}
+}
+
+// Insert adds a stopped time entry
+func (r *mongoDbTimeEntryRepository) Insert(ctx context.Context, entry *entities.TimeEntry) error {
+	if entry == nil {
+		return errors.New("time entry cannot be nil")
+	}
+	if entry.Running() {
+		return errors.New("time entry has no end, use InsertTimer instead")
+	}
+	_, err := r.insert(ctx, entry)
+	return err
+}
+
+// InsertTimer adds a running time entry. It reports false without inserting
+// when the user already has a running timer.
+func (r *mongoDbTimeEntryRepository) InsertTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error) {
+	if entry == nil {
+		return false, errors.New("time entry cannot be nil")
+	}
+	if !entry.Running() {
+		return false, errors.New("time entry has an end, use Insert instead")
+	}
+	return r.insert(ctx, entry)
+}
+
+func (r *mongoDbTimeEntryRepository) insert(ctx context.Context, entry *entities.TimeEntry) (bool, error) {
+	mongoEntry, err := toMongoTimeEntry(*entry)
+	if err != nil {
+		return false, err
+	}
+	mongoEntry.ID = primitive.NewObjectID()
+	mongoEntry.CreatedAt = time.Now()
+	mongoEntry.UpdatedAt = mongoEntry.CreatedAt
+
+	if _, err := r.collection.InsertOne(ctx, mongoEntry); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	entry.ID = mongoEntry.ID.Hex()
+	entry.CreatedAt = mongoEntry.CreatedAt
+	entry.UpdatedAt = mongoEntry.UpdatedAt
+	return true, nil
+}
+
+// StopTimer stops the running timer of a user on a task at end and returns it
+// with its duration in whole seconds. It reports false if the user has no
+// timer running on the task.
+func (r *mongoDbTimeEntryRepository) StopTimer(ctx context.Context, userID, taskID string, end time.Time) (entities.TimeEntry, bool, error) {
+	taskOid, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return entities.TimeEntry{}, false, nil
+	}
+
+	// The timer is stopped atomically, so that concurrent stops stop it once;
+	// the update pipeline computes the duration from the stored start
+	elapsed := bson.M{"$toLong": bson.M{"$trunc": bson.M{"$divide": bson.A{bson.M{"$subtract": bson.A{end, "$start"}}, 1000}}}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"end":        end,
+			"duration":   bson.M{"$max": bson.A{0, elapsed}},
+			"updated_at": time.Now(),
+		}}},
+		{{Key: "$unset", Value: "running"}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var mongoEntry mongoDbTimeEntry
+	filter := bson.M{"user.id": userID, "task_id": taskOid, "running": true}
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&mongoEntry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.TimeEntry{}, false, nil
+		}
+		return entities.TimeEntry{}, false, err
+	}
+
+	return fromMongoTimeEntry(mongoEntry), true, nil
+}
+
+// FindRunning returns the running timer of a user. It reports false if there is none.
+func (r *mongoDbTimeEntryRepository) FindRunning(ctx context.Context, userID string) (entities.TimeEntry, bool, error) {
+	var mongoEntry mongoDbTimeEntry
+	if err := r.collection.FindOne(ctx, bson.M{"user.id": userID, "running": true}).Decode(&mongoEntry); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.TimeEntry{}, false, nil
+		}
+		return entities.TimeEntry{}, false, err
+	}
+	return fromMongoTimeEntry(mongoEntry), true, nil
+}
+
+func (r *mongoDbTimeEntryRepository) Update(ctx context.Context, entry *entities.TimeEntry) error {
+	if entry == nil {
+		return errors.New("time entry cannot be nil")
+	}
+
+	if entry.ID == "" {
+		return errors.New("time entry has no ID, use Insert instead")
+	}
+
+	mongoEntry, err := toMongoTimeEntry(*entry)
+	if err != nil {
+		return err
+	}
+	mongoEntry.UpdatedAt = time.Now()
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": mongoEntry.ID}, mongoEntry)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return errors.New("no time entry found with the given ID")
+	}
+
+	entry.UpdatedAt = mongoEntry.UpdatedAt
+	return nil
+}
+
+func (r *mongoDbTimeEntryRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid time entry ID format")
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return errors.New("no time entry found with the given ID")
+	}
+
+	return nil
+}
+
+func (r *mongoDbTimeEntryRepository) FindByID(ctx context.Context, id string) (entities.TimeEntry, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return entities.TimeEntry{}, errors.New("invalid time entry ID format")
+	}
+
+	var mongoEntry mongoDbTimeEntry
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&mongoEntry)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return entities.TimeEntry{}, errors.New("time entry not found")
+		}
+		return entities.TimeEntry{}, err
+	}
+
+	return fromMongoTimeEntry(mongoEntry), nil
+}
+
+// FindByTaskID returns the time entries of a task in order of their start
+func (r *mongoDbTimeEntryRepository) FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error) {
+	taskOid, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, errors.New("invalid task ID format")
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"task_id": taskOid}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mongoEntries []mongoDbTimeEntry
+	if err := cursor.All(ctx, &mongoEntries); err != nil {
+		return nil, err
+	}
+
+	entries := make([]entities.TimeEntry, len(mongoEntries))
+	for i, mongoEntry := range mongoEntries {
+		entries[i] = fromMongoTimeEntry(mongoEntry)
+	}
+	return entries, nil
+}
+
+// Report sums up the stopped time entries of a project starting in [from, to)
+// in a single aggregation. Days are calendar days in the IANA time zone; the
+// names of users are taken from their latest entry, the titles and estimates
+// of tasks from the tasks.
+func (r *mongoDbTimeEntryRepository) Report(ctx context.Context, projectID string, from, to time.Time, timezone string) (entities.TimeReport, error) {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return entities.TimeReport{}, errors.New("invalid project ID format")
+	}
+
+	totals := bson.M{"duration": bson.M{"$sum": "$duration"}, "entries": bson.M{"$sum": 1}}
+	group := func(id interface{}, fields bson.M) bson.D {
+		fields["_id"] = id
+		for key, value := range totals {
+			fields[key] = value
+		}
+		return bson.D{{Key: "$group", Value: fields}}
+	}
+	largestFirst := bson.D{{Key: "$sort", Value: bson.D{{Key: "duration", Value: -1}, {Key: "_id", Value: 1}}}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"project_id": projectOid,
+			"running":    bson.M{"$ne": true},
+			"start":      bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "start", Value: 1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{group(nil, bson.M{})},
+			"by_user": bson.A{
+				group("$user.id", bson.M{"name": bson.M{"$last": "$user.name"}, "email": bson.M{"$last": "$user.email"}}),
+				largestFirst,
+			},
+			"by_task": bson.A{
+				group("$task_id", bson.M{}),
+				bson.D{{Key: "$lookup", Value: bson.M{"from": "tasks", "localField": "_id", "foreignField": "_id", "as": "task"}}},
+				bson.D{{Key: "$set", Value: bson.M{
+					"title":    bson.M{"$arrayElemAt": bson.A{"$task.title", 0}},
+					"estimate": bson.M{"$arrayElemAt": bson.A{"$task.estimate", 0}},
+				}}},
+				largestFirst,
+			},
+			"by_day": bson.A{
+				group(bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$start", "timezone": timezone}}, bson.M{}),
+				bson.D{{Key: "$sort", Value: bson.M{"_id": 1}}},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return entities.TimeReport{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total []struct {
+			Duration int64 `bson:"duration"`
+			Entries  int   `bson:"entries"`
+		} `bson:"total"`
+		ByUser []struct {
+			UserID   string `bson:"_id"`
+			Name     string `bson:"name"`
+			Email    string `bson:"email"`
+			Duration int64  `bson:"duration"`
+			Entries  int    `bson:"entries"`
+		} `bson:"by_user"`
+		ByTask []struct {
+			TaskID   primitive.ObjectID `bson:"_id"`
+			Title    string             `bson:"title"`
+			Estimate int64              `bson:"estimate"`
+			Duration int64              `bson:"duration"`
+			Entries  int                `bson:"entries"`
+		} `bson:"by_task"`
+		ByDay []struct {
+			Date     string `bson:"_id"`
+			Duration int64  `bson:"duration"`
+			Entries  int    `bson:"entries"`
+		} `bson:"by_day"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return entities.TimeReport{}, err
+	}
+
+	report := entities.TimeReport{
+		ProjectID: projectID,
+		ByUser:    []entities.TimeByUser{},
+		ByTask:    []entities.TimeByTask{},
+		ByDay:     []entities.TimeByDay{},
+	}
+	if len(results) == 0 {
+		return report, nil
+	}
+	result := results[0]
+	if len(result.Total) > 0 {
+		report.Duration = result.Total[0].Duration
+		report.Entries = result.Total[0].Entries
+	}
+	for _, user := range result.ByUser {
+		report.ByUser = append(report.ByUser, entities.TimeByUser{
+			User:     entities.UserRef{ID: user.UserID, Name: user.Name, Email: user.Email},
+			Duration: user.Duration,
+			Entries:  user.Entries,
+		})
+	}
+	for _, task := range result.ByTask {
+		report.ByTask = append(report.ByTask, entities.TimeByTask{
+			TaskID:   task.TaskID.Hex(),
+			Title:    task.Title,
+			Estimate: task.Estimate,
+			Duration: task.Duration,
+			Entries:  task.Entries,
+		})
+	}
+	for _, day := range result.ByDay {
+		report.ByDay = append(report.ByDay, entities.TimeByDay{Date: day.Date, Duration: day.Duration, Entries: day.Entries})
+	}
+	return report, nil
+}
+
+// DeleteByTaskIDs removes the time entries of the tasks
+func (r *mongoDbTimeEntryRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []string) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	oids, err := toObjectIDs(taskIDs, "invalid task ID format")
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": oids}})
+	return err
+}
+
+// DeleteByProjectID removes the time entries of all tasks of a project
+func (r *mongoDbTimeEntryRepository) DeleteByProjectID(ctx context.Context, projectID string) error {
+	projectOid, err := primitive.ObjectIDFromHex(projectID)
+	if err != nil {
+		return errors.New("invalid project ID format")
+	}
+
+	_, err = r.collection.DeleteMany(ctx, bson.M{"project_id": projectOid})
+	return err
+}
+
+func toMongoTimeEntry(entry entities.TimeEntry) (*mongoDbTimeEntry, error) {
+	mongoEntry := &mongoDbTimeEntry{
+		User:      mongoDbUserRef{ID: entry.User.ID, Name: entry.User.Name, Email: entry.User.Email},
+		Start:     entry.Start,
+		End:       entry.End,
+		Duration:  entry.Duration,
+		Note:      entry.Note,
+		Running:   entry.Running(),
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+
+	if entry.ID != "" {
+		oid, err := primitive.ObjectIDFromHex(entry.ID)
+		if err != nil {
+			return nil, errors.New("invalid time entry ID format")
+		}
+		mongoEntry.ID = oid
+	}
+
+	oids, err := toObjectIDs([]string{entry.TaskID, entry.ProjectID}, "invalid task or project ID format")
+	if err != nil {
+		return nil, err
+	}
+	mongoEntry.TaskID = oids[0]
+	mongoEntry.ProjectID = oids[1]
+
+	return mongoEntry, nil
+}
+
+func fromMongoTimeEntry(entry mongoDbTimeEntry) entities.TimeEntry {
+	result := entities.TimeEntry{
+		ID:        entry.ID.Hex(),
+		TaskID:    entry.TaskID.Hex(),
+		ProjectID: entry.ProjectID.Hex(),
+		User:      entities.UserRef{ID: entry.User.ID, Name: entry.User.Name, Email: entry.User.Email},
+		Start:     entry.Start.UTC(),
+		Duration:  entry.Duration,
+		Note:      entry.Note,
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+
+	if entry.End != nil {
+		end := entry.End.UTC()
+		result.End = &end
+	}
+
+	return result
+}
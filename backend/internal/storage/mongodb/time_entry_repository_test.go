@@ -0,0 +1,162 @@
+package mongodb_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"boilerplate/internal/entities"
+	"boilerplate/internal/storage/mongodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMongoDbTimeEntryRepository_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	mongoURI, cleanup := setupMongoContainer(t)
+	defer cleanup()
+
+	client := createTestClient(t, mongoURI)
+	ctx := context.Background()
+	// The running timer index is created by a migration
+	_, err := mongodb.NewMigrator(client, testDBName, mongodb.Migrations).Up(ctx, 0)
+	require.NoError(t, err)
+	repo := mongodb.NewTimeEntryRepository(client, testDBName)
+	taskRepo := mongodb.NewTaskRepository(client, testDBName)
+
+	projectID := primitive.NewObjectID().Hex()
+	design := &entities.Task{ProjectID: projectID, Title: "Design", Status: entities.TaskStatusTodo, Estimate: 7200}
+	require.NoError(t, taskRepo.Insert(ctx, design))
+	review := &entities.Task{ProjectID: projectID, Title: "Review", Status: entities.TaskStatusTodo}
+	require.NoError(t, taskRepo.Insert(ctx, review))
+
+	jane := entities.UserRef{ID: "jane", Name: "Jane Doe", Email: "jane@example.com"}
+	joe := entities.UserRef{ID: "joe", Name: "Joe", Email: "joe@example.com"}
+	stopped := func(task *entities.Task, user entities.UserRef, start time.Time, duration int64) *entities.TimeEntry {
+		end := start.Add(time.Duration(duration) * time.Second)
+		return &entities.TimeEntry{TaskID: task.ID, ProjectID: projectID, User: user, Start: start, End: &end, Duration: duration}
+	}
+
+	t.Run("Task estimates round-trip", func(t *testing.T) {
+		found, err := taskRepo.FindByID(ctx, design.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7200), found.Estimate)
+	})
+
+	t.Run("One running timer per user", func(t *testing.T) {
+		start := time.Now().UTC().Truncate(time.Second).Add(-90 * time.Minute)
+		timer := &entities.TimeEntry{TaskID: design.ID, ProjectID: projectID, User: jane, Start: start}
+		started, err := repo.InsertTimer(ctx, timer)
+		require.NoError(t, err)
+		require.True(t, started)
+
+		started, err = repo.InsertTimer(ctx, &entities.TimeEntry{TaskID: review.ID, ProjectID: projectID, User: jane, Start: start})
+		require.NoError(t, err)
+		assert.False(t, started)
+		started, err = repo.InsertTimer(ctx, &entities.TimeEntry{TaskID: review.ID, ProjectID: projectID, User: joe, Start: start})
+		require.NoError(t, err)
+		assert.True(t, started, "other users have their own timer")
+
+		running, found, err := repo.FindRunning(ctx, "jane")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, timer.ID, running.ID)
+		assert.True(t, running.Running())
+	})
+
+	t.Run("StopTimer records the duration once", func(t *testing.T) {
+		running, _, err := repo.FindRunning(ctx, "jane")
+		require.NoError(t, err)
+		end := running.Start.Add(90 * time.Minute)
+
+		_, found, err := repo.StopTimer(ctx, "jane", review.ID, end)
+		require.NoError(t, err)
+		assert.False(t, found, "the timer runs on another task")
+
+		entry, found, err := repo.StopTimer(ctx, "jane", design.ID, end)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, int64(5400), entry.Duration)
+		require.NotNil(t, entry.End)
+		assert.True(t, end.Equal(*entry.End))
+
+		_, found, err = repo.StopTimer(ctx, "jane", design.ID, end)
+		require.NoError(t, err)
+		assert.False(t, found)
+		_, found, err = repo.FindRunning(ctx, "jane")
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		// With the timer stopped a new one can be started
+		started, err := repo.InsertTimer(ctx, &entities.TimeEntry{TaskID: review.ID, ProjectID: projectID, User: jane, Start: end})
+		require.NoError(t, err)
+		assert.True(t, started)
+	})
+
+	t.Run("Report sums up per user, task and day", func(t *testing.T) {
+		reportProject := primitive.NewObjectID().Hex()
+		spec := &entities.Task{ProjectID: reportProject, Title: "Spec", Status: entities.TaskStatusTodo, Estimate: 10800}
+		require.NoError(t, taskRepo.Insert(ctx, spec))
+		build := &entities.Task{ProjectID: reportProject, Title: "Build", Status: entities.TaskStatusTodo}
+		require.NoError(t, taskRepo.Insert(ctx, build))
+
+		for _, entry := range []*entities.TimeEntry{
+			// 23:30 UTC on March 24 is March 25 in Berlin
+			stopped(spec, jane, time.Date(2030, 3, 24, 23, 30, 0, 0, time.UTC), 3600),
+			stopped(spec, joe, time.Date(2030, 3, 25, 10, 0, 0, 0, time.UTC), 1800),
+			stopped(build, jane, time.Date(2030, 3, 26, 8, 0, 0, 0, time.UTC), 7200),
+			// Outside of the range
+			stopped(build, jane, time.Date(2030, 4, 2, 8, 0, 0, 0, time.UTC), 600),
+		} {
+			entry.ProjectID = reportProject
+			require.NoError(t, repo.Insert(ctx, entry))
+		}
+		timer := &entities.TimeEntry{TaskID: build.ID, ProjectID: reportProject, User: joe, Start: time.Date(2030, 3, 26, 9, 0, 0, 0, time.UTC)}
+		_, err := repo.InsertTimer(ctx, timer)
+		require.NoError(t, err)
+
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		from := time.Date(2030, 3, 1, 0, 0, 0, 0, berlin)
+		to := time.Date(2030, 4, 1, 0, 0, 0, 0, berlin)
+		report, err := repo.Report(ctx, reportProject, from, to, "Europe/Berlin")
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(12600), report.Duration)
+		assert.Equal(t, 3, report.Entries)
+		assert.Equal(t, []entities.TimeByUser{
+			{User: jane, Duration: 10800, Entries: 2},
+			{User: joe, Duration: 1800, Entries: 1},
+		}, report.ByUser)
+		assert.Equal(t, []entities.TimeByTask{
+			{TaskID: build.ID, Title: "Build", Duration: 7200, Entries: 1},
+			{TaskID: spec.ID, Title: "Spec", Estimate: 10800, Duration: 5400, Entries: 2},
+		}, report.ByTask)
+		assert.Equal(t, []entities.TimeByDay{
+			{Date: "2030-03-25", Duration: 5400, Entries: 2},
+			{Date: "2030-03-26", Duration: 7200, Entries: 1},
+		}, report.ByDay)
+
+		empty, err := repo.Report(ctx, primitive.NewObjectID().Hex(), from, to, "UTC")
+		require.NoError(t, err)
+		assert.Zero(t, empty.Duration)
+		assert.NotNil(t, empty.ByUser)
+		assert.Empty(t, empty.ByTask)
+	})
+
+	t.Run("DeleteByTaskIDs removes the entries of deleted tasks", func(t *testing.T) {
+		require.NoError(t, repo.Insert(ctx, stopped(review, joe, time.Date(2030, 3, 20, 9, 0, 0, 0, time.UTC), 900)))
+		require.NoError(t, repo.DeleteByTaskIDs(ctx, []string{review.ID}))
+
+		entries, err := repo.FindByTaskID(ctx, review.ID)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+		entries, err = repo.FindByTaskID(ctx, design.ID)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoDbTransactor struct {
+	client *mongo.Client
+}
+
+func NewTransactor(client *mongo.Client) *mongoDbTransactor {
+	return &mongoDbTransactor{
+		client: client,
+	}
+}
+
+// WithTransaction runs fn in a multi-document transaction. Transactions require
+// MongoDB to run as a replica set or sharded cluster.
+func (t *mongoDbTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
@@ -3,8 +3,11 @@ package storage
 import (
 	"boilerplate/internal/entities"
 	"boilerplate/internal/storage/mongodb"
+	"context"
 	"errors"
 	"go.mongodb.org/mongo-driver/mongo"
+	"io"
+	"time"
 )
 
 var (
@@ -14,31 +17,239 @@ var (
 )
 
 type TaskRepository interface {
-	Insert(task *entities.Task) error
-	Update(task *entities.Task) error
-	Delete(id string) error
-	FindByID(id string) (entities.Task, error)
-	FindAll() ([]entities.Task, error)
-	FindByProjectID(projectID string) ([]entities.Task, error)
+	Insert(ctx context.Context, task *entities.Task) error
+	Update(ctx context.Context, task *entities.Task) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Task, error)
+	FindAll(ctx context.Context) ([]entities.Task, error)
+	FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error)
+	CountByStatus(ctx context.Context, projectID string) (map[entities.TaskStatus]int, error)
+	// CountByCustomFieldValue counts the tasks of a project per text value of a custom field
+	CountByCustomFieldValue(ctx context.Context, projectID, fieldID string) (map[string]int, error)
+	DeleteByProjectID(ctx context.Context, projectID string) error
+
+	// RemoveLabel and RemoveCustomField detach a deleted label or custom field
+	// from all tasks of a project
+	RemoveLabel(ctx context.Context, projectID, labelID string) error
+	RemoveCustomField(ctx context.Context, projectID, fieldID string) error
+	// RemoveUser unassigns a user from all tasks of a project and removes them as watcher
+	RemoveUser(ctx context.Context, projectID, userID string) error
+	// FindByAssignee returns the tasks of all projects assigned to a user
+	FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error)
+
+	// InsertOccurrence inserts the next occurrence of a recurring task and
+	// reports false if the series already has it
+	InsertOccurrence(ctx context.Context, task *entities.Task) (bool, error)
+	// ClearRecurrence removes the recurrence from a task
+	ClearRecurrence(ctx context.Context, id string) error
+	// FindRecurring returns the latest occurrence of every series that still recurs
+	FindRecurring(ctx context.Context) ([]entities.Task, error)
+	// FindDue returns the assigned tasks of all projects due between from and to
+	FindDue(ctx context.Context, from, to time.Time) ([]entities.Task, error)
+
+	// Bulk operations return one error slot per input item (nil on success)
+	// plus an error for failures that affect the whole call.
+	InsertMany(ctx context.Context, tasks []*entities.Task) ([]error, error)
+	UpdateMany(ctx context.Context, projectID string, patches []entities.TaskPatch) ([]error, error)
+	DeleteMany(ctx context.Context, projectID string, ids []string) ([]error, error)
 }
 
 type ProjectRepository interface {
-	Insert(project *entities.Project) error
-	Update(project *entities.Project) error
-	Delete(id string) error
-	FindByID(id string) (entities.Project, error)
-	FindAll() ([]entities.Project, error)
-	FindAllPaginated(limit, offset int) ([]entities.Project, int64, error)
+	Insert(ctx context.Context, project *entities.Project) error
+	Update(ctx context.Context, project *entities.Project) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Project, error)
+	FindAll(ctx context.Context) ([]entities.Project, error)
+	FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error)
+}
+
+type LabelRepository interface {
+	Insert(ctx context.Context, label *entities.Label) error
+	Update(ctx context.Context, label *entities.Label) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Label, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error)
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+type CustomFieldRepository interface {
+	Insert(ctx context.Context, field *entities.CustomField) error
+	Update(ctx context.Context, field *entities.CustomField) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.CustomField, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error)
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// MemberRepository stores the users taking part in a project
+type MemberRepository interface {
+	// Upsert adds a member or refreshes the name and email of an existing one
+	Upsert(ctx context.Context, member *entities.ProjectMember) error
+	Delete(ctx context.Context, projectID, userID string) error
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error)
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// DependencyRepository stores the blocks / blocked-by links between tasks
+type DependencyRepository interface {
+	Insert(ctx context.Context, dependency *entities.TaskDependency) error
+	Delete(ctx context.Context, blockerID, blockedID string) error
+	// FindByBlockers returns the links starting at any of the tasks
+	FindByBlockers(ctx context.Context, taskIDs []string) ([]entities.TaskDependency, error)
+	// FindByBlocked returns the links ending at any of the tasks
+	FindByBlocked(ctx context.Context, taskIDs []string) ([]entities.TaskDependency, error)
+	// FindByProjectID returns the links with at least one end in the project
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.TaskDependency, error)
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// CommentRepository stores the comments on tasks
+type CommentRepository interface {
+	Insert(ctx context.Context, comment *entities.Comment) error
+	Update(ctx context.Context, comment *entities.Comment) error
+	// Delete removes a comment together with its replies
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Comment, error)
+	// FindByTaskID returns a page of the top-level comments of a task, oldest
+	// first, and the total number of top-level comments
+	FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.Comment, int64, error)
+	// FindReplies returns the replies to any of the comments, oldest first
+	FindReplies(ctx context.Context, parentIDs []string) ([]entities.Comment, error)
+	// CountByTaskIDs counts the comments and replies per task
+	CountByTaskIDs(ctx context.Context, taskIDs []string) (map[string]int, error)
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// AttachmentRepository stores the metadata of the files attached to tasks;
+// their content is kept in a BlobStore
+type AttachmentRepository interface {
+	Insert(ctx context.Context, attachment *entities.Attachment) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.Attachment, error)
+	// FindByTaskID returns the attachments of a task, oldest first
+	FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error)
+	FindByTaskIDs(ctx context.Context, taskIDs []string) ([]entities.Attachment, error)
+	FindByProjectID(ctx context.Context, projectID string) ([]entities.Attachment, error)
+	// SizeByProjectID sums the sizes of the attachments of a project
+	SizeByProjectID(ctx context.Context, projectID string) (int64, error)
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// TimeEntryRepository stores the time spent on tasks
+type TimeEntryRepository interface {
+	// Insert adds a stopped time entry
+	Insert(ctx context.Context, entry *entities.TimeEntry) error
+	// InsertTimer adds a running time entry and reports false without
+	// inserting if the user already has a running timer
+	InsertTimer(ctx context.Context, entry *entities.TimeEntry) (bool, error)
+	// StopTimer stops the running timer of a user on a task at end and returns
+	// it; it reports false if the user has no timer running on the task
+	StopTimer(ctx context.Context, userID, taskID string, end time.Time) (entities.TimeEntry, bool, error)
+	// FindRunning returns the running timer of a user; it reports false if there is none
+	FindRunning(ctx context.Context, userID string) (entities.TimeEntry, bool, error)
+	Update(ctx context.Context, entry *entities.TimeEntry) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (entities.TimeEntry, error)
+	// FindByTaskID returns the time entries of a task in order of their start
+	FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error)
+	// Report sums up the stopped entries of a project starting in [from, to)
+	// per user, per task and per calendar day in the time zone
+	Report(ctx context.Context, projectID string, from, to time.Time, timezone string) (entities.TimeReport, error)
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// BlobStore keeps the content of attachments under opaque, slash separated keys
+type BlobStore interface {
+	// Put stores the content read from r; size is -1 if the length is unknown
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the content of a blob, or ErrNotFound if there is none
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes a blob; deleting a missing blob is no error
+	Delete(ctx context.Context, key string) error
+}
+
+// NotificationRepository stores the inboxes of the users
+type NotificationRepository interface {
+	// Insert adds a notification and reports false without inserting if the
+	// user already has a notification with the same key
+	Insert(ctx context.Context, notification *entities.Notification) (bool, error)
+	// FindByUserID returns a page of the notifications of a user, newest
+	// first, and the total number of matching notifications
+	FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+	// MarkRead sets or clears the read state of a notification of a user and
+	// returns it; it reports false if the user has no such notification
+	MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, bool, error)
+	// MarkAllRead marks the unread notifications of a user as read and returns their number
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	// FindPending returns up to limit notifications with pending deliveries, oldest first
+	FindPending(ctx context.Context, limit int) ([]entities.Notification, error)
+	UpdateDeliveries(ctx context.Context, id string, deliveries []entities.NotificationDelivery) error
+	// DeleteByTaskIDs removes the notifications about the tasks, cancelling their pending deliveries
+	DeleteByTaskIDs(ctx context.Context, taskIDs []string) error
+	DeleteByProjectID(ctx context.Context, projectID string) error
+}
+
+// NotificationPreferenceRepository stores the reminder settings of the users
+type NotificationPreferenceRepository interface {
+	// FindByUserIDs returns the stored preferences of the users; users without
+	// stored preferences are left out
+	FindByUserIDs(ctx context.Context, userIDs []string) ([]entities.NotificationPreferences, error)
+	Upsert(ctx context.Context, preferences *entities.NotificationPreferences) error
+}
+
+// LeaseRepository hands out named leases that expire on their own, so that
+// only one of several replicas runs a background job at a time
+type LeaseRepository interface {
+	// Acquire takes the lease for ttl if it is free, expired or already held
+	// by this process, and reports whether it did
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+	// Release gives up the lease if this process holds it
+	Release(ctx context.Context, name string) error
+}
+
+// Transactor runs a function inside a database transaction. The context passed
+// to fn carries the transaction and must be handed to the repository calls.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Repository struct {
-	ProjectRepository ProjectRepository
-	TaskRepository    TaskRepository
+	ProjectRepository                ProjectRepository
+	TaskRepository                   TaskRepository
+	LabelRepository                  LabelRepository
+	CustomFieldRepository            CustomFieldRepository
+	DependencyRepository             DependencyRepository
+	MemberRepository                 MemberRepository
+	CommentRepository                CommentRepository
+	AttachmentRepository             AttachmentRepository
+	TimeEntryRepository              TimeEntryRepository
+	NotificationRepository           NotificationRepository
+	NotificationPreferenceRepository NotificationPreferenceRepository
+	LeaseRepository                  LeaseRepository
+	Transactor                       Transactor
+	// BlobStore is not backed by MongoDB and is set by the caller
+	BlobStore BlobStore
 }
 
 func NewRepository(client *mongo.Client, database string) Repository {
 	return Repository{
-		ProjectRepository: mongodb.NewProjectRepository(client, database),
-		TaskRepository:    mongodb.NewTaskRepository(client, database),
+		ProjectRepository:                mongodb.NewProjectRepository(client, database),
+		TaskRepository:                   mongodb.NewTaskRepository(client, database),
+		LabelRepository:                  mongodb.NewLabelRepository(client, database),
+		CustomFieldRepository:            mongodb.NewCustomFieldRepository(client, database),
+		DependencyRepository:             mongodb.NewDependencyRepository(client, database),
+		MemberRepository:                 mongodb.NewMemberRepository(client, database),
+		CommentRepository:                mongodb.NewCommentRepository(client, database),
+		AttachmentRepository:             mongodb.NewAttachmentRepository(client, database),
+		TimeEntryRepository:              mongodb.NewTimeEntryRepository(client, database),
+		NotificationRepository:           mongodb.NewNotificationRepository(client, database),
+		NotificationPreferenceRepository: mongodb.NewNotificationPreferenceRepository(client, database),
+		LeaseRepository:                  mongodb.NewLeaseRepository(client, database),
+		Transactor:                       mongodb.NewTransactor(client),
 	}
 }
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"boilerplate/internal/config"
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestSetupWithExporter_ExportsSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := SetupWithExporter(config.TracingConfig{ServiceName: "test-service", SampleRatio: 1}, exporter)
+	defer provider.Shutdown(context.Background())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("failed to flush spans: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	if spans[0].Name != "operation" {
+		t.Errorf("expected span name 'operation', got '%s'", spans[0].Name)
+	}
+
+	serviceName, ok := spans[0].Resource.Set().Value(semconv.ServiceNameKey)
+	if !ok || serviceName.AsString() != "test-service" {
+		t.Errorf("expected service name 'test-service', got '%v'", serviceName.AsString())
+	}
+}
+
+func TestSetupWithExporter_SampleRatioZeroDropsSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := SetupWithExporter(config.TracingConfig{SampleRatio: 0}, exporter)
+	defer provider.Shutdown(context.Background())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("failed to flush spans: %v", err)
+	}
+
+	if len(exporter.GetSpans()) != 0 {
+		t.Errorf("expected no spans with sample ratio 0, got %d", len(exporter.GetSpans()))
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.TracingConfig
+		expectErr bool
+	}{
+		{name: "stdout", cfg: config.TracingConfig{Exporter: "stdout"}},
+		{name: "otlp http", cfg: config.TracingConfig{Exporter: "otlp", Protocol: "http", Endpoint: "localhost:4318", Insecure: true}},
+		{name: "otlp grpc", cfg: config.TracingConfig{Exporter: "otlp", Protocol: "grpc", Endpoint: "localhost:4317", Insecure: true}},
+		{name: "unknown exporter", cfg: config.TracingConfig{Exporter: "zipkin"}, expectErr: true},
+		{name: "unknown protocol", cfg: config.TracingConfig{Exporter: "otlp", Protocol: "thrift"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := NewExporter(context.Background(), tt.cfg)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			exporter.Shutdown(context.Background())
+		})
+	}
+}
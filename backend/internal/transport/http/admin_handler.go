@@ -0,0 +1,114 @@
+package http
+
+import (
+	"boilerplate/internal/logger"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// globalComponent addresses the global log level in the admin endpoints
+const globalComponent = "global"
+
+// AdminHandler handles operational endpoints reserved for administrators
+type AdminHandler struct {
+	levels *logger.Levels
+	logger *slog.Logger
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(levels *logger.Levels, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		levels: levels,
+		logger: logger,
+	}
+}
+
+// SetLogLevelRequest represents the request body for changing a log level
+type SetLogLevelRequest struct {
+	Level string `json:"level" example:"debug" enums:"debug,info,warn,error"`
+	// TTL after which the level reverts to the configured one, as a Go duration
+	TTL string `json:"ttl,omitempty" example:"15m"`
+}
+
+// GetLogLevels godoc
+// @Summary      Get log levels
+// @Description  Get the global log level and the levels of individual components
+// @Tags         admin
+// @Produce      json
+// @Success      200  {object}  logger.LevelsSnapshot
+// @Failure      403  {object}  map[string]string  "Missing admin role"
+// @Security     BearerAuth
+// @Router       /api/v1/admin/log-levels [get]
+func (h *AdminHandler) GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, h.levels.Snapshot(), http.StatusOK)
+}
+
+// SetLogLevel godoc
+// @Summary      Set log level
+// @Description  Change the global log level or the level of a component (e.g. auth, http, storage), optionally for a limited time
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        component  path      string              true  "Component name or 'global'"
+// @Param        level      body      SetLogLevelRequest  true  "New level"
+// @Success      200        {object}  logger.LevelsSnapshot
+// @Failure      400        {object}  map[string]string  "Invalid level or TTL"
+// @Failure      403        {object}  map[string]string  "Missing admin role"
+// @Security     BearerAuth
+// @Router       /api/v1/admin/log-levels/{component} [put]
+func (h *AdminHandler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req SetLogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil || req.Level == "" {
+		respondError(w, "Invalid level, expected debug, info, warn or error", http.StatusBadRequest)
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			respondError(w, "Invalid TTL, expected a positive duration such as 15m", http.StatusBadRequest)
+			return
+		}
+	}
+
+	component := r.PathValue("component")
+	h.levels.Set(levelComponent(component), level, ttl)
+	h.logger.InfoContext(r.Context(), "log level changed", "target", component, "level", level.String(), "ttl", ttl)
+
+	respondJSON(w, h.levels.Snapshot(), http.StatusOK)
+}
+
+// ResetLogLevel godoc
+// @Summary      Reset log level
+// @Description  Restore the configured level of a component or of the global level
+// @Tags         admin
+// @Produce      json
+// @Param        component  path      string  true  "Component name or 'global'"
+// @Success      200        {object}  logger.LevelsSnapshot
+// @Failure      403        {object}  map[string]string  "Missing admin role"
+// @Security     BearerAuth
+// @Router       /api/v1/admin/log-levels/{component} [delete]
+func (h *AdminHandler) ResetLogLevel(w http.ResponseWriter, r *http.Request) {
+	component := r.PathValue("component")
+	h.levels.Reset(levelComponent(component))
+	h.logger.InfoContext(r.Context(), "log level reset", "target", component)
+
+	respondJSON(w, h.levels.Snapshot(), http.StatusOK)
+}
+
+// levelComponent maps the path value to the component name used by logger.Levels
+func levelComponent(component string) string {
+	if component == globalComponent {
+		return ""
+	}
+	return component
+}
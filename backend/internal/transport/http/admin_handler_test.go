@@ -0,0 +1,117 @@
+package http
+
+import (
+	"boilerplate/internal/logger"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestAdminMux(levels *logger.Levels) *http.ServeMux {
+	handler := NewAdminHandler(levels, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/admin/log-levels", handler.GetLogLevels)
+	mux.HandleFunc("PUT /api/v1/admin/log-levels/{component}", handler.SetLogLevel)
+	mux.HandleFunc("DELETE /api/v1/admin/log-levels/{component}", handler.ResetLogLevel)
+	return mux
+}
+
+func TestAdminHandler_SetLogLevel(t *testing.T) {
+	tests := []struct {
+		name           string
+		component      string
+		body           string
+		expectedStatus int
+		expected       logger.LevelsSnapshot
+	}{
+		{
+			name:           "global level",
+			component:      "global",
+			body:           `{"level":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expected:       logger.LevelsSnapshot{Global: "debug", Components: map[string]string{"auth": "warn"}},
+		},
+		{
+			name:           "component level with ttl",
+			component:      "storage",
+			body:           `{"level":"error","ttl":"10m"}`,
+			expectedStatus: http.StatusOK,
+			expected:       logger.LevelsSnapshot{Global: "info", Components: map[string]string{"auth": "warn", "storage": "error"}},
+		},
+		{
+			name:           "unknown level",
+			component:      "auth",
+			body:           `{"level":"verbose"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing level",
+			component:      "auth",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid ttl",
+			component:      "auth",
+			body:           `{"level":"debug","ttl":"soon"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := logger.NewLevels("info", map[string]string{"auth": "warn"})
+			mux := newTestAdminMux(levels)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/log-levels/"+tt.component, bytes.NewBufferString(tt.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var snapshot logger.LevelsSnapshot
+			if err := json.NewDecoder(rec.Body).Decode(&snapshot); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if snapshot.Global != tt.expected.Global || len(snapshot.Components) != len(tt.expected.Components) {
+				t.Fatalf("expected %+v, got %+v", tt.expected, snapshot)
+			}
+			for component, level := range tt.expected.Components {
+				if snapshot.Components[component] != level {
+					t.Errorf("expected %s level %s, got %s", component, level, snapshot.Components[component])
+				}
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ResetLogLevel(t *testing.T) {
+	levels := logger.NewLevels("info", map[string]string{"auth": "warn"})
+	levels.Set("auth", slog.LevelDebug, 0)
+	mux := newTestAdminMux(levels)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/v1/admin/log-levels/auth", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if levels.Level("auth") != slog.LevelWarn {
+		t.Errorf("expected configured level to be restored, got %s", levels.Level("auth"))
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/log-levels", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
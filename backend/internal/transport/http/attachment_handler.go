@@ -0,0 +1,261 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// maxChecksumFieldSize limits the checksum form field of an upload
+const maxChecksumFieldSize = 128
+
+// inlineContentTypes are shown by browsers without the risk of running
+// scripts; all other attachments are downloaded
+var inlineContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf", "text/plain"}
+
+// AttachmentHandler handles files attached to tasks
+type AttachmentHandler struct {
+	service service.AttachmentService
+	logger  *slog.Logger
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(svc service.AttachmentService, logger *slog.Logger) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// List godoc
+// @Summary      List task attachments
+// @Description  Get the metadata of all files attached to a task, oldest first
+// @Tags         attachments
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {array}   entities.Attachment
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/attachments [get]
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	attachments, err := h.service.FindByTaskID(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to list attachments", "task_id", taskID, "error", err)
+		respondError(w, "Failed to list attachments", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, attachments, http.StatusOK)
+}
+
+// Upload godoc
+// @Summary      Upload attachment
+// @Description  Attach a file to a task. The body is streamed to the attachment store; the content type is sniffed from the content. An optional checksum field with the hex encoded SHA-256 digest of the file must precede the file part and is verified.
+// @Tags         attachments
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id        path      string  true   "Task ID"
+// @Param        checksum  formData  string  false  "Hex encoded SHA-256 digest of the file"
+// @Param        file      formData  file    true   "File to attach"
+// @Success      201  {object}  entities.Attachment
+// @Failure      400  {object}  map[string]string  "Invalid multipart body, missing or empty file, or checksum mismatch"
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      413  {object}  map[string]string  "File too large or project quota exceeded"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		respondError(w, "Expected a multipart/form-data body", http.StatusBadRequest)
+		return
+	}
+
+	var checksum string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			respondError(w, "Missing file part", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			respondError(w, "Invalid multipart body", http.StatusBadRequest)
+			return
+		}
+
+		switch part.FormName() {
+		case "checksum":
+			value, err := io.ReadAll(io.LimitReader(part, maxChecksumFieldSize))
+			if err != nil {
+				respondError(w, "Invalid multipart body", http.StatusBadRequest)
+				return
+			}
+			checksum = string(value)
+		case "file":
+			h.upload(w, r, part, checksum)
+			part.Close()
+			return
+		}
+		part.Close()
+	}
+}
+
+// upload stores the file part of an upload request
+func (h *AttachmentHandler) upload(w http.ResponseWriter, r *http.Request, file *multipart.Part, checksum string) {
+	uploader, _ := currentUser(r)
+	attachment := &entities.Attachment{
+		TaskID:     r.PathValue("id"),
+		FileName:   file.FileName(),
+		UploadedBy: uploader,
+	}
+
+	if err := h.service.Upload(r.Context(), attachment, file, checksum); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, domain.ErrTaskNotFound):
+			respondError(w, "Task not found", http.StatusNotFound)
+		case errors.Is(err, domain.ErrFileTooLarge), errors.Is(err, domain.ErrQuotaExceeded):
+			respondError(w, err.Error(), http.StatusRequestEntityTooLarge)
+		default:
+			h.logger.ErrorContext(r.Context(), "failed to upload attachment", "task_id", attachment.TaskID, "error", err)
+			respondError(w, "Failed to upload attachment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	respondJSON(w, attachment, http.StatusCreated)
+}
+
+// Get godoc
+// @Summary      Get attachment
+// @Description  Get the metadata of an attachment
+// @Tags         attachments
+// @Accept       json
+// @Produce      json
+// @Param        id            path      string  true  "Task ID"
+// @Param        attachmentId  path      string  true  "Attachment ID"
+// @Success      200  {object}  entities.Attachment
+// @Failure      404  {object}  map[string]string  "Attachment not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/attachments/{attachmentId} [get]
+func (h *AttachmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, attachment, http.StatusOK)
+}
+
+// Download godoc
+// @Summary      Download attachment
+// @Description  Stream the content of an attachment. Range requests are supported; the ETag and Repr-Digest headers carry the SHA-256 digest of the content. Images, PDFs and plain text are served inline, everything else as a download.
+// @Tags         attachments
+// @Produce      octet-stream
+// @Param        id            path    string  true   "Task ID"
+// @Param        attachmentId  path    string  true   "Attachment ID"
+// @Param        Range         header  string  false  "Byte range, e.g. bytes=0-1023"
+// @Success      200  {file}    file
+// @Success      206  {file}    file  "Partial content"
+// @Failure      404  {object}  map[string]string  "Attachment not found"
+// @Failure      416  {string}  string  "Range not satisfiable"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/attachments/{attachmentId}/content [get]
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.service.Open(r.Context(), existing.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to open attachment", "id", existing.ID, "error", err)
+		respondError(w, "Failed to download attachment", http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	disposition := "attachment"
+	if slices.Contains(inlineContentTypes, mediaType(attachment.ContentType)) {
+		disposition = "inline"
+	}
+	header := w.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("ETag", `"`+attachment.SHA256+`"`)
+	if digest, err := hex.DecodeString(attachment.SHA256); err == nil {
+		header.Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(digest)+":")
+	}
+
+	// ServeContent answers Range, If-Range and conditional requests
+	http.ServeContent(w, r, "", attachment.CreatedAt, content)
+}
+
+// mediaType strips the parameters from a content type
+func mediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.TrimSpace(mediaType)
+}
+
+// Delete godoc
+// @Summary      Delete attachment
+// @Description  Delete an attachment and its content
+// @Tags         attachments
+// @Accept       json
+// @Produce      json
+// @Param        id            path  string  true  "Task ID"
+// @Param        attachmentId  path  string  true  "Attachment ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  map[string]string  "Attachment not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/attachments/{attachmentId} [delete]
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), existing.ID); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to delete attachment", "id", existing.ID, "error", err)
+		respondError(w, "Failed to delete attachment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// find loads the attachment named in the path and checks that it belongs to the task in the path
+func (h *AttachmentHandler) find(w http.ResponseWriter, r *http.Request) (entities.Attachment, bool) {
+	id := r.PathValue("attachmentId")
+	attachment, err := h.service.FindByID(r.Context(), id)
+	if err != nil || attachment.TaskID != r.PathValue("id") {
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to find attachment", "id", id, "error", err)
+		}
+		respondError(w, "Attachment not found", http.StatusNotFound)
+		return entities.Attachment{}, false
+	}
+	return attachment, true
+}
@@ -0,0 +1,451 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Mock AttachmentService for testing
+type mockAttachmentService struct {
+	uploadFunc       func(*entities.Attachment, io.Reader, string) error
+	openFunc         func(string) (entities.Attachment, io.ReadSeekCloser, error)
+	deleteFunc       func(string) error
+	findByIDFunc     func(string) (entities.Attachment, error)
+	findByTaskIDFunc func(string) ([]entities.Attachment, error)
+}
+
+func (m *mockAttachmentService) Upload(ctx context.Context, attachment *entities.Attachment, content io.Reader, checksum string) error {
+	if m.uploadFunc != nil {
+		return m.uploadFunc(attachment, content, checksum)
+	}
+	return nil
+}
+
+func (m *mockAttachmentService) Open(ctx context.Context, id string) (entities.Attachment, io.ReadSeekCloser, error) {
+	if m.openFunc != nil {
+		return m.openFunc(id)
+	}
+	return entities.Attachment{}, nil, errors.New("not found")
+}
+
+func (m *mockAttachmentService) Delete(ctx context.Context, id string) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id)
+	}
+	return nil
+}
+
+func (m *mockAttachmentService) FindByID(ctx context.Context, id string) (entities.Attachment, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(id)
+	}
+	return entities.Attachment{}, errors.New("not found")
+}
+
+func (m *mockAttachmentService) FindByTaskID(ctx context.Context, taskID string) ([]entities.Attachment, error) {
+	if m.findByTaskIDFunc != nil {
+		return m.findByTaskIDFunc(taskID)
+	}
+	return []entities.Attachment{}, nil
+}
+
+type readSeekNopCloser struct {
+	io.ReadSeeker
+}
+
+func (readSeekNopCloser) Close() error { return nil }
+
+// multipartBody builds an upload body with the given form fields in order;
+// the field "file" becomes a file part
+func multipartBody(t *testing.T, fields ...[2]string) (io.Reader, string) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, field := range fields {
+		var part io.Writer
+		var err error
+		if field[0] == "file" {
+			part, err = writer.CreateFormFile("file", "report.txt")
+		} else {
+			part, err = writer.CreateFormField(field[0])
+		}
+		if err != nil {
+			t.Fatalf("failed to create part: %v", err)
+		}
+		part.Write([]byte(field[1]))
+	}
+	writer.Close()
+	return &body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	tests := []struct {
+		name             string
+		fields           [][2]string
+		uploadErr        error
+		expectedStatus   int
+		expectedChecksum string
+	}{
+		{
+			name:             "file with checksum",
+			fields:           [][2]string{{"checksum", "abc123"}, {"file", "quarterly"}},
+			expectedStatus:   http.StatusCreated,
+			expectedChecksum: "abc123",
+		},
+		{
+			name:           "unknown fields are skipped",
+			fields:         [][2]string{{"comment", "hi"}, {"file", "quarterly"}},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing file part",
+			fields:         [][2]string{{"checksum", "abc123"}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "empty file",
+			fields:         [][2]string{{"file", ""}},
+			uploadErr:      &domain.ValidationError{Field: "file", Message: "is empty"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too large",
+			fields:         [][2]string{{"file", "quarterly"}},
+			uploadErr:      domain.ErrFileTooLarge,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "quota exceeded",
+			fields:         [][2]string{{"file", "quarterly"}},
+			uploadErr:      domain.ErrQuotaExceeded,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "unknown task",
+			fields:         [][2]string{{"file", "quarterly"}},
+			uploadErr:      fmt.Errorf("%w: no task task1", domain.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checksum string
+			mockService := &mockAttachmentService{
+				uploadFunc: func(attachment *entities.Attachment, content io.Reader, sum string) error {
+					if tt.uploadErr != nil {
+						return tt.uploadErr
+					}
+					data, err := io.ReadAll(content)
+					if err != nil {
+						return err
+					}
+					checksum = sum
+					attachment.ID = "a3"
+					attachment.Size = int64(len(data))
+					attachment.ContentType = http.DetectContentType(data)
+					return nil
+				},
+			}
+
+			handler := NewAttachmentHandler(mockService, testLogger())
+
+			body, contentType := multipartBody(t, tt.fields...)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task1/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			req.SetPathValue("id", "task1")
+			req = withUser(req)
+			w := httptest.NewRecorder()
+
+			handler.Upload(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			var attachment entities.Attachment
+			if err := json.NewDecoder(w.Body).Decode(&attachment); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if attachment.TaskID != "task1" || attachment.FileName != "report.txt" || attachment.Size != 9 || attachment.UploadedBy.ID != "jane" {
+				t.Errorf("expected report.txt of 9 bytes uploaded by jane to task1, got %+v", attachment)
+			}
+			if checksum != tt.expectedChecksum {
+				t.Errorf("expected checksum %q to be passed on, got %q", tt.expectedChecksum, checksum)
+			}
+		})
+	}
+
+	t.Run("body is not multipart", func(t *testing.T) {
+		handler := NewAttachmentHandler(&mockAttachmentService{}, testLogger())
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task1/attachments", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.SetPathValue("id", "task1")
+		w := httptest.NewRecorder()
+
+		handler.Upload(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+// storedAttachments mocks the lookup and download of a plain text and an HTML
+// attachment of task1
+func storedAttachments() *mockAttachmentService {
+	attachments := map[string]entities.Attachment{
+		"a1": {ID: "a1", TaskID: "task1", FileName: "notes.txt", ContentType: "text/plain; charset=utf-8", Size: 11,
+			SHA256: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"},
+		"a2": {ID: "a2", TaskID: "task1", FileName: "page.html", ContentType: "text/html; charset=utf-8", Size: 13},
+	}
+	content := map[string]string{"a1": "hello world", "a2": "<html></html>"}
+
+	findByID := func(id string) (entities.Attachment, error) {
+		attachment, ok := attachments[id]
+		if !ok {
+			return entities.Attachment{}, errors.New("attachment not found")
+		}
+		return attachment, nil
+	}
+	return &mockAttachmentService{
+		findByIDFunc: findByID,
+		openFunc: func(id string) (entities.Attachment, io.ReadSeekCloser, error) {
+			attachment, err := findByID(id)
+			if err != nil {
+				return entities.Attachment{}, nil, err
+			}
+			return attachment, readSeekNopCloser{strings.NewReader(content[id])}, nil
+		},
+	}
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	tests := []struct {
+		name                string
+		taskID              string
+		attachmentID        string
+		headers             map[string]string
+		expectedStatus      int
+		expectedBody        string
+		expectedDisposition string
+	}{
+		{
+			name:                "whole file",
+			taskID:              "task1",
+			attachmentID:        "a1",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "hello world",
+			expectedDisposition: `inline; filename=notes.txt`,
+		},
+		{
+			name:           "range",
+			taskID:         "task1",
+			attachmentID:   "a1",
+			headers:        map[string]string{"Range": "bytes=6-"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "world",
+		},
+		{
+			name:           "unsatisfiable range",
+			taskID:         "task1",
+			attachmentID:   "a1",
+			headers:        map[string]string{"Range": "bytes=100-"},
+			expectedStatus: http.StatusRequestedRangeNotSatisfiable,
+		},
+		{
+			name:           "cached copy is current",
+			taskID:         "task1",
+			attachmentID:   "a1",
+			headers:        map[string]string{"If-None-Match": `"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"`},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:                "HTML is downloaded",
+			taskID:              "task1",
+			attachmentID:        "a2",
+			expectedStatus:      http.StatusOK,
+			expectedBody:        "<html></html>",
+			expectedDisposition: `attachment; filename=page.html`,
+		},
+		{
+			name:           "attachment of another task",
+			taskID:         "task2",
+			attachmentID:   "a1",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown attachment",
+			taskID:         "task1",
+			attachmentID:   "a9",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAttachmentHandler(storedAttachments(), testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+tt.taskID+"/attachments/"+tt.attachmentID+"/content", nil)
+			req.SetPathValue("id", tt.taskID)
+			req.SetPathValue("attachmentId", tt.attachmentID)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Download(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+			if tt.expectedDisposition != "" && w.Header().Get("Content-Disposition") != tt.expectedDisposition {
+				t.Errorf("expected Content-Disposition %q, got %q", tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+			}
+			if w.Code == http.StatusOK && w.Header().Get("X-Content-Type-Options") != "nosniff" {
+				t.Error("expected X-Content-Type-Options: nosniff")
+			}
+		})
+	}
+
+	t.Run("digest headers", func(t *testing.T) {
+		handler := NewAttachmentHandler(storedAttachments(), testLogger())
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task1/attachments/a1/content", nil)
+		req.SetPathValue("id", "task1")
+		req.SetPathValue("attachmentId", "a1")
+		w := httptest.NewRecorder()
+
+		handler.Download(w, req)
+
+		if got := w.Header().Get("Repr-Digest"); got != "sha-256=:uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=:" {
+			t.Errorf("unexpected Repr-Digest %q", got)
+		}
+		if got := w.Header().Get("Accept-Ranges"); got != "bytes" {
+			t.Errorf("expected Accept-Ranges: bytes, got %q", got)
+		}
+	})
+}
+
+func TestAttachmentHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		findErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "attachments of the task",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown task",
+			findErr:        domain.ErrTaskNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockAttachmentService{
+				findByTaskIDFunc: func(taskID string) ([]entities.Attachment, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					return []entities.Attachment{{ID: "a1", TaskID: taskID}, {ID: "a2", TaskID: taskID}}, nil
+				},
+			}
+
+			handler := NewAttachmentHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task1/attachments", nil)
+			req.SetPathValue("id", "task1")
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var attachments []entities.Attachment
+			if err := json.NewDecoder(w.Body).Decode(&attachments); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(attachments) != 2 {
+				t.Errorf("expected two attachments, got %+v", attachments)
+			}
+		})
+	}
+}
+
+func TestAttachmentHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		taskID         string
+		expectedStatus int
+		expectDelete   bool
+	}{
+		{
+			name:           "attachment of the task",
+			taskID:         "task1",
+			expectedStatus: http.StatusNoContent,
+			expectDelete:   true,
+		},
+		{
+			name:           "attachment of another task",
+			taskID:         "task2",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			mockService := &mockAttachmentService{
+				findByIDFunc: func(id string) (entities.Attachment, error) {
+					return entities.Attachment{ID: id, TaskID: "task1"}, nil
+				},
+				deleteFunc: func(id string) error {
+					deleted = id
+					return nil
+				},
+			}
+
+			handler := NewAttachmentHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/"+tt.taskID+"/attachments/a1", nil)
+			req.SetPathValue("id", tt.taskID)
+			req.SetPathValue("attachmentId", "a1")
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectDelete && deleted != "a1" {
+				t.Errorf("expected a1 to be deleted, got %q", deleted)
+			}
+			if !tt.expectDelete && deleted != "" {
+				t.Errorf("expected no delete, got %q", deleted)
+			}
+		})
+	}
+}
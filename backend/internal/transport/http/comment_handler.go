@@ -0,0 +1,241 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// defaultCommentPageSize applies when a comment list is requested without page and limit
+	defaultCommentPageSize = 20
+	// maxCommentPageSize caps the limit of a comment list
+	maxCommentPageSize = 100
+)
+
+// CommentHandler handles comments on tasks
+type CommentHandler struct {
+	service service.CommentService
+	logger  *slog.Logger
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(svc service.CommentService, logger *slog.Logger) *CommentHandler {
+	return &CommentHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// CommentRequest carries the Markdown body of a comment. ParentID turns a new
+// comment into a reply and is ignored on updates.
+type CommentRequest struct {
+	Body     string `json:"body" example:"Looks good, @jane@example.com please review"`
+	ParentID string `json:"parentId,omitempty" example:"507f1f77bcf86cd799439011"`
+}
+
+// List godoc
+// @Summary      List task comments
+// @Description  Get a page of the top-level comments of a task, oldest first, each with all of its replies
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id     path   string  true   "Task ID"
+// @Param        page   query  int     false  "Page number (1-based, default 1)"
+// @Param        limit  query  int     false  "Threads per page (default 20, at most 100)"
+// @Success      200  {object}  map[string]interface{}  "Paginated response with data, total, page, and limit"
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/comments [get]
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePaginationParams(r)
+	if page == 0 {
+		page, limit = 1, defaultCommentPageSize
+	}
+	limit = min(limit, maxCommentPageSize)
+
+	taskID := r.PathValue("id")
+	threads, total, err := h.service.FindByTaskID(r.Context(), taskID, limit, (page-1)*limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to list comments", "task_id", taskID, "error", err)
+		respondError(w, "Failed to list comments", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"data":  threads,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	}
+	respondJSON(w, response, http.StatusOK)
+}
+
+// Get godoc
+// @Summary      Get comment
+// @Description  Get a single comment with its edit history
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true  "Task ID"
+// @Param        commentId  path      string  true  "Comment ID"
+// @Success      200  {object}  entities.Comment
+// @Failure      404  {object}  map[string]string  "Comment not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/comments/{commentId} [get]
+func (h *CommentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	comment, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	respondJSON(w, comment, http.StatusOK)
+}
+
+// Create godoc
+// @Summary      Create comment
+// @Description  Comment on a task, or reply to a top-level comment with parentId. The body is Markdown; members mentioned as @userId or @email are recorded.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string          true  "Task ID"
+// @Param        comment  body      CommentRequest  true  "Comment"
+// @Success      201  {object}  entities.Comment
+// @Failure      400  {object}  map[string]string  "Invalid request body, empty body or reply to a reply"
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/comments [post]
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	author, _ := currentUser(r)
+	comment := &entities.Comment{
+		TaskID:   r.PathValue("id"),
+		ParentID: req.ParentID,
+		Author:   author,
+		Body:     req.Body,
+	}
+
+	if err := h.service.Create(r.Context(), comment); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to create comment", "task_id", comment.TaskID, "error", err)
+		respondError(w, "Failed to create comment", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, comment, http.StatusCreated)
+}
+
+// Update godoc
+// @Summary      Update comment
+// @Description  Replace the body of a comment; the previous body is kept in its edit history. Only the author can edit a comment.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string          true  "Task ID"
+// @Param        commentId  path      string          true  "Comment ID"
+// @Param        comment    body      CommentRequest  true  "New body"
+// @Success      200  {object}  entities.Comment
+// @Failure      400  {object}  map[string]string  "Invalid request body or empty body"
+// @Failure      403  {object}  map[string]string  "Not the author of the comment"
+// @Failure      404  {object}  map[string]string  "Comment not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/comments/{commentId} [put]
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	var req CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	editor, _ := currentUser(r)
+	comment := &entities.Comment{ID: existing.ID, Body: req.Body}
+	if err := h.service.Update(r.Context(), comment, editor); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrNotCommentAuthor) {
+			respondError(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update comment", "id", comment.ID, "error", err)
+		respondError(w, "Failed to update comment", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, comment, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete comment
+// @Description  Delete a comment together with its replies. Only the author can delete a comment.
+// @Tags         comments
+// @Accept       json
+// @Produce      json
+// @Param        id         path  string  true  "Task ID"
+// @Param        commentId  path  string  true  "Comment ID"
+// @Success      204  "No Content"
+// @Failure      403  {object}  map[string]string  "Not the author of the comment"
+// @Failure      404  {object}  map[string]string  "Comment not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	if err := h.service.Delete(r.Context(), existing.ID, actor); err != nil {
+		if errors.Is(err, domain.ErrNotCommentAuthor) {
+			respondError(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to delete comment", "id", existing.ID, "error", err)
+		respondError(w, "Failed to delete comment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// find loads the comment named in the path and checks that it belongs to the task in the path
+func (h *CommentHandler) find(w http.ResponseWriter, r *http.Request) (entities.Comment, bool) {
+	id := r.PathValue("commentId")
+	comment, err := h.service.FindByID(r.Context(), id)
+	if err != nil || comment.TaskID != r.PathValue("id") {
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to find comment", "id", id, "error", err)
+		}
+		respondError(w, "Comment not found", http.StatusNotFound)
+		return entities.Comment{}, false
+	}
+	return comment, true
+}
@@ -0,0 +1,374 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Mock CommentService for testing
+type mockCommentService struct {
+	createFunc       func(*entities.Comment) error
+	updateFunc       func(*entities.Comment, entities.UserRef) error
+	deleteFunc       func(string, entities.UserRef) error
+	findByIDFunc     func(string) (entities.Comment, error)
+	findByTaskIDFunc func(string, int, int) ([]entities.CommentThread, int64, error)
+}
+
+func (m *mockCommentService) Create(ctx context.Context, comment *entities.Comment) error {
+	if m.createFunc != nil {
+		return m.createFunc(comment)
+	}
+	return nil
+}
+
+func (m *mockCommentService) Update(ctx context.Context, comment *entities.Comment, editor entities.UserRef) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(comment, editor)
+	}
+	return nil
+}
+
+func (m *mockCommentService) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, actor)
+	}
+	return nil
+}
+
+func (m *mockCommentService) FindByID(ctx context.Context, id string) (entities.Comment, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(id)
+	}
+	return entities.Comment{}, errors.New("not found")
+}
+
+func (m *mockCommentService) FindByTaskID(ctx context.Context, taskID string, limit, offset int) ([]entities.CommentThread, int64, error) {
+	if m.findByTaskIDFunc != nil {
+		return m.findByTaskIDFunc(taskID, limit, offset)
+	}
+	return []entities.CommentThread{}, 0, nil
+}
+
+// janesComment is the comment c1 of jane on task1
+func janesComment(id string) (entities.Comment, error) {
+	if id != "c1" {
+		return entities.Comment{}, errors.New("comment not found")
+	}
+	return entities.Comment{ID: "c1", TaskID: "task1", Author: entities.UserRef{ID: "jane"}, Body: "Hello"}, nil
+}
+
+func TestCommentHandler_List(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		findErr        error
+		expectedStatus int
+		expectedLimit  int
+		expectedOffset int
+	}{
+		{
+			name:           "default page",
+			expectedStatus: http.StatusOK,
+			expectedLimit:  20,
+			expectedOffset: 0,
+		},
+		{
+			name:           "third page",
+			query:          "?page=3&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedLimit:  5,
+			expectedOffset: 10,
+		},
+		{
+			name:           "limit is capped",
+			query:          "?page=1&limit=1000",
+			expectedStatus: http.StatusOK,
+			expectedLimit:  100,
+			expectedOffset: 0,
+		},
+		{
+			name:           "unknown task",
+			findErr:        fmt.Errorf("%w: no task task1", domain.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limit, offset int
+			mockService := &mockCommentService{
+				findByTaskIDFunc: func(taskID string, l, o int) ([]entities.CommentThread, int64, error) {
+					if tt.findErr != nil {
+						return nil, 0, tt.findErr
+					}
+					limit, offset = l, o
+					comment, _ := janesComment("c1")
+					return []entities.CommentThread{{Comment: comment, Replies: []entities.Comment{}}}, 1, nil
+				},
+			}
+
+			handler := NewCommentHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task1/comments"+tt.query, nil)
+			req.SetPathValue("id", "task1")
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if limit != tt.expectedLimit || offset != tt.expectedOffset {
+				t.Errorf("expected limit %d and offset %d, got %d and %d", tt.expectedLimit, tt.expectedOffset, limit, offset)
+			}
+			var response struct {
+				Data  []entities.CommentThread `json:"data"`
+				Total int64                    `json:"total"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Total != 1 || len(response.Data) != 1 || response.Data[0].ID != "c1" {
+				t.Errorf("expected the thread of c1, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestCommentHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		authenticated  bool
+		createErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "authenticated",
+			body:           `{"body":"Nice"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "reply",
+			body:           `{"body":"Agreed","parentId":"c1"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "empty body",
+			body:           `{"body":""}`,
+			createErr:      &domain.ValidationError{Field: "body", Message: "is required"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown task",
+			body:           `{"body":"Nice"}`,
+			createErr:      fmt.Errorf("%w: no task task1", domain.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *entities.Comment
+			mockService := &mockCommentService{
+				createFunc: func(comment *entities.Comment) error {
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					comment.ID = "c2"
+					comment.HTML = "<p>" + comment.Body + "</p>\n"
+					created = comment
+					return nil
+				},
+			}
+
+			handler := NewCommentHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task1/comments", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "task1")
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			if created == nil || created.TaskID != "task1" {
+				t.Fatalf("expected a comment on task1, got %+v", created)
+			}
+			var comment entities.Comment
+			if err := json.NewDecoder(w.Body).Decode(&comment); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.authenticated && comment.Author != (entities.UserRef{ID: "jane", Name: "Jane Doe", Email: "jane@example.com"}) {
+				t.Errorf("expected jane as author, got %+v", comment.Author)
+			}
+			if comment.ID != "c2" || comment.HTML == "" {
+				t.Errorf("expected the rendered comment, got %+v", comment)
+			}
+		})
+	}
+}
+
+func TestCommentHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		taskID         string
+		commentID      string
+		authenticated  bool
+		expectedStatus int
+	}{
+		{
+			name:           "author edits",
+			taskID:         "task1",
+			commentID:      "c1",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "others cannot edit",
+			taskID:         "task1",
+			commentID:      "c1",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "comment of another task",
+			taskID:         "task2",
+			commentID:      "c1",
+			authenticated:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown comment",
+			taskID:         "task1",
+			commentID:      "c9",
+			authenticated:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *entities.Comment
+			mockService := &mockCommentService{
+				findByIDFunc: janesComment,
+				updateFunc: func(comment *entities.Comment, editor entities.UserRef) error {
+					if editor.ID != "jane" {
+						return domain.ErrNotCommentAuthor
+					}
+					existing, _ := janesComment(comment.ID)
+					existing.Edits = []entities.CommentEdit{{Body: existing.Body}}
+					existing.Body = comment.Body
+					*comment = existing
+					updated = comment
+					return nil
+				},
+			}
+
+			handler := NewCommentHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/"+tt.taskID+"/comments/"+tt.commentID, bytes.NewBufferString(`{"body":"Hello again"}`))
+			req.SetPathValue("id", tt.taskID)
+			req.SetPathValue("commentId", tt.commentID)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if updated == nil || updated.ID != "c1" || updated.Body != "Hello again" {
+				t.Fatalf("expected c1 to get the new body, got %+v", updated)
+			}
+			var comment entities.Comment
+			if err := json.NewDecoder(w.Body).Decode(&comment); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(comment.Edits) != 1 || comment.Edits[0].Body != "Hello" {
+				t.Errorf("expected the previous body in the history, got %+v", comment.Edits)
+			}
+		})
+	}
+}
+
+func TestCommentHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		authenticated  bool
+		expectedStatus int
+	}{
+		{
+			name:           "author deletes",
+			authenticated:  true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "others cannot delete",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			mockService := &mockCommentService{
+				findByIDFunc: janesComment,
+				deleteFunc: func(id string, actor entities.UserRef) error {
+					if actor.ID != "jane" {
+						return domain.ErrNotCommentAuthor
+					}
+					deleted = id
+					return nil
+				},
+			}
+
+			handler := NewCommentHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/task1/comments/c1", nil)
+			req.SetPathValue("id", "task1")
+			req.SetPathValue("commentId", "c1")
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusNoContent && deleted != "c1" {
+				t.Errorf("expected c1 to be deleted, got %q", deleted)
+			}
+		})
+	}
+}
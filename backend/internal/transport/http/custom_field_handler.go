@@ -0,0 +1,197 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// CustomFieldHandler handles requests for the custom field definitions of a project
+type CustomFieldHandler struct {
+	service service.CustomFieldService
+	logger  *slog.Logger
+}
+
+// NewCustomFieldHandler creates a new custom field handler
+func NewCustomFieldHandler(svc service.CustomFieldService, logger *slog.Logger) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// CreateCustomFieldRequest represents the request body for creating a custom field
+type CreateCustomFieldRequest struct {
+	Name    string   `json:"name" example:"Team"`
+	Type    string   `json:"type" example:"single_select" enums:"text,number,date,single_select,user"`
+	Options []string `json:"options,omitempty" example:"web,mobile"`
+}
+
+// UpdateCustomFieldRequest represents the request body for updating a custom field.
+// The type of a field cannot be changed.
+type UpdateCustomFieldRequest struct {
+	Name    string   `json:"name" example:"Team"`
+	Options []string `json:"options,omitempty" example:"web,mobile,backend"`
+}
+
+// List godoc
+// @Summary      List custom fields
+// @Description  Get the custom field definitions of a project
+// @Tags         custom-fields
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Project ID"
+// @Success      200  {array}   entities.CustomField
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/custom-fields [get]
+func (h *CustomFieldHandler) List(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+
+	fields, err := h.service.FindByProjectID(r.Context(), projectID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list custom fields", "project_id", projectID, "error", err)
+		respondError(w, "Failed to list custom fields", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, fields, http.StatusOK)
+}
+
+// Create godoc
+// @Summary      Create custom field
+// @Description  Define a typed custom field for the tasks of a project. single_select fields require options.
+// @Tags         custom-fields
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                    true  "Project ID"
+// @Param        field  body      CreateCustomFieldRequest  true  "Custom field to create"
+// @Success      201    {object}  entities.CustomField
+// @Failure      400    {object}  map[string]string  "Invalid request body, name, type or options"
+// @Failure      404    {object}  map[string]string  "Project not found"
+// @Failure      500    {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/custom-fields [post]
+func (h *CustomFieldHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateCustomFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	field := &entities.CustomField{
+		ProjectID: r.PathValue("id"),
+		Name:      req.Name,
+		Type:      entities.CustomFieldType(req.Type),
+		Options:   req.Options,
+	}
+
+	if err := h.service.Insert(r.Context(), field); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			respondError(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to create custom field", "error", err)
+		respondError(w, "Failed to create custom field", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, field, http.StatusCreated)
+}
+
+// Update godoc
+// @Summary      Update custom field
+// @Description  Rename a custom field or change the options of a single_select field. Options that tasks still use cannot be removed.
+// @Tags         custom-fields
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                    true  "Project ID"
+// @Param        fieldId  path      string                    true  "Custom field ID"
+// @Param        field    body      UpdateCustomFieldRequest  true  "Custom field updates"
+// @Success      200      {object}  entities.CustomField
+// @Failure      400      {object}  map[string]string  "Invalid request body, name or options, or a removed option still in use"
+// @Failure      404      {object}  map[string]string  "Custom field not found"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/custom-fields/{fieldId} [put]
+func (h *CustomFieldHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateCustomFieldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	field := &entities.CustomField{
+		ID:        existing.ID,
+		ProjectID: existing.ProjectID,
+		Name:      req.Name,
+		Type:      existing.Type,
+		Options:   req.Options,
+		CreatedAt: existing.CreatedAt,
+	}
+
+	if err := h.service.Update(r.Context(), field); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update custom field", "id", field.ID, "error", err)
+		respondError(w, "Failed to update custom field", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, field, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete custom field
+// @Description  Delete a custom field and remove its values from all tasks of the project
+// @Tags         custom-fields
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string  true  "Project ID"
+// @Param        fieldId  path  string  true  "Custom field ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  map[string]string  "Custom field not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/custom-fields/{fieldId} [delete]
+func (h *CustomFieldHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	field, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), field.ID); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to delete custom field", "id", field.ID, "error", err)
+		respondError(w, "Failed to delete custom field", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// find loads the field from the path and answers 404 unless it belongs to the project in the path
+func (h *CustomFieldHandler) find(w http.ResponseWriter, r *http.Request) (entities.CustomField, bool) {
+	id := r.PathValue("fieldId")
+	field, err := h.service.FindByID(r.Context(), id)
+	if err != nil || field.ProjectID != r.PathValue("id") {
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to find custom field", "id", id, "error", err)
+		}
+		respondError(w, "Custom field not found", http.StatusNotFound)
+		return entities.CustomField{}, false
+	}
+	return field, true
+}
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Mock CustomFieldService for testing
+type mockCustomFieldService struct {
+	insertFunc          func(*entities.CustomField) error
+	updateFunc          func(*entities.CustomField) error
+	deleteFunc          func(string) error
+	findByIDFunc        func(string) (entities.CustomField, error)
+	findByProjectIDFunc func(string) ([]entities.CustomField, error)
+}
+
+func (m *mockCustomFieldService) Insert(ctx context.Context, field *entities.CustomField) error {
+	if m.insertFunc != nil {
+		return m.insertFunc(field)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldService) Update(ctx context.Context, field *entities.CustomField) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(field)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldService) Delete(ctx context.Context, id string) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id)
+	}
+	return nil
+}
+
+func (m *mockCustomFieldService) FindByID(ctx context.Context, id string) (entities.CustomField, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(id)
+	}
+	return entities.CustomField{}, errors.New("not found")
+}
+
+func (m *mockCustomFieldService) FindByProjectID(ctx context.Context, projectID string) ([]entities.CustomField, error) {
+	if m.findByProjectIDFunc != nil {
+		return m.findByProjectIDFunc(projectID)
+	}
+	return []entities.CustomField{}, nil
+}
+
+// teamField is a single_select field of project 123
+func teamField(id string) (entities.CustomField, error) {
+	if id != "team" {
+		return entities.CustomField{}, errors.New("not found")
+	}
+	return entities.CustomField{ID: "team", ProjectID: "123", Name: "Team", Type: entities.CustomFieldTypeSingleSelect, Options: []string{"web", "mobile"}}, nil
+}
+
+func TestCustomFieldHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		insertErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "valid field",
+			body:           `{"name":"Team","type":"single_select","options":["web","mobile"]}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "validation error",
+			body:           `{"name":"","type":"text"}`,
+			insertErr:      &domain.ValidationError{Field: "name", Message: "is required"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown project",
+			body:           `{"name":"Team","type":"text"}`,
+			insertErr:      domain.ErrProjectNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockCustomFieldService{
+				insertFunc: func(field *entities.CustomField) error {
+					if tt.insertErr != nil {
+						return tt.insertErr
+					}
+					field.ID = "new-field"
+					return nil
+				},
+			}
+
+			handler := NewCustomFieldHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/custom-fields", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "123")
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			var field entities.CustomField
+			if err := json.NewDecoder(w.Body).Decode(&field); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if field.ID != "new-field" || field.ProjectID != "123" || field.Type != entities.CustomFieldTypeSingleSelect {
+				t.Errorf("expected a single_select field of project 123, got %+v", field)
+			}
+		})
+	}
+}
+
+func TestCustomFieldHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		projectID      string
+		fieldID        string
+		body           string
+		updateErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "options changed",
+			projectID:      "123",
+			fieldID:        "team",
+			body:           `{"name":"Squad","options":["web","mobile","backend"]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "validation error",
+			projectID:      "123",
+			fieldID:        "team",
+			body:           `{"name":"Team"}`,
+			updateErr:      &domain.ValidationError{Field: "options", Message: "are required for single_select fields"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			projectID:      "123",
+			fieldID:        "team",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "field of another project",
+			projectID:      "456",
+			fieldID:        "team",
+			body:           `{"name":"Team","options":["web"]}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown field",
+			projectID:      "123",
+			fieldID:        "points",
+			body:           `{"name":"Points"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *entities.CustomField
+			mockService := &mockCustomFieldService{
+				findByIDFunc: teamField,
+				updateFunc: func(field *entities.CustomField) error {
+					updated = field
+					return tt.updateErr
+				},
+			}
+
+			handler := NewCustomFieldHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/projects/"+tt.projectID+"/custom-fields/"+tt.fieldID, bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", tt.projectID)
+			req.SetPathValue("fieldId", tt.fieldID)
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if updated == nil || updated.Name != "Squad" || updated.ProjectID != "123" || updated.Type != entities.CustomFieldTypeSingleSelect || len(updated.Options) != 3 {
+				t.Errorf("expected the new name and options with the stored project and type, got %+v", updated)
+			}
+		})
+	}
+}
+
+func TestCustomFieldHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		projectID      string
+		expectedStatus int
+		expectDelete   bool
+	}{
+		{
+			name:           "field of the project",
+			projectID:      "123",
+			expectedStatus: http.StatusNoContent,
+			expectDelete:   true,
+		},
+		{
+			name:           "field of another project",
+			projectID:      "456",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			mockService := &mockCustomFieldService{
+				findByIDFunc: teamField,
+				deleteFunc: func(id string) error {
+					deleted = id
+					return nil
+				},
+			}
+
+			handler := NewCustomFieldHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/projects/"+tt.projectID+"/custom-fields/team", nil)
+			req.SetPathValue("id", tt.projectID)
+			req.SetPathValue("fieldId", "team")
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectDelete && deleted != "team" {
+				t.Errorf("expected team to be deleted, got %q", deleted)
+			}
+			if !tt.expectDelete && deleted != "" {
+				t.Errorf("expected no delete, got %q", deleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// DependencyHandler handles "blocks" links between tasks
+type DependencyHandler struct {
+	service service.DependencyService
+	logger  *slog.Logger
+}
+
+// NewDependencyHandler creates a new dependency handler
+func NewDependencyHandler(svc service.DependencyService, logger *slog.Logger) *DependencyHandler {
+	return &DependencyHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// DependencyRequest names the task at the other end of a new link
+type DependencyRequest struct {
+	TaskID string `json:"taskId" example:"507f1f77bcf86cd799439011"`
+}
+
+// List godoc
+// @Summary      List task dependencies
+// @Description  Get the tasks blocking a task and the tasks it blocks, across projects
+// @Tags         dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {object}  entities.TaskDependencies
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/dependencies [get]
+func (h *DependencyHandler) List(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	dependencies, err := h.service.FindByTaskID(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list dependencies", "id", id, "error", err)
+		respondError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, dependencies, http.StatusOK)
+}
+
+// AddBlocker godoc
+// @Summary      Add blocker
+// @Description  Record that another task, possibly of another project, has to be done before this task
+// @Tags         dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string             true  "Blocked task ID"
+// @Param        dependency  body      DependencyRequest  true  "Blocking task"
+// @Success      201         {object}  entities.TaskDependency
+// @Failure      400         {object}  map[string]string  "Invalid request body, unknown task or duplicate link"
+// @Failure      409         {object}  map[string]string  "Link would create a cycle"
+// @Failure      500         {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/blocked-by [post]
+func (h *DependencyHandler) AddBlocker(w http.ResponseWriter, r *http.Request) {
+	var req DependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	h.add(w, r, req.TaskID, r.PathValue("id"))
+}
+
+// AddBlocked godoc
+// @Summary      Add blocked task
+// @Description  Record that this task has to be done before another task, possibly of another project
+// @Tags         dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string             true  "Blocking task ID"
+// @Param        dependency  body      DependencyRequest  true  "Blocked task"
+// @Success      201         {object}  entities.TaskDependency
+// @Failure      400         {object}  map[string]string  "Invalid request body, unknown task or duplicate link"
+// @Failure      409         {object}  map[string]string  "Link would create a cycle"
+// @Failure      500         {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/blocks [post]
+func (h *DependencyHandler) AddBlocked(w http.ResponseWriter, r *http.Request) {
+	var req DependencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	h.add(w, r, r.PathValue("id"), req.TaskID)
+}
+
+func (h *DependencyHandler) add(w http.ResponseWriter, r *http.Request, blockerID, blockedID string) {
+	dependency, err := h.service.Add(r.Context(), blockerID, blockedID)
+	if err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrDependencyCycle) {
+			respondError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to add dependency", "blocker_id", blockerID, "blocked_id", blockedID, "error", err)
+		respondError(w, "Failed to add dependency", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, dependency, http.StatusCreated)
+}
+
+// RemoveBlocker godoc
+// @Summary      Remove blocker
+// @Description  Remove the link from a blocking task to this task
+// @Tags         dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id         path  string  true  "Blocked task ID"
+// @Param        blockerId  path  string  true  "Blocking task ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  map[string]string  "Dependency not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/blocked-by/{blockerId} [delete]
+func (h *DependencyHandler) RemoveBlocker(w http.ResponseWriter, r *http.Request) {
+	h.remove(w, r, r.PathValue("blockerId"), r.PathValue("id"))
+}
+
+// RemoveBlocked godoc
+// @Summary      Remove blocked task
+// @Description  Remove the link from this task to a task it blocks
+// @Tags         dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id         path  string  true  "Blocking task ID"
+// @Param        blockedId  path  string  true  "Blocked task ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  map[string]string  "Dependency not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/blocks/{blockedId} [delete]
+func (h *DependencyHandler) RemoveBlocked(w http.ResponseWriter, r *http.Request) {
+	h.remove(w, r, r.PathValue("id"), r.PathValue("blockedId"))
+}
+
+func (h *DependencyHandler) remove(w http.ResponseWriter, r *http.Request, blockerID, blockedID string) {
+	if err := h.service.Remove(r.Context(), blockerID, blockedID); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to remove dependency", "blocker_id", blockerID, "blocked_id", blockedID, "error", err)
+		respondError(w, "Dependency not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Graph godoc
+// @Summary      Get dependency graph
+// @Description  Get the dependency DAG of a project's tasks, including linked tasks of other projects.
+// @Description  order lists every blocker before the tasks it blocks, earlier due dates first.
+// @Description  criticalPath is the chain of open tasks whose due dates span the longest time.
+// @Tags         dependencies
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Project ID"
+// @Success      200  {object}  entities.TaskGraph
+// @Failure      404  {object}  map[string]string  "Project not found"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/tasks/graph [get]
+func (h *DependencyHandler) Graph(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	graph, err := h.service.Graph(r.Context(), projectID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to build dependency graph", "project_id", projectID, "error", err)
+		respondError(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, graph, http.StatusOK)
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Mock DependencyService for testing
+type mockDependencyService struct {
+	addFunc          func(string, string) (entities.TaskDependency, error)
+	removeFunc       func(string, string) error
+	findByTaskIDFunc func(string) (entities.TaskDependencies, error)
+	graphFunc        func(string) (entities.TaskGraph, error)
+}
+
+func (m *mockDependencyService) Add(ctx context.Context, blockerID, blockedID string) (entities.TaskDependency, error) {
+	if m.addFunc != nil {
+		return m.addFunc(blockerID, blockedID)
+	}
+	return entities.TaskDependency{BlockerID: blockerID, BlockedID: blockedID}, nil
+}
+
+func (m *mockDependencyService) Remove(ctx context.Context, blockerID, blockedID string) error {
+	if m.removeFunc != nil {
+		return m.removeFunc(blockerID, blockedID)
+	}
+	return nil
+}
+
+func (m *mockDependencyService) FindByTaskID(ctx context.Context, taskID string) (entities.TaskDependencies, error) {
+	if m.findByTaskIDFunc != nil {
+		return m.findByTaskIDFunc(taskID)
+	}
+	return entities.TaskDependencies{BlockedBy: []entities.TaskDependency{}, Blocks: []entities.TaskDependency{}}, nil
+}
+
+func (m *mockDependencyService) Graph(ctx context.Context, projectID string) (entities.TaskGraph, error) {
+	if m.graphFunc != nil {
+		return m.graphFunc(projectID)
+	}
+	return entities.TaskGraph{}, errors.New("not found")
+}
+
+func TestDependencyHandler_Add(t *testing.T) {
+	tests := []struct {
+		name            string
+		blocker         bool
+		body            string
+		addErr          error
+		expectedStatus  int
+		expectedBlocker string
+		expectedBlocked string
+	}{
+		{
+			name:            "blocked by",
+			blocker:         true,
+			body:            `{"taskId":"early"}`,
+			expectedStatus:  http.StatusCreated,
+			expectedBlocker: "early",
+			expectedBlocked: "task1",
+		},
+		{
+			name:            "blocks",
+			body:            `{"taskId":"late"}`,
+			expectedStatus:  http.StatusCreated,
+			expectedBlocker: "task1",
+			expectedBlocked: "late",
+		},
+		{
+			name:           "self",
+			body:           `{"taskId":"task1"}`,
+			addErr:         &domain.ValidationError{Field: "blockerId", Message: "a task cannot block itself"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "cycle",
+			blocker:        true,
+			body:           `{"taskId":"late"}`,
+			addErr:         fmt.Errorf("%w: \"Late\" already waits for \"Early\"", domain.ErrDependencyCycle),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var blocker, blocked string
+			mockService := &mockDependencyService{
+				addFunc: func(blockerID, blockedID string) (entities.TaskDependency, error) {
+					if tt.addErr != nil {
+						return entities.TaskDependency{}, tt.addErr
+					}
+					blocker, blocked = blockerID, blockedID
+					return entities.TaskDependency{BlockerID: blockerID, BlockedID: blockedID}, nil
+				},
+			}
+
+			handler := NewDependencyHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task1/blocks", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "task1")
+			w := httptest.NewRecorder()
+
+			if tt.blocker {
+				handler.AddBlocker(w, req)
+			} else {
+				handler.AddBlocked(w, req)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if blocker != tt.expectedBlocker || blocked != tt.expectedBlocked {
+				t.Errorf("expected link %q -> %q, got %q -> %q", tt.expectedBlocker, tt.expectedBlocked, blocker, blocked)
+			}
+		})
+	}
+}
+
+func TestDependencyHandler_Remove(t *testing.T) {
+	tests := []struct {
+		name           string
+		blocker        bool
+		expectedStatus int
+	}{
+		{
+			name:           "blocked by",
+			blocker:        true,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "link in the other direction",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockDependencyService{
+				removeFunc: func(blockerID, blockedID string) error {
+					if blockerID == "early" && blockedID == "task1" {
+						return nil
+					}
+					return errors.New("dependency not found")
+				},
+			}
+
+			handler := NewDependencyHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/task1/blocks/early", nil)
+			req.SetPathValue("id", "task1")
+			w := httptest.NewRecorder()
+
+			if tt.blocker {
+				req.SetPathValue("blockerId", "early")
+				handler.RemoveBlocker(w, req)
+			} else {
+				req.SetPathValue("blockedId", "early")
+				handler.RemoveBlocked(w, req)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDependencyHandler_Graph(t *testing.T) {
+	mockService := &mockDependencyService{
+		graphFunc: func(projectID string) (entities.TaskGraph, error) {
+			if projectID != "123" {
+				return entities.TaskGraph{}, errors.New("project not found")
+			}
+			return entities.TaskGraph{
+				Nodes:        []entities.TaskGraphNode{{ID: "early", ProjectID: "123"}, {ID: "late", ProjectID: "123"}},
+				Edges:        []entities.TaskDependency{{BlockerID: "early", BlockedID: "late"}},
+				Order:        []string{"early", "late"},
+				CriticalPath: []string{"early", "late"},
+			}, nil
+		},
+	}
+
+	handler := NewDependencyHandler(mockService, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/123/tasks/graph", nil)
+	req.SetPathValue("id", "123")
+	w := httptest.NewRecorder()
+
+	handler.Graph(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var graph entities.TaskGraph
+	if err := json.NewDecoder(w.Body).Decode(&graph); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(graph.Nodes) != 2 || len(graph.CriticalPath) != 2 || graph.Order[0] != "early" {
+		t.Errorf("unexpected graph %+v", graph)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/456/tasks/graph", nil)
+	req.SetPathValue("id", "456")
+	w = httptest.NewRecorder()
+
+	handler.Graph(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,187 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// LabelHandler handles label-related HTTP requests
+type LabelHandler struct {
+	service service.LabelService
+	logger  *slog.Logger
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(svc service.LabelService, logger *slog.Logger) *LabelHandler {
+	return &LabelHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// LabelRequest represents the request body for creating or updating a label
+type LabelRequest struct {
+	Name  string `json:"name" example:"bug"`
+	Color string `json:"color" example:"#d73a4a"`
+}
+
+// List godoc
+// @Summary      List labels
+// @Description  Get the labels of a project sorted by name
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Project ID"
+// @Success      200  {array}   entities.Label
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/labels [get]
+func (h *LabelHandler) List(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+
+	labels, err := h.service.FindByProjectID(r.Context(), projectID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list labels", "project_id", projectID, "error", err)
+		respondError(w, "Failed to list labels", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, labels, http.StatusOK)
+}
+
+// Create godoc
+// @Summary      Create label
+// @Description  Create a label for a project. Names are unique per project, ignoring case.
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string        true  "Project ID"
+// @Param        label  body      LabelRequest  true  "Label to create"
+// @Success      201    {object}  entities.Label
+// @Failure      400    {object}  map[string]string  "Invalid request body, name or colour"
+// @Failure      404    {object}  map[string]string  "Project not found"
+// @Failure      500    {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/labels [post]
+func (h *LabelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req LabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	label := &entities.Label{
+		ProjectID: r.PathValue("id"),
+		Name:      req.Name,
+		Color:     req.Color,
+	}
+
+	if err := h.service.Insert(r.Context(), label); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			respondError(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to create label", "error", err)
+		respondError(w, "Failed to create label", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, label, http.StatusCreated)
+}
+
+// Update godoc
+// @Summary      Update label
+// @Description  Rename a label or change its colour
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string        true  "Project ID"
+// @Param        labelId  path      string        true  "Label ID"
+// @Param        label    body      LabelRequest  true  "Label updates"
+// @Success      200      {object}  entities.Label
+// @Failure      400      {object}  map[string]string  "Invalid request body, name or colour"
+// @Failure      404      {object}  map[string]string  "Label not found"
+// @Failure      500      {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/labels/{labelId} [put]
+func (h *LabelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	var req LabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	label := &entities.Label{
+		ID:        existing.ID,
+		ProjectID: existing.ProjectID,
+		Name:      req.Name,
+		Color:     req.Color,
+		CreatedAt: existing.CreatedAt,
+	}
+
+	if err := h.service.Update(r.Context(), label); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update label", "id", label.ID, "error", err)
+		respondError(w, "Failed to update label", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, label, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete label
+// @Description  Delete a label and remove it from all tasks of the project
+// @Tags         labels
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string  true  "Project ID"
+// @Param        labelId  path  string  true  "Label ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  map[string]string  "Label not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/labels/{labelId} [delete]
+func (h *LabelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	label, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), label.ID); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to delete label", "id", label.ID, "error", err)
+		respondError(w, "Failed to delete label", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// find loads the label from the path and answers 404 unless it belongs to the project in the path
+func (h *LabelHandler) find(w http.ResponseWriter, r *http.Request) (entities.Label, bool) {
+	id := r.PathValue("labelId")
+	label, err := h.service.FindByID(r.Context(), id)
+	if err != nil || label.ProjectID != r.PathValue("id") {
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to find label", "id", id, "error", err)
+		}
+		respondError(w, "Label not found", http.StatusNotFound)
+		return entities.Label{}, false
+	}
+	return label, true
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Mock LabelService for testing
+type mockLabelService struct {
+	insertFunc          func(*entities.Label) error
+	updateFunc          func(*entities.Label) error
+	deleteFunc          func(string) error
+	findByIDFunc        func(string) (entities.Label, error)
+	findByProjectIDFunc func(string) ([]entities.Label, error)
+}
+
+func (m *mockLabelService) Insert(ctx context.Context, label *entities.Label) error {
+	if m.insertFunc != nil {
+		return m.insertFunc(label)
+	}
+	return nil
+}
+
+func (m *mockLabelService) Update(ctx context.Context, label *entities.Label) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(label)
+	}
+	return nil
+}
+
+func (m *mockLabelService) Delete(ctx context.Context, id string) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id)
+	}
+	return nil
+}
+
+func (m *mockLabelService) FindByID(ctx context.Context, id string) (entities.Label, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(id)
+	}
+	return entities.Label{}, errors.New("not found")
+}
+
+func (m *mockLabelService) FindByProjectID(ctx context.Context, projectID string) ([]entities.Label, error) {
+	if m.findByProjectIDFunc != nil {
+		return m.findByProjectIDFunc(projectID)
+	}
+	return []entities.Label{}, nil
+}
+
+func TestLabelHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		insertErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "valid label",
+			body:           `{"name":"bug","color":"#d73a4a"}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "validation error",
+			body:           `{"name":"","color":"#d73a4a"}`,
+			insertErr:      &domain.ValidationError{Field: "name", Message: "is required"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown project",
+			body:           `{"name":"bug","color":"#d73a4a"}`,
+			insertErr:      domain.ErrProjectNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inserted *entities.Label
+			mockService := &mockLabelService{
+				insertFunc: func(label *entities.Label) error {
+					inserted = label
+					if tt.insertErr != nil {
+						return tt.insertErr
+					}
+					label.ID = "new-label"
+					return nil
+				},
+			}
+
+			handler := NewLabelHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/labels", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "123")
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			if inserted == nil || inserted.ProjectID != "123" {
+				t.Errorf("expected the label to be added to project 123, got %+v", inserted)
+			}
+			var label entities.Label
+			if err := json.NewDecoder(w.Body).Decode(&label); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if label.ID != "new-label" || label.Name != "bug" {
+				t.Errorf("expected the new label, got %+v", label)
+			}
+		})
+	}
+}
+
+func TestLabelHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		projectID      string
+		expectedStatus int
+		expectDelete   bool
+	}{
+		{
+			name:           "label of the project",
+			projectID:      "123",
+			expectedStatus: http.StatusNoContent,
+			expectDelete:   true,
+		},
+		{
+			name:           "label of another project",
+			projectID:      "456",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			mockService := &mockLabelService{
+				findByIDFunc: func(id string) (entities.Label, error) {
+					return entities.Label{ID: id, ProjectID: "123", Name: "bug"}, nil
+				},
+				deleteFunc: func(id string) error {
+					deleted = id
+					return nil
+				},
+			}
+
+			handler := NewLabelHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/projects/"+tt.projectID+"/labels/label1", nil)
+			req.SetPathValue("id", tt.projectID)
+			req.SetPathValue("labelId", "label1")
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectDelete && deleted != "label1" {
+				t.Errorf("expected label1 to be deleted, got %q", deleted)
+			}
+			if !tt.expectDelete && deleted != "" {
+				t.Errorf("expected no delete, got %q", deleted)
+			}
+		})
+	}
+}
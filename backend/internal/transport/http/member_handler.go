@@ -0,0 +1,187 @@
+package http
+
+import (
+	"boilerplate/internal/auth"
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// meAlias stands for the authenticated user wherever a user ID is expected
+const meAlias = "me"
+
+var errNotAuthenticated = errors.New("Authentication required")
+
+// MemberHandler handles project membership requests
+type MemberHandler struct {
+	service service.MemberService
+	logger  *slog.Logger
+}
+
+// NewMemberHandler creates a new member handler
+func NewMemberHandler(svc service.MemberService, logger *slog.Logger) *MemberHandler {
+	return &MemberHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// MemberRequest carries the display name and email of a member added by
+// someone else. Users adding themselves get theirs from the access token.
+type MemberRequest struct {
+	Name  string `json:"name,omitempty" example:"Jane Doe"`
+	Email string `json:"email,omitempty" example:"jane@example.com"`
+}
+
+// List godoc
+// @Summary      List project members
+// @Description  Get the users taking part in a project sorted by name
+// @Tags         members
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Project ID"
+// @Success      200  {array}   entities.ProjectMember
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/members [get]
+func (h *MemberHandler) List(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+
+	members, err := h.service.FindByProjectID(r.Context(), projectID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list members", "project_id", projectID, "error", err)
+		respondError(w, "Failed to list members", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, members, http.StatusOK)
+}
+
+// Put godoc
+// @Summary      Add project member
+// @Description  Add a user, identified by the Keycloak subject, to a project or refresh the stored name and email.
+// @Description  Use "me" or your own subject to add yourself with the name and email of your access token.
+// @Tags         members
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string         true   "Project ID"
+// @Param        userId  path      string         true   "Keycloak subject of the user, or me"
+// @Param        member  body      MemberRequest  false  "Name and email of another user"
+// @Success      200     {object}  entities.ProjectMember
+// @Failure      400     {object}  map[string]string  "Invalid request body"
+// @Failure      401     {object}  map[string]string  "me used without authentication"
+// @Failure      404     {object}  map[string]string  "Project not found"
+// @Failure      500     {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/members/{userId} [put]
+func (h *MemberHandler) Put(w http.ResponseWriter, r *http.Request) {
+	var req MemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := resolveUser(r, r.PathValue("userId"))
+	if err != nil {
+		respondError(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if user.Name == "" && user.Email == "" {
+		user.Name, user.Email = req.Name, req.Email
+	}
+
+	member := &entities.ProjectMember{
+		ProjectID: r.PathValue("id"),
+		UserID:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+	}
+	if err := h.service.Add(r.Context(), member); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			respondError(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to add member", "project_id", member.ProjectID, "error", err)
+		respondError(w, "Failed to add member", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, member, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Remove project member
+// @Description  Remove a user from a project. They are unassigned from the project's tasks and stop watching them.
+// @Tags         members
+// @Accept       json
+// @Produce      json
+// @Param        id      path  string  true  "Project ID"
+// @Param        userId  path  string  true  "Keycloak subject of the user, or me"
+// @Success      204  "No Content"
+// @Failure      401  {object}  map[string]string  "me used without authentication"
+// @Failure      404  {object}  map[string]string  "Member not found"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/members/{userId} [delete]
+func (h *MemberHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	user, err := resolveUser(r, r.PathValue("userId"))
+	if err != nil {
+		respondError(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	projectID := r.PathValue("id")
+	if err := h.service.Remove(r.Context(), projectID, user.ID); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to remove member", "project_id", projectID, "user_id", user.ID, "error", err)
+		respondError(w, "Member not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// currentUser returns the authenticated user with the name and email of their token
+func currentUser(r *http.Request) (entities.UserRef, bool) {
+	claims, ok := auth.GetUserClaims(r.Context())
+	if !ok || claims.Subject == "" {
+		return entities.UserRef{}, false
+	}
+	return entities.UserRef{ID: claims.Subject, Name: claims.Name, Email: claims.Email}, true
+}
+
+// resolveUser turns a user ID from a request into a user reference. "me" and
+// the caller's own ID carry the name and email of the token; other IDs are
+// returned bare.
+func resolveUser(r *http.Request, id string) (entities.UserRef, error) {
+	user, ok := currentUser(r)
+	if id == meAlias {
+		if !ok {
+			return entities.UserRef{}, errNotAuthenticated
+		}
+		return user, nil
+	}
+	if ok && id == user.ID {
+		return user, nil
+	}
+	return entities.UserRef{ID: id}, nil
+}
+
+// resolveUsers applies resolveUser to a list of user IDs
+func resolveUsers(r *http.Request, ids []string) ([]entities.UserRef, error) {
+	users := make([]entities.UserRef, 0, len(ids))
+	for _, id := range ids {
+		user, err := resolveUser(r, id)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
@@ -0,0 +1,291 @@
+package http
+
+import (
+	"boilerplate/internal/auth"
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Mock MemberService for testing
+type mockMemberService struct {
+	addFunc             func(*entities.ProjectMember) error
+	removeFunc          func(string, string) error
+	findByProjectIDFunc func(string) ([]entities.ProjectMember, error)
+}
+
+func (m *mockMemberService) Add(ctx context.Context, member *entities.ProjectMember) error {
+	if m.addFunc != nil {
+		return m.addFunc(member)
+	}
+	return nil
+}
+
+func (m *mockMemberService) Remove(ctx context.Context, projectID, userID string) error {
+	if m.removeFunc != nil {
+		return m.removeFunc(projectID, userID)
+	}
+	return nil
+}
+
+func (m *mockMemberService) FindByProjectID(ctx context.Context, projectID string) ([]entities.ProjectMember, error) {
+	if m.findByProjectIDFunc != nil {
+		return m.findByProjectIDFunc(projectID)
+	}
+	return []entities.ProjectMember{}, nil
+}
+
+// withUser authenticates a request as jane
+func withUser(req *http.Request) *http.Request {
+	claims := &auth.UserClaims{Subject: "jane", Name: "Jane Doe", Email: "jane@example.com"}
+	return req.WithContext(context.WithValue(req.Context(), auth.UserContextKey, claims))
+}
+
+func TestMemberHandler_Put(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           string
+		authenticated  bool
+		addErr         error
+		expectedStatus int
+		expectedMember *entities.ProjectMember
+	}{
+		{
+			name:           "me",
+			userID:         "me",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedMember: &entities.ProjectMember{ProjectID: "123", UserID: "jane", Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		{
+			name:           "own ID ignores body",
+			userID:         "jane",
+			body:           `{"name":"Someone"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedMember: &entities.ProjectMember{ProjectID: "123", UserID: "jane", Name: "Jane Doe", Email: "jane@example.com"},
+		},
+		{
+			name:           "other user",
+			userID:         "joe",
+			body:           `{"name":"Joe"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedMember: &entities.ProjectMember{ProjectID: "123", UserID: "joe", Name: "Joe"},
+		},
+		{
+			name:           "me without authentication",
+			userID:         "me",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "invalid JSON",
+			userID:         "joe",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown project",
+			userID:         "me",
+			authenticated:  true,
+			addErr:         fmt.Errorf("%w: 123", domain.ErrProjectNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var added *entities.ProjectMember
+			mockService := &mockMemberService{
+				addFunc: func(member *entities.ProjectMember) error {
+					if tt.addErr != nil {
+						return tt.addErr
+					}
+					added = member
+					return nil
+				},
+			}
+
+			handler := NewMemberHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/projects/123/members/"+tt.userID, bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "123")
+			req.SetPathValue("userId", tt.userID)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Put(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			switch {
+			case tt.expectedMember == nil && added != nil:
+				t.Errorf("expected no member, got %+v", added)
+			case tt.expectedMember != nil && (added == nil || *added != *tt.expectedMember):
+				t.Errorf("expected member %+v, got %+v", tt.expectedMember, added)
+			}
+		})
+	}
+}
+
+func TestMemberHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		expectedStatus int
+	}{
+		{
+			name:           "me",
+			userID:         "me",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "not a member",
+			userID:         "joe",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockMemberService{
+				removeFunc: func(projectID, userID string) error {
+					if projectID == "123" && userID == "jane" {
+						return nil
+					}
+					return errors.New("member not found")
+				},
+			}
+
+			handler := NewMemberHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/projects/123/members/"+tt.userID, nil)
+			req.SetPathValue("id", "123")
+			req.SetPathValue("userId", tt.userID)
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, withUser(req))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestProjectHandler_CreateAddsCreatorAsMember(t *testing.T) {
+	mockService := &mockProjectService{
+		insertFunc: func(project *entities.Project) error {
+			project.ID = "new-id"
+			return nil
+		},
+	}
+	var added *entities.ProjectMember
+	members := &mockMemberService{
+		addFunc: func(member *entities.ProjectMember) error {
+			added = member
+			return nil
+		},
+	}
+	handler := NewProjectHandler(mockService, members, testLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects", bytes.NewBufferString(`{"name":"New Project"}`))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, withUser(req))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+	expected := entities.ProjectMember{ProjectID: "new-id", UserID: "jane", Name: "Jane Doe", Email: "jane@example.com"}
+	if added == nil || *added != expected {
+		t.Errorf("expected creator %+v to become a member, got %+v", expected, added)
+	}
+}
+
+func TestTaskHandler_Assignees(t *testing.T) {
+	var filter entities.TaskFilter
+	var created *entities.Task
+	mockService := &mockTaskService{
+		findByProjectIDFunc: func(projectID string, f entities.TaskFilter) ([]entities.Task, error) {
+			filter = f
+			return []entities.Task{}, nil
+		},
+		insertFunc: func(task *entities.Task) error {
+			created = task
+			return nil
+		},
+		findByAssigneeFunc: func(userID string) ([]entities.Task, error) {
+			return []entities.Task{{ID: "task1", Assignees: []entities.UserRef{{ID: userID}}}}, nil
+		},
+	}
+	handler := NewTaskHandler(mockService, testLogger())
+
+	t.Run("assignee=me", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/123/tasks?assignee=me", nil)
+		req.SetPathValue("id", "123")
+		w := httptest.NewRecorder()
+
+		handler.ListByProject(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d without authentication, got %d", http.StatusUnauthorized, w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		handler.ListByProject(w, withUser(req))
+		if w.Code != http.StatusOK || filter.AssigneeID != "jane" {
+			t.Errorf("expected tasks of jane, got status %d and filter %+v", w.Code, filter)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		body := `{"title":"Task","assigneeIds":["me","joe"],"watcherIds":["joe"]}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/tasks", bytes.NewReader([]byte(body)))
+		req.SetPathValue("id", "123")
+		w := httptest.NewRecorder()
+
+		handler.CreateForProject(w, withUser(req))
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, w.Code)
+		}
+		expected := []entities.UserRef{{ID: "jane", Name: "Jane Doe", Email: "jane@example.com"}, {ID: "joe"}}
+		if len(created.Assignees) != 2 || created.Assignees[0] != expected[0] || created.Assignees[1] != expected[1] {
+			t.Errorf("expected assignees %+v, got %+v", expected, created.Assignees)
+		}
+		if len(created.Watchers) != 1 || created.Watchers[0].ID != "joe" {
+			t.Errorf("expected joe to watch, got %+v", created.Watchers)
+		}
+	})
+
+	t.Run("me/tasks", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/me/tasks", nil)
+		w := httptest.NewRecorder()
+
+		handler.ListMine(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d without authentication, got %d", http.StatusUnauthorized, w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		handler.ListMine(w, withUser(req))
+		var tasks []entities.Task
+		if err := json.NewDecoder(w.Body).Decode(&tasks); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].Assignees[0].ID != "jane" {
+			t.Errorf("expected the tasks of jane, got %+v", tasks)
+		}
+	})
+}
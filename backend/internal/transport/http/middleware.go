@@ -2,16 +2,30 @@ package http
 
 import (
 	"boilerplate/internal/config"
+	"boilerplate/internal/logger"
+	"boilerplate/internal/metrics"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"runtime/debug"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func CORSMiddleware(cfg config.CORSConfig) func(http.Handler) http.Handler {
+// CORSMiddleware sets the CORS headers. The settings are read from the live
+// configuration on every request so that reloaded origins apply immediately.
+func CORSMiddleware(live *config.Live) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cfg := live.Get().CORS
 			origin := r.Header.Get("Origin")
 
 			if isOriginAllowed(origin, cfg.AllowedOrigins) {
@@ -75,7 +89,7 @@ func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 			defer func() {
 				if err := recover(); err != nil {
 					// Log the panic with stack trace
-					logger.Error("panic recovered in HTTP handler",
+					logger.ErrorContext(r.Context(), "panic recovered in HTTP handler",
 						"error", err,
 						"method", r.Method,
 						"path", r.URL.Path,
@@ -92,3 +106,127 @@ func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// MetricsMiddleware records request count and latency. Requests are labelled with the
+// route pattern registered on mux (e.g. /api/v1/tasks/{id}) instead of the raw path.
+func MetricsMiddleware(m *metrics.Metrics, mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			m.ObserveHTTPRequest(r.Method, routePattern(mux, r), rw.statusCode, time.Since(start))
+		})
+	}
+}
+
+// TracingMiddleware extracts the W3C trace context from incoming requests and
+// starts a server span named after the matched route pattern.
+func TracingMiddleware(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		tagged := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.route", routePattern(mux, r)))
+			next.ServeHTTP(w, r)
+		})
+		return otelhttp.NewHandler(tagged, "http.server",
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return r.Method + " " + routePattern(mux, r)
+			}),
+		)
+	}
+}
+
+// routePattern returns the path part of the pattern that mux would route r to,
+// or "unmatched" if no route matches.
+func routePattern(mux *http.ServeMux, r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	if pattern == "" {
+		return "unmatched"
+	}
+	// Strip the method prefix from patterns like "GET /api/v1/tasks/{id}"
+	if i := strings.Index(pattern, " "); i >= 0 {
+		pattern = pattern[i+1:]
+	}
+	return pattern
+}
+
+// BearerTokenMiddleware requires a static bearer token, as used by metrics scrapers.
+// An empty token disables the check.
+func BearerTokenMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+		expected := []byte("Bearer " + token)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(provided, expected) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestIDHeader is the header used to accept and echo request IDs
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits accepted client-provided request IDs
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDMiddleware accepts a request ID from the X-Request-ID header or generates one,
+// echoes it in the response and stores it in the request context. Every record logged with
+// the request context carries request_id, method and the matched route pattern.
+func RequestIDMiddleware(mux *http.ServeMux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !isValidRequestID(requestID) {
+				requestID = newRequestID()
+			}
+
+			w.Header().Set(RequestIDHeader, requestID)
+			trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.request_id", requestID))
+
+			ctx := context.WithValue(r.Context(), requestIDKey{}, requestID)
+			ctx = logger.ContextWithAttrs(ctx,
+				slog.String("request_id", requestID),
+				slog.String("method", r.Method),
+				slog.String("route", routePattern(mux, r)),
+			)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID stored by RequestIDMiddleware
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// isValidRequestID rejects empty, overly long or non-printable IDs so that
+// clients cannot inject arbitrary content into logs and response headers.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
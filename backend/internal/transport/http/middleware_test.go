@@ -1,12 +1,21 @@
 package http
 
 import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/logger"
+	"boilerplate/internal/metrics"
+	"boilerplate/internal/tracing"
 	"bytes"
+	"context"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestRecoveryMiddleware_PanicRecovery(t *testing.T) {
@@ -171,3 +180,188 @@ func TestRecoveryMiddleware_PanicInMiddlewareChain(t *testing.T) {
 		t.Error("expected panic message in log")
 	}
 }
+
+func TestMetricsMiddleware_LabelsRoutePattern(t *testing.T) {
+	m := metrics.New()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := MetricsMiddleware(m, mux)(mux)
+
+	for _, path := range []string{"/api/v1/tasks/1", "/api/v1/tasks/2", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	if !strings.Contains(body, `boilerplate_http_requests_total{method="GET",route="/api/v1/tasks/{id}",status="404"} 2`) {
+		t.Errorf("expected both task requests under one route label, got:\n%s", body)
+	}
+	if !strings.Contains(body, `route="unmatched"`) {
+		t.Error("expected unknown paths to be labelled as unmatched")
+	}
+	if strings.Contains(body, `route="/api/v1/tasks/1"`) {
+		t.Error("expected raw paths not to be used as labels")
+	}
+}
+
+func TestBearerTokenMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		token          string
+		header         string
+		expectedStatus int
+	}{
+		{name: "no token configured", token: "", header: "", expectedStatus: http.StatusOK},
+		{name: "missing header", token: "secret", header: "", expectedStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer wrong", expectedStatus: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", header: "Bearer secret", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			BearerTokenMiddleware(tt.token)(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestTracingMiddleware_ContinuesIncomingTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.SetupWithExporter(config.TracingConfig{SampleRatio: 1}, exporter)
+	defer provider.Shutdown(context.Background())
+	// Propagator normally installed by tracing.Setup
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := TracingMiddleware(mux)(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("failed to flush spans: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "GET /api/v1/tasks/{id}" {
+		t.Errorf("expected span to be named after the route pattern, got '%s'", span.Name)
+	}
+	if span.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace ID from traceparent header, got %s", span.SpanContext.TraceID())
+	}
+	if span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("expected parent span ID from traceparent header, got %s", span.Parent.SpanID())
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		incoming     string
+		expectEchoed bool
+	}{
+		{name: "generates ID when missing", incoming: "", expectEchoed: false},
+		{name: "accepts client ID", incoming: "client-request-42", expectEchoed: true},
+		{name: "rejects ID with control characters", incoming: "bad\nid", expectEchoed: false},
+		{name: "rejects overly long ID", incoming: strings.Repeat("a", 200), expectEchoed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logBuf bytes.Buffer
+			log := slog.New(logger.NewContextHandler(slog.NewJSONHandler(&logBuf, nil)))
+
+			var seenID string
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+				seenID = RequestIDFromContext(r.Context())
+				log.InfoContext(r.Context(), "inside handler")
+			})
+			handler := RequestIDMiddleware(mux)(mux)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/7", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			echoed := rec.Header().Get(RequestIDHeader)
+			if echoed == "" || echoed != seenID {
+				t.Fatalf("expected response header to echo context request ID, got header %q and context %q", echoed, seenID)
+			}
+			if tt.expectEchoed && echoed != tt.incoming {
+				t.Errorf("expected client request ID %q to be kept, got %q", tt.incoming, echoed)
+			}
+			if !tt.expectEchoed && echoed == tt.incoming {
+				t.Errorf("expected request ID %q to be replaced", tt.incoming)
+			}
+
+			output := logBuf.String()
+			for _, expected := range []string{`"request_id":"` + echoed + `"`, `"method":"GET"`, `"route":"/api/v1/tasks/{id}"`} {
+				if !strings.Contains(output, expected) {
+					t.Errorf("expected %s in log output, got %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_FollowsReload(t *testing.T) {
+	live := config.NewLive(&config.Config{
+		CORS: config.CORSConfig{AllowedOrigins: []string{"https://old.example.com"}},
+	})
+	handler := CORSMiddleware(live)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	allowedOrigin := func(origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	if got := allowedOrigin("https://new.example.com"); got != "" {
+		t.Fatalf("expected new origin to be rejected before the reload, got %q", got)
+	}
+
+	live.Reload(&config.Config{
+		CORS: config.CORSConfig{AllowedOrigins: []string{"https://new.example.com"}},
+	})
+
+	if got := allowedOrigin("https://new.example.com"); got != "https://new.example.com" {
+		t.Errorf("expected reloaded origin to be allowed, got %q", got)
+	}
+	if got := allowedOrigin("https://old.example.com"); got != "" {
+		t.Errorf("expected removed origin to be rejected, got %q", got)
+	}
+}
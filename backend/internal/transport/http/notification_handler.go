@@ -0,0 +1,252 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// defaultNotificationPageSize applies when the inbox is requested without page and limit
+	defaultNotificationPageSize = 20
+	// maxNotificationPageSize caps the limit of an inbox page
+	maxNotificationPageSize = 100
+)
+
+// NotificationHandler handles the inbox and reminder settings of the authenticated user
+type NotificationHandler struct {
+	service service.NotificationService
+	logger  *slog.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(svc service.NotificationService, logger *slog.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// NotificationPreferencesRequest replaces the reminder settings of the user
+type NotificationPreferencesRequest struct {
+	// RemindBefore is how many hours before the due date to remind, at most 168; 0 disables due soon reminders
+	RemindBefore int  `json:"remindBefore" example:"24"`
+	Overdue      bool `json:"overdue" example:"true"`
+	// Email sends reminders to the email address the user was assigned with; requires a configured mail server
+	Email bool `json:"email" example:"false"`
+	// WebhookURL receives reminders as signed JSON posts; empty disables the webhook
+	WebhookURL string `json:"webhookUrl,omitempty" example:"https://hooks.example.com/reminders"`
+}
+
+// List godoc
+// @Summary      List my notifications
+// @Description  Get a page of the reminders in the inbox of the authenticated user, newest first. The response also carries the number of unread notifications.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        unread  query  bool  false  "Only unread notifications"
+// @Param        page    query  int   false  "Page number (1-based, default 1)"
+// @Param        limit   query  int   false  "Notifications per page (default 20, at most 100)"
+// @Success      200  {object}  map[string]interface{}  "Paginated response with data, total, page, limit and unread"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/notifications [get]
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	page, limit := parsePaginationParams(r)
+	if page == 0 {
+		page, limit = 1, defaultNotificationPageSize
+	}
+	limit = min(limit, maxNotificationPageSize)
+	unreadOnly, _ := strconv.ParseBool(r.URL.Query().Get("unread"))
+
+	notifications, total, err := h.service.FindByUserID(r.Context(), user.ID, unreadOnly, limit, (page-1)*limit)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list notifications", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to list notifications", http.StatusInternalServerError)
+		return
+	}
+	unread, err := h.service.CountUnread(r.Context(), user.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to count unread notifications", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to list notifications", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"data":   notifications,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+		"unread": unread,
+	}
+	respondJSON(w, response, http.StatusOK)
+}
+
+// MarkRead godoc
+// @Summary      Mark notification as read
+// @Description  Mark a notification in the inbox of the authenticated user as read
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Notification ID"
+// @Success      200  {object}  entities.Notification
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      404  {object}  map[string]string  "Notification not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	h.markRead(w, r, true)
+}
+
+// MarkUnread godoc
+// @Summary      Mark notification as unread
+// @Description  Mark a notification in the inbox of the authenticated user as unread again
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Notification ID"
+// @Success      200  {object}  entities.Notification
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      404  {object}  map[string]string  "Notification not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/notifications/{id}/read [delete]
+func (h *NotificationHandler) MarkUnread(w http.ResponseWriter, r *http.Request) {
+	h.markRead(w, r, false)
+}
+
+func (h *NotificationHandler) markRead(w http.ResponseWriter, r *http.Request, read bool) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	id := r.PathValue("id")
+	notification, err := h.service.MarkRead(r.Context(), user.ID, id, read)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotificationNotFound) {
+			respondError(w, "Notification not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update notification", "id", id, "error", err)
+		respondError(w, "Failed to update notification", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, notification, http.StatusOK)
+}
+
+// MarkAllRead godoc
+// @Summary      Mark all notifications as read
+// @Description  Mark every unread notification in the inbox of the authenticated user as read
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]int  "Number of notifications marked as read"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	updated, err := h.service.MarkAllRead(r.Context(), user.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to mark notifications as read", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to update notifications", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, map[string]int64{"updated": updated}, http.StatusOK)
+}
+
+// GetPreferences godoc
+// @Summary      Get my reminder settings
+// @Description  Get when and how the authenticated user is reminded of the due dates of their tasks. Users who never changed them get the defaults.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  entities.NotificationPreferences
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/notification-preferences [get]
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	preferences, err := h.service.Preferences(r.Context(), user.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to get notification preferences", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to get notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, preferences, http.StatusOK)
+}
+
+// UpdatePreferences godoc
+// @Summary      Update my reminder settings
+// @Description  Replace when and how the authenticated user is reminded of the due dates of their tasks. Reminders always reach the inbox; email and webhooks are only available when the server configures them.
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Param        preferences  body      NotificationPreferencesRequest  true  "Reminder settings"
+// @Success      200  {object}  entities.NotificationPreferences
+// @Failure      400  {object}  map[string]string  "Invalid request body, remind window, unavailable channel or webhook URL"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/notification-preferences [put]
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req NotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preferences := &entities.NotificationPreferences{
+		UserID:       user.ID,
+		RemindBefore: req.RemindBefore,
+		Overdue:      req.Overdue,
+		Email:        req.Email,
+		WebhookURL:   req.WebhookURL,
+	}
+	if err := h.service.UpdatePreferences(r.Context(), preferences); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update notification preferences", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to update notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, preferences, http.StatusOK)
+}
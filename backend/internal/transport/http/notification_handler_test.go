@@ -0,0 +1,382 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Mock NotificationService for testing
+type mockNotificationService struct {
+	findByUserIDFunc      func(string, bool, int, int) ([]entities.Notification, int64, error)
+	countUnreadFunc       func(string) (int64, error)
+	markReadFunc          func(string, string, bool) (entities.Notification, error)
+	markAllReadFunc       func(string) (int64, error)
+	preferencesFunc       func(string) (entities.NotificationPreferences, error)
+	updatePreferencesFunc func(*entities.NotificationPreferences) error
+	sendRemindersFunc     func(time.Time) (int, error)
+	deliverPendingFunc    func() (int, error)
+}
+
+func (m *mockNotificationService) FindByUserID(ctx context.Context, userID string, unreadOnly bool, limit, offset int) ([]entities.Notification, int64, error) {
+	if m.findByUserIDFunc != nil {
+		return m.findByUserIDFunc(userID, unreadOnly, limit, offset)
+	}
+	return []entities.Notification{}, 0, nil
+}
+
+func (m *mockNotificationService) CountUnread(ctx context.Context, userID string) (int64, error) {
+	if m.countUnreadFunc != nil {
+		return m.countUnreadFunc(userID)
+	}
+	return 0, nil
+}
+
+func (m *mockNotificationService) MarkRead(ctx context.Context, userID, id string, read bool) (entities.Notification, error) {
+	if m.markReadFunc != nil {
+		return m.markReadFunc(userID, id, read)
+	}
+	return entities.Notification{}, domain.ErrNotificationNotFound
+}
+
+func (m *mockNotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	if m.markAllReadFunc != nil {
+		return m.markAllReadFunc(userID)
+	}
+	return 0, nil
+}
+
+func (m *mockNotificationService) Preferences(ctx context.Context, userID string) (entities.NotificationPreferences, error) {
+	if m.preferencesFunc != nil {
+		return m.preferencesFunc(userID)
+	}
+	return entities.DefaultNotificationPreferences(userID), nil
+}
+
+func (m *mockNotificationService) UpdatePreferences(ctx context.Context, preferences *entities.NotificationPreferences) error {
+	if m.updatePreferencesFunc != nil {
+		return m.updatePreferencesFunc(preferences)
+	}
+	return nil
+}
+
+func (m *mockNotificationService) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	if m.sendRemindersFunc != nil {
+		return m.sendRemindersFunc(now)
+	}
+	return 0, nil
+}
+
+func (m *mockNotificationService) DeliverPending(ctx context.Context) (int, error) {
+	if m.deliverPendingFunc != nil {
+		return m.deliverPendingFunc()
+	}
+	return 0, nil
+}
+
+func TestNotificationHandler_List(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		authenticated      bool
+		expectedStatus     int
+		expectedUnreadOnly bool
+		expectedLimit      int
+		expectedOffset     int
+	}{
+		{
+			name:           "default page",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedLimit:  20,
+			expectedOffset: 0,
+		},
+		{
+			name:               "unread second page",
+			query:              "?unread=true&page=2&limit=10",
+			authenticated:      true,
+			expectedStatus:     http.StatusOK,
+			expectedUnreadOnly: true,
+			expectedLimit:      10,
+			expectedOffset:     10,
+		},
+		{
+			name:           "limit is capped",
+			query:          "?page=1&limit=500",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+			expectedLimit:  100,
+			expectedOffset: 0,
+		},
+		{
+			name:           "not authenticated",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userID string
+			var unreadOnly bool
+			var limit, offset int
+			mockService := &mockNotificationService{
+				findByUserIDFunc: func(u string, unread bool, l, o int) ([]entities.Notification, int64, error) {
+					userID, unreadOnly, limit, offset = u, unread, l, o
+					return []entities.Notification{{ID: "n1", UserID: u, Kind: entities.NotificationDueSoon, TaskID: "task1", TaskTitle: "Ship it"}}, 1, nil
+				},
+				countUnreadFunc: func(userID string) (int64, error) {
+					return 1, nil
+				},
+			}
+
+			handler := NewNotificationHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me/notifications"+tt.query, nil)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if userID != "jane" || unreadOnly != tt.expectedUnreadOnly || limit != tt.expectedLimit || offset != tt.expectedOffset {
+				t.Errorf("expected jane's inbox with unread %v, limit %d and offset %d, got %q, %v, %d and %d",
+					tt.expectedUnreadOnly, tt.expectedLimit, tt.expectedOffset, userID, unreadOnly, limit, offset)
+			}
+			var response struct {
+				Data   []entities.Notification `json:"data"`
+				Total  int64                   `json:"total"`
+				Unread int64                   `json:"unread"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Total != 1 || response.Unread != 1 || len(response.Data) != 1 || response.Data[0].ID != "n1" {
+				t.Errorf("expected the unread notification n1, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_MarkRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		read           bool
+		notificationID string
+		authenticated  bool
+		expectedStatus int
+	}{
+		{
+			name:           "mark read",
+			read:           true,
+			notificationID: "n1",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "mark unread",
+			notificationID: "n1",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown notification",
+			read:           true,
+			notificationID: "n9",
+			authenticated:  true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "not authenticated",
+			read:           true,
+			notificationID: "n1",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockNotificationService{
+				markReadFunc: func(userID, id string, read bool) (entities.Notification, error) {
+					if userID != "jane" || id != "n1" {
+						return entities.Notification{}, domain.ErrNotificationNotFound
+					}
+					notification := entities.Notification{ID: id, UserID: userID}
+					if read {
+						now := time.Now()
+						notification.ReadAt = &now
+					}
+					return notification, nil
+				},
+			}
+
+			handler := NewNotificationHandler(mockService, testLogger())
+
+			method, handle := http.MethodDelete, handler.MarkUnread
+			if tt.read {
+				method, handle = http.MethodPut, handler.MarkRead
+			}
+			req := httptest.NewRequest(method, "/api/v1/me/notifications/"+tt.notificationID+"/read", nil)
+			req.SetPathValue("id", tt.notificationID)
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handle(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var notification entities.Notification
+			if err := json.NewDecoder(w.Body).Decode(&notification); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if (notification.ReadAt != nil) != tt.read {
+				t.Errorf("expected read %v, got read at %v", tt.read, notification.ReadAt)
+			}
+		})
+	}
+}
+
+func TestNotificationHandler_MarkAllRead(t *testing.T) {
+	mockService := &mockNotificationService{
+		markAllReadFunc: func(userID string) (int64, error) {
+			if userID != "jane" {
+				return 0, nil
+			}
+			return 1, nil
+		},
+	}
+
+	handler := NewNotificationHandler(mockService, testLogger())
+
+	req := withUser(httptest.NewRequest(http.MethodPost, "/api/v1/me/notifications/read-all", nil))
+	w := httptest.NewRecorder()
+
+	handler.MarkAllRead(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response map[string]int64
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response["updated"] != 1 {
+		t.Errorf("expected 1 updated notification, got %v", response)
+	}
+}
+
+func TestNotificationHandler_GetPreferences(t *testing.T) {
+	var userID string
+	mockService := &mockNotificationService{
+		preferencesFunc: func(u string) (entities.NotificationPreferences, error) {
+			userID = u
+			return entities.DefaultNotificationPreferences(u), nil
+		},
+	}
+
+	handler := NewNotificationHandler(mockService, testLogger())
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/api/v1/me/notification-preferences", nil))
+	w := httptest.NewRecorder()
+
+	handler.GetPreferences(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var preferences entities.NotificationPreferences
+	if err := json.NewDecoder(w.Body).Decode(&preferences); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if userID != "jane" {
+		t.Errorf("expected the preferences of jane, got those of %q", userID)
+	}
+	if preferences.RemindBefore != 24 || !preferences.Overdue || preferences.Email {
+		t.Errorf("expected the default preferences, got %+v", preferences)
+	}
+}
+
+func TestNotificationHandler_UpdatePreferences(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		authenticated  bool
+		updateErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "valid",
+			body:           `{"remindBefore":2,"overdue":false,"webhookUrl":"https://hooks.example.com/jane"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid remind window",
+			body:           `{"remindBefore":-1}`,
+			authenticated:  true,
+			updateErr:      &domain.ValidationError{Field: "remindBefore", Message: "must be between 0 and 168 hours"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "not authenticated",
+			body:           `{"remindBefore":2}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *entities.NotificationPreferences
+			mockService := &mockNotificationService{
+				updatePreferencesFunc: func(preferences *entities.NotificationPreferences) error {
+					stored = preferences
+					return tt.updateErr
+				},
+			}
+
+			handler := NewNotificationHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/me/notification-preferences", bytes.NewBufferString(tt.body))
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.UpdatePreferences(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if stored == nil || stored.UserID != "jane" || stored.RemindBefore != 2 || stored.Overdue || stored.WebhookURL != "https://hooks.example.com/jane" {
+				t.Errorf("expected jane's preferences to be replaced, got %+v", stored)
+			}
+		})
+	}
+}
@@ -11,13 +11,15 @@ import (
 // ProjectHandler handles project-related HTTP requests
 type ProjectHandler struct {
 	service service.ProjectService
+	members service.MemberService
 	logger  *slog.Logger
 }
 
 // NewProjectHandler creates a new project handler
-func NewProjectHandler(svc service.ProjectService, logger *slog.Logger) *ProjectHandler {
+func NewProjectHandler(svc service.ProjectService, members service.MemberService, logger *slog.Logger) *ProjectHandler {
 	return &ProjectHandler{
 		service: svc,
+		members: members,
 		logger:  logger,
 	}
 }
@@ -39,9 +41,9 @@ func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 	page, limit := parsePaginationParams(r)
 	if page > 0 && limit > 0 {
 		offset := (page - 1) * limit
-		projects, total, err := h.service.FindAllPaginated(limit, offset)
+		projects, total, err := h.service.FindAllPaginated(r.Context(), limit, offset)
 		if err != nil {
-			h.logger.Error("failed to list projects", "error", err)
+			h.logger.ErrorContext(r.Context(), "failed to list projects", "error", err)
 			respondError(w, "Failed to list projects", http.StatusInternalServerError)
 			return
 		}
@@ -55,9 +57,9 @@ func (h *ProjectHandler) List(w http.ResponseWriter, r *http.Request) {
 		respondJSON(w, response, http.StatusOK)
 		return
 	}
-	projects, err := h.service.FindAll()
+	projects, err := h.service.FindAll(r.Context())
 	if err != nil {
-		h.logger.Error("failed to list projects", "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to list projects", "error", err)
 		respondError(w, "Failed to list projects", http.StatusInternalServerError)
 		return
 	}
@@ -82,9 +84,9 @@ func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 		respondError(w, "Missing project ID", http.StatusBadRequest)
 		return
 	}
-	project, err := h.service.FindByID(id)
+	project, err := h.service.FindByID(r.Context(), id)
 	if err != nil {
-		h.logger.Error("failed to get project", "id", id, "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to get project", "id", id, "error", err)
 		respondError(w, "Project not found", http.StatusNotFound)
 		return
 	}
@@ -92,30 +94,28 @@ func (h *ProjectHandler) Get(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, project, http.StatusOK)
 }
 
-// CreateProjectRequest represents the request body for creating a project
+// CreateProjectRequest represents the request body for creating a project.
+// Projects created without a workflow use the default TODO, IN_PROGRESS, DONE workflow.
 type CreateProjectRequest struct {
-	Name        string `json:"name" example:"My Project"`
-	Description string `json:"description" example:"A sample project description"`
+	Name        string             `json:"name" example:"My Project"`
+	Description string             `json:"description" example:"A sample project description"`
+	Workflow    *entities.Workflow `json:"workflow,omitempty"`
 }
 
 // Create godoc
 // @Summary      Create project
-// @Description  Create a new project
+// @Description  Create a new project. An authenticated creator becomes its first member.
 // @Tags         projects
 // @Accept       json
 // @Produce      json
 // @Param        project  body      CreateProjectRequest  true  "Project to create"
 // @Success      201      {object}  entities.Project
-// @Failure      400      {object}  map[string]string  "Invalid request body or missing name"
+// @Failure      400      {object}  map[string]string  "Invalid request body, missing name or invalid workflow"
 // @Failure      500      {object}  map[string]string  "Internal server error"
 // @Security     BearerAuth
 // @Router       /api/v1/projects [post]
 func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-	}
-
+	var req CreateProjectRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -130,13 +130,28 @@ func (h *ProjectHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Name:        req.Name,
 		Description: req.Description,
 	}
+	if req.Workflow != nil {
+		project.Workflow = *req.Workflow
+	}
 
-	if err := h.service.Insert(project); err != nil {
-		h.logger.Error("failed to create project", "error", err)
+	if err := h.service.Insert(r.Context(), project); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to create project", "error", err)
 		respondError(w, "Failed to create project", http.StatusInternalServerError)
 		return
 	}
 
+	if user, ok := currentUser(r); ok {
+		member := &entities.ProjectMember{ProjectID: project.ID, UserID: user.ID, Name: user.Name, Email: user.Email}
+		if err := h.members.Add(r.Context(), member); err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to add project creator as member", "project_id", project.ID, "error", err)
+			respondError(w, "Failed to create project", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	respondJSON(w, project, http.StatusCreated)
 }
 
@@ -183,9 +198,9 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch existing project to preserve timestamps
-	existing, err := h.service.FindByID(id)
+	existing, err := h.service.FindByID(r.Context(), id)
 	if err != nil {
-		h.logger.Error("failed to find project", "id", id, "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to find project", "id", id, "error", err)
 		respondError(w, "Project not found", http.StatusNotFound)
 		return
 	}
@@ -194,11 +209,15 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 		ID:          id,
 		Name:        req.Name,
 		Description: req.Description,
+		Workflow:    existing.Workflow,
 		CreatedAt:   existing.CreatedAt,
 	}
 
-	if err := h.service.Update(project); err != nil {
-		h.logger.Error("failed to update project", "id", id, "error", err)
+	if err := h.service.Update(r.Context(), project); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update project", "id", id, "error", err)
 		respondError(w, "Failed to update project", http.StatusInternalServerError)
 		return
 	}
@@ -206,9 +225,81 @@ func (h *ProjectHandler) Update(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, project, http.StatusOK)
 }
 
+// GetWorkflow godoc
+// @Summary      Get project workflow
+// @Description  Get the statuses, their categories and the allowed transitions of a project's tasks
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Project ID"
+// @Success      200  {object}  entities.Workflow
+// @Failure      404  {object}  map[string]string  "Project not found"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/workflow [get]
+func (h *ProjectHandler) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	project, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to get project", "id", id, "error", err)
+		respondError(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, project.Workflow, http.StatusOK)
+}
+
+// UpdateWorkflow godoc
+// @Summary      Update project workflow
+// @Description  Replace the workflow of a project. Statuses are listed in board order and new tasks start in the first one.
+// @Description  Without transitions tasks may move between any statuses. Statuses that tasks are still in cannot be removed.
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string             true  "Project ID"
+// @Param        workflow  body      entities.Workflow  true  "New workflow"
+// @Success      200       {object}  entities.Workflow
+// @Failure      400       {object}  map[string]string  "Invalid request body or workflow, or a removed status is still in use"
+// @Failure      404       {object}  map[string]string  "Project not found"
+// @Failure      500       {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/workflow [put]
+func (h *ProjectHandler) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var workflow entities.Workflow
+	if err := json.NewDecoder(r.Body).Decode(&workflow); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	project, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to find project", "id", id, "error", err)
+		respondError(w, "Project not found", http.StatusNotFound)
+		return
+	}
+
+	if len(workflow.Statuses) == 0 {
+		respondError(w, "At least one status is required", http.StatusBadRequest)
+		return
+	}
+	project.Workflow = workflow
+
+	if err := h.service.Update(r.Context(), &project); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update workflow", "id", id, "error", err)
+		respondError(w, "Failed to update workflow", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, project.Workflow, http.StatusOK)
+}
+
 // Delete godoc
 // @Summary      Delete project
-// @Description  Delete a project by ID
+// @Description  Delete a project by ID together with all of its tasks, labels and custom fields
 // @Tags         projects
 // @Accept       json
 // @Produce      json
@@ -225,8 +316,8 @@ func (h *ProjectHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
-		h.logger.Error("failed to delete project", "id", id, "error", err)
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to delete project", "id", id, "error", err)
 		respondError(w, "Failed to delete project", http.StatusInternalServerError)
 		return
 	}
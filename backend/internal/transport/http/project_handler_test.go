@@ -2,7 +2,9 @@ package http
 
 import (
 	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -21,42 +23,42 @@ type mockProjectService struct {
 	findAllPaginatedFunc func(int, int) ([]entities.Project, int64, error)
 }
 
-func (m *mockProjectService) Insert(project *entities.Project) error {
+func (m *mockProjectService) Insert(ctx context.Context, project *entities.Project) error {
 	if m.insertFunc != nil {
 		return m.insertFunc(project)
 	}
 	return nil
 }
 
-func (m *mockProjectService) Update(project *entities.Project) error {
+func (m *mockProjectService) Update(ctx context.Context, project *entities.Project) error {
 	if m.updateFunc != nil {
 		return m.updateFunc(project)
 	}
 	return nil
 }
 
-func (m *mockProjectService) Delete(id string) error {
+func (m *mockProjectService) Delete(ctx context.Context, id string) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(id)
 	}
 	return nil
 }
 
-func (m *mockProjectService) FindByID(id string) (entities.Project, error) {
+func (m *mockProjectService) FindByID(ctx context.Context, id string) (entities.Project, error) {
 	if m.findByIDFunc != nil {
 		return m.findByIDFunc(id)
 	}
 	return entities.Project{}, errors.New("not found")
 }
 
-func (m *mockProjectService) FindAll() ([]entities.Project, error) {
+func (m *mockProjectService) FindAll(ctx context.Context) ([]entities.Project, error) {
 	if m.findAllFunc != nil {
 		return m.findAllFunc()
 	}
 	return []entities.Project{}, nil
 }
 
-func (m *mockProjectService) FindAllPaginated(limit, offset int) ([]entities.Project, int64, error) {
+func (m *mockProjectService) FindAllPaginated(ctx context.Context, limit, offset int) ([]entities.Project, int64, error) {
 	if m.findAllPaginatedFunc != nil {
 		return m.findAllPaginatedFunc(limit, offset)
 	}
@@ -77,7 +79,7 @@ func TestProjectHandler_List(t *testing.T) {
 		},
 	}
 
-	handler := NewProjectHandler(mockService, testLogger())
+	handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects", nil)
 	w := httptest.NewRecorder()
@@ -113,7 +115,7 @@ func TestProjectHandler_Get(t *testing.T) {
 		},
 	}
 
-	handler := NewProjectHandler(mockService, testLogger())
+	handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/123", nil)
 	req.SetPathValue("id", "123")
@@ -144,7 +146,7 @@ func TestProjectHandler_Create(t *testing.T) {
 		},
 	}
 
-	handler := NewProjectHandler(mockService, testLogger())
+	handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
 
 	reqBody := map[string]string{
 		"name":        "New Project",
@@ -192,7 +194,7 @@ func TestProjectHandler_Update(t *testing.T) {
 		},
 	}
 
-	handler := NewProjectHandler(mockService, testLogger())
+	handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
 
 	reqBody := map[string]string{
 		"name":        "Updated Project",
@@ -230,7 +232,7 @@ func TestProjectHandler_Delete(t *testing.T) {
 		},
 	}
 
-	handler := NewProjectHandler(mockService, testLogger())
+	handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/projects/123", nil)
 	req.SetPathValue("id", "123")
@@ -242,3 +244,92 @@ func TestProjectHandler_Delete(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
 	}
 }
+
+func TestProjectHandler_GetWorkflow(t *testing.T) {
+	mockService := &mockProjectService{
+		findByIDFunc: func(id string) (entities.Project, error) {
+			return entities.Project{ID: id, Name: "Project", Workflow: entities.DefaultWorkflow()}, nil
+		},
+	}
+
+	handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/123/workflow", nil)
+	req.SetPathValue("id", "123")
+	w := httptest.NewRecorder()
+
+	handler.GetWorkflow(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var workflow entities.Workflow
+	if err := json.NewDecoder(w.Body).Decode(&workflow); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(workflow.Statuses) != 3 || workflow.Initial() != entities.TaskStatusTodo {
+		t.Errorf("expected the default workflow, got %+v", workflow)
+	}
+}
+
+func TestProjectHandler_UpdateWorkflow(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		updateErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "valid workflow",
+			body:           `{"statuses":[{"key":"OPEN","name":"Open","category":"todo"},{"key":"CLOSED","name":"Closed","category":"done"}],"transitions":[{"from":"OPEN","to":"CLOSED"}]}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no statuses",
+			body:           `{"statuses":[]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "status still in use",
+			body:           `{"statuses":[{"key":"OPEN","name":"Open","category":"todo"}]}`,
+			updateErr:      &domain.ValidationError{Field: "workflow.statuses", Message: "DONE is still used by 1 tasks, move them to another status first"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *entities.Project
+			mockService := &mockProjectService{
+				findByIDFunc: func(id string) (entities.Project, error) {
+					return entities.Project{ID: id, Name: "Project", Workflow: entities.DefaultWorkflow()}, nil
+				},
+				updateFunc: func(project *entities.Project) error {
+					updated = project
+					return tt.updateErr
+				},
+			}
+
+			handler := NewProjectHandler(mockService, &mockMemberService{}, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/projects/123/workflow", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "123")
+			w := httptest.NewRecorder()
+
+			handler.UpdateWorkflow(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusOK && (updated == nil || updated.Workflow.Initial() != "OPEN") {
+				t.Errorf("expected the new workflow to be stored, got %+v", updated)
+			}
+		})
+	}
+}
@@ -2,35 +2,44 @@ package http
 
 import (
 	"boilerplate/internal/config"
+	"boilerplate/internal/metrics"
 	"net/http"
 	"sync"
 
 	"golang.org/x/time/rate"
 )
 
-// RateLimiter manages rate limiting for HTTP requests
+// RateLimiter manages rate limiting for HTTP requests. The limits are read
+// from the live configuration, so reloaded limits apply to existing clients
+// and rate limiting can be switched on and off without a restart.
 type RateLimiter struct {
 	limiters map[string]*rate.Limiter
 	mu       sync.RWMutex
-	rps      int
-	burst    int
+	live     *config.Live
+	metrics  *metrics.Metrics
 }
 
-func NewRateLimiter(cfg config.RateLimitConfig) *RateLimiter {
+func NewRateLimiter(live *config.Live, m *metrics.Metrics) *RateLimiter {
 	return &RateLimiter{
 		limiters: make(map[string]*rate.Limiter),
-		rps:      cfg.RequestsPerSecond,
-		burst:    cfg.Burst,
+		live:     live,
+		metrics:  m,
 	}
 }
 
-// getLimiter returns the rate limiter for the given IP address
-func (rl *RateLimiter) getLimiter(ip string) *rate.Limiter {
+// getLimiter returns the rate limiter for the given IP address, adjusted to the current limits
+func (rl *RateLimiter) getLimiter(ip string, cfg config.RateLimitConfig) *rate.Limiter {
 	rl.mu.RLock()
 	limiter, exists := rl.limiters[ip]
 	rl.mu.RUnlock()
 
 	if exists {
+		if limiter.Limit() != rate.Limit(cfg.RequestsPerSecond) {
+			limiter.SetLimit(rate.Limit(cfg.RequestsPerSecond))
+		}
+		if limiter.Burst() != cfg.Burst {
+			limiter.SetBurst(cfg.Burst)
+		}
 		return limiter
 	}
 
@@ -44,7 +53,7 @@ func (rl *RateLimiter) getLimiter(ip string) *rate.Limiter {
 		return limiter
 	}
 
-	limiter = rate.NewLimiter(rate.Limit(rl.rps), rl.burst)
+	limiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), cfg.Burst)
 	rl.limiters[ip] = limiter
 	return limiter
 }
@@ -53,10 +62,17 @@ func (rl *RateLimiter) getLimiter(ip string) *rate.Limiter {
 func (rl *RateLimiter) Middleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cfg := rl.live.Get().RateLimit
+			if !cfg.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			ip := getClientIP(r)
-			limiter := rl.getLimiter(ip)
+			limiter := rl.getLimiter(ip, cfg)
 
 			if !limiter.Allow() {
+				rl.metrics.RateLimitRejected()
 				http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
 				return
 			}
@@ -55,7 +55,7 @@ func TestRateLimiter_Middleware(t *testing.T) {
 				RequestsPerSecond: tt.rps,
 				Burst:             tt.burst,
 			}
-			rateLimiter := NewRateLimiter(cfg)
+			rateLimiter := NewRateLimiter(config.NewLive(&config.Config{RateLimit: cfg}), nil)
 
 			// Create a simple handler that always returns 200
 			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -105,7 +105,7 @@ func TestRateLimiter_DifferentIPs(t *testing.T) {
 		RequestsPerSecond: 1,
 		Burst:             1,
 	}
-	rateLimiter := NewRateLimiter(cfg)
+	rateLimiter := NewRateLimiter(config.NewLive(&config.Config{RateLimit: cfg}), nil)
 
 	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -144,6 +144,49 @@ func TestRateLimiter_DifferentIPs(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_AppliesReloadedLimits(t *testing.T) {
+	live := config.NewLive(&config.Config{
+		RateLimit: config.RateLimitConfig{Enabled: true, RequestsPerSecond: 1, Burst: 1},
+	})
+	rateLimiter := NewRateLimiter(live, nil)
+
+	limitedHandler := rateLimiter.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "127.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		limitedHandler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	send()
+	if code := send(); code != http.StatusTooManyRequests {
+		t.Fatalf("expected second request to be blocked, got status %d", code)
+	}
+
+	live.Reload(&config.Config{
+		RateLimit: config.RateLimitConfig{Enabled: false, RequestsPerSecond: 1, Burst: 1},
+	})
+	if code := send(); code != http.StatusOK {
+		t.Errorf("expected requests to pass once rate limiting is disabled, got status %d", code)
+	}
+
+	live.Reload(&config.Config{
+		RateLimit: config.RateLimitConfig{Enabled: true, RequestsPerSecond: 1000, Burst: 5},
+	})
+	// Existing limiters pick up the new limits on their next request and refill at the new rate from then on
+	send()
+	time.Sleep(20 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if code := send(); code != http.StatusOK {
+			t.Errorf("expected request %d to pass with the raised burst, got status %d", i, code)
+		}
+	}
+}
+
 func TestGetClientIP(t *testing.T) {
 	tests := []struct {
 		name           string
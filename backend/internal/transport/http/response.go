@@ -1,7 +1,9 @@
 package http
 
 import (
+	"boilerplate/internal/service/domain"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 )
@@ -20,6 +22,17 @@ func respondError(w http.ResponseWriter, message string, status int) {
 	respondJSON(w, ErrorResponse{Error: message}, status)
 }
 
+// respondValidationError answers a domain validation error with 400 Bad Request
+// and reports whether err was one
+func respondValidationError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	respondError(w, validationErr.Error(), http.StatusBadRequest)
+	return true
+}
+
 // parsePaginationParams extracts page and limit from query parameters
 // Returns (page, limit) or (0, 0) if not provided or invalid
 func parsePaginationParams(r *http.Request) (int, int) {
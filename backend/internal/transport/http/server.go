@@ -3,8 +3,12 @@ package http
 import (
 	"boilerplate/internal/auth"
 	"boilerplate/internal/config"
+	"boilerplate/internal/health"
+	"boilerplate/internal/logger"
+	"boilerplate/internal/metrics"
 	"boilerplate/internal/service"
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"time"
@@ -14,65 +18,161 @@ type Server struct {
 	server         *http.Server
 	logger         *slog.Logger
 	authMiddleware *auth.Middleware
+	health         *health.Registry
+	shutdownDelay  time.Duration
 }
 
-func NewServer(cfg config.ServiceConfig, corsCfg config.CORSConfig, authCfg config.AuthConfig, docsCfg config.DocsConfig, rateLimitCfg config.RateLimitConfig, svc *service.Service, authMw *auth.Middleware, logger *slog.Logger) *Server {
+// NewServer creates the HTTP server. Service, auth and metrics settings are read
+// once; CORS, rate limiting and docs enablement follow reloads of live.
+func NewServer(live *config.Live, svc *service.Service, authMw *auth.Middleware, healthRegistry *health.Registry, levels *logger.Levels, m *metrics.Metrics, logger *slog.Logger) *Server {
+	startup := live.Get()
+	cfg, authCfg, metricsCfg := startup.Service, startup.Auth, startup.Metrics
+
 	s := &Server{
 		logger:         logger,
 		authMiddleware: authMw,
+		health:         healthRegistry,
+		shutdownDelay:  time.Duration(cfg.ShutdownDelay) * time.Second,
 	}
 
 	mux := http.NewServeMux()
 
-	// Health check endpoint (no auth required)
+	// Liveness and readiness endpoints (no auth required).
+	// /health only reports that the process is serving; /ready runs the dependency checks.
 	mux.HandleFunc("GET /health", s.handleHealth)
 	mux.HandleFunc("GET /ready", s.handleReady)
 
 	// API Documentation endpoints (no auth required for docs)
-	// Only register if documentation is enabled in config
-	if docsCfg.Enabled {
+	// They respond with 404 while documentation is disabled in config
+	if startup.Docs.Enabled {
 		logger.Info("API documentation endpoints enabled")
-		docsHandler := NewDocsHandler(authCfg)
-		mux.HandleFunc("GET /docs", docsHandler.Redirect)
-		mux.HandleFunc("GET /docs/scalar", docsHandler.ServeScalar)
-		mux.Handle("GET /swagger/", docsHandler.ServeSwaggerUI())
 	} else {
 		logger.Info("API documentation endpoints disabled")
 	}
+	docsHandler := NewDocsHandler(authCfg)
+	docsEnabled := docsEnabledMiddleware(live)
+	mux.Handle("GET /docs", docsEnabled(http.HandlerFunc(docsHandler.Redirect)))
+	mux.Handle("GET /docs/scalar", docsEnabled(http.HandlerFunc(docsHandler.ServeScalar)))
+	mux.Handle("GET /swagger/", docsEnabled(docsHandler.ServeSwaggerUI()))
+
+	// Prometheus metrics endpoint, optionally protected by a static bearer token for scrapers
+	if metricsCfg.Enabled && m != nil {
+		logger.Info("metrics endpoint enabled", "path", metricsCfg.Path)
+		mux.Handle("GET "+metricsCfg.Path, BearerTokenMiddleware(metricsCfg.BearerToken)(m.Handler()))
+	} else {
+		logger.Info("metrics endpoint disabled")
+	}
 
 	// API v1 routes - all protected by auth middleware
 	apiMux := http.NewServeMux()
 
 	// Project handlers
-	projectHandler := NewProjectHandler(svc.Project, logger)
+	projectHandler := NewProjectHandler(svc.Project, svc.Member, logger)
 	apiMux.HandleFunc("GET /api/v1/projects", projectHandler.List)
 	apiMux.HandleFunc("POST /api/v1/projects", projectHandler.Create)
 	apiMux.HandleFunc("GET /api/v1/projects/{id}", projectHandler.Get)
 	apiMux.HandleFunc("PUT /api/v1/projects/{id}", projectHandler.Update)
 	apiMux.HandleFunc("DELETE /api/v1/projects/{id}", projectHandler.Delete)
+	apiMux.HandleFunc("GET /api/v1/projects/{id}/workflow", projectHandler.GetWorkflow)
+	apiMux.HandleFunc("PUT /api/v1/projects/{id}/workflow", projectHandler.UpdateWorkflow)
 
 	// Task handlers
 	taskHandler := NewTaskHandler(svc.Task, logger)
 	apiMux.HandleFunc("GET /api/v1/projects/{id}/tasks", taskHandler.ListByProject)
 	apiMux.HandleFunc("POST /api/v1/projects/{id}/tasks", taskHandler.CreateForProject)
+	apiMux.HandleFunc("POST /api/v1/projects/{id}/tasks:batch", taskHandler.Batch)
 	apiMux.HandleFunc("GET /api/v1/tasks/{id}", taskHandler.Get)
 	apiMux.HandleFunc("PUT /api/v1/tasks/{id}", taskHandler.Update)
 	apiMux.HandleFunc("DELETE /api/v1/tasks/{id}", taskHandler.Delete)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/children", taskHandler.Children)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/subtree", taskHandler.Subtree)
+	apiMux.HandleFunc("GET /api/v1/me/tasks", taskHandler.ListMine)
+
+	// Dependency handlers
+	dependencyHandler := NewDependencyHandler(svc.Dependency, logger)
+	apiMux.HandleFunc("GET /api/v1/projects/{id}/tasks/graph", dependencyHandler.Graph)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/dependencies", dependencyHandler.List)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/blocked-by", dependencyHandler.AddBlocker)
+	apiMux.HandleFunc("DELETE /api/v1/tasks/{id}/blocked-by/{blockerId}", dependencyHandler.RemoveBlocker)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/blocks", dependencyHandler.AddBlocked)
+	apiMux.HandleFunc("DELETE /api/v1/tasks/{id}/blocks/{blockedId}", dependencyHandler.RemoveBlocked)
+
+	// Member handlers
+	memberHandler := NewMemberHandler(svc.Member, logger)
+	apiMux.HandleFunc("GET /api/v1/projects/{id}/members", memberHandler.List)
+	apiMux.HandleFunc("PUT /api/v1/projects/{id}/members/{userId}", memberHandler.Put)
+	apiMux.HandleFunc("DELETE /api/v1/projects/{id}/members/{userId}", memberHandler.Delete)
+
+	// Comment handlers
+	commentHandler := NewCommentHandler(svc.Comment, logger)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/comments", commentHandler.List)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/comments", commentHandler.Create)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/comments/{commentId}", commentHandler.Get)
+	apiMux.HandleFunc("PUT /api/v1/tasks/{id}/comments/{commentId}", commentHandler.Update)
+	apiMux.HandleFunc("DELETE /api/v1/tasks/{id}/comments/{commentId}", commentHandler.Delete)
 
-	// Apply middleware chain to API routes: Recovery -> RateLimit -> CORS -> Logging -> Auth
-	corsMiddleware := CORSMiddleware(corsCfg)
+	// Attachment handlers
+	attachmentHandler := NewAttachmentHandler(svc.Attachment, logger)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/attachments", attachmentHandler.List)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/attachments", attachmentHandler.Upload)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/attachments/{attachmentId}", attachmentHandler.Get)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/attachments/{attachmentId}/content", attachmentHandler.Download)
+	apiMux.HandleFunc("DELETE /api/v1/tasks/{id}/attachments/{attachmentId}", attachmentHandler.Delete)
+
+	// Time tracking handlers - timers and time entries of the caller, reports per project
+	timeEntryHandler := NewTimeEntryHandler(svc.TimeEntry, logger)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/timer/start", timeEntryHandler.StartTimer)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/timer/stop", timeEntryHandler.StopTimer)
+	apiMux.HandleFunc("GET /api/v1/me/timer", timeEntryHandler.RunningTimer)
+	apiMux.HandleFunc("GET /api/v1/tasks/{id}/time-entries", timeEntryHandler.List)
+	apiMux.HandleFunc("POST /api/v1/tasks/{id}/time-entries", timeEntryHandler.Create)
+	apiMux.HandleFunc("PUT /api/v1/tasks/{id}/time-entries/{entryId}", timeEntryHandler.Update)
+	apiMux.HandleFunc("DELETE /api/v1/tasks/{id}/time-entries/{entryId}", timeEntryHandler.Delete)
+	apiMux.HandleFunc("GET /api/v1/projects/{id}/reports/time", timeEntryHandler.Report)
+
+	// Notification handlers - the inbox and reminder settings of the caller
+	notificationHandler := NewNotificationHandler(svc.Notification, logger)
+	apiMux.HandleFunc("GET /api/v1/me/notifications", notificationHandler.List)
+	apiMux.HandleFunc("POST /api/v1/me/notifications/read-all", notificationHandler.MarkAllRead)
+	apiMux.HandleFunc("PUT /api/v1/me/notifications/{id}/read", notificationHandler.MarkRead)
+	apiMux.HandleFunc("DELETE /api/v1/me/notifications/{id}/read", notificationHandler.MarkUnread)
+	apiMux.HandleFunc("GET /api/v1/me/notification-preferences", notificationHandler.GetPreferences)
+	apiMux.HandleFunc("PUT /api/v1/me/notification-preferences", notificationHandler.UpdatePreferences)
+
+	// Label and custom field handlers
+	labelHandler := NewLabelHandler(svc.Label, logger)
+	apiMux.HandleFunc("GET /api/v1/projects/{id}/labels", labelHandler.List)
+	apiMux.HandleFunc("POST /api/v1/projects/{id}/labels", labelHandler.Create)
+	apiMux.HandleFunc("PUT /api/v1/projects/{id}/labels/{labelId}", labelHandler.Update)
+	apiMux.HandleFunc("DELETE /api/v1/projects/{id}/labels/{labelId}", labelHandler.Delete)
+
+	customFieldHandler := NewCustomFieldHandler(svc.CustomField, logger)
+	apiMux.HandleFunc("GET /api/v1/projects/{id}/custom-fields", customFieldHandler.List)
+	apiMux.HandleFunc("POST /api/v1/projects/{id}/custom-fields", customFieldHandler.Create)
+	apiMux.HandleFunc("PUT /api/v1/projects/{id}/custom-fields/{fieldId}", customFieldHandler.Update)
+	apiMux.HandleFunc("DELETE /api/v1/projects/{id}/custom-fields/{fieldId}", customFieldHandler.Delete)
+
+	// Admin handlers - additionally require the admin role
+	adminHandler := NewAdminHandler(levels, logger)
+	requireAdmin := authMw.RequireRole(authCfg.AdminRole)
+	apiMux.Handle("GET /api/v1/admin/log-levels", requireAdmin(http.HandlerFunc(adminHandler.GetLogLevels)))
+	apiMux.Handle("PUT /api/v1/admin/log-levels/{component}", requireAdmin(http.HandlerFunc(adminHandler.SetLogLevel)))
+	apiMux.Handle("DELETE /api/v1/admin/log-levels/{component}", requireAdmin(http.HandlerFunc(adminHandler.ResetLogLevel)))
+
+	// Apply middleware chain to API routes: Metrics -> Tracing -> RequestID -> Recovery -> RateLimit -> CORS -> Logging -> Auth
+	corsMiddleware := CORSMiddleware(live)
 	recoveryMiddleware := RecoveryMiddleware(logger)
 	var apiHandler http.Handler = authMw.Authenticate(apiMux)
 	apiHandler = s.loggingMiddleware(apiHandler)
 	apiHandler = corsMiddleware(apiHandler)
 
-	// Apply rate limiting if enabled
-	if rateLimitCfg.Enabled {
-		rateLimiter := NewRateLimiter(rateLimitCfg)
-		apiHandler = rateLimiter.Middleware()(apiHandler)
+	// Rate limiting is always installed so that it can be enabled by a reload
+	rateLimiter := NewRateLimiter(live, m)
+	apiHandler = rateLimiter.Middleware()(apiHandler)
+	if startup.RateLimit.Enabled {
 		logger.Info("rate limiting enabled",
-			"requests_per_second", rateLimitCfg.RequestsPerSecond,
-			"burst", rateLimitCfg.Burst,
+			"requests_per_second", startup.RateLimit.RequestsPerSecond,
+			"burst", startup.RateLimit.Burst,
 		)
 	} else {
 		logger.Info("rate limiting disabled")
@@ -81,6 +181,15 @@ func NewServer(cfg config.ServiceConfig, corsCfg config.CORSConfig, authCfg conf
 	// Recovery middleware should be outermost to catch all panics
 	apiHandler = recoveryMiddleware(apiHandler)
 
+	// Request IDs are assigned before recovery so that panic logs carry them
+	apiHandler = RequestIDMiddleware(apiMux)(apiHandler)
+
+	// Tracing wraps recovery so that spans of panicking requests are still ended
+	apiHandler = TracingMiddleware(apiMux)(apiHandler)
+
+	// Metrics wrap everything so that rate-limited and recovered requests are counted too
+	apiHandler = MetricsMiddleware(m, apiMux)(apiHandler)
+
 	mux.Handle("/api/", apiHandler)
 
 	s.server = &http.Server{
@@ -101,7 +210,19 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// Shutdown marks the server as not ready, waits for the configured shutdown delay
+// so load balancers can stop routing traffic, then drains open connections.
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.SetShuttingDown()
+
+	if s.shutdownDelay > 0 {
+		s.logger.Info("readiness disabled, waiting before shutdown", "delay", s.shutdownDelay)
+		select {
+		case <-time.After(s.shutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
 	s.logger.Info("shutting down HTTP server")
 	return s.server.Shutdown(ctx)
 }
@@ -112,9 +233,34 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
-	// TODO: Add checks for database connectivity, etc.
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("READY"))
+	report := s.health.Run(r.Context())
+
+	status := http.StatusOK
+	if !report.Healthy() {
+		status = http.StatusServiceUnavailable
+	}
+	for name, result := range report.Checks {
+		if result.Status != health.StatusUp {
+			s.logger.WarnContext(r.Context(), "readiness check failed", "check", name, "critical", result.Critical, "status", report.Status, "error", result.Error)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// docsEnabledMiddleware hides the documentation endpoints while docs.enabled is false
+func docsEnabledMiddleware(live *config.Live) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !live.Get().Docs.Enabled {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
@@ -126,7 +272,7 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(rw, r)
 
-		s.logger.Info("HTTP request",
+		s.logger.InfoContext(r.Context(), "HTTP request",
 			"method", r.Method,
 			"path", r.URL.Path,
 			"status", rw.statusCode,
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"boilerplate/internal/config"
+	"boilerplate/internal/health"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(registry *health.Registry) *Server {
+	return &Server{
+		server: &http.Server{},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		health: registry,
+	}
+}
+
+func TestServer_HandleReady(t *testing.T) {
+	tests := []struct {
+		name           string
+		register       func(r *health.Registry)
+		expectedStatus int
+		expectedReport string
+	}{
+		{
+			name: "all checks pass",
+			register: func(r *health.Registry) {
+				r.Register("mongodb", true, 0, func(ctx context.Context) error { return nil })
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: health.StatusUp,
+		},
+		{
+			name: "critical check fails",
+			register: func(r *health.Registry) {
+				r.Register("mongodb", true, 0, func(ctx context.Context) error { return errors.New("connection refused") })
+				r.Register("loki", false, 0, func(ctx context.Context) error { return nil })
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedReport: health.StatusDown,
+		},
+		{
+			name: "non-critical check fails",
+			register: func(r *health.Registry) {
+				r.Register("mongodb", true, 0, func(ctx context.Context) error { return nil })
+				r.Register("loki", false, 0, func(ctx context.Context) error { return errors.New("unreachable") })
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: health.StatusUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := health.NewRegistry()
+			tt.register(registry)
+			s := newTestServer(registry)
+
+			rec := httptest.NewRecorder()
+			s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+
+			var report health.Report
+			if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if report.Status != tt.expectedReport {
+				t.Errorf("expected report status %s, got %s", tt.expectedReport, report.Status)
+			}
+		})
+	}
+}
+
+func TestServer_HandleReadyLogsErrors(t *testing.T) {
+	registry := health.NewRegistry()
+	registry.Register("mongodb", true, 0, func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.5:27017: connection refused") })
+	var logs bytes.Buffer
+	s := newTestServer(registry)
+	s.logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	rec := httptest.NewRecorder()
+	s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	if strings.Contains(rec.Body.String(), "10.0.0.5") {
+		t.Errorf("expected the response to hide the check error, got %s", rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"mongodb":{"status":"DOWN"`) {
+		t.Errorf("expected the status of the check, got %s", rec.Body.String())
+	}
+	if !strings.Contains(logs.String(), "10.0.0.5") {
+		t.Errorf("expected the check error to be logged, got %s", logs.String())
+	}
+}
+
+func TestServer_ShutdownFailsReadiness(t *testing.T) {
+	registry := health.NewRegistry()
+	s := newTestServer(registry)
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d after shutdown, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+func TestDocsEnabledMiddleware(t *testing.T) {
+	live := config.NewLive(&config.Config{Docs: config.DocsConfig{Enabled: false}})
+	handler := docsEnabledMiddleware(live)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected status %d while docs are disabled, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	live.Reload(&config.Config{Docs: config.DocsConfig{Enabled: true}})
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d after docs were enabled, got %d", http.StatusOK, rec.Code)
+	}
+}
@@ -3,12 +3,25 @@ package http
 import (
 	"boilerplate/internal/entities"
 	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
+// maxBatchOperations limits the number of operations accepted in a single batch request
+const maxBatchOperations = 500
+
+const (
+	invalidStatusMessage   = "Invalid status. Must be a status key of the project's workflow, e.g. IN_PROGRESS"
+	invalidPriorityMessage = "Invalid priority. Must be NONE, LOW, MEDIUM, HIGH, URGENT or P0-P3"
+)
+
 // TaskHandler handles task-related HTTP requests
 type TaskHandler struct {
 	service service.TaskService
@@ -25,13 +38,19 @@ func NewTaskHandler(svc service.TaskService, logger *slog.Logger) *TaskHandler {
 
 // ListByProject godoc
 // @Summary      List tasks by project
-// @Description  Get all tasks for a specific project
+// @Description  Get the tasks of a specific project, optionally filtered. Filters combine with AND.
+// @Description  Custom fields are filtered with one field.<fieldId>=<value> parameter per field.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
-// @Param        id   path      string  true  "Project ID"
+// @Param        id        path      string  true   "Project ID"
+// @Param        status    query     string  false  "Only tasks with this workflow status, e.g. IN_REVIEW"
+// @Param        priority  query     string  false  "Comma-separated priorities, e.g. HIGH,URGENT or P0,P1"
+// @Param        label     query     string  false  "Comma-separated label IDs; tasks must have all of them"
+// @Param        assignee  query     string  false  "Only tasks assigned to this user (Keycloak subject), or me"
 // @Success      200  {array}   entities.Task
-// @Failure      400  {object}  map[string]string  "Missing project ID"
+// @Failure      400  {object}  map[string]string  "Missing project ID or invalid filter"
+// @Failure      401  {object}  map[string]string  "assignee=me used without authentication"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Security     BearerAuth
 // @Router       /api/v1/projects/{id}/tasks [get]
@@ -42,9 +61,26 @@ func (h *TaskHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tasks, err := h.service.FindByProjectID(projectID)
+	filter, err := parseTaskFilter(r.URL.Query())
 	if err != nil {
-		h.logger.Error("failed to list tasks for project", "project_id", projectID, "error", err)
+		respondError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.AssigneeID != "" {
+		assignee, err := resolveUser(r, filter.AssigneeID)
+		if err != nil {
+			respondError(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		filter.AssigneeID = assignee.ID
+	}
+
+	tasks, err := h.service.FindByProjectID(r.Context(), projectID, filter)
+	if err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to list tasks for project", "project_id", projectID, "error", err)
 		respondError(w, "Failed to list tasks", http.StatusInternalServerError)
 		return
 	}
@@ -52,6 +88,89 @@ func (h *TaskHandler) ListByProject(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, tasks, http.StatusOK)
 }
 
+// customFieldFilterPrefix marks query parameters that filter by a custom field
+const customFieldFilterPrefix = "field."
+
+// parseTaskFilter reads the task list filters from query parameters. Custom
+// field values are passed on as text and parsed by the service.
+func parseTaskFilter(query url.Values) (entities.TaskFilter, error) {
+	var filter entities.TaskFilter
+
+	if value := query.Get("status"); value != "" {
+		status, err := entities.ParseTaskStatus(value)
+		if err != nil {
+			return filter, errors.New(invalidStatusMessage)
+		}
+		filter.Status = &status
+	}
+
+	for _, value := range splitList(query.Get("priority")) {
+		priority, err := entities.ParseTaskPriority(strings.ToUpper(value))
+		if err != nil {
+			return filter, errors.New(invalidPriorityMessage)
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+
+	filter.LabelIDs = splitList(query.Get("label"))
+	filter.AssigneeID = strings.TrimSpace(query.Get("assignee"))
+
+	for key, values := range query {
+		fieldID, ok := strings.CutPrefix(key, customFieldFilterPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if fieldID == "" {
+			return filter, errors.New("Custom field filters must be given as field.<fieldId>=<value>")
+		}
+		if filter.CustomFields == nil {
+			filter.CustomFields = make(entities.CustomFieldValues)
+		}
+		filter.CustomFields[fieldID] = values[0]
+	}
+
+	return filter, nil
+}
+
+// ListMine godoc
+// @Summary      List my tasks
+// @Description  Get the tasks of all projects assigned to the authenticated user, earliest due date first
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Success      200  {array}   entities.Task
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/tasks [get]
+func (h *TaskHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	tasks, err := h.service.FindByAssignee(r.Context(), user.ID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list assigned tasks", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to list tasks", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, tasks, http.StatusOK)
+}
+
+// splitList splits a comma-separated query value and drops empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // Get godoc
 // @Summary      Get task by ID
 // @Description  Get a single task by its ID
@@ -71,9 +190,9 @@ func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.service.FindByID(id)
+	task, err := h.service.FindByID(r.Context(), id)
 	if err != nil {
-		h.logger.Error("failed to get task", "id", id, "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to get task", "id", id, "error", err)
 		respondError(w, "Task not found", http.StatusNotFound)
 		return
 	}
@@ -83,22 +202,36 @@ func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 // CreateTaskRequest represents the request body for creating a task
 type CreateTaskRequest struct {
-	Title       string     `json:"title" example:"Implement feature X"`
-	Status      string     `json:"status" example:"TODO" enums:"TODO,IN_PROGRESS,DONE"`
-	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
-	Description string     `json:"description" example:"Detailed task description"`
+	ParentID     string                 `json:"parentId,omitempty" example:"507f1f77bcf86cd799439011"`
+	Title        string                 `json:"title" example:"Implement feature X"`
+	Status       string                 `json:"status,omitempty" example:"TODO"`
+	Priority     string                 `json:"priority,omitempty" example:"HIGH" enums:"NONE,LOW,MEDIUM,HIGH,URGENT,P0,P1,P2,P3"`
+	DueDate      *time.Time             `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
+	Description  string                 `json:"description" example:"Detailed task description"`
+	LabelIDs     []string               `json:"labelIds,omitempty"`
+	CustomFields map[string]interface{} `json:"customFields,omitempty"`
+	// AssigneeIDs and WatcherIDs are Keycloak subjects of project members, or me
+	AssigneeIDs []string `json:"assigneeIds,omitempty" example:"me"`
+	WatcherIDs  []string `json:"watcherIds,omitempty"`
+	// Recurrence makes the task the first occurrence of a recurring series
+	Recurrence *entities.TaskRecurrence `json:"recurrence,omitempty"`
+	// Estimate is the expected effort in seconds
+	Estimate int64 `json:"estimate,omitempty" example:"7200"`
 }
 
 // CreateForProject godoc
 // @Summary      Create task for project
-// @Description  Create a new task for a specific project
+// @Description  Create a new task for a specific project. With parentId the task becomes a subtask of another task of the project.
+// @Description  Assignees and watchers must be members of the project.
+// @Description  With a recurrence (an RFC 5545 RRULE and an IANA time zone) the task becomes the first occurrence of a series; the next occurrence is created when it is completed or when it is due to start within the lookahead of the scheduler.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Param        id    path      string              true  "Project ID"
 // @Param        task  body      CreateTaskRequest   true  "Task to create"
 // @Success      201   {object}  entities.Task
-// @Failure      400   {object}  map[string]string  "Invalid request body, missing title, or invalid status"
+// @Failure      400   {object}  map[string]string  "Invalid request body, missing title, invalid status or priority, unknown label, invalid custom field value or parent, nesting too deep, assignee or watcher not a member, invalid recurrence or negative estimate"
+// @Failure      401   {object}  map[string]string  "me used without authentication"
 // @Failure      500   {object}  map[string]string  "Internal server error"
 // @Security     BearerAuth
 // @Router       /api/v1/projects/{id}/tasks [post]
@@ -109,13 +242,7 @@ func (h *TaskHandler) CreateForProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Title       string     `json:"title"`
-		Status      string     `json:"status"`
-		DueDate     *time.Time `json:"due_date"`
-		Description string     `json:"description"`
-	}
-
+	var req CreateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, "Invalid request body", http.StatusBadRequest)
 		return
@@ -126,27 +253,61 @@ func (h *TaskHandler) CreateForProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set default status if not provided
-	status := entities.TaskStatusTodo
+	// Without a status the task starts in the first status of the project's workflow
+	var status entities.TaskStatus
 	if req.Status != "" {
 		var err error
 		status, err = entities.ParseTaskStatus(req.Status)
 		if err != nil {
-			respondError(w, "Invalid status. Must be TODO, IN_PROGRESS, or DONE", http.StatusBadRequest)
+			respondError(w, invalidStatusMessage, http.StatusBadRequest)
 			return
 		}
 	}
 
+	priority := entities.TaskPriorityNone
+	if req.Priority != "" {
+		var err error
+		priority, err = entities.ParseTaskPriority(req.Priority)
+		if err != nil {
+			respondError(w, invalidPriorityMessage, http.StatusBadRequest)
+			return
+		}
+	}
+
+	assignees, err := resolveUsers(r, req.AssigneeIDs)
+	if err != nil {
+		respondError(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	watchers, err := resolveUsers(r, req.WatcherIDs)
+	if err != nil {
+		respondError(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
 	task := &entities.Task{
-		ProjectID:   projectID,
-		Title:       req.Title,
-		Status:      status,
-		DueDate:     req.DueDate,
-		Description: req.Description,
+		ProjectID:    projectID,
+		ParentID:     req.ParentID,
+		Title:        req.Title,
+		Status:       status,
+		Priority:     priority,
+		DueDate:      req.DueDate,
+		Description:  req.Description,
+		LabelIDs:     req.LabelIDs,
+		CustomFields: req.CustomFields,
+		Assignees:    assignees,
+		Watchers:     watchers,
+		Estimate:     req.Estimate,
+	}
+	if req.Recurrence != nil && req.Recurrence.RRule != "" {
+		task.Recurrence = req.Recurrence
 	}
 
-	if err := h.service.Insert(task); err != nil {
-		h.logger.Error("failed to create task", "error", err)
+	if err := h.service.Insert(r.Context(), task); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to create task", "error", err)
 		respondError(w, "Failed to create task", http.StatusInternalServerError)
 		return
 	}
@@ -154,25 +315,42 @@ func (h *TaskHandler) CreateForProject(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, task, http.StatusCreated)
 }
 
-// UpdateTaskRequest represents the request body for updating a task
+// UpdateTaskRequest represents the request body for updating a task.
+// labelIds replaces the labels; customFields sets the given fields and removes fields set to null.
+// parentId moves the task with its subtasks below another task; an empty parentId makes it a top-level task.
+// assigneeIds and watcherIds replace the assignees and watchers.
+// recurrence replaces the schedule of a recurring task; an empty rrule ends the series.
 type UpdateTaskRequest struct {
-	Title       *string    `json:"title,omitempty" example:"Updated task title"`
-	Status      *string    `json:"status,omitempty" example:"IN_PROGRESS" enums:"TODO,IN_PROGRESS,DONE"`
-	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
-	Description *string    `json:"description,omitempty" example:"Updated description"`
+	ParentID     *string                  `json:"parentId,omitempty" example:"507f1f77bcf86cd799439011"`
+	Title        *string                  `json:"title,omitempty" example:"Updated task title"`
+	Status       *string                  `json:"status,omitempty" example:"IN_PROGRESS"`
+	Priority     *string                  `json:"priority,omitempty" example:"URGENT" enums:"NONE,LOW,MEDIUM,HIGH,URGENT,P0,P1,P2,P3"`
+	DueDate      *time.Time               `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
+	Description  *string                  `json:"description,omitempty" example:"Updated description"`
+	LabelIDs     *[]string                `json:"labelIds,omitempty"`
+	CustomFields map[string]interface{}   `json:"customFields,omitempty"`
+	AssigneeIDs  *[]string                `json:"assigneeIds,omitempty"`
+	WatcherIDs   *[]string                `json:"watcherIds,omitempty"`
+	Recurrence   *entities.TaskRecurrence `json:"recurrence,omitempty"`
+	// Estimate replaces the expected effort in seconds; 0 removes it
+	Estimate *int64 `json:"estimate,omitempty" example:"7200"`
 }
 
 // Update godoc
 // @Summary      Update task
-// @Description  Update an existing task (partial updates supported)
+// @Description  Update an existing task (partial updates supported).
+// @Description  Status changes must follow the transitions of the project's workflow. Subtasks move along with their parent.
+// @Description  Completing the latest occurrence of a recurring task creates the next one, which carries the recurrence from then on. A recurrence with an empty rrule ends the series.
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Param        id    path      string             true  "Task ID"
 // @Param        task  body      UpdateTaskRequest  true  "Task updates"
 // @Success      200   {object}  entities.Task
-// @Failure      400   {object}  map[string]string  "Invalid request body, empty title, or invalid status"
+// @Failure      400   {object}  map[string]string  "Invalid request body, empty title, invalid status or priority, unknown label, invalid custom field value, a parent that is invalid, below the task itself or too deep, assignee or watcher not a member, invalid recurrence or negative estimate"
+// @Failure      401   {object}  map[string]string  "me used without authentication"
 // @Failure      404   {object}  map[string]string  "Task not found"
+// @Failure      422   {object}  map[string]string  "Status transition not allowed by the workflow or task blocked by open tasks"
 // @Failure      500   {object}  map[string]string  "Internal server error"
 // @Security     BearerAuth
 // @Router       /api/v1/tasks/{id} [put]
@@ -183,38 +361,45 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		Title       *string    `json:"title"`
-		Status      *string    `json:"status"`
-		DueDate     *time.Time `json:"due_date"`
-		Description *string    `json:"description"`
-	}
-
+	var req UpdateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Fetch existing task to preserve timestamps and projectID
-	existing, err := h.service.FindByID(id)
+	existing, err := h.service.FindByID(r.Context(), id)
 	if err != nil {
-		h.logger.Error("failed to find task", "id", id, "error", err)
+		h.logger.ErrorContext(r.Context(), "failed to find task", "id", id, "error", err)
 		respondError(w, "Task not found", http.StatusNotFound)
 		return
 	}
 
 	// Start with existing values
 	task := &entities.Task{
-		ID:          id,
-		ProjectID:   existing.ProjectID,
-		Title:       existing.Title,
-		Status:      existing.Status,
-		DueDate:     existing.DueDate,
-		Description: existing.Description,
-		CreatedAt:   existing.CreatedAt,
+		ID:           id,
+		ProjectID:    existing.ProjectID,
+		ParentID:     existing.ParentID,
+		Title:        existing.Title,
+		Status:       existing.Status,
+		Priority:     existing.Priority,
+		DueDate:      existing.DueDate,
+		Description:  existing.Description,
+		LabelIDs:     existing.LabelIDs,
+		CustomFields: existing.CustomFields,
+		Assignees:    existing.Assignees,
+		Watchers:     existing.Watchers,
+		Recurrence:   existing.Recurrence,
+		Estimate:     existing.Estimate,
+		Progress:     existing.Progress,
+		CreatedAt:    existing.CreatedAt,
 	}
 
 	// Update only provided fields
+	if req.ParentID != nil {
+		task.ParentID = *req.ParentID
+	}
+
 	if req.Title != nil {
 		if *req.Title == "" {
 			respondError(w, "Title cannot be empty", http.StatusBadRequest)
@@ -226,12 +411,21 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.Status != nil {
 		status, err := entities.ParseTaskStatus(*req.Status)
 		if err != nil {
-			respondError(w, "Invalid status. Must be TODO, IN_PROGRESS, or DONE", http.StatusBadRequest)
+			respondError(w, invalidStatusMessage, http.StatusBadRequest)
 			return
 		}
 		task.Status = status
 	}
 
+	if req.Priority != nil {
+		priority, err := entities.ParseTaskPriority(*req.Priority)
+		if err != nil {
+			respondError(w, invalidPriorityMessage, http.StatusBadRequest)
+			return
+		}
+		task.Priority = priority
+	}
+
 	if req.DueDate != nil {
 		task.DueDate = req.DueDate
 	}
@@ -240,8 +434,59 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		task.Description = *req.Description
 	}
 
-	if err := h.service.Update(task); err != nil {
-		h.logger.Error("failed to update task", "id", id, "error", err)
+	if req.LabelIDs != nil {
+		task.LabelIDs = *req.LabelIDs
+	}
+
+	if req.AssigneeIDs != nil {
+		if task.Assignees, err = resolveUsers(r, *req.AssigneeIDs); err != nil {
+			respondError(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if req.WatcherIDs != nil {
+		if task.Watchers, err = resolveUsers(r, *req.WatcherIDs); err != nil {
+			respondError(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if req.Estimate != nil {
+		task.Estimate = *req.Estimate
+	}
+
+	if req.Recurrence != nil {
+		task.Recurrence = req.Recurrence
+		if req.Recurrence.RRule == "" {
+			task.Recurrence = nil
+		}
+	}
+
+	if len(req.CustomFields) > 0 {
+		fields := make(entities.CustomFieldValues, len(existing.CustomFields)+len(req.CustomFields))
+		for fieldID, value := range existing.CustomFields {
+			fields[fieldID] = value
+		}
+		for fieldID, value := range req.CustomFields {
+			if value == nil {
+				delete(fields, fieldID)
+				continue
+			}
+			fields[fieldID] = value
+		}
+		task.CustomFields = fields
+	}
+
+	if err := h.service.Update(r.Context(), task); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTransitionNotAllowed) || errors.Is(err, domain.ErrOpenBlockers) {
+			respondError(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update task", "id", id, "error", err)
 		respondError(w, "Failed to update task", http.StatusInternalServerError)
 		return
 	}
@@ -249,9 +494,55 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, task, http.StatusOK)
 }
 
+// Children godoc
+// @Summary      List subtasks
+// @Description  Get the direct subtasks of a task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {array}   entities.Task
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/children [get]
+func (h *TaskHandler) Children(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	children, err := h.service.Children(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to list subtasks", "id", id, "error", err)
+		respondError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, children, http.StatusOK)
+}
+
+// Subtree godoc
+// @Summary      Get task subtree
+// @Description  Get a task with its subtasks at every level nested below it
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Task ID"
+// @Success      200  {object}  entities.TaskTree
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/subtree [get]
+func (h *TaskHandler) Subtree(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	tree, err := h.service.Subtree(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to get subtree", "id", id, "error", err)
+		respondError(w, "Task not found", http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, tree, http.StatusOK)
+}
+
 // Delete godoc
 // @Summary      Delete task
-// @Description  Delete a task by ID
+// @Description  Delete a task by ID together with all of its subtasks
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
@@ -268,11 +559,155 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
-		h.logger.Error("failed to delete task", "id", id, "error", err)
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to delete task", "id", id, "error", err)
 		respondError(w, "Failed to delete task", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// BatchTaskOperation represents a single operation in a batch request.
+// Create uses the task fields, update uses id plus the fields to change, delete uses id.
+type BatchTaskOperation struct {
+	Op          string     `json:"op" example:"update" enums:"create,update,delete"`
+	ID          string     `json:"id,omitempty" example:"507f1f77bcf86cd799439011"`
+	Title       *string    `json:"title,omitempty" example:"Implement feature X"`
+	Status      *string    `json:"status,omitempty" example:"DONE"`
+	Priority    *string    `json:"priority,omitempty" example:"HIGH" enums:"NONE,LOW,MEDIUM,HIGH,URGENT,P0,P1,P2,P3"`
+	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-12-31T23:59:59Z"`
+	Description *string    `json:"description,omitempty" example:"Detailed task description"`
+}
+
+// BatchTasksRequest represents the request body for batch task operations
+type BatchTasksRequest struct {
+	Atomic     bool                 `json:"atomic" example:"false"`
+	Operations []BatchTaskOperation `json:"operations"`
+}
+
+// BatchTasksResponse represents the per-operation results of a batch request
+type BatchTasksResponse struct {
+	Committed bool                       `json:"committed"`
+	Results   []entities.TaskBatchResult `json:"results"`
+}
+
+// Batch godoc
+// @Summary      Batch task operations
+// @Description  Apply a list of create, update and delete operations to the tasks of a project using bulk writes.
+// @Description  Creates run first, then updates, then deletes; a task may only be updated or deleted by one operation of a batch.
+// @Description  With atomic=true the operations run in a transaction and either all or none are committed.
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string             true  "Project ID"
+// @Param        batch  body      BatchTasksRequest  true  "Operations to apply"
+// @Success      200    {object}  BatchTasksResponse
+// @Failure      400    {object}  map[string]string   "Invalid request body, no operations, or too many operations"
+// @Failure      422    {object}  BatchTasksResponse  "Atomic batch rolled back"
+// @Failure      500    {object}  map[string]string   "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/tasks:batch [post]
+func (h *TaskHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	projectID := r.PathValue("id")
+	if projectID == "" {
+		respondError(w, "Missing project ID", http.StatusBadRequest)
+		return
+	}
+
+	var req BatchTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Operations) == 0 {
+		respondError(w, "At least one operation is required", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Operations) > maxBatchOperations {
+		respondError(w, fmt.Sprintf("Too many operations. Maximum is %d", maxBatchOperations), http.StatusBadRequest)
+		return
+	}
+
+	ops := make([]entities.TaskBatchOperation, len(req.Operations))
+	for i, reqOp := range req.Operations {
+		op, err := toBatchOperation(reqOp)
+		if err != nil {
+			respondError(w, fmt.Sprintf("Operation %d: %s", i, err.Error()), http.StatusBadRequest)
+			return
+		}
+		ops[i] = op
+	}
+
+	results, err := h.service.ExecuteBatch(r.Context(), projectID, ops, req.Atomic)
+	if err != nil {
+		if errors.Is(err, domain.ErrBatchAborted) {
+			respondJSON(w, BatchTasksResponse{Committed: false, Results: results}, http.StatusUnprocessableEntity)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to execute task batch", "project_id", projectID, "error", err)
+		respondError(w, "Failed to execute batch", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, BatchTasksResponse{Committed: true, Results: results}, http.StatusOK)
+}
+
+func toBatchOperation(req BatchTaskOperation) (entities.TaskBatchOperation, error) {
+	op := entities.TaskBatchOperation{
+		Op: entities.TaskBatchOpType(req.Op),
+		ID: req.ID,
+	}
+
+	var status *entities.TaskStatus
+	if req.Status != nil {
+		parsed, err := entities.ParseTaskStatus(*req.Status)
+		if err != nil {
+			return op, errors.New("invalid status. Must be a status key like IN_PROGRESS")
+		}
+		status = &parsed
+	}
+
+	var priority *entities.TaskPriority
+	if req.Priority != nil {
+		parsed, err := entities.ParseTaskPriority(*req.Priority)
+		if err != nil {
+			return op, errors.New("invalid priority. Must be NONE, LOW, MEDIUM, HIGH, URGENT or P0-P3")
+		}
+		priority = &parsed
+	}
+
+	switch op.Op {
+	case entities.TaskBatchOpCreate:
+		task := &entities.Task{
+			DueDate: req.DueDate,
+		}
+		if req.Title != nil {
+			task.Title = *req.Title
+		}
+		if status != nil {
+			task.Status = *status
+		}
+		if priority != nil {
+			task.Priority = *priority
+		}
+		if req.Description != nil {
+			task.Description = *req.Description
+		}
+		op.Task = task
+	case entities.TaskBatchOpUpdate:
+		if req.Title != nil || status != nil || priority != nil || req.DueDate != nil || req.Description != nil {
+			op.Patch = &entities.TaskPatch{
+				Title:       req.Title,
+				Status:      status,
+				Priority:    priority,
+				DueDate:     req.DueDate,
+				Description: req.Description,
+			}
+		}
+	}
+
+	return op, nil
+}
@@ -2,9 +2,12 @@ package http
 
 import (
 	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -15,56 +18,92 @@ import (
 
 // Mock TaskService for testing
 type mockTaskService struct {
-	insertFunc         func(*entities.Task) error
-	updateFunc         func(*entities.Task) error
-	deleteFunc         func(string) error
-	findByIDFunc       func(string) (entities.Task, error)
-	findAllFunc        func() ([]entities.Task, error)
-	findByProjectIDFunc func(string) ([]entities.Task, error)
+	insertFunc          func(*entities.Task) error
+	updateFunc          func(*entities.Task) error
+	deleteFunc          func(string) error
+	findByIDFunc        func(string) (entities.Task, error)
+	findAllFunc         func() ([]entities.Task, error)
+	findByAssigneeFunc  func(string) ([]entities.Task, error)
+	findByProjectIDFunc func(string, entities.TaskFilter) ([]entities.Task, error)
+	childrenFunc        func(string) ([]entities.Task, error)
+	subtreeFunc         func(string) (entities.TaskTree, error)
+	executeBatchFunc    func(context.Context, string, []entities.TaskBatchOperation, bool) ([]entities.TaskBatchResult, error)
 }
 
-func (m *mockTaskService) Insert(task *entities.Task) error {
+func (m *mockTaskService) Insert(ctx context.Context, task *entities.Task) error {
 	if m.insertFunc != nil {
 		return m.insertFunc(task)
 	}
 	return nil
 }
 
-func (m *mockTaskService) Update(task *entities.Task) error {
+func (m *mockTaskService) Update(ctx context.Context, task *entities.Task) error {
 	if m.updateFunc != nil {
 		return m.updateFunc(task)
 	}
 	return nil
 }
 
-func (m *mockTaskService) Delete(id string) error {
+func (m *mockTaskService) Delete(ctx context.Context, id string) error {
 	if m.deleteFunc != nil {
 		return m.deleteFunc(id)
 	}
 	return nil
 }
 
-func (m *mockTaskService) FindByID(id string) (entities.Task, error) {
+func (m *mockTaskService) FindByID(ctx context.Context, id string) (entities.Task, error) {
 	if m.findByIDFunc != nil {
 		return m.findByIDFunc(id)
 	}
 	return entities.Task{}, errors.New("not found")
 }
 
-func (m *mockTaskService) FindAll() ([]entities.Task, error) {
+func (m *mockTaskService) FindByAssignee(ctx context.Context, userID string) ([]entities.Task, error) {
+	if m.findByAssigneeFunc != nil {
+		return m.findByAssigneeFunc(userID)
+	}
+	return []entities.Task{}, nil
+}
+
+func (m *mockTaskService) FindAll(ctx context.Context) ([]entities.Task, error) {
 	if m.findAllFunc != nil {
 		return m.findAllFunc()
 	}
 	return []entities.Task{}, nil
 }
 
-func (m *mockTaskService) FindByProjectID(projectID string) ([]entities.Task, error) {
+func (m *mockTaskService) FindByProjectID(ctx context.Context, projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
 	if m.findByProjectIDFunc != nil {
-		return m.findByProjectIDFunc(projectID)
+		return m.findByProjectIDFunc(projectID, filter)
 	}
 	return []entities.Task{}, nil
 }
 
+func (m *mockTaskService) Children(ctx context.Context, id string) ([]entities.Task, error) {
+	if m.childrenFunc != nil {
+		return m.childrenFunc(id)
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockTaskService) Subtree(ctx context.Context, id string) (entities.TaskTree, error) {
+	if m.subtreeFunc != nil {
+		return m.subtreeFunc(id)
+	}
+	return entities.TaskTree{}, errors.New("not found")
+}
+
+func (m *mockTaskService) ExecuteBatch(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error) {
+	if m.executeBatchFunc != nil {
+		return m.executeBatchFunc(ctx, projectID, ops, atomic)
+	}
+	return []entities.TaskBatchResult{}, nil
+}
+
+func (m *mockTaskService) MaterializeRecurrences(ctx context.Context, horizon time.Time) (int, error) {
+	return 0, nil
+}
+
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelError, // Only show errors in tests
@@ -73,7 +112,7 @@ func testLogger() *slog.Logger {
 
 func TestTaskHandler_ListByProject(t *testing.T) {
 	mockService := &mockTaskService{
-		findByProjectIDFunc: func(projectID string) ([]entities.Task, error) {
+		findByProjectIDFunc: func(projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
 			if projectID == "123" {
 				return []entities.Task{
 					{
@@ -327,3 +366,479 @@ func TestTaskHandler_Delete(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
 	}
 }
+
+func TestTaskHandler_Batch(t *testing.T) {
+	var receivedOps []entities.TaskBatchOperation
+	var receivedAtomic bool
+	mockService := &mockTaskService{
+		executeBatchFunc: func(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error) {
+			receivedOps = ops
+			receivedAtomic = atomic
+			results := make([]entities.TaskBatchResult, len(ops))
+			for i, op := range ops {
+				results[i] = entities.TaskBatchResult{Index: i, Op: op.Op, ID: op.ID, Status: entities.TaskBatchStatusOK}
+			}
+			return results, nil
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	reqBody := map[string]interface{}{
+		"atomic": true,
+		"operations": []map[string]interface{}{
+			{"op": "create", "title": "New Task"},
+			{"op": "update", "id": "task1", "status": "DONE"},
+			{"op": "delete", "id": "task2"},
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/tasks:batch", bytes.NewReader(body))
+	req.SetPathValue("id", "123")
+	w := httptest.NewRecorder()
+
+	handler.Batch(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	if !receivedAtomic {
+		t.Error("expected atomic flag to be passed to the service")
+	}
+
+	if len(receivedOps) != 3 {
+		t.Fatalf("expected 3 operations, got %d", len(receivedOps))
+	}
+
+	// The service starts tasks without a status in the first status of the workflow
+	if receivedOps[0].Task == nil || receivedOps[0].Task.Status != "" {
+		t.Error("expected create operation to leave the status to the workflow")
+	}
+
+	if receivedOps[1].Patch == nil || receivedOps[1].Patch.Status == nil || *receivedOps[1].Patch.Status != entities.TaskStatusDone {
+		t.Error("expected update operation to carry the DONE status")
+	}
+
+	var resp BatchTasksResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if !resp.Committed || len(resp.Results) != 3 {
+		t.Errorf("expected committed response with 3 results, got %+v", resp)
+	}
+}
+
+func TestTaskHandler_BatchErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		batchErr       error
+		expectedStatus int
+	}{
+		{
+			name:           "invalid body",
+			body:           "{",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no operations",
+			body:           `{"operations": []}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid status",
+			body:           `{"operations": [{"op": "update", "id": "task1", "status": "not a status"}]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "atomic batch aborted",
+			body:           `{"atomic": true, "operations": [{"op": "delete", "id": "task1"}]}`,
+			batchErr:       domain.ErrBatchAborted,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "service failure",
+			body:           `{"operations": [{"op": "delete", "id": "task1"}]}`,
+			batchErr:       errors.New("connection lost"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &mockTaskService{
+				executeBatchFunc: func(ctx context.Context, projectID string, ops []entities.TaskBatchOperation, atomic bool) ([]entities.TaskBatchResult, error) {
+					return []entities.TaskBatchResult{}, tt.batchErr
+				},
+			}
+
+			handler := NewTaskHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/tasks:batch", bytes.NewReader([]byte(tt.body)))
+			req.SetPathValue("id", "123")
+			w := httptest.NewRecorder()
+
+			handler.Batch(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_ListByProjectFilters(t *testing.T) {
+	var received entities.TaskFilter
+	mockService := &mockTaskService{
+		findByProjectIDFunc: func(projectID string, filter entities.TaskFilter) ([]entities.Task, error) {
+			received = filter
+			return []entities.Task{}, nil
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/123/tasks?status=TODO&priority=P0,high&label=l1,l2&field.f1=web", nil)
+	req.SetPathValue("id", "123")
+	w := httptest.NewRecorder()
+
+	handler.ListByProject(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if received.Status == nil || *received.Status != entities.TaskStatusTodo {
+		t.Errorf("expected status filter TODO, got %v", received.Status)
+	}
+	if len(received.Priorities) != 2 || received.Priorities[0] != entities.TaskPriorityUrgent || received.Priorities[1] != entities.TaskPriorityHigh {
+		t.Errorf("expected priorities [URGENT HIGH], got %v", received.Priorities)
+	}
+	if len(received.LabelIDs) != 2 || received.LabelIDs[0] != "l1" || received.LabelIDs[1] != "l2" {
+		t.Errorf("expected labels [l1 l2], got %v", received.LabelIDs)
+	}
+	if received.CustomFields["f1"] != "web" {
+		t.Errorf("expected custom field filter f1=web, got %v", received.CustomFields)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/projects/123/tasks?priority=P9", nil)
+	req.SetPathValue("id", "123")
+	w = httptest.NewRecorder()
+
+	handler.ListByProject(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for invalid priority, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_UpdateMergesCustomFields(t *testing.T) {
+	var updated *entities.Task
+	mockService := &mockTaskService{
+		findByIDFunc: func(id string) (entities.Task, error) {
+			return entities.Task{
+				ID:           "task1",
+				ProjectID:    "123",
+				Title:        "Existing Title",
+				Priority:     entities.TaskPriorityLow,
+				LabelIDs:     []string{"l1"},
+				CustomFields: entities.CustomFieldValues{"keep": "a", "remove": "b", "change": 1.0},
+			}, nil
+		},
+		updateFunc: func(task *entities.Task) error {
+			updated = task
+			return nil
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	body := []byte(`{"priority":"P1","customFields":{"remove":null,"change":2}}`)
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/task1", bytes.NewReader(body))
+	req.SetPathValue("id", "task1")
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if updated.Priority != entities.TaskPriorityHigh {
+		t.Errorf("expected priority HIGH, got %s", updated.Priority)
+	}
+	if len(updated.LabelIDs) != 1 || updated.LabelIDs[0] != "l1" {
+		t.Errorf("expected labels to be preserved, got %v", updated.LabelIDs)
+	}
+	expected := entities.CustomFieldValues{"keep": "a", "change": 2.0}
+	if len(updated.CustomFields) != len(expected) || updated.CustomFields["keep"] != "a" || updated.CustomFields["change"] != 2.0 {
+		t.Errorf("expected custom fields %v, got %v", expected, updated.CustomFields)
+	}
+}
+
+func TestTaskHandler_Recurrence(t *testing.T) {
+	weekly := &entities.TaskRecurrence{RRule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "Europe/Berlin"}
+	tests := []struct {
+		name     string
+		body     string
+		expected *entities.TaskRecurrence
+	}{
+		{name: "recurrence is kept", body: `{"title":"Weekly report"}`, expected: weekly},
+		{name: "recurrence is replaced", body: `{"recurrence":{"rrule":"FREQ=MONTHLY","timezone":"UTC"}}`, expected: &entities.TaskRecurrence{RRule: "FREQ=MONTHLY", Timezone: "UTC"}},
+		{name: "empty rule ends the series", body: `{"recurrence":{"rrule":""}}`, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *entities.Task
+			mockService := &mockTaskService{
+				findByIDFunc: func(id string) (entities.Task, error) {
+					return entities.Task{ID: "task1", ProjectID: "123", Title: "Report", Recurrence: weekly, SeriesID: "s1"}, nil
+				},
+				updateFunc: func(task *entities.Task) error {
+					updated = task
+					return nil
+				},
+			}
+			handler := NewTaskHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/task1", bytes.NewReader([]byte(tt.body)))
+			req.SetPathValue("id", "task1")
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d. Body: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			if (updated.Recurrence == nil) != (tt.expected == nil) || (tt.expected != nil && *updated.Recurrence != *tt.expected) {
+				t.Errorf("expected recurrence %+v, got %+v", tt.expected, updated.Recurrence)
+			}
+		})
+	}
+
+	t.Run("create with recurrence", func(t *testing.T) {
+		var inserted *entities.Task
+		mockService := &mockTaskService{
+			insertFunc: func(task *entities.Task) error {
+				inserted = task
+				return nil
+			},
+		}
+		handler := NewTaskHandler(mockService, testLogger())
+
+		body := []byte(`{"title":"Weekly report","recurrence":{"rrule":"FREQ=WEEKLY;BYDAY=MO","timezone":"Europe/Berlin","start":"2030-03-25T08:00:00Z"}}`)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/tasks", bytes.NewReader(body))
+		req.SetPathValue("id", "123")
+		w := httptest.NewRecorder()
+
+		handler.CreateForProject(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d. Body: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		if inserted.Recurrence == nil || inserted.Recurrence.RRule != "FREQ=WEEKLY;BYDAY=MO" || !inserted.Recurrence.Start.Equal(time.Date(2030, 3, 25, 8, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected the recurrence to be passed on, got %+v", inserted.Recurrence)
+		}
+	})
+}
+
+func TestTaskHandler_CreateValidationError(t *testing.T) {
+	mockService := &mockTaskService{
+		insertFunc: func(task *entities.Task) error {
+			return &domain.ValidationError{Field: "labelIds", Message: "l9 is not a label of the project"}
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	body := []byte(`{"title":"New Task","labelIds":["l9"]}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/tasks", bytes.NewReader(body))
+	req.SetPathValue("id", "123")
+	w := httptest.NewRecorder()
+
+	handler.CreateForProject(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte("l9 is not a label of the project")) {
+		t.Errorf("expected validation message in body, got %s", w.Body.String())
+	}
+}
+
+func TestTaskHandler_UpdateRejectedTransition(t *testing.T) {
+	mockService := &mockTaskService{
+		findByIDFunc: func(id string) (entities.Task, error) {
+			return entities.Task{ID: id, ProjectID: "123", Title: "Task", Status: entities.TaskStatusTodo}, nil
+		},
+		updateFunc: func(task *entities.Task) error {
+			return fmt.Errorf("%w: TODO to DONE", domain.ErrTransitionNotAllowed)
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	body, _ := json.Marshal(map[string]interface{}{"status": "DONE"})
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/tasks/task1", bytes.NewReader(body))
+	req.SetPathValue("id", "task1")
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+
+	var response map[string]string
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response["error"] != "status transition not allowed: TODO to DONE" {
+		t.Errorf("unexpected error message %q", response["error"])
+	}
+}
+
+func TestTaskHandler_UpdateRejectsOpenBlockers(t *testing.T) {
+	mockService := &mockTaskService{
+		findByIDFunc: func(id string) (entities.Task, error) {
+			return entities.Task{ID: id, ProjectID: "123", Title: "Task", Status: entities.TaskStatusInProgress}, nil
+		},
+		updateFunc: func(task *entities.Task) error {
+			return fmt.Errorf("%w: \"Audit\"", domain.ErrOpenBlockers)
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	body, _ := json.Marshal(map[string]interface{}{"status": "DONE"})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/task1", bytes.NewReader(body))
+	req.SetPathValue("id", "task1")
+	w := httptest.NewRecorder()
+
+	handler.Update(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status %d, got %d", http.StatusUnprocessableEntity, w.Code)
+	}
+}
+
+func TestTaskHandler_UpdateMovesTask(t *testing.T) {
+	var updated *entities.Task
+	mockService := &mockTaskService{
+		findByIDFunc: func(id string) (entities.Task, error) {
+			return entities.Task{ID: id, ProjectID: "123", ParentID: "parent1", Title: "Task", Status: entities.TaskStatusTodo}, nil
+		},
+		updateFunc: func(task *entities.Task) error {
+			updated = task
+			return nil
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	for _, tt := range []struct {
+		body           string
+		expectedParent string
+	}{
+		{body: `{"title":"Renamed"}`, expectedParent: "parent1"},
+		{body: `{"parentId":"parent2"}`, expectedParent: "parent2"},
+		{body: `{"parentId":""}`, expectedParent: ""},
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/task1", bytes.NewBufferString(tt.body))
+		req.SetPathValue("id", "task1")
+		w := httptest.NewRecorder()
+
+		handler.Update(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d", tt.body, http.StatusOK, w.Code)
+		}
+		if updated.ParentID != tt.expectedParent {
+			t.Errorf("%s: expected parent %q, got %q", tt.body, tt.expectedParent, updated.ParentID)
+		}
+	}
+}
+
+func TestTaskHandler_CreateSubtaskWithInvalidParent(t *testing.T) {
+	mockService := &mockTaskService{
+		insertFunc: func(task *entities.Task) error {
+			if task.ParentID != "parent1" {
+				t.Errorf("expected parent 'parent1', got %q", task.ParentID)
+			}
+			return &domain.ValidationError{Field: "parentId", Message: "subtasks can be nested at most 5 levels deep"}
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/projects/123/tasks", bytes.NewBufferString(`{"title":"Step","parentId":"parent1"}`))
+	req.SetPathValue("id", "123")
+	w := httptest.NewRecorder()
+
+	handler.CreateForProject(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestTaskHandler_ChildrenAndSubtree(t *testing.T) {
+	mockService := &mockTaskService{
+		childrenFunc: func(id string) ([]entities.Task, error) {
+			if id != "task1" {
+				return nil, errors.New("task not found")
+			}
+			return []entities.Task{{ID: "child1", ParentID: "task1", Progress: &entities.TaskProgress{Done: 1, Total: 2}}}, nil
+		},
+		subtreeFunc: func(id string) (entities.TaskTree, error) {
+			if id != "task1" {
+				return entities.TaskTree{}, errors.New("task not found")
+			}
+			return entities.TaskTree{
+				Task:     entities.Task{ID: "task1"},
+				Subtasks: []entities.TaskTree{{Task: entities.Task{ID: "child1", ParentID: "task1"}, Subtasks: []entities.TaskTree{}}},
+			}, nil
+		},
+	}
+
+	handler := NewTaskHandler(mockService, testLogger())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task1/children", nil)
+	req.SetPathValue("id", "task1")
+	w := httptest.NewRecorder()
+	handler.Children(w, req)
+
+	var children []entities.Task
+	if err := json.NewDecoder(w.Body).Decode(&children); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(children) != 1 || children[0].Progress == nil || children[0].Progress.Total != 2 {
+		t.Errorf("unexpected children %+v", children)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task1/subtree", nil)
+	req.SetPathValue("id", "task1")
+	w = httptest.NewRecorder()
+	handler.Subtree(w, req)
+
+	var tree map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&tree); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if tree["id"] != "task1" {
+		t.Errorf("expected the task fields at the top level, got %v", tree)
+	}
+	if subtasks, ok := tree["subtasks"].([]interface{}); !ok || len(subtasks) != 1 {
+		t.Errorf("expected one subtask, got %v", tree["subtasks"])
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/tasks/missing/subtree", nil)
+	req.SetPathValue("id", "missing")
+	w = httptest.NewRecorder()
+	handler.Subtree(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
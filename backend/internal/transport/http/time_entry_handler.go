@@ -0,0 +1,489 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service"
+	"boilerplate/internal/service/domain"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeEntryHandler handles timers, time entries and time reports
+type TimeEntryHandler struct {
+	service service.TimeEntryService
+	logger  *slog.Logger
+}
+
+// NewTimeEntryHandler creates a new time entry handler
+func NewTimeEntryHandler(svc service.TimeEntryService, logger *slog.Logger) *TimeEntryHandler {
+	return &TimeEntryHandler{
+		service: svc,
+		logger:  logger,
+	}
+}
+
+// TimerRequest carries an optional note for a timer that is started
+type TimerRequest struct {
+	Note string `json:"note,omitempty" example:"Call with the client"`
+}
+
+// TimeEntryRequest records time spent on a task without a timer
+type TimeEntryRequest struct {
+	// Start defaults to the duration before now
+	Start    time.Time `json:"start,omitempty" example:"2030-03-25T09:00:00Z"`
+	Duration int64     `json:"duration" example:"5400"` // in seconds, at most 24 hours
+	Note     string    `json:"note,omitempty" example:"Workshop"`
+}
+
+// UpdateTimeEntryRequest changes a stopped time entry; omitted fields are kept
+type UpdateTimeEntryRequest struct {
+	Start    *time.Time `json:"start,omitempty" example:"2030-03-25T09:00:00Z"`
+	Duration *int64     `json:"duration,omitempty" example:"3600"` // in seconds, at most 24 hours
+	Note     *string    `json:"note,omitempty" example:"Workshop and minutes"`
+}
+
+// StartTimer godoc
+// @Summary      Start timer
+// @Description  Start a timer for the authenticated user on a task. A user has at most one running timer.
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string        true   "Task ID"
+// @Param        timer  body      TimerRequest  false  "Note"
+// @Success      201  {object}  entities.TimeEntry
+// @Failure      400  {object}  map[string]string  "Invalid request body or note too long"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      409  {object}  map[string]string  "A timer is already running"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req TimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	taskID := r.PathValue("id")
+	entry, err := h.service.StartTimer(r.Context(), taskID, user, req.Note)
+	if err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, domain.ErrTimerRunning) {
+			respondError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to start timer", "task_id", taskID, "user_id", user.ID, "error", err)
+		respondError(w, "Failed to start timer", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, entry, http.StatusCreated)
+}
+
+// StopTimer godoc
+// @Summary      Stop timer
+// @Description  Stop the timer of the authenticated user on a task and record the time spent
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Param        id  path  string  true  "Task ID"
+// @Success      200  {object}  entities.TimeEntry
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      404  {object}  map[string]string  "No timer is running on the task"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	taskID := r.PathValue("id")
+	entry, err := h.service.StopTimer(r.Context(), taskID, user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoTimerRunning) {
+			respondError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to stop timer", "task_id", taskID, "user_id", user.ID, "error", err)
+		respondError(w, "Failed to stop timer", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, entry, http.StatusOK)
+}
+
+// RunningTimer godoc
+// @Summary      Get my running timer
+// @Description  Get the running timer of the authenticated user
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  entities.TimeEntry
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      404  {object}  map[string]string  "No timer is running"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/me/timer [get]
+func (h *TimeEntryHandler) RunningTimer(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	entry, err := h.service.RunningTimer(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoTimerRunning) {
+			respondError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to find running timer", "user_id", user.ID, "error", err)
+		respondError(w, "Failed to find running timer", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, entry, http.StatusOK)
+}
+
+// List godoc
+// @Summary      List task time entries
+// @Description  Get the time entries of a task in order of their start, running timers included
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Param        id  path  string  true  "Task ID"
+// @Success      200  {array}   entities.TimeEntry
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/time-entries [get]
+func (h *TimeEntryHandler) List(w http.ResponseWriter, r *http.Request) {
+	taskID := r.PathValue("id")
+	entries, err := h.service.FindByTaskID(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to list time entries", "task_id", taskID, "error", err)
+		respondError(w, "Failed to list time entries", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, entries, http.StatusOK)
+}
+
+// Create godoc
+// @Summary      Create time entry
+// @Description  Record time the authenticated user spent on a task, with a duration of up to 24 hours that must not end in the future
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string            true  "Task ID"
+// @Param        entry  body      TimeEntryRequest  true  "Time entry"
+// @Success      201  {object}  entities.TimeEntry
+// @Failure      400  {object}  map[string]string  "Invalid request body, duration, start or note"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      404  {object}  map[string]string  "Task not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/time-entries [post]
+func (h *TimeEntryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	user, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var req TimeEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry := &entities.TimeEntry{
+		TaskID:   r.PathValue("id"),
+		User:     user,
+		Start:    req.Start,
+		Duration: req.Duration,
+		Note:     req.Note,
+	}
+	if err := h.service.Create(r.Context(), entry); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTaskNotFound) {
+			respondError(w, "Task not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to create time entry", "task_id", entry.TaskID, "error", err)
+		respondError(w, "Failed to create time entry", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, entry, http.StatusCreated)
+}
+
+// Update godoc
+// @Summary      Update time entry
+// @Description  Change the start, duration or note of a stopped time entry. Only the user who tracked the time can change it.
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "Task ID"
+// @Param        entryId  path      string                  true  "Time entry ID"
+// @Param        entry    body      UpdateTimeEntryRequest  true  "Changes"
+// @Success      200  {object}  entities.TimeEntry
+// @Failure      400  {object}  map[string]string  "Invalid request body, duration, start or note"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      403  {object}  map[string]string  "Not the user who tracked the time"
+// @Failure      404  {object}  map[string]string  "Time entry not found"
+// @Failure      409  {object}  map[string]string  "The timer is still running"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/time-entries/{entryId} [put]
+func (h *TimeEntryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	editor, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	var req UpdateTimeEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry := &entities.TimeEntry{ID: existing.ID, Start: existing.Start, Duration: existing.Duration, Note: existing.Note}
+	if req.Start != nil {
+		entry.Start = *req.Start
+	}
+	if req.Duration != nil {
+		entry.Duration = *req.Duration
+	}
+	if req.Note != nil {
+		entry.Note = *req.Note
+	}
+
+	if err := h.service.Update(r.Context(), entry, editor); err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrNotTimeEntryOwner) {
+			respondError(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, domain.ErrTimerRunning) {
+			respondError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to update time entry", "id", entry.ID, "error", err)
+		respondError(w, "Failed to update time entry", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, entry, http.StatusOK)
+}
+
+// Delete godoc
+// @Summary      Delete time entry
+// @Description  Delete a time entry or discard a running timer. Only the user who tracked the time can delete it.
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string  true  "Task ID"
+// @Param        entryId  path  string  true  "Time entry ID"
+// @Success      204  "No Content"
+// @Failure      401  {object}  map[string]string  "Not authenticated"
+// @Failure      403  {object}  map[string]string  "Not the user who tracked the time"
+// @Failure      404  {object}  map[string]string  "Time entry not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/tasks/{id}/time-entries/{entryId} [delete]
+func (h *TimeEntryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := currentUser(r)
+	if !ok {
+		respondError(w, errNotAuthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+	existing, ok := h.find(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), existing.ID, actor); err != nil {
+		if errors.Is(err, domain.ErrNotTimeEntryOwner) {
+			respondError(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to delete time entry", "id", existing.ID, "error", err)
+		respondError(w, "Failed to delete time entry", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Report godoc
+// @Summary      Get time report
+// @Description  Sum up the time spent on the tasks of a project per user, per task and per day. Entries count on the day they started in the time zone; running timers are left out. With format=csv one breakdown, chosen by groupBy, is returned as a CSV download.
+// @Tags         time-tracking
+// @Accept       json
+// @Produce      json
+// @Produce      text/csv
+// @Param        id        path   string  true   "Project ID"
+// @Param        from      query  string  true   "First day, YYYY-MM-DD"
+// @Param        to        query  string  true   "Last day, YYYY-MM-DD, at most 366 days after from"
+// @Param        timezone  query  string  false  "IANA time zone of the days (default UTC)"
+// @Param        format    query  string  false  "json (default) or csv"
+// @Param        groupBy   query  string  false  "Breakdown of the CSV export: task (default), user or day"
+// @Success      200  {object}  entities.TimeReport
+// @Failure      400  {object}  map[string]string  "Invalid range, time zone, format or grouping"
+// @Failure      404  {object}  map[string]string  "Project not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Security     BearerAuth
+// @Router       /api/v1/projects/{id}/reports/time [get]
+func (h *TimeEntryHandler) Report(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		respondError(w, "Invalid format, expected json or csv", http.StatusBadRequest)
+		return
+	}
+	groupBy := query.Get("groupBy")
+	if groupBy == "" {
+		groupBy = "task"
+	}
+	if groupBy != "task" && groupBy != "user" && groupBy != "day" {
+		respondError(w, "Invalid groupBy, expected task, user or day", http.StatusBadRequest)
+		return
+	}
+
+	projectID := r.PathValue("id")
+	report, err := h.service.Report(r.Context(), projectID, query.Get("from"), query.Get("to"), query.Get("timezone"))
+	if err != nil {
+		if respondValidationError(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrProjectNotFound) {
+			respondError(w, "Project not found", http.StatusNotFound)
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "failed to report time", "project_id", projectID, "error", err)
+		respondError(w, "Failed to report time", http.StatusInternalServerError)
+		return
+	}
+
+	if format != "csv" {
+		respondJSON(w, report, http.StatusOK)
+		return
+	}
+
+	fileName := fmt.Sprintf("time-%s-%s-by-%s.csv", report.From, report.To, groupBy)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	for _, record := range timeReportRecords(report, groupBy) {
+		if err := out.Write(record); err != nil {
+			break
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		h.logger.ErrorContext(r.Context(), "failed to write time report", "project_id", projectID, "error", err)
+	}
+}
+
+// find loads the time entry named in the path and checks that it belongs to the task in the path
+func (h *TimeEntryHandler) find(w http.ResponseWriter, r *http.Request) (entities.TimeEntry, bool) {
+	id := r.PathValue("entryId")
+	entry, err := h.service.FindByID(r.Context(), id)
+	if err != nil || entry.TaskID != r.PathValue("id") {
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "failed to find time entry", "id", id, "error", err)
+		}
+		respondError(w, "Time entry not found", http.StatusNotFound)
+		return entities.TimeEntry{}, false
+	}
+	return entry, true
+}
+
+// timeReportRecords returns one breakdown of a report as CSV records, the header first
+func timeReportRecords(report entities.TimeReport, groupBy string) [][]string {
+	var records [][]string
+	switch groupBy {
+	case "user":
+		records = append(records, []string{"user_id", "name", "email", "duration_seconds", "hours", "entries"})
+		for _, row := range report.ByUser {
+			records = append(records, []string{
+				csvText(row.User.ID), csvText(row.User.Name), csvText(row.User.Email),
+				strconv.FormatInt(row.Duration, 10), hours(row.Duration), strconv.Itoa(row.Entries),
+			})
+		}
+	case "day":
+		records = append(records, []string{"date", "duration_seconds", "hours", "entries"})
+		for _, row := range report.ByDay {
+			records = append(records, []string{
+				row.Date, strconv.FormatInt(row.Duration, 10), hours(row.Duration), strconv.Itoa(row.Entries),
+			})
+		}
+	default:
+		records = append(records, []string{"task_id", "title", "estimate_seconds", "estimate_hours", "duration_seconds", "hours", "entries"})
+		for _, row := range report.ByTask {
+			estimateSeconds, estimateHours := "", ""
+			if row.Estimate > 0 {
+				estimateSeconds, estimateHours = strconv.FormatInt(row.Estimate, 10), hours(row.Estimate)
+			}
+			records = append(records, []string{
+				csvText(row.TaskID), csvText(row.Title), estimateSeconds, estimateHours,
+				strconv.FormatInt(row.Duration, 10), hours(row.Duration), strconv.Itoa(row.Entries),
+			})
+		}
+	}
+	return records
+}
+
+// hours formats seconds as hours with two decimals
+func hours(seconds int64) string {
+	return strconv.FormatFloat(float64(seconds)/3600, 'f', 2, 64)
+}
+
+// csvText keeps spreadsheets from evaluating user-entered text as a formula
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
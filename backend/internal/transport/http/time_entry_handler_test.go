@@ -0,0 +1,565 @@
+package http
+
+import (
+	"boilerplate/internal/entities"
+	"boilerplate/internal/service/domain"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Mock TimeEntryService for testing
+type mockTimeEntryService struct {
+	startTimerFunc   func(string, entities.UserRef, string) (entities.TimeEntry, error)
+	stopTimerFunc    func(string, string) (entities.TimeEntry, error)
+	runningTimerFunc func(string) (entities.TimeEntry, error)
+	createFunc       func(*entities.TimeEntry) error
+	updateFunc       func(*entities.TimeEntry, entities.UserRef) error
+	deleteFunc       func(string, entities.UserRef) error
+	findByIDFunc     func(string) (entities.TimeEntry, error)
+	findByTaskIDFunc func(string) ([]entities.TimeEntry, error)
+	reportFunc       func(string, string, string, string) (entities.TimeReport, error)
+}
+
+func (m *mockTimeEntryService) StartTimer(ctx context.Context, taskID string, user entities.UserRef, note string) (entities.TimeEntry, error) {
+	if m.startTimerFunc != nil {
+		return m.startTimerFunc(taskID, user, note)
+	}
+	return entities.TimeEntry{TaskID: taskID, User: user, Start: time.Now(), Note: note}, nil
+}
+
+func (m *mockTimeEntryService) StopTimer(ctx context.Context, taskID, userID string) (entities.TimeEntry, error) {
+	if m.stopTimerFunc != nil {
+		return m.stopTimerFunc(taskID, userID)
+	}
+	return entities.TimeEntry{}, domain.ErrNoTimerRunning
+}
+
+func (m *mockTimeEntryService) RunningTimer(ctx context.Context, userID string) (entities.TimeEntry, error) {
+	if m.runningTimerFunc != nil {
+		return m.runningTimerFunc(userID)
+	}
+	return entities.TimeEntry{}, domain.ErrNoTimerRunning
+}
+
+func (m *mockTimeEntryService) Create(ctx context.Context, entry *entities.TimeEntry) error {
+	if m.createFunc != nil {
+		return m.createFunc(entry)
+	}
+	return nil
+}
+
+func (m *mockTimeEntryService) Update(ctx context.Context, entry *entities.TimeEntry, editor entities.UserRef) error {
+	if m.updateFunc != nil {
+		return m.updateFunc(entry, editor)
+	}
+	return nil
+}
+
+func (m *mockTimeEntryService) Delete(ctx context.Context, id string, actor entities.UserRef) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(id, actor)
+	}
+	return nil
+}
+
+func (m *mockTimeEntryService) FindByID(ctx context.Context, id string) (entities.TimeEntry, error) {
+	if m.findByIDFunc != nil {
+		return m.findByIDFunc(id)
+	}
+	return entities.TimeEntry{}, errors.New("not found")
+}
+
+func (m *mockTimeEntryService) FindByTaskID(ctx context.Context, taskID string) ([]entities.TimeEntry, error) {
+	if m.findByTaskIDFunc != nil {
+		return m.findByTaskIDFunc(taskID)
+	}
+	return []entities.TimeEntry{}, nil
+}
+
+func (m *mockTimeEntryService) Report(ctx context.Context, projectID, from, to, timezone string) (entities.TimeReport, error) {
+	if m.reportFunc != nil {
+		return m.reportFunc(projectID, from, to, timezone)
+	}
+	return entities.TimeReport{}, domain.ErrProjectNotFound
+}
+
+// storedEntry returns the entries of task1: e1 is a stopped entry of jane and e2 a running timer of joe
+func storedEntry(id string) (entities.TimeEntry, error) {
+	start := time.Date(2030, 3, 25, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	switch id {
+	case "e1":
+		return entities.TimeEntry{ID: "e1", TaskID: "task1", User: entities.UserRef{ID: "jane"}, Start: start, End: &end, Duration: 3600}, nil
+	case "e2":
+		return entities.TimeEntry{ID: "e2", TaskID: "task1", User: entities.UserRef{ID: "joe"}, Start: start}, nil
+	}
+	return entities.TimeEntry{}, errors.New("time entry not found")
+}
+
+func TestTimeEntryHandler_Timer(t *testing.T) {
+	tests := []struct {
+		name           string
+		action         string
+		body           string
+		authenticated  bool
+		timerErr       error
+		expectedStatus int
+		expectedNote   string
+	}{
+		{
+			name:           "start",
+			action:         "start",
+			body:           `{"note":"Call"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusCreated,
+			expectedNote:   "Call",
+		},
+		{
+			name:           "start without note",
+			action:         "start",
+			authenticated:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "start on unknown task",
+			action:         "start",
+			authenticated:  true,
+			timerErr:       fmt.Errorf("%w: no task task1", domain.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "timer already running",
+			action:         "start",
+			authenticated:  true,
+			timerErr:       domain.ErrTimerRunning,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "start unauthenticated",
+			action:         "start",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "stop",
+			action:         "stop",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "stop without timer",
+			action:         "stop",
+			authenticated:  true,
+			timerErr:       domain.ErrNoTimerRunning,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "running timer",
+			action:         "running",
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no running timer",
+			action:         "running",
+			authenticated:  true,
+			timerErr:       domain.ErrNoTimerRunning,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userID string
+			start := time.Date(2030, 3, 25, 9, 0, 0, 0, time.UTC)
+			mockService := &mockTimeEntryService{
+				startTimerFunc: func(taskID string, user entities.UserRef, note string) (entities.TimeEntry, error) {
+					if tt.timerErr != nil {
+						return entities.TimeEntry{}, tt.timerErr
+					}
+					userID = user.ID
+					return entities.TimeEntry{ID: "e3", TaskID: taskID, User: user, Start: start, Note: note}, nil
+				},
+				stopTimerFunc: func(taskID, u string) (entities.TimeEntry, error) {
+					if tt.timerErr != nil {
+						return entities.TimeEntry{}, tt.timerErr
+					}
+					userID = u
+					end := start.Add(30 * time.Minute)
+					return entities.TimeEntry{ID: "e3", TaskID: taskID, User: entities.UserRef{ID: u}, Start: start, End: &end, Duration: 1800}, nil
+				},
+				runningTimerFunc: func(u string) (entities.TimeEntry, error) {
+					if tt.timerErr != nil {
+						return entities.TimeEntry{}, tt.timerErr
+					}
+					userID = u
+					return entities.TimeEntry{ID: "e3", TaskID: "task1", User: entities.UserRef{ID: u}, Start: start}, nil
+				},
+			}
+
+			handler := NewTimeEntryHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task1/timer/"+tt.action, bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "task1")
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			switch tt.action {
+			case "start":
+				handler.StartTimer(w, req)
+			case "stop":
+				handler.StopTimer(w, req)
+			default:
+				handler.RunningTimer(w, req)
+			}
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.timerErr != nil || !tt.authenticated {
+				return
+			}
+			if userID != "jane" {
+				t.Errorf("expected the timer of jane, got that of %q", userID)
+			}
+			var entry entities.TimeEntry
+			if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if entry.ID != "e3" || entry.TaskID != "task1" || entry.Note != tt.expectedNote {
+				t.Errorf("expected the timer e3 on task1 with note %q, got %+v", tt.expectedNote, entry)
+			}
+		})
+	}
+}
+
+func TestTimeEntryHandler_Create(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		authenticated  bool
+		createErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "with start",
+			body:           `{"start":"2030-03-25T09:00:00Z","duration":5400,"note":"Workshop"}`,
+			authenticated:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "without start",
+			body:           `{"duration":900}`,
+			authenticated:  true,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "no duration",
+			body:           `{}`,
+			authenticated:  true,
+			createErr:      &domain.ValidationError{Field: "duration", Message: "must be between 1 second and 24 hours"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid JSON",
+			body:           `{`,
+			authenticated:  true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown task",
+			body:           `{"duration":900}`,
+			authenticated:  true,
+			createErr:      fmt.Errorf("%w: no task task1", domain.ErrTaskNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unauthenticated",
+			body:           `{"duration":900}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *entities.TimeEntry
+			mockService := &mockTimeEntryService{
+				createFunc: func(entry *entities.TimeEntry) error {
+					if tt.createErr != nil {
+						return tt.createErr
+					}
+					entry.ID = "e3"
+					created = entry
+					return nil
+				},
+			}
+
+			handler := NewTimeEntryHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tasks/task1/time-entries", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "task1")
+			if tt.authenticated {
+				req = withUser(req)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			if created == nil || created.TaskID != "task1" {
+				t.Fatalf("expected an entry on task1, got %+v", created)
+			}
+			var entry entities.TimeEntry
+			if err := json.NewDecoder(w.Body).Decode(&entry); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if entry.ID != "e3" || entry.User.ID != "jane" || entry.TaskID != "task1" {
+				t.Errorf("expected an entry of jane on task1, got %+v", entry)
+			}
+		})
+	}
+}
+
+func TestTimeEntryHandler_Update(t *testing.T) {
+	tests := []struct {
+		name           string
+		taskID         string
+		entryID        string
+		updateErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "owner edits",
+			taskID:         "task1",
+			entryID:        "e1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "others cannot edit",
+			taskID:         "task1",
+			entryID:        "e2",
+			updateErr:      domain.ErrNotTimeEntryOwner,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "running timer",
+			taskID:         "task1",
+			entryID:        "e2",
+			updateErr:      domain.ErrTimerRunning,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "entry of another task",
+			taskID:         "task2",
+			entryID:        "e1",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "unknown entry",
+			taskID:         "task1",
+			entryID:        "e9",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated *entities.TimeEntry
+			mockService := &mockTimeEntryService{
+				findByIDFunc: storedEntry,
+				updateFunc: func(entry *entities.TimeEntry, editor entities.UserRef) error {
+					if tt.updateErr != nil {
+						return tt.updateErr
+					}
+					updated = entry
+					return nil
+				},
+			}
+
+			handler := NewTimeEntryHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/"+tt.taskID+"/time-entries/"+tt.entryID, bytes.NewBufferString(`{"duration":2700}`))
+			req.SetPathValue("id", tt.taskID)
+			req.SetPathValue("entryId", tt.entryID)
+			req = withUser(req)
+			w := httptest.NewRecorder()
+
+			handler.Update(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if updated == nil || updated.ID != "e1" || updated.Duration != 2700 || !updated.Start.Equal(time.Date(2030, 3, 25, 9, 0, 0, 0, time.UTC)) {
+				t.Errorf("expected the new duration and the previous start, got %+v", updated)
+			}
+		})
+	}
+}
+
+func TestTimeEntryHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name           string
+		entryID        string
+		deleteErr      error
+		expectedStatus int
+	}{
+		{
+			name:           "owner deletes",
+			entryID:        "e1",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "others cannot delete",
+			entryID:        "e2",
+			deleteErr:      domain.ErrNotTimeEntryOwner,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "unknown entry",
+			entryID:        "e9",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted string
+			mockService := &mockTimeEntryService{
+				findByIDFunc: storedEntry,
+				deleteFunc: func(id string, actor entities.UserRef) error {
+					if tt.deleteErr != nil {
+						return tt.deleteErr
+					}
+					deleted = id
+					return nil
+				},
+			}
+
+			handler := NewTimeEntryHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/task1/time-entries/"+tt.entryID, nil)
+			req.SetPathValue("id", "task1")
+			req.SetPathValue("entryId", tt.entryID)
+			req = withUser(req)
+			w := httptest.NewRecorder()
+
+			handler.Delete(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus == http.StatusNoContent && deleted != tt.entryID {
+				t.Errorf("expected %s to be deleted, got %q", tt.entryID, deleted)
+			}
+		})
+	}
+}
+
+func TestTimeEntryHandler_Report(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		reportErr      error
+		expectedStatus int
+		records        [][]string
+	}{
+		{name: "json", query: "?from=2030-03-01&to=2030-03-31&timezone=Europe/Berlin", expectedStatus: http.StatusOK},
+		{name: "csv by task", query: "?from=2030-03-01&to=2030-03-31&format=csv", expectedStatus: http.StatusOK, records: [][]string{
+			{"task_id", "title", "estimate_seconds", "estimate_hours", "duration_seconds", "hours", "entries"},
+			{"task1", "Design", "7200", "2.00", "5400", "1.50", "2"},
+			{"task2", "Review", "", "", "3600", "1.00", "1"},
+		}},
+		{name: "csv by user", query: "?from=2030-03-01&to=2030-03-31&format=csv&groupBy=user", expectedStatus: http.StatusOK, records: [][]string{
+			{"user_id", "name", "email", "duration_seconds", "hours", "entries"},
+			{"jane", "'=HYPERLINK(\"http://evil\")", "jane@example.com", "9000", "2.50", "3"},
+		}},
+		{name: "csv by day", query: "?from=2030-03-01&to=2030-03-31&format=csv&groupBy=day", expectedStatus: http.StatusOK, records: [][]string{
+			{"date", "duration_seconds", "hours", "entries"},
+			{"2030-03-25", "9000", "2.50", "3"},
+		}},
+		{name: "unknown format", query: "?from=2030-03-01&to=2030-03-31&format=xlsx", expectedStatus: http.StatusBadRequest},
+		{name: "unknown grouping", query: "?from=2030-03-01&to=2030-03-31&format=csv&groupBy=week", expectedStatus: http.StatusBadRequest},
+		{name: "invalid range", query: "?to=2030-03-31", reportErr: &domain.ValidationError{Field: "from", Message: "must be a date in the form YYYY-MM-DD"}, expectedStatus: http.StatusBadRequest},
+		{name: "unknown project", query: "?from=2030-03-01&to=2030-03-31", reportErr: fmt.Errorf("%w: project1", domain.ErrProjectNotFound), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args [4]string
+			mockService := &mockTimeEntryService{
+				reportFunc: func(projectID, from, to, timezone string) (entities.TimeReport, error) {
+					args = [4]string{projectID, from, to, timezone}
+					if tt.reportErr != nil {
+						return entities.TimeReport{}, tt.reportErr
+					}
+					return entities.TimeReport{
+						ProjectID: projectID, From: from, To: to, Timezone: "UTC", Duration: 9000, Entries: 3,
+						ByUser: []entities.TimeByUser{{User: entities.UserRef{ID: "jane", Name: "=HYPERLINK(\"http://evil\")", Email: "jane@example.com"}, Duration: 9000, Entries: 3}},
+						ByTask: []entities.TimeByTask{{TaskID: "task1", Title: "Design", Estimate: 7200, Duration: 5400, Entries: 2}, {TaskID: "task2", Title: "Review", Duration: 3600, Entries: 1}},
+						ByDay:  []entities.TimeByDay{{Date: "2030-03-25", Duration: 9000, Entries: 3}},
+					}, nil
+				},
+			}
+
+			handler := NewTimeEntryHandler(mockService, testLogger())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/projects/project1/reports/time"+tt.query, nil)
+			req.SetPathValue("id", "project1")
+			w := httptest.NewRecorder()
+
+			handler.Report(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			if tt.records == nil {
+				if args != [4]string{"project1", "2030-03-01", "2030-03-31", "Europe/Berlin"} {
+					t.Errorf("expected the project, range and time zone of the query, got %v", args)
+				}
+				var report entities.TimeReport
+				if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if report.Duration != 9000 || len(report.ByTask) != 2 {
+					t.Errorf("expected the report of the service, got %+v", report)
+				}
+				return
+			}
+
+			if contentType := w.Header().Get("Content-Type"); contentType != "text/csv; charset=utf-8" {
+				t.Errorf("expected a CSV content type, got %q", contentType)
+			}
+			if disposition := w.Header().Get("Content-Disposition"); !strings.HasPrefix(disposition, "attachment; filename=time-2030-03-01-2030-03-31-by-") {
+				t.Errorf("expected a CSV download, got %q", disposition)
+			}
+			records, err := csv.NewReader(w.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("failed to read CSV: %v", err)
+			}
+			if fmt.Sprint(records) != fmt.Sprint(tt.records) {
+				t.Errorf("expected records %q, got %q", tt.records, records)
+			}
+		})
+	}
+}
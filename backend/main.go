@@ -3,22 +3,37 @@ package main
 import (
 	"boilerplate/internal/auth"
 	"boilerplate/internal/config"
+	"boilerplate/internal/health"
 	"boilerplate/internal/logger"
+	"boilerplate/internal/metrics"
+	"boilerplate/internal/scheduler"
 	"boilerplate/internal/service"
 	"boilerplate/internal/storage"
+	"boilerplate/internal/storage/blob"
+	"boilerplate/internal/storage/mongodb"
+	"boilerplate/internal/tracing"
 	httpTransport "boilerplate/internal/transport/http"
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Recurring tasks resolve IANA time zones also in images without zoneinfo
+	_ "time/tzdata"
 
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
+//go:generate swag init --output docs --parseDependency --parseInternal
+
 // @title           Boilerplate API
 // @version         1.0
 // @description     Production-ready full-stack todo application API with Go backend and MongoDB persistence
@@ -41,24 +56,56 @@ import (
 // @scope.profile Profile scope
 // @scope.email Email scope
 
+const (
+	// readinessTimeout bounds each dependency check run by /ready
+	readinessTimeout = 2 * time.Second
+	// shutdownTimeout bounds the graceful shutdown and each cleanup step after it
+	shutdownTimeout = 30 * time.Second
+)
+
 func main() {
-	configPath := os.Getenv("CONFIG_PATH")
-	if configPath == "" {
-		configPath = "config/local.yaml"
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
 	}
+}
 
-	cfg, err := config.Load(configPath)
-	if err != nil {
-		log.Printf("Warning: failed to load config file: %v. Using defaults and environment variables.", err)
-		// Try loading with empty path to use defaults
-		cfg, err = config.Load("")
-		if err != nil {
-			log.Fatalf("Failed to initialize configuration: %v", err)
+// serve runs the HTTP server until it fails or a shutdown signal arrives.
+// flags are used to load the configuration again on reload. Every return goes
+// through the deferred cleanups, which stop the jobs, disconnect MongoDB and
+// flush traces and logs, so that the records explaining a failure reach Loki.
+func serve(cfg *config.Config, flags *configFlags) (err error) {
+	appLogger, logLevels, closeLogger := logger.New(cfg.Logging)
+	slog.SetDefault(appLogger)
+	// Close the logger last so that the records of all other cleanups reach Loki
+	defer func() {
+		if err == nil {
+			appLogger.Info("server shutdown complete")
 		}
-	}
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := closeLogger(ctx); err != nil {
+			log.Printf("failed to flush logs: %v", err)
+		}
+	}()
 
-	appLogger := logger.New(cfg.Logging)
-	slog.SetDefault(appLogger)
+	// Component loggers can be tuned separately through logging.components and the admin endpoint
+	storageLogger := appLogger.With(logger.ComponentKey, "storage")
+	authLogger := appLogger.With(logger.ComponentKey, "auth")
+	httpLogger := appLogger.With(logger.ComponentKey, "http")
+	schedulerLogger := appLogger.With(logger.ComponentKey, "scheduler")
+
+	// SIGUSR1 toggles debug logging without a restart
+	watchDebugToggle(logLevels, appLogger)
+
+	// Reloadable settings are read through the live handle; SIGHUP and config
+	// file changes reload them. Log levels are pushed to the level registry.
+	live := config.NewLive(cfg)
+	live.Subscribe(func(cfg *config.Config) {
+		logLevels.Configure(cfg.Logging.Level, cfg.Logging.Components)
+	})
+	watchCtx, stopWatching := context.WithCancel(context.Background())
+	defer stopWatching()
+	watchConfig(watchCtx, flags, live, appLogger)
 
 	appLogger.Info("starting boilerplate server",
 		"service_host", cfg.Service.Host,
@@ -66,40 +113,109 @@ func main() {
 		"auth_enabled", cfg.Auth.Enabled,
 	)
 
-	appLogger.Info("connecting to MongoDB", "uri", cfg.Database.URI)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Database.Timeout)*time.Second)
-	defer cancel()
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		appLogger.Error("failed to initialize tracing", "error", err)
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			appLogger.Error("failed to flush traces", "error", err)
+		}
+	}()
+	if cfg.Tracing.Enabled {
+		appLogger.Info("tracing enabled",
+			"exporter", cfg.Tracing.Exporter,
+			"protocol", cfg.Tracing.Protocol,
+			"endpoint", cfg.Tracing.Endpoint,
+		)
+	}
 
-	clientOptions := options.Client().ApplyURI(cfg.Database.URI)
+	// Metrics are collected only when the endpoint is enabled; a nil collector is a no-op
+	var appMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+	}
 
-	// Add authentication if credentials are provided
-	if cfg.Database.Username != "" && cfg.Database.Password != "" {
-		credential := options.Credential{
-			Username: cfg.Database.Username,
-			Password: cfg.Database.Password,
-		}
-		clientOptions.SetAuth(credential)
-		appLogger.Info("MongoDB authentication enabled", "username", cfg.Database.Username)
+	storageLogger.Info("connecting to MongoDB", "uri", cfg.Database.URI)
+
+	// Record command latency for metrics and create a span per command when tracing
+	monitors := []*event.CommandMonitor{appMetrics.CommandMonitor()}
+	if cfg.Tracing.Enabled {
+		monitors = append(monitors, otelmongo.NewMonitor())
+	}
+	if cfg.Database.Username != "" {
+		storageLogger.Info("MongoDB authentication enabled", "username", cfg.Database.Username)
 	}
 
-	mongoClient, err := mongo.Connect(ctx, clientOptions)
+	mongoClient, err := connectMongo(cfg.Database, mongodb.CombineMonitors(monitors...))
 	if err != nil {
-		appLogger.Error("failed to connect to MongoDB", "error", err)
-		os.Exit(1)
+		storageLogger.Error("MongoDB unavailable", "error", err)
+		return err
 	}
+	storageLogger.Info("connected to MongoDB")
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := mongoClient.Disconnect(ctx); err != nil {
+			storageLogger.Error("failed to disconnect from MongoDB", "error", err)
+		}
+	}()
 
-	if err := mongoClient.Ping(ctx, nil); err != nil {
-		appLogger.Error("failed to ping MongoDB", "error", err)
-		os.Exit(1)
+	if cfg.Database.MigrateOnStartup {
+		migrator := mongodb.NewMigrator(mongoClient, cfg.Database.Database, mongodb.Migrations)
+		applied, err := migrator.Up(context.Background(), 0)
+		for _, migration := range applied {
+			storageLogger.Info("applied migration", "version", migration.Version, "description", migration.Description)
+		}
+		if err != nil {
+			storageLogger.Error("failed to migrate database", "error", err)
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
+
+	blobStore, err := blob.New(context.Background(), cfg.Attachments)
+	if err != nil {
+		storageLogger.Error("attachment store unavailable", "store", cfg.Attachments.Store, "error", err)
+		return fmt.Errorf("attachment store unavailable: %w", err)
 	}
-	appLogger.Info("connected to MongoDB")
 
 	repo := storage.NewRepository(mongoClient, cfg.Database.Database)
-	svc := service.NewService(&repo)
+	repo.BlobStore = blobStore
+	svc := service.NewService(&repo, cfg.Tasks, cfg.Attachments, cfg.Notifications)
 
-	authMiddleware := auth.NewMiddleware(cfg.Auth, appLogger)
+	authMiddleware := auth.NewMiddleware(cfg.Auth, authLogger, appMetrics)
 
-	httpServer := httpTransport.NewServer(cfg.Service, cfg.CORS, cfg.Auth, cfg.Docs, cfg.RateLimit, svc, authMiddleware, appLogger)
+	// Readiness checks: MongoDB and the JWKS are required to serve requests, Loki and the attachment store are optional
+	healthRegistry := health.NewRegistry()
+	healthRegistry.Register("mongodb", true, readinessTimeout, func(ctx context.Context) error {
+		return mongoClient.Ping(ctx, readpref.Primary())
+	})
+	healthRegistry.Register("attachments", false, readinessTimeout, blobStore.Check)
+	if cfg.Auth.Enabled {
+		healthRegistry.Register("jwks", true, readinessTimeout, authMiddleware.CheckJWKS)
+	}
+	if cfg.Logging.LokiConfig != nil && cfg.Logging.LokiConfig.URL != "" {
+		healthRegistry.Register("loki", false, readinessTimeout, func(ctx context.Context) error {
+			return logger.CheckLoki(ctx, cfg.Logging.LokiConfig)
+		})
+	}
+
+	// Background jobs stop before the database connection is closed
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	jobs := scheduler.New(repo.LeaseRepository, schedulerLogger)
+	defer func() {
+		stopScheduler()
+		jobs.Wait()
+	}()
+	if cfg.Scheduler.Enabled {
+		registerJobs(jobs, svc, cfg, schedulerLogger)
+		jobs.Start(schedulerCtx)
+	}
+
+	httpServer := httpTransport.NewServer(live, svc, authMiddleware, healthRegistry, logLevels, appMetrics, httpLogger)
 
 	serverErrors := make(chan error, 1)
 	go func() {
@@ -113,25 +229,75 @@ func main() {
 	select {
 	case err := <-serverErrors:
 		appLogger.Error("server error", "error", err)
+		return fmt.Errorf("server error: %w", err)
 	case sig := <-shutdown:
 		appLogger.Info("received shutdown signal", "signal", sig)
 
-		// Graceful shutdown with 30 second timeout
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// Graceful shutdown with 30 second timeout; the deferred cleanups run afterwards
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 		defer cancel()
 
+		stopScheduler()
 		if err := httpServer.Shutdown(ctx); err != nil {
 			appLogger.Error("failed to gracefully shutdown server", "error", err)
-			if err := mongoClient.Disconnect(context.Background()); err != nil {
-				appLogger.Error("failed to disconnect from MongoDB", "error", err)
-			}
-			os.Exit(1)
+			return fmt.Errorf("failed to gracefully shutdown server: %w", err)
+		}
+		return nil
+	}
+}
+
+// registerJobs adds the background jobs of the application to the scheduler
+func registerJobs(jobs *scheduler.Scheduler, svc *service.Service, cfg *config.Config, jobLogger *slog.Logger) {
+	recurrence := cfg.Tasks.Recurrence
+	lookahead := time.Duration(recurrence.Lookahead) * time.Hour
+	jobs.Register("materialize-recurrences", time.Duration(recurrence.Interval)*time.Second, func(ctx context.Context) error {
+		created, err := svc.Task.MaterializeRecurrences(ctx, time.Now().Add(lookahead))
+		if created > 0 {
+			jobLogger.InfoContext(ctx, "created occurrences of recurring tasks", "count", created)
 		}
+		return err
+	})
 
-		if err := mongoClient.Disconnect(ctx); err != nil {
-			appLogger.Error("failed to disconnect from MongoDB", "error", err)
+	// Reminders are created before the deliveries run, so that new ones go out right away
+	jobs.Register("send-reminders", time.Duration(cfg.Notifications.Interval)*time.Second, func(ctx context.Context) error {
+		created, err := svc.Notification.SendReminders(ctx, time.Now())
+		if created > 0 {
+			jobLogger.InfoContext(ctx, "sent due date reminders", "count", created)
 		}
+		sent, deliveryErr := svc.Notification.DeliverPending(ctx)
+		if sent > 0 {
+			jobLogger.InfoContext(ctx, "delivered notifications", "count", sent)
+		}
+		return errors.Join(err, deliveryErr)
+	})
+}
+
+// connectMongo connects to MongoDB and verifies the connection with a ping.
+// monitor may be nil.
+func connectMongo(cfg config.DatabaseConfig, monitor *event.CommandMonitor) (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
 
-		appLogger.Info("server shutdown complete")
+	clientOptions := options.Client().ApplyURI(cfg.URI)
+	if monitor != nil {
+		clientOptions.SetMonitor(monitor)
+	}
+
+	// Add authentication if credentials are provided
+	if cfg.Username != "" && cfg.Password != "" {
+		clientOptions.SetAuth(options.Credential{
+			Username: cfg.Username,
+			Password: cfg.Password,
+		})
+	}
+
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
+	return client, nil
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"boilerplate/internal/config"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// reloadMu serializes reloads triggered by signals and file changes, so an
+// older load can never replace a newer one
+var reloadMu sync.Mutex
+
+// reloadConfig loads and validates the configuration again and applies the
+// reloadable settings. An invalid configuration is rejected as a whole and
+// the current one stays in effect.
+func reloadConfig(flags *configFlags, live *config.Live, appLogger *slog.Logger) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	cfg, err := flags.load()
+	if err != nil {
+		appLogger.Error("configuration reload rejected, keeping the current configuration", "error", err)
+		return
+	}
+
+	applied, ignored := live.Reload(cfg)
+	for _, key := range ignored {
+		appLogger.Warn("configuration change requires a restart to take effect", "key", key)
+	}
+	if len(applied) > 0 {
+		appLogger.Info("configuration reloaded", "changed", applied)
+	} else if len(ignored) == 0 {
+		appLogger.Info("configuration reloaded, no changes")
+	}
+}
+
+// watchConfig reloads the configuration on SIGHUP and, when service.watch_config
+// is enabled, whenever a file in the config directory changes
+func watchConfig(ctx context.Context, flags *configFlags, live *config.Live, appLogger *slog.Logger) {
+	reload := func() { reloadConfig(flags, live, appLogger) }
+	watchReloadSignal(reload)
+
+	if !live.Get().Service.WatchConfig {
+		return
+	}
+	dir := config.Dir(flags.path)
+	if err := config.Watch(ctx, dir, reload); err != nil {
+		appLogger.Warn("config file watching disabled", "error", err)
+		return
+	}
+	appLogger.Info("watching config files for changes", "dir", dir)
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package main
+
+import (
+	"boilerplate/internal/logger"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchDebugToggle switches the global log level between debug and the
+// configured level whenever the process receives SIGUSR1
+func watchDebugToggle(levels *logger.Levels, appLogger *slog.Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+
+	go func() {
+		for range signals {
+			level := levels.ToggleDebug()
+			appLogger.Warn("log level toggled by signal", "level", level.String())
+		}
+	}()
+}
+
+// watchReloadSignal calls reload whenever the process receives SIGHUP
+func watchReloadSignal(reload func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	go func() {
+		for range signals {
+			reload()
+		}
+	}()
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package main
+
+import (
+	"boilerplate/internal/logger"
+	"log/slog"
+)
+
+// watchDebugToggle is a no-op on Windows, which has no SIGUSR1
+func watchDebugToggle(levels *logger.Levels, appLogger *slog.Logger) {}
+
+// watchReloadSignal is a no-op on Windows, which has no SIGHUP; config files
+// are still watched when service.watch_config is enabled
+func watchReloadSignal(reload func()) {}